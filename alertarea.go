@@ -0,0 +1,55 @@
+package noaa
+
+// Polygons returns the polygons of the alert geometry, or nil for alerts
+// without a geometry.
+func (a Alert) Polygons() ([]Polygon, error) {
+	return parseGeometry("alert", a.Geometry)
+}
+
+// Covers reports whether the point is inside the alert polygon, ex. whether a
+// site is in the path of a tornado warning instead of only in its county.
+// Alerts without a polygon, ex. most watches and advisories which are issued
+// for whole zones, cover the point when one of their zones contains it, which
+// requires looking up the zones of the point, see ZonesForPoint.
+func (a Alert) Covers(lat string, lon string) (bool, error) {
+	polygons, err := a.Polygons()
+	if err != nil {
+		return false, err
+	}
+	if len(polygons) > 0 {
+		c, err := ParseCoordinates(lat, lon)
+		if err != nil {
+			return false, err
+		}
+		for _, p := range polygons {
+			if p.Contains(c) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	zones, err := ZonesForPoint(lat, lon)
+	if err != nil {
+		return false, err
+	}
+	alertZones := map[string]bool{}
+	for _, id := range a.Geocode.UGC {
+		alertZones[id] = true
+	}
+	for _, u := range a.AffectedZones {
+		alertZones[ZoneID(u)] = true
+	}
+	for _, zone := range []*Zone{zones.Forecast, zones.County, zones.Fire} {
+		if zone == nil {
+			continue
+		}
+		id := zone.ID
+		if id == "" {
+			id = ZoneID(zone.URI)
+		}
+		if alertZones[id] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,64 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestAlertCovers(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685": `{"forecastZone": "{api}/zones/forecast/ILZ014", "county": "{api}/zones/county/ILC031"}`,
+		"/points/41.9,-88.3":     `{"forecastZone": "{api}/zones/forecast/ILZ012", "county": "{api}/zones/county/ILC089"}`,
+		"/zones/forecast/ILZ014": `{"id": "ILZ014"}`,
+		"/zones/county/ILC031":   `{"id": "ILC031"}`,
+		"/zones/forecast/ILZ012": `{"id": "ILZ012"}`,
+		"/zones/county/ILC089":   `{"id": "ILC089"}`,
+		"/alerts/active": `{"type": "FeatureCollection", "features": [
+			{"geometry": {"type": "Polygon", "coordinates": [[[-87.8, 41.7], [-87.6, 41.7], [-87.6, 41.9], [-87.8, 41.9], [-87.8, 41.7]]]},
+			 "properties": {"id": "urn:oid:1", "event": "Tornado Warning", "geocode": {"UGC": ["ILC031"]}}},
+			{"geometry": null,
+			 "properties": {"id": "urn:oid:2", "event": "Heat Advisory", "affectedZones": ["{api}/zones/forecast/ILZ014"]}}
+		]}`,
+	})
+
+	alerts, err := noaa.Alerts("41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("noaa.Alerts() should return 2 alerts, got %d", len(alerts))
+	}
+	warning, advisory := alerts[0], alerts[1]
+	if polygons, err := warning.Polygons(); err != nil || len(polygons) != 1 {
+		t.Errorf("noaa.Alert.Polygons() should return the polygon of GeoJSON features, got %v, %v", polygons, err)
+	}
+	for _, test := range []struct {
+		alert    noaa.Alert
+		lat, lon string
+		covers   bool
+	}{
+		{warning, "41.837", "-87.685", true},
+		{warning, "41.9", "-88.3", false}, // in another county
+		{warning, "41.75", "-87.55", false},
+		{advisory, "41.837", "-87.685", true},
+		{advisory, "41.9", "-88.3", false},
+	} {
+		if covers, err := test.alert.Covers(test.lat, test.lon); err != nil || covers != test.covers {
+			t.Errorf("noaa.Alert.Covers(%s, %s) for the %s should return %v, got %v, %v", test.lat, test.lon, test.alert.Event, test.covers, covers, err)
+		}
+	}
+
+	wkt := noaa.Alert{Geometry: []byte(`"POLYGON ((-87.8 41.7, -87.6 41.7, -87.6 41.9, -87.8 41.7))"`)}
+	if covers, err := wkt.Covers("41.72", "-87.61"); err != nil || !covers {
+		t.Errorf("noaa.Alert.Covers() should parse WKT polygons, got %v, %v", covers, err)
+	}
+	invalid := noaa.Alert{Geometry: []byte(`{"type": "Point", "coordinates": [-87.7, 41.8]}`)}
+	if _, err := invalid.Covers("41.8", "-87.7"); err == nil || !strings.Contains(err.Error(), "alert geometry") {
+		t.Errorf("noaa.Alert.Covers() should return an error for unsupported geometries, got %v", err)
+	}
+}
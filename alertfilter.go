@@ -0,0 +1,144 @@
+package noaa
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// AlertFilter selects alerts by event, severity, urgency and status. The zero
+// filter matches every alert. Filters can be applied to any alert slice with
+// Apply, or passed to AlertsQuery and AlertsWithFilter which also send them as
+// query parameters so weather.gov returns fewer alerts.
+type AlertFilter struct {
+	// Events are the event names to keep, ex. Tornado Warning. Names are
+	// compared ignoring case.
+	Events []string
+
+	// MinSeverity keeps alerts with at least this severity, ex.
+	// SeveritySevere keeps Severe and Extreme alerts. Alerts of unknown
+	// severity are dropped unless MinSeverity is SeverityUnknown.
+	MinSeverity Severity
+
+	// Urgency are the urgencies to keep, ex. UrgencyImmediate.
+	Urgency []Urgency
+
+	// ExcludeTest drops alerts whose status is not Actual, ex. Test and
+	// Exercise messages.
+	ExcludeTest bool
+}
+
+// Match reports whether the alert passes the filter.
+func (f AlertFilter) Match(a Alert) bool {
+	if len(f.Events) > 0 {
+		found := false
+		for _, event := range f.Events {
+			if strings.EqualFold(strings.TrimSpace(event), strings.TrimSpace(a.Event)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinSeverity > SeverityUnknown {
+		if severity, _ := ParseSeverity(a.Severity); severity < f.MinSeverity {
+			return false
+		}
+	}
+	if len(f.Urgency) > 0 {
+		urgency, _ := ParseUrgency(a.Urgency)
+		found := false
+		for _, u := range f.Urgency {
+			if u == urgency {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.ExcludeTest {
+		if status, _ := ParseAlertStatus(a.Status); status != AlertStatusActual {
+			return false
+		}
+	}
+	return true
+}
+
+// isZero reports whether the filter is the zero filter, matching every alert
+func (f AlertFilter) isZero() bool {
+	return len(f.Events) == 0 && f.MinSeverity == SeverityUnknown && len(f.Urgency) == 0 && !f.ExcludeTest
+}
+
+// Apply returns the alerts which pass the filter, keeping their order.
+func (f AlertFilter) Apply(alerts []Alert) []Alert {
+	filtered := []Alert{}
+	for _, a := range alerts {
+		if f.Match(a) {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
+
+// Query adds the /alerts/active query parameters equivalent to the filter to
+// query, keeping parameters which are already set, and returns it. A nil
+// query is allocated.
+func (f AlertFilter) Query(query url.Values) url.Values {
+	if query == nil {
+		query = url.Values{}
+	}
+	set := func(key string, values []string) {
+		if len(values) > 0 && query.Get(key) == "" {
+			query.Set(key, strings.Join(values, ","))
+		}
+	}
+	set("event", f.Events)
+	if f.MinSeverity > SeverityUnknown {
+		var severities []string
+		for s := f.MinSeverity; s <= SeverityExtreme; s++ {
+			severities = append(severities, s.String())
+		}
+		set("severity", severities)
+	}
+	var urgencies []string
+	for _, u := range f.Urgency {
+		urgencies = append(urgencies, u.String())
+	}
+	set("urgency", urgencies)
+	if f.ExcludeTest {
+		set("status", []string{"actual"})
+	}
+	return query
+}
+
+// AlertsQuery returns the active alerts matching the /alerts/active query
+// parameters, ex. area=IL, and the filter. The filter is sent as query
+// parameters and also applied to the response, so the result is the same
+// whether weather.gov supports every parameter or not.
+func AlertsQuery(query url.Values, filter AlertFilter) ([]Alert, error) {
+	return alertsQuery(context.Background(), query, filter)
+}
+
+// alertsQuery is AlertsQuery with the request made with the context
+func alertsQuery(ctx context.Context, query url.Values, filter AlertFilter) ([]Alert, error) {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q = filter.Query(q)
+	alerts, err := activeAlerts(ctx, apiURL("alerts", "active").withQuery(q).String())
+	if err != nil {
+		return alerts, err
+	}
+	return filter.Apply(alerts), nil
+}
+
+// AlertsWithFilter is like Alerts but only returns the alerts matching the
+// filter, see AlertsQuery.
+func AlertsWithFilter(lat string, lon string, filter AlertFilter) ([]Alert, error) {
+	return AlertsQuery(url.Values{"point": {lat + "," + lon}}, filter)
+}
@@ -0,0 +1,100 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+var filterAlerts = []noaa.Alert{
+	{Identifier: "1", Event: "Tornado Warning", Severity: "Extreme", Urgency: "Immediate", Status: "Actual"},
+	{Identifier: "2", Event: "Heat Advisory", Severity: "Moderate", Urgency: "Expected", Status: "Actual"},
+	{Identifier: "3", Event: "Tornado Warning", Severity: "Extreme", Urgency: "Immediate", Status: "Test"},
+	{Identifier: "4", Event: "Severe Thunderstorm Warning", Severity: "Severe", Urgency: "Immediate", Status: "Actual"},
+	{Identifier: "5", Event: "Special Weather Statement", Severity: "Unknown", Urgency: "Unknown", Status: "Actual"},
+}
+
+func TestAlertFilterApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   noaa.AlertFilter
+		expected string
+	}{
+		{"zero", noaa.AlertFilter{}, "12345"},
+		{"events", noaa.AlertFilter{Events: []string{"tornado warning", "Heat Advisory"}}, "123"},
+		{"severity", noaa.AlertFilter{MinSeverity: noaa.SeveritySevere}, "134"},
+		{"urgency", noaa.AlertFilter{Urgency: []noaa.Urgency{noaa.UrgencyExpected, noaa.UrgencyUnknown}}, "25"},
+		{"test", noaa.AlertFilter{ExcludeTest: true}, "1245"},
+		{"combined", noaa.AlertFilter{Events: []string{"Tornado Warning"}, MinSeverity: noaa.SeverityExtreme, ExcludeTest: true}, "1"},
+	}
+	for _, test := range tests {
+		ids := ""
+		for _, a := range test.filter.Apply(filterAlerts) {
+			ids += a.Identifier
+		}
+		if ids != test.expected {
+			t.Errorf("%s: expected alerts %s, got %s", test.name, test.expected, ids)
+		}
+	}
+}
+
+func TestAlertFilterQuery(t *testing.T) {
+	f := noaa.AlertFilter{
+		Events:      []string{"Tornado Warning", "Flood Warning"},
+		MinSeverity: noaa.SeveritySevere,
+		Urgency:     []noaa.Urgency{noaa.UrgencyImmediate},
+		ExcludeTest: true,
+	}
+	q := f.Query(url.Values{"status": {"test"}})
+	expected := url.Values{
+		"event":    {"Tornado Warning,Flood Warning"},
+		"severity": {"Severe,Extreme"},
+		"urgency":  {"Immediate"},
+		"status":   {"test"},
+	}
+	if q.Encode() != expected.Encode() {
+		t.Errorf("noaa.AlertFilter.Query() should return %s, got %s", expected.Encode(), q.Encode())
+	}
+}
+
+func TestAlertsWithFilter(t *testing.T) {
+	var query url.Values
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		// ignore the filter, as if weather.gov did not support it
+		fmt.Fprint(w, `{"@graph": [`)
+		for i, a := range filterAlerts {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id": %q, "event": %q, "severity": %q, "urgency": %q, "status": %q}`, a.Identifier, a.Event, a.Severity, a.Urgency, a.Status)
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	alerts, err := noaa.AlertsWithFilter("41.837", "-87.685", noaa.AlertFilter{MinSeverity: noaa.SeveritySevere, ExcludeTest: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("point") != "41.837,-87.685" || query.Get("severity") != "Severe,Extreme" || query.Get("status") != "actual" {
+		t.Errorf("noaa.AlertsWithFilter() should send the filter as query parameters, got %s", query.Encode())
+	}
+	if len(alerts) != 2 || alerts[0].Identifier != "1" || alerts[1].Identifier != "4" {
+		t.Errorf("noaa.AlertsWithFilter() should filter the response, got %+v", alerts)
+	}
+}
@@ -0,0 +1,115 @@
+package noaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAlertHistoryPages is the number of pages of alerts followed by
+// AlertsHistory when AlertHistoryOptions.MaxPages is 0.
+const DefaultAlertHistoryPages = 20
+
+// ErrHistoryTruncated is returned by AlertsHistory with the alerts of the
+// pages it followed when more pages remained.
+var ErrHistoryTruncated = errors.New("alert history truncated")
+
+// zoneIDPattern matches public and county zone IDs, ex. ILZ014 or ILC031
+var zoneIDPattern = regexp.MustCompile(`^[A-Z]{2}[CZ][0-9]{3}$`)
+
+// ValidateZoneID returns an error for IDs which are not public or county zone
+// IDs, ex. ILZ014 or ILC031, as accepted by AlertsForZone.
+func ValidateZoneID(id string) error {
+	if !zoneIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid zone ID %q, expected a public or county zone ID such as ILZ014", id)
+	}
+	return nil
+}
+
+// alertsPage is a page of an /alerts response
+type alertsPage struct {
+	Alerts     []Alert `json:"@graph"`
+	Pagination struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+// AlertHistoryOptions limit the alerts returned by AlertsHistory.
+type AlertHistoryOptions struct {
+	// Until is the end of the history, now if zero.
+	Until time.Time
+	// PageSize is the number of alerts requested per page, up to 500. The
+	// weather.gov default is used if 0.
+	PageSize int
+	// MaxPages is the number of pages followed, DefaultAlertHistoryPages if
+	// 0, so a long history cannot make unbounded requests.
+	MaxPages int
+}
+
+// AlertsHistory returns the alerts of a location sent since the given time,
+// newest first, including those which are no longer active, ex. to
+// reconstruct the warnings of a storm for a post-event report:
+//
+//	alerts, err := noaa.AlertsHistory(ctx, "ILZ014", time.Now().AddDate(0, 0, -3), noaa.AlertHistoryOptions{})
+//
+// The location is a zone ID, ex. ILZ014, or a point, ex. 41.837,-87.685. The
+// pages of the /alerts endpoint are followed until the history is complete or
+// MaxPages were requested, in which case the alerts are returned with
+// ErrHistoryTruncated. Each page is retried like other requests, see
+// SetRetries. When a page still fails, the alerts of the previous pages are
+// returned with the error, so a partial history is not lost.
+func AlertsHistory(ctx context.Context, location string, since time.Time, opts AlertHistoryOptions) ([]Alert, error) {
+	query := url.Values{}
+	location = strings.ToUpper(strings.ReplaceAll(location, " ", ""))
+	if zoneIDPattern.MatchString(location) {
+		query.Set("zone", location)
+	} else {
+		lat, lon, _ := strings.Cut(location, ",")
+		if err := validateCoordinates(lat, lon); err != nil {
+			return nil, fmt.Errorf("invalid location %q: %w", location, err)
+		}
+		query.Set("point", location)
+	}
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	query.Set("start", since.UTC().Format(time.RFC3339))
+	query.Set("end", until.UTC().Format(time.RFC3339))
+	if opts.PageSize > 0 {
+		query.Set("limit", strconv.Itoa(opts.PageSize))
+	}
+	maxPages := opts.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultAlertHistoryPages
+	}
+
+	alerts := []Alert{}
+	endpoint := apiURL("alerts").withQuery(query).String()
+	for page := 0; endpoint != ""; page++ {
+		if page == maxPages {
+			return alerts, fmt.Errorf("%w after %d pages", ErrHistoryTruncated, maxPages)
+		}
+		r, err := GetJSON[alertsPage](ctx, endpoint, nil)
+		if err != nil {
+			return alerts, fmt.Errorf("alert history page %d: %w", page+1, err)
+		}
+		for _, a := range r.Alerts {
+			// weather.gov filters by time too, check in case it does not
+			if sent, err := time.Parse(time.RFC3339, a.Sent); err == nil && (sent.Before(since) || sent.After(until)) {
+				continue
+			}
+			alerts = append(alerts, a)
+		}
+		if len(r.Alerts) == 0 || r.Pagination.Next == endpoint {
+			break
+		}
+		endpoint = r.Pagination.Next
+	}
+	return alerts, nil
+}
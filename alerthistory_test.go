@@ -0,0 +1,89 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// historyAPI serves pages of alerts sent every hour before now, newest first,
+// failing the page given by fail, and counts the requests
+func historyAPI(t *testing.T, now time.Time, pages int, fail int) (*int32, *string) {
+	var requests int32
+	var query string
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if r.URL.Query().Get("cursor") == "" {
+			query = r.URL.RawQuery
+		}
+		if n == fail {
+			http.Error(w, `{"title": "Bad Request"}`, http.StatusBadRequest)
+			return
+		}
+		if n > pages {
+			fmt.Fprint(w, `{"@graph": []}`)
+			return
+		}
+		sent := now.Add(-time.Duration(n) * time.Hour).Format(time.RFC3339)
+		fmt.Fprintf(w, `{"@graph": [{"id": "urn:oid:%d", "sent": "%s", "event": "Wind Advisory"}], "pagination": {"next": "%s/alerts?cursor=%d"}}`,
+			n, sent, api.URL, n)
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	return &requests, &query
+}
+
+func TestAlertsHistory(t *testing.T) {
+	now := time.Now()
+	requests, query := historyAPI(t, now, 3, 0)
+	alerts, err := noaa.AlertsHistory(context.Background(), "ilz014", now.Add(-150*time.Minute), noaa.AlertHistoryOptions{PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 || alerts[0].Identifier != "urn:oid:1" {
+		t.Errorf("noaa.AlertsHistory() should return the alerts sent since the time, got %+v", alerts)
+	}
+	if n := atomic.LoadInt32(requests); n != 4 {
+		t.Errorf("noaa.AlertsHistory() should follow the pages until an empty one, got %d requests", n)
+	}
+	if q := *query; !strings.Contains(q, "zone=ILZ014") || !strings.Contains(q, "limit=1") || !strings.Contains(q, "start=") || !strings.Contains(q, "end=") {
+		t.Errorf("noaa.AlertsHistory() should request the zone and time range, got %q", *query)
+	}
+}
+
+func TestAlertsHistoryLimits(t *testing.T) {
+	now := time.Now()
+	historyAPI(t, now, 10, 0)
+	alerts, err := noaa.AlertsHistory(context.Background(), "41.837,-87.685", now.Add(-24*time.Hour), noaa.AlertHistoryOptions{MaxPages: 2})
+	if !errors.Is(err, noaa.ErrHistoryTruncated) || len(alerts) != 2 {
+		t.Errorf("noaa.AlertsHistory() should return the alerts of MaxPages with ErrHistoryTruncated, got %d alerts, %v", len(alerts), err)
+	}
+
+	historyAPI(t, now, 10, 3)
+	alerts, err = noaa.AlertsHistory(context.Background(), "41.837,-87.685", now.Add(-24*time.Hour), noaa.AlertHistoryOptions{})
+	if err == nil || len(alerts) != 2 {
+		t.Errorf("noaa.AlertsHistory() should return the alerts of the pages before a failure with its error, got %d alerts, %v", len(alerts), err)
+	}
+
+	if _, err := noaa.AlertsHistory(context.Background(), "Chicago", now, noaa.AlertHistoryOptions{}); err == nil {
+		t.Error("noaa.AlertsHistory() should reject a location which is not a zone or point")
+	}
+}
@@ -0,0 +1,243 @@
+package noaa
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// AlertStore persists the events of an AlertTracker and the identifiers of
+// the alerts applied to them, so a tracker restored after a restart does not
+// report the alerts it already reported, see NewPersistentAlertTracker.
+// Implementations must be safe for concurrent use.
+//
+// MemoryAlertStore and FileAlertStore are provided, and the storage module
+// stores them in its SQLite database. Stores of other databases, ex. bbolt or
+// Redis, are not provided; they implement the three methods with the event
+// keys and alert identifiers as keys.
+type AlertStore interface {
+	// Load returns the stored events and the keys of the events each stored
+	// alert identifier was applied to.
+	Load() (events []AlertEvent, seen map[string][]string, err error)
+	// Save stores the events, replacing the events with the same keys, and
+	// the event keys of the alert identifiers.
+	Save(events []AlertEvent, seen map[string][]string) error
+	// Delete removes the events with the keys and the alert identifiers.
+	Delete(keys []string, alerts []string) error
+}
+
+// alertState is the state of a tracker as saved by the stores of this package
+type alertState struct {
+	Events map[string]AlertEvent `json:"events"`
+	Seen   map[string][]string   `json:"seen"`
+}
+
+// newAlertState returns an empty state
+func newAlertState() alertState {
+	return alertState{Events: map[string]AlertEvent{}, Seen: map[string][]string{}}
+}
+
+// events returns the events of the state
+func (s alertState) events() ([]AlertEvent, map[string][]string) {
+	events := make([]AlertEvent, 0, len(s.Events))
+	for _, e := range s.Events {
+		events = append(events, copyAlertEvent(&e))
+	}
+	seen := make(map[string][]string, len(s.Seen))
+	for id, keys := range s.Seen {
+		seen[id] = append([]string(nil), keys...)
+	}
+	return events, seen
+}
+
+// save applies Save to the state
+func (s alertState) save(events []AlertEvent, seen map[string][]string) {
+	for i := range events {
+		s.Events[events[i].Key] = copyAlertEvent(&events[i])
+	}
+	for id, keys := range seen {
+		s.Seen[id] = append([]string(nil), keys...)
+	}
+}
+
+// delete applies Delete to the state
+func (s alertState) delete(keys []string, alerts []string) {
+	for _, key := range keys {
+		delete(s.Events, key)
+	}
+	for _, id := range alerts {
+		delete(s.Seen, id)
+	}
+}
+
+// MemoryAlertStore is an AlertStore which keeps the state in memory, ex. to
+// share it between the trackers of a process or in tests. It does not
+// survive restarts.
+type MemoryAlertStore struct {
+	mu    sync.Mutex
+	state alertState
+}
+
+// NewMemoryAlertStore returns an empty MemoryAlertStore.
+func NewMemoryAlertStore() *MemoryAlertStore {
+	return &MemoryAlertStore{state: newAlertState()}
+}
+
+// Load implements AlertStore.
+func (s *MemoryAlertStore) Load() ([]AlertEvent, map[string][]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events, seen := s.state.events()
+	return events, seen, nil
+}
+
+// Save implements AlertStore.
+func (s *MemoryAlertStore) Save(events []AlertEvent, seen map[string][]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.save(events, seen)
+	return nil
+}
+
+// Delete implements AlertStore.
+func (s *MemoryAlertStore) Delete(keys []string, alerts []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.delete(keys, alerts)
+	return nil
+}
+
+// FileAlertStore is an AlertStore which keeps the state in a JSON file. The
+// file is read once and rewritten on each change, by writing and syncing a
+// temporary file next to it and renaming it, so a crash leaves either the
+// previous or the new state. It suits the few dozen events of a location;
+// use a database for more.
+type FileAlertStore struct {
+	Path string
+
+	mu     sync.Mutex
+	state  alertState
+	loaded bool
+}
+
+// NewFileAlertStore returns a store of the state in the file at path, which
+// is created on the first change.
+func NewFileAlertStore(path string) *FileAlertStore {
+	return &FileAlertStore{Path: path}
+}
+
+// Load implements AlertStore. A missing file is an empty state.
+func (s *FileAlertStore) Load() ([]AlertEvent, map[string][]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return nil, nil, err
+	}
+	events, seen := s.state.events()
+	return events, seen, nil
+}
+
+// Save implements AlertStore.
+func (s *FileAlertStore) Save(events []AlertEvent, seen map[string][]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.state.save(events, seen)
+	return s.write()
+}
+
+// Delete implements AlertStore.
+func (s *FileAlertStore) Delete(keys []string, alerts []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.state.delete(keys, alerts)
+	return s.write()
+}
+
+// load reads the file unless it was already read
+func (s *FileAlertStore) load() error {
+	if s.loaded {
+		return nil
+	}
+	state := newAlertState()
+	data, err := os.ReadFile(s.Path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &state); err != nil {
+			return err
+		}
+		if state.Events == nil {
+			state.Events = map[string]AlertEvent{}
+		}
+		if state.Seen == nil {
+			state.Seen = map[string][]string{}
+		}
+	}
+	s.state, s.loaded = state, true
+	return nil
+}
+
+// write replaces the file with the state
+func (s *FileAlertStore) write() error {
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.Path, data)
+}
+
+// writeFile replaces the file at path with the data by writing a temporary
+// file next to it and renaming it, so a crash leaves the previous file. The
+// file and then its directory are synced, so the rename is durable.
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir syncs the directory, persisting the renames in it. It does nothing
+// on Windows, which does not support syncing directories.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
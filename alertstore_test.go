@@ -0,0 +1,104 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestPersistentAlertTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	issued := vtecAlert("a1", "2023-06-15T21:00:00Z", "/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/")
+	extended := vtecAlert("a2", "2023-06-15T21:30:00Z", "/O.EXT.KBOU.SV.W.0042.000000T0000Z-230615T2230Z/")
+
+	tracker, err := noaa.NewPersistentAlertTracker(noaa.NewFileAlertStore(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := tracker.Update(issued); len(changed) != 1 || tracker.Err() != nil {
+		t.Fatalf("noaa.AlertTracker.Update() should save the new event, got %+v, %v", changed, tracker.Err())
+	}
+
+	// restart
+	tracker, err = noaa.NewPersistentAlertTracker(noaa.NewFileAlertStore(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := tracker.Update(issued); len(changed) != 0 {
+		t.Errorf("noaa.NewPersistentAlertTracker() should restore the alerts seen, got %+v", changed)
+	}
+	changed := tracker.Update(issued, extended)
+	if len(changed) != 1 || changed[0].Status != "EXT" || len(changed[0].History) != 2 {
+		t.Fatalf("noaa.NewPersistentAlertTracker() should restore the events, got %+v", changed)
+	}
+	if changed[0].VTEC == nil || !changed[0].VTEC.End.Equal(time.Date(2023, 6, 15, 22, 30, 0, 0, time.UTC)) {
+		t.Errorf("noaa.NewPersistentAlertTracker() should restore the VTEC of the events, got %+v", changed[0].VTEC)
+	}
+
+	tracker.Prune(time.Date(2023, 6, 16, 0, 0, 0, 0, time.UTC))
+	events, seen, err := noaa.NewFileAlertStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 || len(seen) != 0 {
+		t.Errorf("noaa.AlertTracker.Prune() should delete the events from the store, got %+v, %v", events, seen)
+	}
+}
+
+// failingStore is an AlertStore which fails to save
+type failingStore struct{ *noaa.MemoryAlertStore }
+
+func (failingStore) Save([]noaa.AlertEvent, map[string][]string) error {
+	return errors.New("disk full")
+}
+
+func TestAlertTrackerStoreError(t *testing.T) {
+	tracker, err := noaa.NewPersistentAlertTracker(failingStore{noaa.NewMemoryAlertStore()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := vtecAlert("a1", "2023-06-15T21:00:00Z", "/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/")
+	if changed := tracker.Update(alert); len(changed) != 1 {
+		t.Errorf("noaa.AlertTracker.Update() should track events when saving fails, got %+v", changed)
+	}
+	if tracker.Err() == nil {
+		t.Error("noaa.AlertTracker.Err() should return the error of the store")
+	}
+
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := noaa.NewPersistentAlertTracker(noaa.NewFileAlertStore(path)); err == nil {
+		t.Error("noaa.NewPersistentAlertTracker() should fail on an invalid file")
+	}
+}
+
+func TestPollerAlertStore(t *testing.T) {
+	store := noaa.NewMemoryAlertStore()
+	for run := 0; run < 2; run++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		alertsAPI(t, "Moderate", "Expected", cancel)
+		var alerts int
+		p := &noaa.Poller{
+			Locations:     []noaa.Location{{Name: "home", Lat: "41.837", Lon: "-87.685"}},
+			Bus:           noaa.NewBus(),
+			AlertInterval: time.Hour,
+			AlertStore:    func(noaa.Location) (noaa.AlertStore, error) { return store, nil },
+			OnAlert:       func(noaa.Location, noaa.AlertEvent) { alerts++ },
+		}
+		p.Run(ctx)
+		cancel()
+		if want := 1 - run; alerts != want {
+			t.Errorf("noaa.Poller run %d should report %d alerts with the store, got %d", run+1, want, alerts)
+		}
+	}
+}
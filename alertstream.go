@@ -0,0 +1,96 @@
+package noaa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// DecodeAlerts decodes the alerts of an /alerts response one at a time and
+// passes each to fn, without holding the whole list in memory. Decoding stops
+// when fn returns false. Both the JSON-LD @graph list and GeoJSON features are
+// supported.
+func DecodeAlerts(r io.Reader, fn func(Alert) bool) error {
+	decoder := json.NewDecoder(r)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+		if key != "@graph" && key != "features" {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := expectDelim(decoder, '['); err != nil {
+			return err
+		}
+		for decoder.More() {
+			var alert Alert
+			if key == "features" {
+				var feature struct {
+					Geometry   json.RawMessage `json:"geometry"`
+					Properties Alert           `json:"properties"`
+				}
+				err = decoder.Decode(&feature)
+				alert = feature.Properties
+				alert.Geometry = feature.Geometry
+			} else {
+				err = decoder.Decode(&alert)
+			}
+			if err != nil {
+				return err
+			}
+			if !fn(alert) {
+				return nil
+			}
+		}
+		if _, err := decoder.Token(); err != nil { // ]
+			return err
+		}
+	}
+	return nil
+}
+
+// expectDelim reads the next token and checks that it is the delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("invalid alerts response: expected %q, got %v", delim, token)
+	}
+	return nil
+}
+
+// StreamAlerts requests the active alerts matching the query, ex.
+// url.Values{"severity": {"Extreme"}}, or all active alerts for a nil query,
+// and passes them to fn one at a time as they are decoded. The request stops
+// early when fn returns false, ex. once a matching alert is found:
+//
+//	var tornado *noaa.Alert
+//	err := noaa.StreamAlerts(ctx, nil, func(a noaa.Alert) bool {
+//		if a.Event == "Tornado Warning" {
+//			tornado = &a
+//			return false
+//		}
+//		return true
+//	})
+func StreamAlerts(ctx context.Context, query url.Values, fn func(Alert) bool) error {
+	endpoint := apiURL("alerts", "active").withQuery(query).String()
+	res, err := apiCallContext(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return DecodeAlerts(res.Body, fn)
+}
@@ -0,0 +1,51 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestDecodeAlerts(t *testing.T) {
+	for name, body := range map[string]string{
+		"JSON-LD": `{"@context": {"@version": "1.1"}, "@graph": [{"id": "a1", "event": "Flood Watch"}, {"id": "a2", "event": "Tornado Warning"}, {"id": "a3"}], "title": "current watches, warnings, and advisories"}`,
+		"GeoJSON": `{"type": "FeatureCollection", "features": [{"id": "x", "properties": {"id": "a1", "event": "Flood Watch"}}, {"properties": {"id": "a2", "event": "Tornado Warning"}}, {"properties": {"id": "a3"}}]}`,
+	} {
+		var ids []string
+		err := noaa.DecodeAlerts(strings.NewReader(body), func(a noaa.Alert) bool {
+			ids = append(ids, a.Identifier)
+			return a.Event != "Tornado Warning"
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if strings.Join(ids, ",") != "a1,a2" {
+			t.Errorf("noaa.DecodeAlerts() should decode %s alerts until fn returns false, got %v", name, ids)
+		}
+	}
+
+	if err := noaa.DecodeAlerts(strings.NewReader(`[]`), func(noaa.Alert) bool { return true }); err == nil {
+		t.Error("noaa.DecodeAlerts() should return an error for an invalid response")
+	}
+}
+
+func TestStreamAlerts(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/alerts/active": `{"@graph": [{"id": "a1", "severity": "Extreme"}, {"id": "a2", "severity": "Extreme"}]}`,
+	})
+
+	n := 0
+	err := noaa.StreamAlerts(context.Background(), url.Values{"severity": {"Extreme"}}, func(noaa.Alert) bool {
+		n++
+		return true
+	})
+	if err != nil || n != 2 {
+		t.Errorf("noaa.StreamAlerts() should pass each alert to fn, got %d alerts, %v", n, err)
+	}
+}
@@ -0,0 +1,168 @@
+package noaa
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AlertDetails is the description and instruction of an alert split into the
+// bullets used by weather.gov, ex. * WHAT...Heat index values up to 105
+// expected. Text is reflowed into paragraphs and all-caps text is converted to
+// sentence case, see ReflowText and NormalizeCase. Fields are blank when the
+// alert has no such bullet.
+type AlertDetails struct {
+	Summary           string // text preceding the bullets, ex. the headline and overview
+	What              string // ex. Heat index values up to 105 expected.
+	Where             string // ex. Portions of northeast Illinois.
+	When              string // ex. From noon to 8 PM CDT Tuesday.
+	Impacts           string // ex. Hot temperatures may cause heat illnesses.
+	AdditionalDetails string
+	Hazard            string // short-fused warnings, ex. 60 mph wind gusts.
+	Source            string // short-fused warnings, ex. Radar indicated.
+	Instruction       string // precautionary and preparedness actions
+}
+
+var (
+	// alertBullet matches a bullet of an alert description, ex.
+	// * WHAT...Heat index values up to 105 expected.
+	alertBullet = regexp.MustCompile(`(?s)^(\* )?([A-Z][A-Z ]*?)\.\.\.(.*)$`)
+	// alertWord matches a word for NormalizeCase
+	alertWord = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)?`)
+)
+
+// alertBullets are the bullet names recognized without a leading "* ", ex.
+// HAZARD...60 mph wind gusts.
+var alertBullets = map[string]bool{
+	"WHAT": true, "WHERE": true, "WHEN": true, "IMPACT": true, "IMPACTS": true,
+	"HAZARD": true, "SOURCE": true, "ADDITIONAL DETAILS": true,
+}
+
+// alertAcronyms are kept in capitals by NormalizeCase
+var alertAcronyms = map[string]bool{
+	"NWS": true, "AM": true, "PM": true, "UTC": true, "US": true,
+	"EST": true, "EDT": true, "CST": true, "CDT": true, "MST": true, "MDT": true,
+	"PST": true, "PDT": true, "AKST": true, "AKDT": true, "HST": true, "SST": true, "CHST": true,
+}
+
+// alertProperNouns are capitalized by NormalizeCase
+var alertProperNouns = map[string]bool{
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true,
+	"saturday": true, "sunday": true, "january": true, "february": true, "march": true,
+	"april": true, "june": true, "july": true, "august": true, "september": true,
+	"october": true, "november": true, "december": true, "i": true,
+}
+
+// Details returns the description and instruction of the alert split into
+// bullets, see ParseAlertDescription.
+func (a Alert) Details() AlertDetails {
+	details := ParseAlertDescription(a.Description)
+	details.Instruction = NormalizeCase(ReflowText(a.Instruction))
+	return details
+}
+
+// ParseAlertDescription splits the description of an alert into its bullets.
+// Paragraphs which are not bullets are added to Summary, and bullets of
+// unknown names are added to AdditionalDetails.
+func ParseAlertDescription(description string) AlertDetails {
+	var details AlertDetails
+	var summary, additional []string
+	for _, paragraph := range strings.Split(ReflowText(description), "\n\n") {
+		m := alertBullet.FindStringSubmatch(paragraph)
+		if m == nil || (m[1] == "" && !alertBullets[m[2]]) {
+			if text := strings.Trim(paragraph, ". "); text != "" {
+				if strings.HasPrefix(paragraph, "...") {
+					paragraph = text + "." // headline, ex. ...HEAT ADVISORY IN EFFECT...
+				}
+				summary = append(summary, NormalizeCase(strings.TrimPrefix(paragraph, "* ")))
+			}
+			continue
+		}
+		text := NormalizeCase(strings.TrimSpace(m[3]))
+		var field *string
+		switch m[2] {
+		case "WHAT":
+			field = &details.What
+		case "WHERE":
+			field = &details.Where
+		case "WHEN":
+			field = &details.When
+		case "IMPACT", "IMPACTS":
+			field = &details.Impacts
+		case "HAZARD":
+			field = &details.Hazard
+		case "SOURCE":
+			field = &details.Source
+		case "ADDITIONAL DETAILS":
+			additional = append(additional, text)
+			continue
+		default:
+			additional = append(additional, NormalizeCase(m[2])+": "+text)
+			continue
+		}
+		*field = text
+	}
+	details.Summary = strings.Join(summary, "\n\n")
+	details.AdditionalDetails = strings.Join(additional, "\n\n")
+	return details
+}
+
+// ReflowText joins the hard wrapped lines of alert and product text into
+// paragraphs separated by a blank line. Bullets starting with "* " or "- "
+// start a new paragraph.
+func ReflowText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var paragraphs, lines []string
+	flush := func() {
+		if len(lines) > 0 {
+			paragraphs = append(paragraphs, strings.Join(lines, " "))
+			lines = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "* "), strings.HasPrefix(line, "- "):
+			flush()
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// NormalizeCase converts all-caps text to sentence case, ex. HEAT ADVISORY IN
+// EFFECT FROM NOON TO 8 PM CDT TUESDAY becomes Heat advisory in effect from
+// noon to 8 PM CDT Tuesday. Time zones, AM and PM are kept in capitals, and
+// days and months are capitalized. Text containing lowercase letters is
+// returned unchanged.
+func NormalizeCase(text string) string {
+	if strings.ToUpper(text) != text || strings.ToLower(text) == text {
+		return text
+	}
+	var b strings.Builder
+	last, sentence := 0, true
+	for _, loc := range alertWord.FindAllStringIndex(text, -1) {
+		between := text[last:loc[0]]
+		b.WriteString(between)
+		if end := strings.TrimSpace(between); strings.HasSuffix(end, ".") || strings.HasSuffix(end, "!") ||
+			strings.HasSuffix(end, "?") || strings.Contains(between, "...") {
+			sentence = true
+		}
+		word := text[loc[0]:loc[1]]
+		lower := strings.ToLower(word)
+		switch {
+		case alertAcronyms[word]:
+		case sentence || alertProperNouns[lower]:
+			word = word[:1] + lower[1:]
+		default:
+			word = lower
+		}
+		b.WriteString(word)
+		last, sentence = loc[1], false
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
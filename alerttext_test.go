@@ -0,0 +1,75 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+const heatAdvisory = `...HEAT ADVISORY IN EFFECT FROM NOON TO 8 PM CDT
+TUESDAY...
+
+* WHAT...Heat index values up to 105
+expected.
+
+* WHERE...Portions of northeast
+Illinois.
+
+* WHEN...From noon to 8 PM CDT Tuesday.
+
+* IMPACTS...Hot temperatures and high humidity may cause heat
+illnesses to occur.
+
+* ADDITIONAL DETAILS...THE HIGHEST HEAT INDEX VALUES ARE EXPECTED
+NEAR THE LAKE.`
+
+func TestAlertDetails(t *testing.T) {
+	alert := noaa.Alert{
+		Description: heatAdvisory,
+		Instruction: "Drink plenty of fluids, stay in an air-conditioned\nroom, and stay out of the sun.",
+	}
+	details := alert.Details()
+	expected := noaa.AlertDetails{
+		Summary:           "Heat advisory in effect from noon to 8 PM CDT Tuesday.",
+		What:              "Heat index values up to 105 expected.",
+		Where:             "Portions of northeast Illinois.",
+		When:              "From noon to 8 PM CDT Tuesday.",
+		Impacts:           "Hot temperatures and high humidity may cause heat illnesses to occur.",
+		AdditionalDetails: "The highest heat index values are expected near the lake.",
+		Instruction:       "Drink plenty of fluids, stay in an air-conditioned room, and stay out of the sun.",
+	}
+	if details != expected {
+		t.Errorf("noaa.Alert.Details() should split the description into bullets, got %+v", details)
+	}
+
+	warning := noaa.ParseAlertDescription("At 315 PM CDT, a severe thunderstorm was located near Joliet.\n\nHAZARD...60 mph wind gusts.\n\nSOURCE...Radar indicated.\n\nIMPACT...Expect damage to trees.")
+	if warning.Hazard != "60 mph wind gusts." || warning.Source != "Radar indicated." || warning.Impacts != "Expect damage to trees." {
+		t.Errorf("noaa.ParseAlertDescription() should parse warning bullets, got %+v", warning)
+	}
+	if warning.Summary != "At 315 PM CDT, a severe thunderstorm was located near Joliet." {
+		t.Errorf("noaa.ParseAlertDescription() should keep the overview, got %q", warning.Summary)
+	}
+}
+
+func TestReflowText(t *testing.T) {
+	text := noaa.ReflowText("Line one\r\ncontinues here.\n\n\n- bullet one\n- bullet\n  two\n")
+	if text != "Line one continues here.\n\n- bullet one\n\n- bullet two" {
+		t.Errorf("noaa.ReflowText() should join wrapped lines into paragraphs, got %q", text)
+	}
+}
+
+func TestNormalizeCase(t *testing.T) {
+	for text, expected := range map[string]string{
+		"FLOOD WATCH REMAINS IN EFFECT THROUGH SATURDAY MORNING. RIVERS RISE.": "Flood watch remains in effect through Saturday morning. Rivers rise.",
+		"WINDS UP TO 40 MPH UNTIL 10 AM EDT...DIMINISHING LATE":                "Winds up to 40 mph until 10 AM EDT...Diminishing late",
+		"Mixed Case Is Kept": "Mixed Case Is Kept",
+		"105 °F":             "105 °F",
+	} {
+		if normalized := noaa.NormalizeCase(text); normalized != expected {
+			t.Errorf("noaa.NormalizeCase(%q) should return %q, got %q", text, expected, normalized)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package archive retrieves past gridpoint forecasts, as they were issued at a
+// given time, for forecast verification (forecast vs. actual) analysis:
+//
+//	rec := archive.Dir("forecasts")
+//	rec.Save(forecast) // ex. from a Poller, each time the forecast updates
+//	...
+//	past, err := archive.Forecast(ctx, rec, "41.837", "-87.685", yesterday)
+//
+// Forecasts are returned in the same noaa.GridpointForecastResponse structure
+// as current forecasts. Sources implement Source; Dir stores the forecasts
+// fetched by the application, since weather.gov only serves the current
+// forecast. The NDFD archives of the Iowa Environmental Mesonet and NCEI are in
+// GRIB2, which needs a GRIB2 decoder outside of this module, and can be used
+// by implementing Source with one. PeriodConfidences estimates the confidence
+// in the periods of a forecast from the changes between archived forecasts.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// ErrNotFound is returned when no forecast was issued for the grid cell at or
+// before the requested time.
+var ErrNotFound = errors.New("archived forecast not found")
+
+// Source is an archive of gridpoint forecasts.
+type Source interface {
+	// GridForecast returns the latest forecast for the grid cell issued at or
+	// before the time.
+	GridForecast(ctx context.Context, office string, x, y int64, at time.Time) (*noaa.GridpointForecastResponse, error)
+}
+
+// Forecast returns the latest forecast for the point issued at or before the
+// time. The point is resolved to its grid cell with noaa.Points.
+func Forecast(ctx context.Context, src Source, lat string, lon string, at time.Time) (*noaa.GridpointForecastResponse, error) {
+	point, err := noaa.Points(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	forecast, err := src.GridForecast(ctx, point.GridID, point.GridX, point.GridY, at)
+	if err != nil {
+		return nil, err
+	}
+	forecast.Point = point
+	return forecast, nil
+}
+
+// snapshotLayout names snapshot files by their update time
+const snapshotLayout = "20060102T150405Z"
+
+// Dir is a Source storing forecasts as JSON files in a directory, one per
+// update in <dir>/<office>/<x>,<y>/<update time>.json.
+type Dir string
+
+// Save stores the forecast. Forecasts must have a Point, which is set by
+// noaa.GridpointForecast, and an update time. Saving the same update again
+// replaces it.
+func (d Dir) Save(forecast *noaa.GridpointForecastResponse) error {
+	if forecast.Point == nil || forecast.Point.GridID == "" {
+		return errors.New("archive: forecast has no grid point")
+	}
+	updated, err := time.Parse(time.RFC3339, forecast.Updated)
+	if err != nil {
+		return fmt.Errorf("archive: invalid forecast update time %q", forecast.Updated)
+	}
+	p := forecast.Point
+	dir := d.cell(p.GridID, p.GridX, p.GridY)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(forecast)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so readers never see partial files
+	name := filepath.Join(dir, updated.UTC().Format(snapshotLayout)+".json")
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// GridForecast implements Source.
+func (d Dir) GridForecast(ctx context.Context, office string, x, y int64, at time.Time) (*noaa.GridpointForecastResponse, error) {
+	entries, err := os.ReadDir(d.cell(office, x, y))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var updates []time.Time
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		if t, err := time.Parse(snapshotLayout, strings.TrimSuffix(name, ".json")); err == nil && !t.After(at) {
+			updates = append(updates, t)
+		}
+	}
+	if len(updates) == 0 {
+		return nil, ErrNotFound
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Before(updates[j]) })
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(d.cell(office, x, y), updates[len(updates)-1].Format(snapshotLayout)+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var forecast noaa.GridpointForecastResponse
+	if err := json.Unmarshal(data, &forecast); err != nil {
+		return nil, fmt.Errorf("archive: %w", err)
+	}
+	return &forecast, nil
+}
+
+// cell returns the directory of a grid cell
+func (d Dir) cell(office string, x, y int64) string {
+	return filepath.Join(string(d), filepath.Base(office), fmt.Sprintf("%d,%d", x, y))
+}
@@ -0,0 +1,82 @@
+package archive_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/archive"
+)
+
+// gridpoint returns a forecast for the LOT 76,73 grid cell updated at t
+func gridpoint(t string, temperature float64) *noaa.GridpointForecastResponse {
+	return &noaa.GridpointForecastResponse{
+		Updated: t,
+		Temperature: noaa.GridpointForecastTimeSeries{Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-07-04T18:00:00+00:00/PT1H", Value: temperature},
+		}},
+		Point: &noaa.PointsResponse{GridID: "LOT", GridX: 76, GridY: 73},
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := archive.Dir(t.TempDir())
+	for _, f := range []*noaa.GridpointForecastResponse{
+		gridpoint("2023-07-04T08:00:00+00:00", 24),
+		gridpoint("2023-07-04T14:00:00+00:00", 26),
+		gridpoint("2023-07-04T20:00:00+00:00", 27),
+	} {
+		if err := dir.Save(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	forecast, err := dir.GridForecast(ctx, "LOT", 76, 73, time.Date(2023, 7, 4, 15, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forecast.Updated != "2023-07-04T14:00:00+00:00" || forecast.Temperature.Values[0].Value != 26 {
+		t.Errorf("expected the forecast issued at 14:00, got %s", forecast.Updated)
+	}
+	if _, err := dir.GridForecast(ctx, "LOT", 76, 73, time.Date(2023, 7, 4, 7, 0, 0, 0, time.UTC)); !errors.Is(err, archive.ErrNotFound) {
+		t.Errorf("expected ErrNotFound before the first forecast, got %v", err)
+	}
+	if _, err := dir.GridForecast(ctx, "LOT", 1, 1, time.Now()); !errors.Is(err, archive.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for another grid cell, got %v", err)
+	}
+	if err := dir.Save(&noaa.GridpointForecastResponse{Updated: "2023-07-04T08:00:00+00:00"}); err == nil {
+		t.Error("expected an error saving a forecast without a point")
+	}
+}
+
+func TestForecast(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"gridId": "LOT", "gridX": 76, "gridY": 73}`)
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	dir := archive.Dir(t.TempDir())
+	if err := dir.Save(gridpoint("2023-07-04T08:00:00+00:00", 24)); err != nil {
+		t.Fatal(err)
+	}
+	forecast, err := archive.Forecast(context.Background(), dir, "41.837", "-87.685", time.Date(2023, 7, 5, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forecast.Temperature.Values[0].Value != 24 || forecast.Point.GridID != "LOT" {
+		t.Errorf("expected the archived forecast for the point, got %+v", forecast)
+	}
+}
@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// DefaultSnapshots is the number of archived forecasts compared by
+// PeriodConfidences when n is 0.
+const DefaultSnapshots = 8
+
+// Scales of the volatility scores: a mean change of TemperatureScale °C or
+// PrecipitationScale percent between successive forecasts halves the
+// confidence.
+var (
+	TemperatureScale   = 2.0
+	PrecipitationScale = 20.0
+)
+
+// PeriodConfidence is the estimated confidence in the forecast of a period,
+// see PeriodConfidences.
+type PeriodConfidence struct {
+	Period *noaa.ForecastResponsePeriod
+
+	// Score is from 0 to 1, 1 when successive forecasts agreed on the period.
+	Score float64
+	// Mean absolute change between successive forecasts of the mean
+	// temperature (°C) and highest chance of precipitation (percent) of the
+	// period.
+	TemperatureVolatility   float64
+	PrecipitationVolatility float64
+	// Spread of the mean temperature of the period (°C) across the forecasts.
+	TemperatureSpread float64
+	Snapshots         int  // forecasts covering the period
+	OK                bool // false if fewer than 2 forecasts cover the period
+}
+
+// PeriodConfidences estimates the confidence in each period of the forecast
+// from how much the gridpoint forecasts of its grid cell changed for the
+// period over the last n updates archived in src, DefaultSnapshots if n is 0.
+// Forecasts which keep changing for a period are less reliable than ones
+// which stay the same. The forecast must have a Point, which is set by
+// noaa.Forecast.
+func PeriodConfidences(ctx context.Context, src Source, forecast *noaa.ForecastResponse, n int) ([]PeriodConfidence, error) {
+	if forecast.Point == nil {
+		return nil, errors.New("archive: the forecast has no point")
+	}
+	if n <= 0 {
+		n = DefaultSnapshots
+	}
+	snapshots, err := latest(ctx, src, forecast.Point, time.Now(), n)
+	if err != nil {
+		return nil, err
+	}
+	confidences := make([]PeriodConfidence, len(forecast.Periods))
+	for i := range forecast.Periods {
+		p := &forecast.Periods[i]
+		confidences[i] = confidence(p, snapshots)
+	}
+	return confidences, nil
+}
+
+// latest returns up to n of the forecasts of the point's grid cell issued at
+// or before the time, oldest first
+func latest(ctx context.Context, src Source, p *noaa.PointsResponse, at time.Time, n int) ([]*noaa.GridpointForecastResponse, error) {
+	var snapshots []*noaa.GridpointForecastResponse
+	for len(snapshots) < n {
+		f, err := src.GridForecast(ctx, p.GridID, p.GridX, p.GridY, at)
+		if errors.Is(err, ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		updated, err := time.Parse(time.RFC3339, f.Updated)
+		if err != nil {
+			return nil, fmt.Errorf("archive: invalid forecast update time %q", f.Updated)
+		}
+		snapshots = append([]*noaa.GridpointForecastResponse{f}, snapshots...)
+		at = updated.Add(-time.Second)
+	}
+	return snapshots, nil
+}
+
+// confidence compares the forecasts of the period in the snapshots
+func confidence(p *noaa.ForecastResponsePeriod, snapshots []*noaa.GridpointForecastResponse) PeriodConfidence {
+	c := PeriodConfidence{Period: p}
+	start, end, err := p.Interval()
+	if err != nil {
+		return c
+	}
+	var temperatures, chances []float64
+	for _, s := range snapshots {
+		celsius := noaa.ConvertSeries(s.Temperature.Series(), noaa.LocaleSI)
+		t, ok := noaa.SeriesMean(celsius.Window(start, end))
+		if !ok {
+			continue
+		}
+		temperatures = append(temperatures, t)
+		highest, _ := noaa.SeriesMax(s.ProbabilityOfPrecipitation.Series().Window(start, end))
+		chances = append(chances, highest.Value)
+	}
+	c.Snapshots = len(temperatures)
+	if c.Snapshots < 2 {
+		return c
+	}
+	c.TemperatureVolatility = volatility(temperatures)
+	c.PrecipitationVolatility = volatility(chances)
+	lowest, highest := temperatures[0], temperatures[0]
+	for _, t := range temperatures {
+		lowest, highest = math.Min(lowest, t), math.Max(highest, t)
+	}
+	c.TemperatureSpread = highest - lowest
+	c.Score = 1 / (1 + c.TemperatureVolatility/TemperatureScale + c.PrecipitationVolatility/PrecipitationScale)
+	c.OK = true
+	return c
+}
+
+// volatility returns the mean absolute change between successive values
+func volatility(values []float64) float64 {
+	var sum float64
+	for i := 1; i < len(values); i++ {
+		sum += math.Abs(values[i] - values[i-1])
+	}
+	return sum / float64(len(values)-1)
+}
@@ -0,0 +1,57 @@
+package archive_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/archive"
+)
+
+func TestPeriodConfidences(t *testing.T) {
+	dir := archive.Dir(t.TempDir())
+	for _, f := range []*noaa.GridpointForecastResponse{
+		gridpoint("2023-07-03T08:00:00+00:00", 20),
+		gridpoint("2023-07-03T20:00:00+00:00", 24),
+		gridpoint("2023-07-04T08:00:00+00:00", 22),
+	} {
+		f.Temperature.Uom = "wmoUnit:degC"
+		if err := dir.Save(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	forecast := &noaa.ForecastResponse{
+		Point: &noaa.PointsResponse{GridID: "LOT", GridX: 76, GridY: 73},
+		Periods: []noaa.ForecastResponsePeriod{
+			{Name: "This Afternoon", StartTime: "2023-07-04T13:00:00-05:00", EndTime: "2023-07-04T18:00:00-05:00"},
+			{Name: "Tonight", StartTime: "2023-07-04T18:00:00-05:00", EndTime: "2023-07-05T06:00:00-05:00"},
+		},
+	}
+	confidences, err := archive.PeriodConfidences(context.Background(), dir, forecast, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(confidences) != 2 || confidences[0].Period != &forecast.Periods[0] {
+		t.Fatalf("expected a confidence for each period, got %+v", confidences)
+	}
+	c := confidences[0]
+	// changes of 4 and 2 °C between the 3 forecasts
+	if !c.OK || c.Snapshots != 3 || c.TemperatureVolatility != 3 || c.TemperatureSpread != 4 {
+		t.Errorf("expected the volatility of the 3 forecasts, got %+v", c)
+	}
+	if math.Abs(c.Score-0.4) > 1e-9 {
+		t.Errorf("expected a score of 0.4, got %v", c.Score)
+	}
+	if confidences[1].OK || confidences[1].Snapshots != 0 {
+		t.Errorf("expected no confidence for a period without archived forecasts, got %+v", confidences[1])
+	}
+
+	confidences, err = archive.PeriodConfidences(context.Background(), dir, forecast, 1)
+	if err != nil || confidences[0].OK || confidences[0].Snapshots != 1 {
+		t.Errorf("expected no confidence from a single forecast, got %+v, %v", confidences[0], err)
+	}
+	if _, err := archive.PeriodConfidences(context.Background(), dir, &noaa.ForecastResponse{}, 0); err == nil {
+		t.Error("expected an error for a forecast without a point")
+	}
+}
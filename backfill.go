@@ -0,0 +1,189 @@
+package noaa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBackfill is how far back a Poller backfills if MaxBackfill is 0.
+// weather.gov keeps about a week of observations.
+const DefaultMaxBackfill = 7 * 24 * time.Hour
+
+// WatermarkStore persists the time up to which a Poller received the data of
+// each location, so it can backfill the data it missed while it was down, see
+// Poller.Backfill. The keys are the location name followed by /observations
+// or /alerts. Implementations must be safe for concurrent use.
+type WatermarkStore interface {
+	// Watermark returns the watermark of the key, zero if there is none.
+	Watermark(key string) (time.Time, error)
+	// SetWatermark stores the watermark of the key.
+	SetWatermark(key string, t time.Time) error
+}
+
+// FileWatermarkStore is a WatermarkStore which keeps the watermarks in a JSON
+// file, rewritten on each change like FileAlertStore.
+type FileWatermarkStore struct {
+	Path string
+
+	mu         sync.Mutex
+	watermarks map[string]time.Time
+}
+
+// NewFileWatermarkStore returns a store of the watermarks in the file at
+// path, which is created on the first change.
+func NewFileWatermarkStore(path string) *FileWatermarkStore {
+	return &FileWatermarkStore{Path: path}
+}
+
+// Watermark implements WatermarkStore.
+func (s *FileWatermarkStore) Watermark(key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return time.Time{}, err
+	}
+	return s.watermarks[key], nil
+}
+
+// SetWatermark implements WatermarkStore.
+func (s *FileWatermarkStore) SetWatermark(key string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.watermarks[key] = t
+	data, err := json.Marshal(s.watermarks)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.Path, data)
+}
+
+// load reads the file unless it was already read
+func (s *FileWatermarkStore) load() error {
+	if s.watermarks != nil {
+		return nil
+	}
+	watermarks := map[string]time.Time{}
+	data, err := os.ReadFile(s.Path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &watermarks); err != nil {
+			return err
+		}
+	}
+	s.watermarks = watermarks
+	return nil
+}
+
+// watermarkKeys returns the keys of the observation and alert watermarks of a
+// location
+func watermarkKeys(loc Location) (observations string, alerts string) {
+	return loc.Name + "/observations", loc.Name + "/alerts"
+}
+
+// setWatermark stores the watermark, if the poller has a store
+func (p *Poller) setWatermark(loc Location, key string, t time.Time) {
+	if p.Watermarks == nil {
+		return
+	}
+	if err := p.Watermarks.SetWatermark(key, t); err != nil {
+		p.error(loc, err)
+	}
+}
+
+// backfill reports the observations and alerts of the location since its
+// watermarks, oldest first. Nothing is backfilled for locations without
+// watermarks, ex. on the first run.
+func (p *Poller) backfill(ctx context.Context, loc Location) {
+	observationsKey, alertsKey := watermarkKeys(loc)
+	limit := p.MaxBackfill
+	if limit <= 0 {
+		limit = DefaultMaxBackfill
+	}
+	earliest := time.Now().Add(-limit)
+	since := func(key string) time.Time {
+		t, err := p.Watermarks.Watermark(key)
+		if err != nil {
+			p.error(loc, err)
+			return time.Time{}
+		}
+		if !t.IsZero() && t.Before(earliest) {
+			t = earliest
+		}
+		return t
+	}
+
+	if start := since(observationsKey); !start.IsZero() && (p.ObservationInterval > 0 || p.ObservationSchedule != nil) {
+		p.backfillObservations(ctx, loc, start)
+	}
+	if start := since(alertsKey); !start.IsZero() && p.AlertInterval > 0 {
+		polled := time.Now()
+		alerts, err := AlertsHistory(ctx, loc.Lat+","+loc.Lon, start, AlertHistoryOptions{})
+		if err != nil {
+			// a truncated or partial history is still applied
+			p.requestError(ctx, loc, err)
+		}
+		if !loc.Alerts.isZero() {
+			alerts = loc.Alerts.Apply(alerts)
+		}
+		for i, j := 0, len(alerts)-1; i < j; i, j = i+1, j-1 {
+			alerts[i], alerts[j] = alerts[j], alerts[i]
+		}
+		tracker := p.trackers[loc.Name]
+		for _, event := range tracker.Update(alerts...) {
+			p.bus().Publish(AlertIssued{Location: loc, Event: event, Backfill: true})
+			if p.OnAlert != nil {
+				p.OnAlert(loc, event)
+			}
+		}
+		if storeErr := tracker.Err(); storeErr != nil {
+			p.error(loc, storeErr)
+		}
+		if err == nil {
+			p.setWatermark(loc, alertsKey, polled)
+		}
+	}
+}
+
+// backfillObservations reports the observations of the nearest station since
+// start, oldest first
+func (p *Poller) backfillObservations(ctx context.Context, loc Location, start time.Time) {
+	stations, err := StationsContext(ctx, loc.Lat, loc.Lon)
+	if err != nil {
+		p.requestError(ctx, loc, err)
+		return
+	}
+	if len(stations.Stations) == 0 {
+		return
+	}
+	observations, err := stationObservations(ctx, stations.Stations[0], ObservationsOptions{Start: start})
+	if err != nil {
+		p.requestError(ctx, loc, err)
+		return
+	}
+	for i := len(observations) - 1; i >= 0; i-- {
+		o := observations[i]
+		if !o.Timestamp.After(start) || !o.Timestamp.After(p.observations[loc.Name]) {
+			continue
+		}
+		p.observations[loc.Name] = o.Timestamp
+		p.bus().Publish(ObservationReceived{Location: loc, Observation: o, Backfill: true})
+		if p.OnObservation != nil {
+			p.OnObservation(loc, o)
+		}
+	}
+	if latest := p.observations[loc.Name]; latest.After(start) {
+		observationsKey, _ := watermarkKeys(loc)
+		p.setWatermark(loc, observationsKey, latest)
+	}
+}
@@ -0,0 +1,107 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestPollerBackfill(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	alert := func(id string, sent time.Time, expires time.Time) string {
+		return fmt.Sprintf(`{"id": "%s", "messageType": "Alert", "event": "Wind Advisory", "sent": "%s", "expires": "%s"}`,
+			id, sent.Format(time.RFC3339), expires.Format(time.RFC3339))
+	}
+	fakeAPI(t, map[string]string{
+		"/points/40.11,-88.24":           `{"observationStations": "{api}/gridpoints/ILX/96,71/stations"}`,
+		"/gridpoints/ILX/96,71/stations": `{"observationStations": ["{api}/stations/KCMI"]}`,
+		"/stations/KCMI/observations": `{"@graph": [` + observation(now.Add(-time.Hour), "V") + `,` +
+			observation(now.Add(-2*time.Hour), "V") + `,` + observation(now.Add(-4*time.Hour), "V") + `]}`,
+		"/stations/KCMI/observations/latest": observation(now.Add(-time.Hour), "V"),
+		"/alerts": `{"@graph": [` + alert("a2", now.Add(-time.Hour), now.Add(time.Hour)) + `,` +
+			alert("a1", now.Add(-2*time.Hour), now.Add(-90*time.Minute)) + `]}`,
+		"/alerts/active": `{"@graph": [` + alert("a2", now.Add(-time.Hour), now.Add(time.Hour)) + `]}`,
+	})
+
+	watermarks := noaa.NewFileWatermarkStore(filepath.Join(t.TempDir(), "watermarks.json"))
+	for _, key := range []string{"home/observations", "home/alerts"} {
+		if err := watermarks.SetWatermark(key, now.Add(-3*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bus := noaa.NewBus()
+	var backfilled int
+	bus.Subscribe(func(e noaa.Event) {
+		switch e := e.(type) {
+		case noaa.ObservationReceived:
+			if e.Backfill {
+				backfilled++
+			}
+		case noaa.AlertIssued:
+			if e.Backfill {
+				backfilled++
+			}
+		}
+	})
+	var observations []time.Time
+	var alerts []string
+	p := &noaa.Poller{
+		Locations:           []noaa.Location{{Name: "home", Lat: "40.11", Lon: "-88.24"}},
+		Bus:                 bus,
+		ObservationInterval: time.Hour,
+		AlertInterval:       time.Hour,
+		Watermarks:          watermarks,
+		Backfill:            true,
+		OnObservation:       func(_ noaa.Location, o noaa.Observation) { observations = append(observations, o.Timestamp) },
+		OnAlert:             func(_ noaa.Location, e noaa.AlertEvent) { alerts = append(alerts, e.Current.Identifier) },
+		OnError:             func(_ noaa.Location, err error) { t.Error(err) },
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	p.Run(ctx)
+
+	if len(observations) != 3 || !observations[0].Equal(now.Add(-2*time.Hour)) || !observations[1].Equal(now.Add(-time.Hour)) {
+		t.Errorf("noaa.Poller should backfill the observations since the watermark, oldest first, got %v", observations)
+	}
+	if len(alerts) != 2 || alerts[0] != "a1" || alerts[1] != "a2" {
+		t.Errorf("noaa.Poller should backfill the alert history once, oldest first, got %v", alerts)
+	}
+	if backfilled != 4 {
+		t.Errorf("noaa.Poller should publish backfilled data with Backfill set, got %d events", backfilled)
+	}
+	if w, _ := watermarks.Watermark("home/observations"); !w.Equal(now.Add(-time.Hour)) {
+		t.Errorf("noaa.Poller should move the watermark to the last observation, got %v", w)
+	}
+	if w, _ := watermarks.Watermark("home/alerts"); !w.After(now.Add(-time.Minute)) {
+		t.Errorf("noaa.Poller should move the watermark to the last alert poll, got %v", w)
+	}
+}
+
+func TestPollerBackfillFirstRun(t *testing.T) {
+	requests := alertsAPI(t, "Moderate", "Expected", func() {})
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	watermarks := noaa.NewFileWatermarkStore(filepath.Join(t.TempDir(), "watermarks.json"))
+	p := &noaa.Poller{
+		Locations:     []noaa.Location{{Name: "home", Lat: "41.837", Lon: "-87.685"}},
+		Bus:           noaa.NewBus(),
+		AlertInterval: time.Hour,
+		Watermarks:    watermarks,
+		Backfill:      true,
+	}
+	p.Run(ctx)
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("noaa.Poller should not backfill without watermarks, got %d requests", n)
+	}
+	if w, _ := watermarks.Watermark("home/alerts"); w.IsZero() {
+		t.Error("noaa.Poller should set the watermark of the alerts polled")
+	}
+}
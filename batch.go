@@ -0,0 +1,111 @@
+package noaa
+
+import (
+	"context"
+)
+
+// DefaultBatchConcurrency is the number of locations fetched at once by the
+// batch functions when a concurrency of 0 is given.
+const DefaultBatchConcurrency = 4
+
+// BatchResult is the result of a batch function for one location. Err is set
+// if the fetch failed, or is the context error if the location was not
+// fetched before the context was done.
+type BatchResult[T any] struct {
+	Location Location
+	Value    T
+	Err      error
+}
+
+// ForecastBatch fetches the forecasts of the locations, concurrency at a time,
+// and publishes a ForecastUpdated event for each. It returns when all
+// locations are fetched or the context is done, whichever is first, with a
+// result per location in the same order as locations. Locations which did not
+// complete in time have the context error, so callers can use the partial
+// results instead of waiting for slow requests. Their requests are cancelled
+// with the context.
+func ForecastBatch(ctx context.Context, locations []Location, concurrency int) []BatchResult[*ForecastResponse] {
+	return batch(ctx, locations, concurrency, func(loc Location) (*ForecastResponse, error) {
+		forecast, err := fetchForecast(ctx, loc.Lat, loc.Lon, loc.Units)
+		if err != nil {
+			return nil, err
+		}
+		DefaultBus.Publish(ForecastUpdated{Location: loc, Forecast: forecast})
+		return forecast, nil
+	})
+}
+
+// ObservationBatch fetches the latest observation of the locations like
+// LatestObservationWithFallback, trying up to 3 stations per location. Results
+// are returned, and requests cancelled, like ForecastBatch.
+func ObservationBatch(ctx context.Context, locations []Location, concurrency int) []BatchResult[*StationObservation] {
+	return batch(ctx, locations, concurrency, func(loc Location) (*StationObservation, error) {
+		return LatestObservationWithFallbackContext(ctx, loc.Lat, loc.Lon, 3)
+	})
+}
+
+// PreloadPoints looks up the points of the locations so that later calls for
+// those locations do not need to. Results are returned like ForecastBatch.
+func PreloadPoints(ctx context.Context, locations []Location, concurrency int) []BatchResult[*PointsResponse] {
+	return batch(ctx, locations, concurrency, func(loc Location) (*PointsResponse, error) {
+		return PointsContext(ctx, loc.Lat, loc.Lon)
+	})
+}
+
+// batch calls fetch for each location with up to concurrency calls at once
+// until the context is done. Calls in progress when the context is done are
+// left to finish in the background, or to be cancelled if fetch uses the
+// context, and their results are discarded.
+func batch[T any](ctx context.Context, locations []Location, concurrency int, fetch func(Location) (T, error)) []BatchResult[T] {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	type done struct {
+		i      int
+		result BatchResult[T]
+	}
+	results := make([]BatchResult[T], len(locations))
+	completed := make([]bool, len(locations))
+	finished := make(chan done, len(locations)) // buffered so abandoned calls do not block
+	slots := make(chan struct{}, concurrency)
+
+	pending := 0
+	start := func(i int) {
+		pending++
+		go func() {
+			defer func() { <-slots }()
+			value, err := fetch(locations[i])
+			finished <- done{i, BatchResult[T]{Location: locations[i], Value: value, Err: err}}
+		}()
+	}
+	incomplete := func() []BatchResult[T] {
+		for i, loc := range locations {
+			if !completed[i] {
+				results[i] = BatchResult[T]{Location: loc, Err: ctx.Err()}
+			}
+		}
+		return results
+	}
+	next := 0
+	for next < len(locations) || pending > 0 {
+		if ctx.Err() != nil {
+			return incomplete()
+		}
+		var acquire chan struct{}
+		if next < len(locations) {
+			acquire = slots // a nil channel blocks once all locations are started
+		}
+		select {
+		case <-ctx.Done():
+			return incomplete()
+		case acquire <- struct{}{}:
+			start(next)
+			next++
+		case d := <-finished:
+			pending--
+			results[d.i] = d.result
+			completed[d.i] = true
+		}
+	}
+	return results
+}
@@ -0,0 +1,147 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestForecastBatch(t *testing.T) {
+	release := make(chan struct{})
+	cancelled := make(chan struct{})
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/points/"):
+			// each point has its own grid cell, named after the latitude
+			lat := strings.Split(strings.TrimPrefix(r.URL.Path, "/points/"), ",")[0]
+			fmt.Fprintf(w, `{"forecast": "%s/gridpoints/LOT/%s/forecast"}`, api.URL, lat)
+		case r.URL.Path == "/gridpoints/LOT/3/forecast":
+			// a straggler
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				close(cancelled)
+				return
+			}
+			fallthrough
+		default:
+			fmt.Fprint(w, `{"periods": [{"number": 1, "temperature": 59}]}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		close(release)
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	var locations []noaa.Location
+	for i := 1; i <= 5; i++ {
+		locations = append(locations, noaa.Location{Name: fmt.Sprint(i), Lat: fmt.Sprint(i), Lon: "-87"})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	results := noaa.ForecastBatch(ctx, locations, 2)
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("noaa.ForecastBatch() should return at the deadline, took %s", time.Since(start))
+	}
+
+	if len(results) != 5 {
+		t.Fatalf("noaa.ForecastBatch() should return a result per location, got %d", len(results))
+	}
+	for i, r := range results {
+		if r.Location.Name != locations[i].Name {
+			t.Errorf("noaa.ForecastBatch() should return results in order, got %s at %d", r.Location.Name, i)
+		}
+		if i == 2 {
+			if !errors.Is(r.Err, context.DeadlineExceeded) {
+				t.Errorf("noaa.ForecastBatch() should return the context error for stragglers, got %v", r.Err)
+			}
+			continue
+		}
+		if r.Err != nil || r.Value == nil || len(r.Value.Periods) != 1 {
+			t.Errorf("noaa.ForecastBatch() should return completed forecasts, got %+v", r)
+		}
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("noaa.ForecastBatch() should cancel the requests of stragglers")
+	}
+}
+
+func TestObservationBatch(t *testing.T) {
+	release := make(chan struct{})
+	cancelled := make(chan struct{})
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/points/"):
+			// each point has its own station, named after the latitude
+			lat := strings.Split(strings.TrimPrefix(r.URL.Path, "/points/"), ",")[0]
+			fmt.Fprintf(w, `{"observationStations": "%s/gridpoints/LOT/%s/stations"}`, api.URL, lat)
+		case strings.HasSuffix(r.URL.Path, "/stations"):
+			lat := strings.Split(r.URL.Path, "/")[3]
+			fmt.Fprintf(w, `{"observationStations": ["%s/stations/K%s"]}`, api.URL, lat)
+		case r.URL.Path == "/stations/K3/observations/latest":
+			// a straggler
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				close(cancelled)
+				return
+			}
+			fallthrough
+		default:
+			fmt.Fprint(w, observation(time.Now(), "V"))
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		close(release)
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	var locations []noaa.Location
+	for i := 1; i <= 4; i++ {
+		locations = append(locations, noaa.Location{Name: fmt.Sprint(i), Lat: fmt.Sprint(i), Lon: "-86"})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	results := noaa.ObservationBatch(ctx, locations, 2)
+	for i, r := range results {
+		if i == 2 {
+			if !errors.Is(r.Err, context.DeadlineExceeded) {
+				t.Errorf("noaa.ObservationBatch() should return the context error for stragglers, got %v", r.Err)
+			}
+			continue
+		}
+		if r.Err != nil || r.Value == nil || r.Value.Station != api.URL+"/stations/K"+r.Location.Lat {
+			t.Errorf("noaa.ObservationBatch() should return completed observations, got %+v", r)
+		}
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("noaa.ObservationBatch() should cancel the requests of stragglers")
+	}
+}
@@ -0,0 +1,193 @@
+package noaa
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BundleStatus is the status of a component of a WeatherBundle.
+type BundleStatus int
+
+const (
+	// BundleFailed components could not be fetched and were never fetched
+	// before for the point, they are nil or empty.
+	BundleFailed BundleStatus = iota
+	// BundleFresh components were fetched by the call.
+	BundleFresh
+	// BundleStale components could not be fetched and are the last value
+	// fetched for the point instead. Their error is still set.
+	BundleStale
+)
+
+// String returns the name of the status, ex. stale.
+func (s BundleStatus) String() string {
+	switch s {
+	case BundleFresh:
+		return "fresh"
+	case BundleStale:
+		return "stale"
+	}
+	return "failed"
+}
+
+// WeatherBundle is the forecast, hourly forecast, latest observation and
+// active alerts for a point, see GetWeatherBundle. Each component has its own
+// error and status, so partial data can be shown when some components fail.
+// Supplementary holds the series of the registered supplementary data
+// providers by provider name, and SupplementaryErrs the errors of those which
+// failed, see RegisterSupplementaryProvider. They are not cached and not
+// reported by Err.
+type WeatherBundle struct {
+	Forecast    *ForecastResponse
+	Hourly      *HourlyForecastResponse
+	Observation *StationObservation
+	Alerts      []Alert
+
+	ForecastErr    error
+	HourlyErr      error
+	ObservationErr error
+	AlertsErr      error
+
+	ForecastStatus    BundleStatus
+	HourlyStatus      BundleStatus
+	ObservationStatus BundleStatus
+	AlertsStatus      BundleStatus
+
+	Supplementary     map[string][]SupplementarySeries
+	SupplementaryErrs map[string]error
+}
+
+// bundleCache holds the last value of each component fetched by
+// GetWeatherBundle by point, the fallback for components which fail
+var bundleCache = map[string]*WeatherBundle{}
+
+// bundleCacheMu guards bundleCache
+var bundleCacheMu sync.Mutex
+
+// GetWeatherBundle fetches the forecast, hourly forecast, latest observation
+// and active alerts for <lat,lon> at the same time, so the latency is that of
+// the slowest component instead of their sum. The observation is chosen like
+// LatestObservationWithFallback, trying up to 3 stations. A component which
+// fails does not prevent the others from being returned, see Err, and is
+// replaced by the last value fetched for the point, if any, see BundleStatus.
+// Forecasts whose validTimes ended are not used as stale values, see
+// ForecastResponse.IsExpired. Note, stale alerts may have ended since they
+// were fetched.
+func GetWeatherBundle(lat string, lon string) *WeatherBundle {
+	b := fetchWeatherBundle(lat, lon)
+	b.degrade(lat + "," + lon)
+	return b
+}
+
+// fetchWeatherBundle fetches the components of the bundle
+func fetchWeatherBundle(lat string, lon string) *WeatherBundle {
+	b := &WeatherBundle{}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.Supplementary, b.SupplementaryErrs = DefaultSupplementaryProviders.Fetch(context.Background(), lat, lon)
+	}()
+	// the forecasts and observation all need the point, look it up once
+	if _, err := Points(lat, lon); err != nil {
+		b.ForecastErr, b.HourlyErr, b.ObservationErr = err, err, err
+		b.Alerts, b.AlertsErr = Alerts(lat, lon)
+		wg.Wait()
+		return b
+	}
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		b.Forecast, b.ForecastErr = Forecast(lat, lon)
+	}()
+	go func() {
+		defer wg.Done()
+		b.Hourly, b.HourlyErr = HourlyForecast(lat, lon)
+	}()
+	go func() {
+		defer wg.Done()
+		b.Observation, b.ObservationErr = LatestObservationWithFallback(lat, lon, 3)
+	}()
+	go func() {
+		defer wg.Done()
+		b.Alerts, b.AlertsErr = Alerts(lat, lon)
+	}()
+	wg.Wait()
+	return b
+}
+
+// degrade sets the status of each component, caching the components which
+// were fetched and replacing those which failed with the cached ones
+func (b *WeatherBundle) degrade(key string) {
+	bundleCacheMu.Lock()
+	defer bundleCacheMu.Unlock()
+	cached := bundleCache[key]
+	if cached == nil {
+		cached = &WeatherBundle{}
+		bundleCache[key] = cached
+	}
+	switch {
+	case b.ForecastErr == nil:
+		b.ForecastStatus, cached.Forecast = BundleFresh, b.Forecast
+	case cached.Forecast != nil && !cached.Forecast.IsExpired():
+		b.ForecastStatus, b.Forecast = BundleStale, cached.Forecast
+	}
+	switch {
+	case b.HourlyErr == nil:
+		b.HourlyStatus, cached.Hourly = BundleFresh, b.Hourly
+	case cached.Hourly != nil && !cached.Hourly.IsExpired():
+		b.HourlyStatus, b.Hourly = BundleStale, cached.Hourly
+	}
+	switch {
+	case b.ObservationErr == nil:
+		b.ObservationStatus, cached.Observation = BundleFresh, b.Observation
+	case cached.Observation != nil:
+		b.ObservationStatus, b.Observation = BundleStale, cached.Observation
+	}
+	switch {
+	case b.AlertsErr == nil:
+		b.AlertsStatus, cached.Alerts, cached.AlertsStatus = BundleFresh, b.Alerts, BundleFresh
+	case cached.AlertsStatus == BundleFresh: // no alerts is a valid cached value
+		b.AlertsStatus, b.Alerts = BundleStale, cached.Alerts
+	}
+}
+
+// Err returns the error of the first component which failed, in the order
+// forecast, hourly forecast, observation and alerts, or nil if all were
+// fetched. Stale components have an error too.
+func (b *WeatherBundle) Err() error {
+	for _, c := range []struct {
+		name string
+		err  error
+	}{
+		{"forecast", b.ForecastErr},
+		{"hourly forecast", b.HourlyErr},
+		{"observation", b.ObservationErr},
+		{"alerts", b.AlertsErr},
+	} {
+		if c.err != nil {
+			return fmt.Errorf("%s: %w", c.name, c.err)
+		}
+	}
+	return nil
+}
+
+// Problems returns the values of the forecasts skipped by lenient decoding,
+// with their paths prefixed by forecast or hourly, ex. hourly.periods[3], see
+// SetLenientDecoding.
+func (b *WeatherBundle) Problems() []DecodeProblem {
+	var problems []DecodeProblem
+	add := func(prefix string, ps []DecodeProblem) {
+		for _, p := range ps {
+			problems = append(problems, DecodeProblem{Path: prefix + "." + p.Path, Err: p.Err})
+		}
+	}
+	if b.Forecast != nil {
+		add("forecast", b.Forecast.Problems)
+	}
+	if b.Hourly != nil {
+		add("hourly", b.Hourly.Problems)
+	}
+	return problems
+}
@@ -0,0 +1,141 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestGetWeatherBundle(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685": `{
+			"forecast": "{api}/gridpoints/LOT/76,73/forecast",
+			"forecastHourly": "{api}/gridpoints/LOT/76,73/forecast/hourly",
+			"observationStations": "{api}/gridpoints/LOT/76,73/stations"
+		}`,
+		"/gridpoints/LOT/76,73/forecast":     `{"periods": [{"number": 1, "temperature": 59}]}`,
+		"/gridpoints/LOT/76,73/stations":     `{"observationStations": ["{api}/stations/KMDW"]}`,
+		"/stations/KMDW/observations/latest": observation(time.Now(), "V"),
+		"/alerts/active":                     `{"@graph": [{"id": "urn:oid:1", "event": "Heat Advisory"}]}`,
+	})
+
+	b := noaa.GetWeatherBundle("41.837", "-87.685")
+	if b.ForecastErr != nil || b.Forecast == nil || len(b.Forecast.Periods) != 1 {
+		t.Errorf("noaa.GetWeatherBundle() should return the forecast, got %+v, %v", b.Forecast, b.ForecastErr)
+	}
+	if b.ObservationErr != nil || b.Observation == nil || b.Observation.Observation.Temperature.Value != 21 {
+		t.Errorf("noaa.GetWeatherBundle() should return the observation, got %+v, %v", b.Observation, b.ObservationErr)
+	}
+	if b.AlertsErr != nil || len(b.Alerts) != 1 || b.Alerts[0].Event != "Heat Advisory" {
+		t.Errorf("noaa.GetWeatherBundle() should return the alerts, got %+v, %v", b.Alerts, b.AlertsErr)
+	}
+	if b.HourlyErr == nil || b.Hourly != nil {
+		t.Errorf("noaa.GetWeatherBundle() should return the hourly forecast error, got %+v", b.Hourly)
+	}
+	if b.Err() == nil {
+		t.Error("noaa.WeatherBundle.Err() should return the hourly forecast error")
+	}
+	if b.ForecastStatus != noaa.BundleFresh || b.AlertsStatus != noaa.BundleFresh || b.HourlyStatus != noaa.BundleFailed {
+		t.Errorf("noaa.GetWeatherBundle() should return fresh and failed statuses, got %s, %s and %s", b.ForecastStatus, b.AlertsStatus, b.HourlyStatus)
+	}
+
+	b = noaa.GetWeatherBundle("0", "0")
+	if b.ForecastErr == nil || b.HourlyErr == nil || b.ObservationErr == nil {
+		t.Error("noaa.GetWeatherBundle() should return the points error for each forecast and the observation")
+	}
+}
+
+func TestWeatherBundleStale(t *testing.T) {
+	var failing int32
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/40.1,-88.2":
+			fmt.Fprintf(w, `{"forecast": "%[1]s/forecast", "forecastHourly": "%[1]s/hourly", "observationStations": "%[1]s/stations"}`, api.URL)
+		case "/forecast", "/hourly":
+			if atomic.LoadInt32(&failing) == 1 {
+				http.Error(w, "unavailable", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"periods": [{"number": 1, "temperature": 59}]}`)
+		case "/stations":
+			fmt.Fprintf(w, `{"observationStations": ["%s/stations/KCMI"]}`, api.URL)
+		case "/stations/KCMI/observations/latest":
+			fmt.Fprint(w, observation(time.Now(), "V"))
+		default:
+			fmt.Fprint(w, `{"@graph": []}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	if b := noaa.GetWeatherBundle("40.1", "-88.2"); b.Err() != nil {
+		t.Fatal(b.Err())
+	}
+	atomic.StoreInt32(&failing, 1)
+	b := noaa.GetWeatherBundle("40.1", "-88.2")
+	if b.ForecastStatus != noaa.BundleStale || b.HourlyStatus != noaa.BundleStale {
+		t.Errorf("noaa.GetWeatherBundle() should return stale forecasts, got %s and %s", b.ForecastStatus, b.HourlyStatus)
+	}
+	if b.Forecast == nil || b.Forecast.Periods[0].Temperature != 59 || b.ForecastErr == nil {
+		t.Errorf("noaa.GetWeatherBundle() should return the cached forecast with its error, got %+v, %v", b.Forecast, b.ForecastErr)
+	}
+	if b.ObservationStatus != noaa.BundleFresh || b.AlertsStatus != noaa.BundleFresh {
+		t.Errorf("noaa.GetWeatherBundle() should return the other components fresh, got %s and %s", b.ObservationStatus, b.AlertsStatus)
+	}
+}
+
+func TestWeatherBundleExpired(t *testing.T) {
+	var failing int32
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/40.3,-88.4":
+			fmt.Fprintf(w, `{"forecast": "%[1]s/forecast", "forecastHourly": "%[1]s/hourly", "observationStations": "%[1]s/stations"}`, api.URL)
+		case "/forecast", "/hourly":
+			if atomic.LoadInt32(&failing) == 1 {
+				http.Error(w, "unavailable", http.StatusBadRequest)
+				return
+			}
+			validTimes := time.Now().Add(-24*time.Hour).Format(time.RFC3339) + "/P7D"
+			if r.URL.Path == "/hourly" {
+				validTimes = time.Now().Add(-8*24*time.Hour).Format(time.RFC3339) + "/P7D"
+			}
+			fmt.Fprintf(w, `{"validTimes": "%s", "periods": [{"number": 1, "temperature": 59}]}`, validTimes)
+		default:
+			fmt.Fprint(w, `{"@graph": []}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	noaa.GetWeatherBundle("40.3", "-88.4")
+	atomic.StoreInt32(&failing, 1)
+	b := noaa.GetWeatherBundle("40.3", "-88.4")
+	if b.ForecastStatus != noaa.BundleStale || b.Forecast == nil {
+		t.Errorf("noaa.GetWeatherBundle() should return the cached forecast while it is valid, got %s", b.ForecastStatus)
+	}
+	if b.HourlyStatus != noaa.BundleFailed || b.Hourly != nil || b.HourlyErr == nil {
+		t.Errorf("noaa.GetWeatherBundle() should not return an expired hourly forecast, got %s", b.HourlyStatus)
+	}
+}
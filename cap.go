@@ -0,0 +1,301 @@
+package noaa
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCAP is returned by DecodeCAP for documents which are not CAP
+// alerts or Atom feeds of them, or alerts missing required values.
+var ErrInvalidCAP = errors.New("invalid CAP document")
+
+// capAlert holds the values of a CAP 1.2 <alert>
+type capAlert struct {
+	Identifier string    `xml:"identifier"`
+	Sender     string    `xml:"sender"`
+	Sent       string    `xml:"sent"`
+	Status     string    `xml:"status"`
+	MsgType    string    `xml:"msgType"`
+	References string    `xml:"references"`
+	Info       []capInfo `xml:"info"`
+}
+
+// capInfo holds the values of a CAP <info>, also used for the cap: elements
+// of Atom entries
+type capInfo struct {
+	Language     string     `xml:"language"`
+	Event        string     `xml:"event"`
+	ResponseType string     `xml:"responseType"`
+	Urgency      string     `xml:"urgency"`
+	Severity     string     `xml:"severity"`
+	Certainty    string     `xml:"certainty"`
+	EventCodes   []capValue `xml:"eventCode"`
+	Effective    string     `xml:"effective"`
+	Onset        string     `xml:"onset"`
+	Expires      string     `xml:"expires"`
+	SenderName   string     `xml:"senderName"`
+	Headline     string     `xml:"headline"`
+	Description  string     `xml:"description"`
+	Instruction  string     `xml:"instruction"`
+	Parameters   []capValue `xml:"parameter"`
+	Areas        []capArea  `xml:"area"`
+}
+
+// capArea holds the values of a CAP <area>
+type capArea struct {
+	Polygons []string   `xml:"polygon"`
+	Geocodes []capValue `xml:"geocode"`
+}
+
+// capValue holds the valueName and value pairs of CAP elements such as
+// <geocode>. The weather.gov Atom feeds put several pairs in one element.
+type capValue struct {
+	Names  []string `xml:"valueName"`
+	Values []string `xml:"value"`
+}
+
+// capEntry holds the values of an Atom <entry> of CAP alerts, which either
+// contains the alert or summarizes it with cap: elements
+type capEntry struct {
+	ID        string `xml:"id"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Content   struct {
+		Alert *capAlert `xml:"alert"`
+	} `xml:"content"`
+
+	Sent     string     `xml:"sent"`
+	Status   string     `xml:"status"`
+	MsgType  string     `xml:"msgType"`
+	Polygons []string   `xml:"polygon"`
+	Geocodes []capValue `xml:"geocode"`
+	capInfo
+}
+
+// DecodeCAP decodes the alerts of a Common Alerting Protocol 1.2 document, a
+// single <alert> or an Atom <feed> whose entries contain CAP alerts or
+// summarize them with the cap: elements of the weather.gov Atom feeds. Alerts
+// with several <info> blocks, ex. in English and Spanish, use the first one,
+// with the event, headline, description and instruction of the one in the
+// preferred language if any, see SetLanguage and Alert.Language. Polygons are converted to a GeoJSON Geometry, see Alert.Covers.
+func DecodeCAP(r io.Reader) ([]Alert, error) {
+	var doc struct {
+		XMLName xml.Name
+		capAlert
+		Entries []capEntry `xml:"entry"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCAP, err)
+	}
+	switch doc.XMLName.Local {
+	case "alert":
+		a, err := doc.capAlert.alert()
+		if err != nil {
+			return nil, err
+		}
+		return []Alert{a}, nil
+	case "feed":
+		alerts := make([]Alert, 0, len(doc.Entries))
+		for _, e := range doc.Entries {
+			a, err := e.alert()
+			if err != nil {
+				return nil, err
+			}
+			alerts = append(alerts, a)
+		}
+		return alerts, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected <%s> element", ErrInvalidCAP, doc.XMLName.Local)
+}
+
+// alert converts the CAP alert, checking the values CAP requires
+func (c capAlert) alert() (Alert, error) {
+	for _, required := range []struct{ name, value string }{
+		{"identifier", c.Identifier}, {"sender", c.Sender}, {"sent", c.Sent}, {"status", c.Status}, {"msgType", c.MsgType},
+	} {
+		if strings.TrimSpace(required.value) == "" {
+			return Alert{}, fmt.Errorf("%w: alert %s has no %s", ErrInvalidCAP, c.Identifier, required.name)
+		}
+	}
+	a := Alert{
+		Identifier:  strings.TrimSpace(c.Identifier),
+		Sender:      strings.TrimSpace(c.Sender),
+		Sent:        strings.TrimSpace(c.Sent),
+		Status:      strings.TrimSpace(c.Status),
+		MessageType: strings.TrimSpace(c.MsgType),
+	}
+	for _, ref := range strings.Fields(c.References) {
+		parts := strings.Split(ref, ",")
+		if len(parts) != 3 {
+			return Alert{}, fmt.Errorf("%w: alert %s has an invalid reference %q", ErrInvalidCAP, a.Identifier, ref)
+		}
+		a.References = append(a.References, AlertReference{Sender: parts[0], Identifier: parts[1], Sent: parts[2]})
+	}
+	if len(c.Info) > 0 {
+		if err := c.Info[0].apply(&a, nil, nil); err != nil {
+			return Alert{}, err
+		}
+	}
+	if lang := preferredLanguage(); lang != "" && !sameLanguage(a.Language, lang) {
+		for _, info := range c.Info {
+			if sameLanguage(info.Language, lang) {
+				info.translate(&a)
+				break
+			}
+		}
+	}
+	if a.Language == "" {
+		a.Language = "en-US" // the CAP default
+	}
+	return a, nil
+}
+
+// alert converts the Atom entry
+func (e capEntry) alert() (Alert, error) {
+	if e.Content.Alert != nil {
+		return e.Content.Alert.alert()
+	}
+	id := strings.TrimSpace(e.ID)
+	if id == "" {
+		return Alert{}, fmt.Errorf("%w: entry %q has no id", ErrInvalidCAP, e.Title)
+	}
+	a := Alert{ID: id, Identifier: id, Status: e.Status, MessageType: e.MsgType, Sent: e.Sent}
+	if i := strings.LastIndex(id, "/"); i >= 0 && strings.HasPrefix(id[i+1:], "urn:oid:") {
+		a.Identifier = id[i+1:]
+	}
+	if a.Sent == "" {
+		a.Sent = e.Published
+	}
+	if a.Sent == "" {
+		a.Sent = e.Updated
+	}
+	if err := e.capInfo.apply(&a, e.Polygons, e.Geocodes); err != nil {
+		return Alert{}, err
+	}
+	if a.Headline == "" {
+		a.Headline = strings.TrimSpace(e.Title)
+	}
+	if a.Description == "" {
+		a.Description = strings.TrimSpace(e.Summary)
+	}
+	return a, nil
+}
+
+// apply sets the values of the info on the alert, with the polygons and
+// geocodes of Atom entries besides those of the areas
+func (info capInfo) apply(a *Alert, polygons []string, geocodes []capValue) error {
+	a.Language = info.Language
+	a.Event = info.Event
+	a.Response = info.ResponseType
+	a.Urgency = info.Urgency
+	a.Severity = info.Severity
+	a.Certainty = info.Certainty
+	a.Effective = info.Effective
+	a.Onset = info.Onset
+	a.Expires = info.Expires
+	a.SenderName = info.SenderName
+	a.Headline = info.Headline
+	a.Description = info.Description
+	a.Instruction = info.Instruction
+	for _, code := range info.EventCodes {
+		code.each(func(name, value string) {
+			switch name {
+			case "SAME":
+				a.EventCode.SAME = append(a.EventCode.SAME, value)
+			case "NationalWeatherService":
+				a.EventCode.NationalWeatherService = append(a.EventCode.NationalWeatherService, value)
+			}
+		})
+	}
+	for _, p := range info.Parameters {
+		p.each(func(name, value string) {
+			if a.Parameters == nil {
+				a.Parameters = map[string][]string{}
+			}
+			a.Parameters[name] = append(a.Parameters[name], value)
+		})
+	}
+	for _, area := range info.Areas {
+		polygons = append(polygons, area.Polygons...)
+		geocodes = append(geocodes, area.Geocodes...)
+	}
+	for _, g := range geocodes {
+		g.each(func(name, value string) {
+			for _, code := range strings.Fields(value) {
+				switch name {
+				case "SAME", "FIPS6":
+					a.Geocode.SAME = append(a.Geocode.SAME, code)
+				case "UGC":
+					a.Geocode.UGC = append(a.Geocode.UGC, code)
+				}
+			}
+		})
+	}
+	geometry, err := capGeometry(polygons)
+	if err != nil {
+		return fmt.Errorf("%w: alert %s: %v", ErrInvalidCAP, a.Identifier, err)
+	}
+	a.Geometry = geometry
+	return nil
+}
+
+// translate sets the text of the info on the alert, keeping the values of
+// the first info where it has none
+func (info capInfo) translate(a *Alert) {
+	a.Language = info.Language
+	for _, v := range []struct{ from, to *string }{
+		{&info.Event, &a.Event}, {&info.Headline, &a.Headline}, {&info.Description, &a.Description}, {&info.Instruction, &a.Instruction},
+	} {
+		if *v.from != "" {
+			*v.to = *v.from
+		}
+	}
+}
+
+// each calls fn with the trimmed name and value of each pair
+func (v capValue) each(fn func(name, value string)) {
+	for i, name := range v.Names {
+		if i < len(v.Values) {
+			fn(strings.TrimSpace(name), strings.TrimSpace(v.Values[i]))
+		}
+	}
+}
+
+// capGeometry converts CAP polygons, space separated lat,lon pairs, to a
+// GeoJSON Polygon or MultiPolygon, nil without polygons
+func capGeometry(polygons []string) (json.RawMessage, error) {
+	var rings [][][][2]float64
+	for _, polygon := range polygons {
+		if strings.TrimSpace(polygon) == "" {
+			continue
+		}
+		var ring [][2]float64
+		for _, pair := range strings.Fields(polygon) {
+			lat, lon, ok := strings.Cut(pair, ",")
+			y, err1 := strconv.ParseFloat(lat, 64)
+			x, err2 := strconv.ParseFloat(lon, 64)
+			if !ok || err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid polygon point %q", pair)
+			}
+			ring = append(ring, [2]float64{x, y})
+		}
+		if len(ring) < 4 {
+			return nil, fmt.Errorf("polygon has %d points, at least 4 are required", len(ring))
+		}
+		rings = append(rings, [][][2]float64{ring})
+	}
+	switch len(rings) {
+	case 0:
+		return nil, nil
+	case 1:
+		return json.Marshal(map[string]interface{}{"type": "Polygon", "coordinates": rings[0]})
+	}
+	return json.Marshal(map[string]interface{}{"type": "MultiPolygon", "coordinates": rings})
+}
@@ -0,0 +1,103 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestDecodeCAP(t *testing.T) {
+	f, err := os.Open("testdata/cap.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	alerts, err := noaa.DecodeCAP(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("noaa.DecodeCAP() should return 1 alert, got %d", len(alerts))
+	}
+	a := alerts[0]
+	if a.Identifier != "urn:oid:2.49.0.1.840.0.2f1d.002.1" || a.MessageType != "Update" || a.Event != "Tornado Warning" || a.Severity != "Extreme" || a.Response != "Shelter" {
+		t.Errorf("noaa.DecodeCAP() should decode the alert and its first info, got %+v", a)
+	}
+	if len(a.References) != 1 || a.References[0].Identifier != "urn:oid:2.49.0.1.840.0.2f1d.001.1" {
+		t.Errorf("noaa.DecodeCAP() should decode the references, got %+v", a.References)
+	}
+	if len(a.VTEC()) != 1 || a.SAMECode() != "TOR" || a.Geocode.UGC[0] != "ILC031" || a.Geocode.SAME[0] != "017031" {
+		t.Errorf("noaa.DecodeCAP() should decode the parameters and codes, got %v, %+v and %+v", a.Parameters, a.EventCode, a.Geocode)
+	}
+	if covers, err := a.Covers("41.837", "-87.685"); err != nil || !covers {
+		t.Errorf("noaa.DecodeCAP() should convert the polygon, got %v, %v", covers, err)
+	}
+	if a.Language != "en-US" {
+		t.Errorf("noaa.DecodeCAP() should decode the language of the info, got %q", a.Language)
+	}
+
+	for _, doc := range []string{
+		`<alert><identifier>1</identifier></alert>`,
+		`<html></html>`,
+		`not xml`,
+	} {
+		if _, err := noaa.DecodeCAP(strings.NewReader(doc)); !errors.Is(err, noaa.ErrInvalidCAP) {
+			t.Errorf("noaa.DecodeCAP(%q) should return ErrInvalidCAP, got %v", doc, err)
+		}
+	}
+}
+
+func TestDecodeCAPLanguage(t *testing.T) {
+	t.Cleanup(func() { noaa.SetConfig(noaa.GetDefaultConfig()) })
+	noaa.SetLanguage("es")
+	f, err := os.Open("testdata/cap.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	alerts, err := noaa.DecodeCAP(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := alerts[0]
+	if a.Language != "es-US" || a.Event != "Aviso de Tornado" {
+		t.Errorf("noaa.DecodeCAP() should use the Spanish info, got %q in %q", a.Event, a.Language)
+	}
+	if a.Severity != "Extreme" || a.Geocode.UGC[0] != "ILC031" || a.Headline == "" {
+		t.Errorf("noaa.DecodeCAP() should keep the values missing from the Spanish info, got %+v", a)
+	}
+}
+
+func TestDecodeCAPFeed(t *testing.T) {
+	f, err := os.Open("testdata/cap.atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	alerts, err := noaa.DecodeCAP(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("noaa.DecodeCAP() should return an alert for each entry, got %d", len(alerts))
+	}
+	heat := alerts[0]
+	if heat.Identifier != "urn:oid:2.49.0.1.840.0.9a3e.001.1" || heat.Event != "Heat Advisory" || heat.Sent != "2023-07-04T14:50:00-05:00" {
+		t.Errorf("noaa.DecodeCAP() should decode the cap: elements of entries, got %+v", heat)
+	}
+	if strings.Join(heat.Geocode.UGC, ",") != "ILZ014,ILZ013" || strings.Join(heat.Geocode.SAME, ",") != "017031,017043" {
+		t.Errorf("noaa.DecodeCAP() should split the geocodes of entries, got %+v", heat.Geocode)
+	}
+	if heat.Headline == "" || heat.Description != "Heat index values up to 105 expected." || heat.Geometry != nil {
+		t.Errorf("noaa.DecodeCAP() should use the title and summary of entries, got %q, %q", heat.Headline, heat.Description)
+	}
+	if tornado := alerts[1]; tornado.Identifier != "urn:oid:2.49.0.1.840.0.2f1d.001.1" || len(tornado.VTEC()) != 1 {
+		t.Errorf("noaa.DecodeCAP() should decode the alerts contained in entries, got %+v", tornado)
+	}
+}
@@ -0,0 +1,383 @@
+// Package charts renders forecast time series as meteogram style charts in
+// SVG or PNG format, suitable for embedding in chat messages and emails. The
+// SVG output includes titles and axis labels. The PNG output only uses the
+// standard library and therefore does not render any text.
+package charts
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// Default colors of the series created by Meteogram and HourlyMeteogram.
+var (
+	ColorTemperature   = color.RGBA{R: 0xd6, G: 0x27, B: 0x28, A: 0xff}
+	ColorDewpoint      = color.RGBA{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff}
+	ColorPrecipitation = color.RGBA{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff}
+	ColorWind          = color.RGBA{R: 0x7f, G: 0x7f, B: 0x7f, A: 0xff}
+	ColorWindGust      = color.RGBA{R: 0xbc, G: 0xbd, B: 0x22, A: 0xff}
+)
+
+// Point is a single value of a series.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is a named set of points drawn as a line, or as bars when Bars is set.
+type Series struct {
+	Name   string
+	Unit   string
+	Color  color.RGBA
+	Bars   bool
+	Points []Point
+}
+
+// Panel is a chart area with its own vertical axis. Panels share the time axis.
+type Panel struct {
+	Title  string
+	Series []Series
+	Min    *float64 // fixed axis minimum, ex. 0 for percentages; nil scales to the data
+	Max    *float64 // fixed axis maximum, ex. 100 for percentages; nil scales to the data
+}
+
+// Chart is a set of panels stacked vertically, ex. a meteogram.
+type Chart struct {
+	Title  string
+	Width  int // total width in pixels, defaults to 800
+	Height int // total height in pixels, defaults to 200 per panel
+	Panels []Panel
+}
+
+// layout constants (in pixels)
+const (
+	marginLeft   = 50
+	marginRight  = 20
+	marginTop    = 30
+	marginBottom = 30
+	panelGap     = 25
+)
+
+// Meteogram returns a chart of the temperature, chance of precipitation and
+// wind of a gridpoint forecast for the given number of hours from its first
+// value. Series are converted to one value per hour.
+func Meteogram(forecast *noaa.GridpointForecastResponse, hours int) Chart {
+	from := firstTime(forecast.Temperature)
+	temperature := hourly(forecast.Temperature, from, hours)
+	dewpoint := hourly(forecast.Dewpoint, from, hours)
+	pop := hourly(forecast.ProbabilityOfPrecipitation, from, hours)
+	wind := hourly(forecast.WindSpeed, from, hours)
+	gust := hourly(forecast.WindGust, from, hours)
+	return Chart{
+		Title: "Forecast",
+		Panels: []Panel{
+			{Title: "Temperature", Series: []Series{
+				{Name: "Temperature", Unit: noaa.UnitName(forecast.Temperature.Uom), Color: ColorTemperature, Points: temperature},
+				{Name: "Dewpoint", Unit: noaa.UnitName(forecast.Dewpoint.Uom), Color: ColorDewpoint, Points: dewpoint},
+			}},
+			{Title: "Chance of precipitation", Min: float(0), Max: float(100), Series: []Series{
+				{Name: "Precipitation", Unit: "%", Color: ColorPrecipitation, Bars: true, Points: pop},
+			}},
+			{Title: "Wind", Min: float(0), Series: []Series{
+				{Name: "Wind", Unit: noaa.UnitName(forecast.WindSpeed.Uom), Color: ColorWind, Points: wind},
+				{Name: "Gust", Unit: noaa.UnitName(forecast.WindGust.Uom), Color: ColorWindGust, Points: gust},
+			}},
+		},
+	}
+}
+
+// HourlyMeteogram returns a chart of the temperature, chance of precipitation
+// and wind of an hourly forecast. A value of hours <= 0 charts all periods.
+func HourlyMeteogram(forecast *noaa.HourlyForecastResponse, hours int) Chart {
+	var temperature, pop, wind []Point
+	tempUnit, windUnit := "", ""
+	for i, p := range forecast.Periods {
+		if hours > 0 && i >= hours {
+			break
+		}
+		t, err := time.Parse(time.RFC3339, p.StartTime)
+		if err != nil {
+			continue
+		}
+		tempUnit = p.TemperatureUnit
+		temperature = append(temperature, Point{t, p.Temperature})
+		pop = append(pop, Point{t, p.ProbabilityOfPrecipitation.Value})
+		speed, unit, _ := noaa.ParseWindSpeed(p.WindSpeed)
+		windUnit = unit
+		wind = append(wind, Point{t, speed})
+	}
+	return Chart{
+		Title: "Hourly forecast",
+		Panels: []Panel{
+			{Title: "Temperature", Series: []Series{
+				{Name: "Temperature", Unit: tempUnit, Color: ColorTemperature, Points: temperature},
+			}},
+			{Title: "Chance of precipitation", Min: float(0), Max: float(100), Series: []Series{
+				{Name: "Precipitation", Unit: "%", Color: ColorPrecipitation, Bars: true, Points: pop},
+			}},
+			{Title: "Wind", Min: float(0), Series: []Series{
+				{Name: "Wind", Unit: windUnit, Color: ColorWind, Points: wind},
+			}},
+		},
+	}
+}
+
+// SVG writes the chart as an SVG document.
+func (c Chart) SVG(w io.Writer) error {
+	width, height, ph := c.size()
+	start, end := c.timeRange()
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n", width, height, width, height)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	if c.Title != "" {
+		fmt.Fprintf(b, `<text x="%d" y="18" font-size="14" font-weight="bold">%s</text>`+"\n", marginLeft, html.EscapeString(c.Title))
+	}
+	for i, panel := range c.Panels {
+		top := marginTop + i*(ph+panelGap)
+		min, max := panel.valueRange()
+		plotWidth := width - marginLeft - marginRight
+		x := func(t time.Time) float64 { return marginLeft + scaleTime(t, start, end)*float64(plotWidth) }
+		y := func(v float64) float64 { return float64(top+ph) - scale(v, min, max)*float64(ph) }
+
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", marginLeft, top-6, html.EscapeString(panel.title()))
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#ccc"/>`+"\n", marginLeft, top, plotWidth, ph)
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", marginLeft-4, top+10, formatValue(max))
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", marginLeft-4, top+ph, formatValue(min))
+		for _, s := range panel.Series {
+			stroke := fmt.Sprintf("#%02x%02x%02x", s.Color.R, s.Color.G, s.Color.B)
+			if s.Bars {
+				bw := barWidth(s.Points, plotWidth)
+				for _, p := range s.Points {
+					fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n",
+						x(p.Time), y(p.Value), bw, float64(top+ph)-y(p.Value), stroke)
+				}
+				continue
+			}
+			if len(s.Points) == 0 {
+				continue
+			}
+			fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="2" points="`, stroke)
+			for j, p := range s.Points {
+				if j > 0 {
+					b.WriteString(" ")
+				}
+				fmt.Fprintf(b, "%.1f,%.1f", x(p.Time), y(p.Value))
+			}
+			b.WriteString(`"/>` + "\n")
+		}
+	}
+	if !start.IsZero() {
+		bottom := marginTop + len(c.Panels)*(ph+panelGap) - panelGap + 15
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", marginLeft, bottom, start.Format("Mon Jan 2 15:04"))
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", width-marginRight, bottom, end.Format("Mon Jan 2 15:04"))
+	}
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
+
+// PNG writes the chart as a PNG image. Text is not rendered.
+func (c Chart) PNG(w io.Writer) error {
+	return png.Encode(w, c.Image())
+}
+
+// Image draws the chart onto a new image, ex. for further processing before encoding.
+func (c Chart) Image() *image.RGBA {
+	width, height, ph := c.size()
+	start, end := c.timeRange()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	border := color.RGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff}
+	plotWidth := width - marginLeft - marginRight
+	for i, panel := range c.Panels {
+		top := marginTop + i*(ph+panelGap)
+		min, max := panel.valueRange()
+		x := func(t time.Time) int { return marginLeft + int(scaleTime(t, start, end)*float64(plotWidth)) }
+		y := func(v float64) int { return top + ph - int(scale(v, min, max)*float64(ph)) }
+
+		for _, s := range panel.Series {
+			if s.Bars {
+				bw := int(math.Max(1, barWidth(s.Points, plotWidth)))
+				for _, p := range s.Points {
+					r := image.Rect(x(p.Time), y(p.Value), x(p.Time)+bw, top+ph)
+					draw.Draw(img, r, &image.Uniform{C: s.Color}, image.Point{}, draw.Src)
+				}
+				continue
+			}
+			for j := 1; j < len(s.Points); j++ {
+				a, b := s.Points[j-1], s.Points[j]
+				line(img, x(a.Time), y(a.Value), x(b.Time), y(b.Value), s.Color)
+			}
+		}
+		line(img, marginLeft, top, marginLeft+plotWidth, top, border)
+		line(img, marginLeft, top+ph, marginLeft+plotWidth, top+ph, border)
+		line(img, marginLeft, top, marginLeft, top+ph, border)
+		line(img, marginLeft+plotWidth, top, marginLeft+plotWidth, top+ph, border)
+	}
+	return img
+}
+
+// size returns the width and height of the chart and the height of each panel
+func (c Chart) size() (width, height, panelHeight int) {
+	width, height = c.Width, c.Height
+	n := len(c.Panels)
+	if n == 0 {
+		n = 1
+	}
+	if width <= 0 {
+		width = 800
+	}
+	if height <= 0 {
+		height = marginTop + marginBottom + n*200
+	}
+	panelHeight = (height - marginTop - marginBottom - (n-1)*panelGap) / n
+	if panelHeight < 10 {
+		panelHeight = 10
+	}
+	return width, height, panelHeight
+}
+
+// timeRange returns the earliest and latest times of all series
+func (c Chart) timeRange() (start, end time.Time) {
+	for _, panel := range c.Panels {
+		for _, s := range panel.Series {
+			for _, p := range s.Points {
+				if start.IsZero() || p.Time.Before(start) {
+					start = p.Time
+				}
+				if end.IsZero() || p.Time.After(end) {
+					end = p.Time
+				}
+			}
+		}
+	}
+	return start, end
+}
+
+// title returns the panel title including the unit of the first series
+func (p Panel) title() string {
+	if len(p.Series) > 0 && p.Series[0].Unit != "" {
+		return fmt.Sprintf("%s (%s)", p.Title, p.Series[0].Unit)
+	}
+	return p.Title
+}
+
+// valueRange returns the axis range of the panel
+func (p Panel) valueRange() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, s := range p.Series {
+		for _, v := range s.Points {
+			min = math.Min(min, v.Value)
+			max = math.Max(max, v.Value)
+		}
+	}
+	if math.IsInf(min, 1) {
+		min, max = 0, 1
+	}
+	if p.Min != nil {
+		min = *p.Min
+	}
+	if p.Max != nil {
+		max = *p.Max
+	}
+	if max <= min {
+		max = min + 1
+	}
+	return min, max
+}
+
+// hourly converts a gridpoint series into one point per hour starting at from
+func hourly(series noaa.GridpointForecastTimeSeries, from time.Time, hours int) []Point {
+	var points []Point
+	for h := 0; h < hours; h++ {
+		t := from.Add(time.Duration(h) * time.Hour)
+		if v, ok := series.At(t); ok {
+			points = append(points, Point{t, v})
+		}
+	}
+	return points
+}
+
+// firstTime returns the start of the first value of the series
+func firstTime(series noaa.GridpointForecastTimeSeries) time.Time {
+	for _, v := range series.Values {
+		if start, _, err := v.Interval(); err == nil {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// scale returns v as a fraction of the range min..max clamped to 0..1
+func scale(v, min, max float64) float64 {
+	return math.Max(0, math.Min(1, (v-min)/(max-min)))
+}
+
+// scaleTime returns t as a fraction of the range start..end
+func scaleTime(t, start, end time.Time) float64 {
+	if !end.After(start) {
+		return 0
+	}
+	return float64(t.Sub(start)) / float64(end.Sub(start))
+}
+
+// barWidth returns the width of bars so that adjacent bars nearly touch
+func barWidth(points []Point, plotWidth int) float64 {
+	if len(points) < 2 {
+		return 4
+	}
+	return math.Max(1, float64(plotWidth)/float64(len(points))*0.8)
+}
+
+// line draws a line from x0,y0 to x1,y1 using Bresenham's algorithm
+func line(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		img.SetRGBA(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// float returns a pointer to v for the optional Panel limits
+func float(v float64) *float64 {
+	return &v
+}
+
+// formatValue formats an axis label
+func formatValue(v float64) string {
+	return fmt.Sprintf("%.0f", v)
+}
@@ -0,0 +1,46 @@
+package charts_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/charts"
+)
+
+func TestMeteogram(t *testing.T) {
+	forecast := &noaa.GridpointForecastResponse{}
+	forecast.Temperature = noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{
+		{ValidTime: "2023-06-15T18:00:00+00:00/PT2H", Value: 20},
+		{ValidTime: "2023-06-15T20:00:00+00:00/PT4H", Value: 15},
+	}}
+	forecast.ProbabilityOfPrecipitation = noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:percent", Values: []noaa.GridpointForecastTimeSeriesValue{
+		{ValidTime: "2023-06-15T18:00:00+00:00/PT6H", Value: 40},
+	}}
+	chart := charts.Meteogram(forecast, 6)
+	if n := len(chart.Panels[0].Series[0].Points); n != 6 {
+		t.Errorf("expected 6 hourly temperature points, got %d", n)
+	}
+
+	var svg bytes.Buffer
+	if err := chart.SVG(&svg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(svg.String(), "<polyline") || !strings.Contains(svg.String(), "Temperature (degC)") {
+		t.Error("the svg should include the temperature line and title")
+	}
+
+	var buf bytes.Buffer
+	if err := chart.PNG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 800 {
+		t.Errorf("unexpected image width %d", img.Bounds().Dx())
+	}
+}
@@ -0,0 +1,30 @@
+package noaa
+
+import (
+	"context"
+	"net/http"
+)
+
+// httpClientKey is the context key of the HTTP client
+type httpClientKey struct{}
+
+// WithHTTPClient returns a context carrying the HTTP client which requests
+// made with the context use instead of http.DefaultClient, ex. to serve
+// several applications with their own transports from one process:
+//
+//	ctx := noaa.WithHTTPClient(r.Context(), client)
+//	forecast, err := noaa.ForecastContext(ctx, lat, lon, "")
+//
+// A nil client uses http.DefaultClient. The redirect policy of the config
+// still applies.
+func WithHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, httpClientKey{}, client)
+}
+
+// httpClient returns the HTTP client of the context, or http.DefaultClient
+func httpClient(ctx context.Context) *http.Client {
+	if client, _ := ctx.Value(httpClientKey{}).(*http.Client); client != nil {
+		return client
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,315 @@
+// Command noaa-exporter exposes current observations, forecast highs and lows
+// and active alert counts for a set of locations as Prometheus metrics.
+//
+// Usage:
+//
+//	noaa-exporter -user-agent "(myapp.com, me@myapp.com)" \
+//		-location home=41.837,-87.685 -location cabin=46.786,-92.1 \
+//		-listen :9464
+//
+// Metrics are served at /metrics in the Prometheus text exposition format and
+// /healthz reports whether the poller and server are running.
+//
+// The client can be configured with a -config file and the NOAA_*
+// environment variables, see noaa.LoadConfigFile and noaa.LoadConfigFromEnv.
+// Flags take precedence over environment variables, which take precedence
+// over the config file. Units default to si.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// locations collects the repeated -location flag values
+type locations []noaa.Location
+
+func (l *locations) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *locations) Set(s string) error {
+	name, coords, ok := strings.Cut(s, "=")
+	lat, lon, ok2 := strings.Cut(coords, ",")
+	if !ok || !ok2 || name == "" {
+		return fmt.Errorf("expected name=lat,lon")
+	}
+	*l = append(*l, noaa.Location{Name: name, Lat: lat, Lon: lon})
+	return nil
+}
+
+func main() {
+	var locs locations
+	flag.Var(&locs, "location", "location to export as name=lat,lon (repeatable)")
+	listen := flag.String("listen", ":9464", "address to serve metrics on")
+	userAgent := flag.String("user-agent", "", "User-Agent identifying your application to weather.gov")
+	configFile := flag.String("config", "", "JSON, YAML or TOML file configuring the weather.gov client")
+	interval := flag.Duration("interval", 10*time.Minute, "interval between observation and forecast updates")
+	alertInterval := flag.Duration("alert-interval", 2*time.Minute, "interval between alert updates")
+	aligned := flag.Bool("aligned", false, "poll forecasts and observations shortly after weather.gov usually updates them instead of every -interval")
+	fastAlertInterval := flag.Duration("fast-alert-interval", 30*time.Second, "interval between alert updates while an extreme or immediate alert is active, 0 to disable")
+	flag.Parse()
+
+	if len(locs) == 0 {
+		fmt.Fprintln(os.Stderr, "at least one -location is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	noaa.SetUnits("si")
+	if *configFile != "" {
+		if err := noaa.LoadConfigFile(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := noaa.LoadConfigFromEnv(); err != nil {
+		log.Fatal(err)
+	}
+	if *userAgent != "" {
+		noaa.SetUserAgent(*userAgent)
+	}
+
+	metrics := newMetrics()
+	poller := &noaa.Poller{
+		Locations:           locs,
+		ForecastInterval:    *interval,
+		ObservationInterval: *interval,
+		AlertInterval:       *alertInterval,
+		FastAlertInterval:   *fastAlertInterval,
+		OnForecast:          metrics.forecast,
+		OnObservation:       metrics.observation,
+		OnAlert:             metrics.alert,
+		OnError: func(loc noaa.Location, err error) {
+			log.Printf("%s: %v", loc.Name, err)
+			metrics.error(loc)
+		},
+	}
+	if *aligned {
+		poller.ForecastSchedule = &noaa.ForecastSchedule
+		poller.ObservationSchedule = &noaa.ObservationSchedule
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := &http.Server{Handler: mux}
+	serve := noaa.NewService(func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			srv.Shutdown(context.Background())
+		}()
+		if err := srv.Serve(ln); err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	})
+
+	rt := noaa.NewRuntime()
+	rt.Add("poller", poller)
+	rt.Add("http", serve)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		for name, h := range rt.Health() {
+			if h.State != noaa.StateRunning {
+				http.Error(w, fmt.Sprintf("%s is %s", name, h.State), http.StatusServiceUnavailable)
+				return
+			}
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := rt.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("serving metrics for %d locations on %s", len(locs), *listen)
+	<-ctx.Done()
+	if err := rt.Stop(10 * time.Second); err != nil {
+		log.Print(err)
+	}
+}
+
+// sample is a single gauge or counter value with its labels
+type sample struct {
+	labels string
+	value  float64
+}
+
+// metric describes a metric family
+type metric struct {
+	name, help, kind string
+}
+
+var (
+	metricTemperature = metric{"noaa_observation_temperature_celsius", "Latest observed temperature.", "gauge"}
+	metricDewpoint    = metric{"noaa_observation_dewpoint_celsius", "Latest observed dewpoint.", "gauge"}
+	metricHumidity    = metric{"noaa_observation_relative_humidity_percent", "Latest observed relative humidity.", "gauge"}
+	metricWindSpeed   = metric{"noaa_observation_wind_speed_kmh", "Latest observed wind speed.", "gauge"}
+	metricWindGust    = metric{"noaa_observation_wind_gust_kmh", "Latest observed wind gust.", "gauge"}
+	metricPressure    = metric{"noaa_observation_pressure_pascals", "Latest observed barometric pressure.", "gauge"}
+	metricObserved    = metric{"noaa_observation_timestamp_seconds", "Time of the latest observation.", "gauge"}
+	metricHigh        = metric{"noaa_forecast_high_celsius", "Forecast high temperature of the next daytime period.", "gauge"}
+	metricLow         = metric{"noaa_forecast_low_celsius", "Forecast low temperature of the next nighttime period.", "gauge"}
+	metricAlerts      = metric{"noaa_alerts_active", "Number of active alerts by severity.", "gauge"}
+	metricErrors      = metric{"noaa_errors_total", "Number of errors calling weather.gov.", "counter"}
+	metricOrder       = []metric{
+		metricTemperature, metricDewpoint, metricHumidity, metricWindSpeed, metricWindGust,
+		metricPressure, metricObserved, metricHigh, metricLow, metricAlerts, metricErrors,
+	}
+)
+
+// metrics holds the latest values reported by the poller
+type metrics struct {
+	mu     sync.Mutex
+	values map[metric]map[string]float64 // metric -> labels -> value
+	alerts map[string]map[string]noaa.AlertEvent
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		values: map[metric]map[string]float64{},
+		alerts: map[string]map[string]noaa.AlertEvent{},
+	}
+}
+
+// set records a value, NaN values (missing data) remove the sample
+func (m *metrics) set(metric metric, labels string, value float64) {
+	if m.values[metric] == nil {
+		m.values[metric] = map[string]float64{}
+	}
+	if math.IsNaN(value) {
+		delete(m.values[metric], labels)
+		return
+	}
+	m.values[metric][labels] = value
+}
+
+func (m *metrics) observation(loc noaa.Location, o noaa.Observation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	station := noaa.StationID(o.Station)
+	labels := fmt.Sprintf(`location=%q,station=%q`, loc.Name, station)
+	m.set(metricTemperature, labels, value(o.Temperature))
+	m.set(metricDewpoint, labels, value(o.Dewpoint))
+	m.set(metricHumidity, labels, raw(o.RelativeHumidity))
+	m.set(metricWindSpeed, labels, value(o.WindSpeed))
+	m.set(metricWindGust, labels, value(o.WindGust))
+	m.set(metricPressure, labels, raw(o.BarometricPressure))
+	m.set(metricObserved, labels, float64(o.Timestamp.Unix()))
+}
+
+func (m *metrics) forecast(loc noaa.Location, f *noaa.ForecastResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	labels := fmt.Sprintf(`location=%q`, loc.Name)
+	high, low := math.NaN(), math.NaN()
+	for _, p := range f.Periods {
+		c, _ := noaa.LocaleSI.Convert(p.Temperature, p.TemperatureUnit)
+		if p.IsDaytime && math.IsNaN(high) {
+			high = c
+		}
+		if !p.IsDaytime && math.IsNaN(low) {
+			low = c
+		}
+	}
+	m.set(metricHigh, labels, high)
+	m.set(metricLow, labels, low)
+}
+
+func (m *metrics) alert(loc noaa.Location, e noaa.AlertEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.alerts[loc.Name] == nil {
+		m.alerts[loc.Name] = map[string]noaa.AlertEvent{}
+	}
+	m.alerts[loc.Name][e.Key] = e
+}
+
+func (m *metrics) error(loc noaa.Location) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	labels := fmt.Sprintf(`location=%q`, loc.Name)
+	if m.values[metricErrors] == nil {
+		m.values[metricErrors] = map[string]float64{}
+	}
+	m.values[metricErrors][labels]++
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.write(w)
+}
+
+// write writes all metrics in the Prometheus text exposition format
+func (m *metrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// alert counts are computed at scrape time since alerts expire
+	now := time.Now()
+	counts := map[string]float64{}
+	for name, events := range m.alerts {
+		for key, e := range events {
+			if !e.IsActiveAt(now) {
+				delete(events, key)
+				continue
+			}
+			severity := e.Current.Severity
+			if severity == "" {
+				severity = "Unknown"
+			}
+			counts[fmt.Sprintf(`location=%q,severity=%q`, name, severity)]++
+		}
+	}
+	m.values[metricAlerts] = counts
+
+	for _, metric := range metricOrder {
+		samples := m.values[metric]
+		if len(samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		var sorted []sample
+		for labels, v := range samples {
+			sorted = append(sorted, sample{labels, v})
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].labels < sorted[j].labels })
+		for _, s := range sorted {
+			fmt.Fprintf(w, "%s{%s} %g\n", metric.name, s.labels, s.value)
+		}
+	}
+}
+
+// value returns the observation value in SI units or NaN if it is missing
+func value(v noaa.ObservationValue) float64 {
+	if v.IsMissing() {
+		return math.NaN()
+	}
+	c, _ := noaa.LocaleSI.Convert(v.Value, v.UnitCode)
+	return c
+}
+
+// raw returns the observation value in its unit or NaN if it is missing
+func raw(v noaa.ObservationValue) float64 {
+	if v.IsMissing() {
+		return math.NaN()
+	}
+	return v.Value
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestMetrics(t *testing.T) {
+	o, err := noaa.DecodeObservation(strings.NewReader(`{
+		"station": "https://api.weather.gov/stations/KMDW",
+		"timestamp": "2023-07-04T18:53:00+00:00",
+		"temperature": {"unitCode": "wmoUnit:degC", "value": 25, "qualityControl": "V"},
+		"dewpoint": {"unitCode": "wmoUnit:degC", "value": null, "qualityControl": "Z"},
+		"windSpeed": {"unitCode": "wmoUnit:km_h-1", "value": 18, "qualityControl": "V"},
+		"windGust": {"unitCode": "wmoUnit:km_h-1", "value": null, "qualityControl": "Z"},
+		"relativeHumidity": {"unitCode": "wmoUnit:percent", "value": 55, "qualityControl": "V"},
+		"barometricPressure": {"unitCode": "wmoUnit:Pa", "value": null, "qualityControl": "Z"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newMetrics()
+	home := noaa.Location{Name: "home"}
+	m.observation(home, *o)
+	m.forecast(home, &noaa.ForecastResponse{Periods: []noaa.ForecastResponsePeriod{
+		{IsDaytime: true, Temperature: 86, TemperatureUnit: "F"},
+		{IsDaytime: false, Temperature: 68, TemperatureUnit: "F"},
+	}})
+	m.alert(home, noaa.AlertEvent{Key: "KLOT.HT.Y.0003", Status: "NEW", Current: noaa.Alert{
+		Severity: "Moderate", Expires: time.Now().Add(time.Hour).Format(time.RFC3339),
+	}})
+	m.error(home)
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected a text exposition, got %q", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE noaa_observation_temperature_celsius gauge\n",
+		`noaa_observation_temperature_celsius{location="home",station="KMDW"} 25` + "\n",
+		`noaa_observation_wind_speed_kmh{location="home",station="KMDW"} 18` + "\n",
+		`noaa_observation_relative_humidity_percent{location="home",station="KMDW"} 55` + "\n",
+		`noaa_observation_timestamp_seconds{location="home",station="KMDW"} 1.68849678e+09` + "\n",
+		`noaa_forecast_high_celsius{location="home"} 30` + "\n",
+		`noaa_forecast_low_celsius{location="home"} 20` + "\n",
+		`noaa_alerts_active{location="home",severity="Moderate"} 1` + "\n",
+		"# TYPE noaa_errors_total counter\n",
+		`noaa_errors_total{location="home"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected the metrics to contain %q, got\n%s", want, body)
+		}
+	}
+	for _, missing := range []string{"noaa_observation_dewpoint_celsius", "noaa_observation_wind_gust_kmh", "noaa_observation_pressure_pascals"} {
+		if strings.Contains(body, missing) {
+			t.Errorf("expected no %s sample for a null value, got\n%s", missing, body)
+		}
+	}
+
+	// a later observation without the temperature removes the sample
+	o.Temperature.Null = true
+	m.observation(home, *o)
+	var buf bytes.Buffer
+	m.write(&buf)
+	if strings.Contains(buf.String(), "noaa_observation_temperature_celsius{") {
+		t.Errorf("expected the missing temperature to remove the sample, got\n%s", buf.String())
+	}
+}
@@ -0,0 +1,174 @@
+// Command noaa-fixtures records weather.gov responses for a point as test
+// fixtures, so fixtures can be refreshed when weather.gov changes its schemas.
+//
+// Usage:
+//
+//	noaa-fixtures -user-agent "(myapp.com, me@myapp.com)" \
+//		-point 41.837,-87.685 -out testdata/recorded
+//
+// The points, forecast, hourly forecast, gridpoint, stations, latest
+// observation, alerts, office and forecast zone responses are written as
+// indented JSON files named after the endpoint, ex. forecast.json, together
+// with an index.json mapping each request path to its file.
+//
+// Responses are sanitized: the API base URL is replaced by {api}, the
+// placeholder the fake APIs of the tests replace with the URL of their server,
+// and timestamps are frozen by shifting them so the hour of recording becomes
+// the -freeze time. Shifting by whole hours keeps forecast periods and valid
+// times aligned, and keeps the times consistent with each other.
+//
+// The client can be configured with a -config file and the NOAA_*
+// environment variables, see noaa.LoadConfigFile and noaa.LoadConfigFromEnv.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// timestamp matches ISO 8601 timestamps with a time zone, ex.
+// 2023-07-04T15:53:00+00:00, including those of ISO 8601 intervals
+var timestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})`)
+
+// fixture is an endpoint to record
+type fixture struct {
+	name string // file name without extension
+	path string // URL, relative to the base URL or absolute
+}
+
+func main() {
+	point := flag.String("point", "41.837,-87.685", "point to record as lat,lon")
+	out := flag.String("out", "testdata/recorded", "directory to write the fixtures to")
+	userAgent := flag.String("user-agent", "", "User-Agent identifying your application to weather.gov")
+	configFile := flag.String("config", "", "JSON, YAML or TOML file configuring the weather.gov client")
+	freeze := flag.String("freeze", "2023-07-04T15:00:00Z", "time the hour of recording is shifted to (RFC 3339)")
+	flag.Parse()
+
+	lat, lon, ok := strings.Cut(*point, ",")
+	if !ok {
+		fmt.Fprintln(os.Stderr, "-point must be lat,lon")
+		flag.Usage()
+		os.Exit(2)
+	}
+	frozen, err := time.Parse(time.RFC3339, *freeze)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid -freeze time:", err)
+		os.Exit(2)
+	}
+	if *configFile != "" {
+		if err := noaa.LoadConfigFile(*configFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := noaa.LoadConfigFromEnv(); err != nil {
+		log.Fatal(err)
+	}
+	if *userAgent != "" {
+		noaa.SetUserAgent(*userAgent)
+	}
+	shift := frozen.Truncate(time.Hour).Sub(time.Now().Truncate(time.Hour))
+
+	fixtures, err := endpoints(lat, lon)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	base := noaa.GetConfig().BaseURL
+	index := map[string]string{}
+	for _, f := range fixtures {
+		if f.path == "" {
+			continue
+		}
+		body, err := noaa.GetJSON[json.RawMessage](context.Background(), f.path, nil)
+		if err != nil {
+			log.Printf("%s: %v", f.name, err)
+			continue
+		}
+		data, err := sanitize(body, base, shift)
+		if err != nil {
+			log.Fatalf("%s: %v", f.name, err)
+		}
+		file := f.name + ".json"
+		if err := os.WriteFile(filepath.Join(*out, file), data, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		index[requestPath(f.path, base)] = file
+		log.Printf("recorded %s", file)
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*out, "index.json"), append(data, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// endpoints returns the fixtures to record for the point
+func endpoints(lat, lon string) ([]fixture, error) {
+	point, err := noaa.Points(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := []fixture{
+		{"points", fmt.Sprintf("/points/%s,%s", lat, lon)},
+		{"forecast", point.EndpointForecast},
+		{"forecast_hourly", point.EndpointForecastHourly},
+		{"gridpoint", point.EndpointForecastGridData},
+		{"stations", point.EndpointObservationStations},
+		{"alerts", "/alerts/active?" + url.Values{"point": {lat + "," + lon}}.Encode()},
+		{"office", point.Office},
+		{"zone", point.ForecastZone},
+	}
+	stations, err := noaa.Stations(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if len(stations.Stations) > 0 {
+		fixtures = append(fixtures, fixture{"observation", stations.Stations[0] + "/observations/latest"})
+	}
+	return fixtures, nil
+}
+
+// sanitize replaces the base URL with {api}, shifts the timestamps and
+// indents the response
+func sanitize(body []byte, base string, shift time.Duration) ([]byte, error) {
+	body = bytes.ReplaceAll(body, []byte(base), []byte("{api}"))
+	body = timestamp.ReplaceAllFunc(body, func(ts []byte) []byte {
+		t, err := time.Parse(time.RFC3339Nano, string(ts))
+		if err != nil {
+			return ts
+		}
+		shifted := t.Add(shift)
+		if bytes.HasSuffix(ts, []byte("Z")) {
+			return []byte(shifted.UTC().Format(time.RFC3339))
+		}
+		return []byte(shifted.Format("2006-01-02T15:04:05-07:00"))
+	})
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "    "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// requestPath returns the path and query of the fixture URL relative to the
+// base URL
+func requestPath(path, base string) string {
+	return "/" + strings.TrimPrefix(strings.TrimPrefix(path, base), "/")
+}
@@ -0,0 +1,249 @@
+package noaa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Severity is the CAP severity of an alert. Severities are ordered, so
+// alerts can be filtered with ex. severity >= SeveritySevere.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityMinor
+	SeverityModerate
+	SeveritySevere
+	SeverityExtreme
+)
+
+var severityNames = []string{"Unknown", "Minor", "Moderate", "Severe", "Extreme"}
+
+// ParseSeverity parses an alert severity, ex. Severe.
+func ParseSeverity(s string) (Severity, error) {
+	i, err := parseCode("severity", severityNames, s)
+	return Severity(i), err
+}
+
+func (s Severity) String() string { return codeName(severityNames, int(s)) }
+
+// Urgency is the CAP urgency of an alert. Urgencies are ordered from Past to
+// Immediate.
+type Urgency int
+
+const (
+	UrgencyUnknown Urgency = iota
+	UrgencyPast
+	UrgencyFuture
+	UrgencyExpected
+	UrgencyImmediate
+)
+
+var urgencyNames = []string{"Unknown", "Past", "Future", "Expected", "Immediate"}
+
+// ParseUrgency parses an alert urgency, ex. Immediate.
+func ParseUrgency(s string) (Urgency, error) {
+	i, err := parseCode("urgency", urgencyNames, s)
+	return Urgency(i), err
+}
+
+func (u Urgency) String() string { return codeName(urgencyNames, int(u)) }
+
+// Certainty is the CAP certainty of an alert. Certainties are ordered from
+// Unlikely to Observed.
+type Certainty int
+
+const (
+	CertaintyUnknown Certainty = iota
+	CertaintyUnlikely
+	CertaintyPossible
+	CertaintyLikely
+	CertaintyObserved
+)
+
+var certaintyNames = []string{"Unknown", "Unlikely", "Possible", "Likely", "Observed"}
+
+// ParseCertainty parses an alert certainty, ex. Observed.
+func ParseCertainty(s string) (Certainty, error) {
+	i, err := parseCode("certainty", certaintyNames, s)
+	return Certainty(i), err
+}
+
+func (c Certainty) String() string { return codeName(certaintyNames, int(c)) }
+
+// AlertStatus is the CAP status of an alert. Only Actual alerts should be
+// acted on.
+type AlertStatus int
+
+const (
+	AlertStatusUnknown AlertStatus = iota
+	AlertStatusActual
+	AlertStatusExercise
+	AlertStatusSystem
+	AlertStatusTest
+	AlertStatusDraft
+)
+
+var alertStatusNames = []string{"Unknown", "Actual", "Exercise", "System", "Test", "Draft"}
+
+// ParseAlertStatus parses an alert status, ex. Actual.
+func ParseAlertStatus(s string) (AlertStatus, error) {
+	i, err := parseCode("alert status", alertStatusNames, s)
+	return AlertStatus(i), err
+}
+
+func (s AlertStatus) String() string { return codeName(alertStatusNames, int(s)) }
+
+// Significance is the VTEC significance of a hazard, see VTEC.Significance.
+type Significance int
+
+const (
+	SignificanceUnknown   Significance = iota
+	SignificanceWarning                // W
+	SignificanceWatch                  // A
+	SignificanceAdvisory               // Y
+	SignificanceStatement              // S
+	SignificanceForecast               // F
+	SignificanceOutlook                // O
+	SignificanceSynopsis               // N
+)
+
+var significanceCodes = []string{"", "W", "A", "Y", "S", "F", "O", "N"}
+
+var significanceNames = []string{"Unknown", "Warning", "Watch", "Advisory", "Statement", "Forecast", "Outlook", "Synopsis"}
+
+// ParseSignificance parses a VTEC significance code, ex. W, or name, ex.
+// Warning.
+func ParseSignificance(s string) (Significance, error) {
+	if i, err := parseCode("significance", significanceCodes[1:], s); err == nil {
+		return Significance(i + 1), nil
+	}
+	i, err := parseCode("significance", significanceNames, s)
+	return Significance(i), err
+}
+
+// String returns the name of the significance, ex. Warning.
+func (s Significance) String() string { return codeName(significanceNames, int(s)) }
+
+// Code returns the VTEC code of the significance, ex. W.
+func (s Significance) Code() string {
+	if s <= SignificanceUnknown || int(s) >= len(significanceCodes) {
+		return ""
+	}
+	return significanceCodes[s]
+}
+
+// WeatherCoverage is the coverage of a gridpoint weather value, see
+// WeatherValueItem.Coverage.
+type WeatherCoverage int
+
+const (
+	WeatherCoverageUnknown WeatherCoverage = iota
+	WeatherCoverageAreas
+	WeatherCoverageBrief
+	WeatherCoverageChance
+	WeatherCoverageDefinite
+	WeatherCoverageFew
+	WeatherCoverageFrequent
+	WeatherCoverageIntermittent
+	WeatherCoverageIsolated
+	WeatherCoverageLikely
+	WeatherCoverageNumerous
+	WeatherCoverageOccasional
+	WeatherCoveragePatchy
+	WeatherCoveragePeriods
+	WeatherCoverageScattered
+	WeatherCoverageSlightChance
+	WeatherCoverageWidespread
+)
+
+var weatherCoverageNames = []string{
+	"unknown", "areas", "brief", "chance", "definite", "few", "frequent", "intermittent", "isolated",
+	"likely", "numerous", "occasional", "patchy", "periods", "scattered", "slight_chance", "widespread",
+}
+
+// ParseWeatherCoverage parses a weather coverage, ex. slight_chance.
+func ParseWeatherCoverage(s string) (WeatherCoverage, error) {
+	i, err := parseCode("weather coverage", weatherCoverageNames, s)
+	return WeatherCoverage(i), err
+}
+
+func (c WeatherCoverage) String() string { return codeName(weatherCoverageNames, int(c)) }
+
+// WeatherIntensity is the intensity of a gridpoint weather value, see
+// WeatherValueItem.Intensity. Intensities are ordered from very light to
+// heavy.
+type WeatherIntensity int
+
+const (
+	WeatherIntensityUnknown WeatherIntensity = iota
+	WeatherIntensityVeryLight
+	WeatherIntensityLight
+	WeatherIntensityModerate
+	WeatherIntensityHeavy
+)
+
+var weatherIntensityNames = []string{"unknown", "very_light", "light", "moderate", "heavy"}
+
+// ParseWeatherIntensity parses a weather intensity, ex. very_light.
+func ParseWeatherIntensity(s string) (WeatherIntensity, error) {
+	i, err := parseCode("weather intensity", weatherIntensityNames, s)
+	return WeatherIntensity(i), err
+}
+
+func (i WeatherIntensity) String() string { return codeName(weatherIntensityNames, int(i)) }
+
+// CloudAmount is the METAR amount of a cloud layer of an observation. Amounts
+// are ordered from clear to overcast, with vertical visibility (an obscured
+// sky) last.
+type CloudAmount int
+
+const (
+	CloudAmountUnknown            CloudAmount = iota
+	CloudAmountClear                          // CLR, no clouds below 12,000 ft detected by an automated station
+	CloudAmountSkyClear                       // SKC
+	CloudAmountFew                            // FEW, 1/8 to 2/8 of the sky
+	CloudAmountScattered                      // SCT, 3/8 to 4/8
+	CloudAmountBroken                         // BKN, 5/8 to 7/8
+	CloudAmountOvercast                       // OVC
+	CloudAmountVerticalVisibility             // VV
+)
+
+var cloudAmountNames = []string{"unknown", "CLR", "SKC", "FEW", "SCT", "BKN", "OVC", "VV"}
+
+// ParseCloudAmount parses a cloud layer amount, ex. BKN.
+func ParseCloudAmount(s string) (CloudAmount, error) {
+	i, err := parseCode("cloud amount", cloudAmountNames[1:], s)
+	if err != nil {
+		return CloudAmountUnknown, err
+	}
+	return CloudAmount(i + 1), nil
+}
+
+func (a CloudAmount) String() string { return codeName(cloudAmountNames, int(a)) }
+
+// IsCeiling reports whether a layer of this amount is a ceiling, i.e. broken,
+// overcast or vertical visibility.
+func (a CloudAmount) IsCeiling() bool {
+	return a >= CloudAmountBroken
+}
+
+// parseCode returns the index of s in names, ignoring case
+func parseCode(kind string, names []string, s string) (int, error) {
+	s = strings.TrimSpace(s)
+	for i, name := range names {
+		if strings.EqualFold(name, s) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q", kind, s)
+}
+
+// codeName returns the name of the code, or the number of unknown codes
+func codeName(names []string, i int) string {
+	if i < 0 || i >= len(names) {
+		return strconv.Itoa(i)
+	}
+	return names[i]
+}
@@ -0,0 +1,54 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestParseCodes(t *testing.T) {
+	if s, err := noaa.ParseSeverity("Severe"); err != nil || s != noaa.SeveritySevere || s.String() != "Severe" || s < noaa.SeverityModerate {
+		t.Errorf("noaa.ParseSeverity() should parse Severe, got %v %v", s, err)
+	}
+	if s, err := noaa.ParseSeverity("Unknown"); err != nil || s != noaa.SeverityUnknown {
+		t.Errorf("noaa.ParseSeverity() should parse Unknown, got %v %v", s, err)
+	}
+	if _, err := noaa.ParseSeverity("Sever"); err == nil {
+		t.Error("noaa.ParseSeverity() should return an error for unknown severities")
+	}
+	if u, err := noaa.ParseUrgency("immediate"); err != nil || u != noaa.UrgencyImmediate {
+		t.Errorf("noaa.ParseUrgency() should ignore case, got %v %v", u, err)
+	}
+	if c, err := noaa.ParseCertainty("Observed"); err != nil || c != noaa.CertaintyObserved {
+		t.Errorf("noaa.ParseCertainty() should parse Observed, got %v %v", c, err)
+	}
+	if s, err := noaa.ParseAlertStatus("Test"); err != nil || s != noaa.AlertStatusTest {
+		t.Errorf("noaa.ParseAlertStatus() should parse Test, got %v %v", s, err)
+	}
+
+	for _, code := range []string{"W", "Warning"} {
+		if s, err := noaa.ParseSignificance(code); err != nil || s != noaa.SignificanceWarning || s.Code() != "W" || s.String() != "Warning" {
+			t.Errorf("noaa.ParseSignificance(%q) should parse a warning, got %v %v", code, s, err)
+		}
+	}
+
+	if c, err := noaa.ParseWeatherCoverage("slight_chance"); err != nil || c != noaa.WeatherCoverageSlightChance || c.String() != "slight_chance" {
+		t.Errorf("noaa.ParseWeatherCoverage() should parse slight_chance, got %v %v", c, err)
+	}
+	if i, err := noaa.ParseWeatherIntensity("very_light"); err != nil || i != noaa.WeatherIntensityVeryLight || i >= noaa.WeatherIntensityLight {
+		t.Errorf("noaa.ParseWeatherIntensity() should parse very_light, got %v %v", i, err)
+	}
+
+	if a, err := noaa.ParseCloudAmount("BKN"); err != nil || a != noaa.CloudAmountBroken || !a.IsCeiling() || a.String() != "BKN" {
+		t.Errorf("noaa.ParseCloudAmount() should parse BKN as a ceiling, got %v %v", a, err)
+	}
+	if a, _ := noaa.ParseCloudAmount("SCT"); a.IsCeiling() {
+		t.Error("CloudAmount.IsCeiling() should be false for scattered layers")
+	}
+	if _, err := noaa.ParseCloudAmount(""); err == nil {
+		t.Error("noaa.ParseCloudAmount() should return an error for an empty amount")
+	}
+}
@@ -0,0 +1,119 @@
+package noaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ForecastComparison holds the forecasts of several points aligned by time,
+// see CompareForecasts. Periods are the rows and points the columns.
+type ForecastComparison struct {
+	Points  []Coordinates
+	Errors  []error // by point, nil if the forecast of the point was fetched
+	Periods []ComparedPeriod
+}
+
+// ComparedPeriod is a row of a ForecastComparison: the forecast period of
+// each point in effect at Start. A period is nil when the forecast of the
+// point failed or does not cover Start.
+type ComparedPeriod struct {
+	Start   time.Time
+	Name    string // name of the period of the first point with one, ex. Tonight
+	Periods []*ForecastResponsePeriod
+}
+
+// CompareForecasts fetches the forecasts of the points and aligns their
+// periods by time, ex. to find which of several sites gets the most rain
+// tonight. Each distinct period start time becomes a row, so points in
+// different time zones or with forecasts issued at different times get a row
+// for each of their period boundaries. The forecasts are fetched like
+// ForecastBatch. An error is only returned if no forecast could be fetched,
+// see Errors for the error of each point.
+func CompareForecasts(points []Coordinates) (*ForecastComparison, error) {
+	locations := make([]Location, len(points))
+	for i, p := range points {
+		lat, lon := p.Strings()
+		locations[i] = Location{Name: lat + "," + lon, Lat: lat, Lon: lon}
+	}
+	results := ForecastBatch(context.Background(), locations, 0)
+
+	c := &ForecastComparison{Points: points, Errors: make([]error, len(points))}
+	type span struct{ start, end time.Time }
+	spans := make([][]span, len(points))
+	starts := map[time.Time]bool{}
+	var lastErr error
+	for i, r := range results {
+		if r.Err != nil {
+			c.Errors[i], lastErr = r.Err, r.Err
+			continue
+		}
+		for _, p := range r.Value.Periods {
+			start, end, err := p.Interval()
+			if err != nil {
+				continue
+			}
+			spans[i] = append(spans[i], span{start, end})
+			starts[start.UTC()] = true
+		}
+	}
+	if len(points) > 0 && len(starts) == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("no forecast periods")
+		}
+		return c, fmt.Errorf("failed to compare forecasts: %w", lastErr)
+	}
+
+	for start := range starts {
+		c.Periods = append(c.Periods, ComparedPeriod{Start: start})
+	}
+	sort.Slice(c.Periods, func(i, j int) bool { return c.Periods[i].Start.Before(c.Periods[j].Start) })
+	for row := range c.Periods {
+		cp := &c.Periods[row]
+		cp.Periods = make([]*ForecastResponsePeriod, len(points))
+		for i, r := range results {
+			for j, s := range spans[i] {
+				if !cp.Start.Before(s.start) && cp.Start.Before(s.end) {
+					cp.Periods[i] = &r.Value.Periods[j]
+					if cp.Name == "" {
+						cp.Name = r.Value.Periods[j].Name
+					}
+					break
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+// Warmest returns the index of the point with the highest temperature, or -1
+// if no point has a period.
+func (cp ComparedPeriod) Warmest() int {
+	return cp.best(func(p *ForecastResponsePeriod) float64 { return p.Temperature })
+}
+
+// Coldest returns the index of the point with the lowest temperature, or -1
+// if no point has a period.
+func (cp ComparedPeriod) Coldest() int {
+	return cp.best(func(p *ForecastResponsePeriod) float64 { return -p.Temperature })
+}
+
+// Wettest returns the index of the point with the highest probability of
+// precipitation, or -1 if no point has a period.
+func (cp ComparedPeriod) Wettest() int {
+	return cp.best(func(p *ForecastResponsePeriod) float64 { return p.ProbabilityOfPrecipitation.Value })
+}
+
+// best returns the index of the point with the highest score, the first on
+// ties
+func (cp ComparedPeriod) best(score func(*ForecastResponsePeriod) float64) int {
+	best := -1
+	for i, p := range cp.Periods {
+		if p != nil && (best < 0 || score(p) > score(cp.Periods[best])) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,52 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestCompareForecasts(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.8000,-87.6000": `{"forecast": "{api}/gridpoints/LOT/76,73/forecast"}`,
+		"/points/41.5000,-88.1000": `{"forecast": "{api}/gridpoints/LOT/60,60/forecast"}`,
+		"/gridpoints/LOT/76,73/forecast": `{"periods": [
+			{"number": 1, "name": "Tonight", "startTime": "2023-07-04T18:00:00-05:00", "endTime": "2023-07-05T06:00:00-05:00", "temperature": 60, "probabilityOfPrecipitation": {"value": 20}},
+			{"number": 2, "name": "Wednesday", "startTime": "2023-07-05T06:00:00-05:00", "endTime": "2023-07-05T18:00:00-05:00", "temperature": 85, "probabilityOfPrecipitation": {"value": 10}}
+		]}`,
+		"/gridpoints/LOT/60,60/forecast": `{"periods": [
+			{"number": 1, "name": "This Afternoon", "startTime": "2023-07-04T15:00:00-05:00", "endTime": "2023-07-04T18:00:00-05:00", "temperature": 88},
+			{"number": 2, "name": "Tonight", "startTime": "2023-07-04T18:00:00-05:00", "endTime": "2023-07-05T06:00:00-05:00", "temperature": 65, "probabilityOfPrecipitation": {"value": 70}},
+			{"number": 3, "name": "Wednesday", "startTime": "2023-07-05T06:00:00-05:00", "endTime": "2023-07-05T18:00:00-05:00", "temperature": 80, "probabilityOfPrecipitation": {"value": 40}}
+		]}`,
+	})
+
+	points := []noaa.Coordinates{{Lat: 41.8, Lon: -87.6}, {Lat: 41.5, Lon: -88.1}, {Lat: 0, Lon: 0}}
+	c, err := noaa.CompareForecasts(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Errors[0] != nil || c.Errors[1] != nil || c.Errors[2] == nil {
+		t.Errorf("noaa.CompareForecasts() should return the error of each point, got %v", c.Errors)
+	}
+	if len(c.Periods) != 3 {
+		t.Fatalf("noaa.CompareForecasts() should return a row per period start, got %d", len(c.Periods))
+	}
+	afternoon, tonight, wednesday := c.Periods[0], c.Periods[1], c.Periods[2]
+	if afternoon.Name != "This Afternoon" || afternoon.Periods[0] != nil || afternoon.Periods[1].Temperature != 88 {
+		t.Errorf("noaa.CompareForecasts() should leave points without a period empty, got %+v", afternoon)
+	}
+	if tonight.Name != "Tonight" || tonight.Periods[2] != nil || tonight.Wettest() != 1 || tonight.Coldest() != 0 {
+		t.Errorf("noaa.CompareForecasts() should align the periods, got %+v", tonight)
+	}
+	if wednesday.Warmest() != 0 || wednesday.Start.Hour() != 11 {
+		t.Errorf("noaa.CompareForecasts() should align the periods by time, got %+v", wednesday)
+	}
+
+	if _, err := noaa.CompareForecasts(points[2:]); err == nil {
+		t.Error("noaa.CompareForecasts() should return an error when no forecast is fetched")
+	}
+}
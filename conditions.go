@@ -0,0 +1,194 @@
+package noaa
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentConditions is a snapshot of the weather at a point: the latest
+// observation, with the current hour of the hourly forecast filling in the
+// values the observation lacks, the active alerts and the sunrise and sunset
+// of the day, see GetCurrentConditions. Values are in the configured units,
+// see SetUnits, and are NaN when neither the observation nor the forecast has
+// them.
+type CurrentConditions struct {
+	Time     time.Time // time of the snapshot, in the timezone of the point
+	Location Coordinates
+	Units    string // us or si
+
+	Station    string    // ID of the observation station, blank without an observation
+	ObservedAt time.Time // time of the observation, zero without an observation
+	Summary    string    // short forecast of the current hour, ex. Mostly Sunny
+
+	Temperature         float64 // °F or °C
+	FeelsLike           float64 // observed heat index when hot, wind chill when cold and windy, else the temperature
+	Dewpoint            float64 // °F or °C
+	RelativeHumidity    float64 // %
+	WindSpeed           float64 // mph or km/h
+	WindGust            float64 // mph or km/h
+	WindDirection       float64 // degrees the wind blows from
+	Pressure            float64 // sea level pressure, inHg or hPa
+	Visibility          float64 // mi or km
+	PrecipitationChance float64 // %, of the current hour
+
+	TemperatureUnit string // °F or °C
+	SpeedUnit       string // mph or km/h
+	PressureUnit    string // inHg or hPa
+	DistanceUnit    string // mi or km
+
+	Sunrise   time.Time // zero if the sun does not rise on the day
+	Sunset    time.Time // zero if the sun does not set on the day
+	IsDaytime bool
+
+	Alerts []Alert
+
+	Observation *Observation                  // nil if no observation could be fetched
+	Period      *ForecastResponsePeriodHourly // nil if no hourly forecast covers the time
+
+	// Err is the error of the first component which failed, see
+	// WeatherBundle.Err. The snapshot is still usable.
+	Err error
+}
+
+// GetCurrentConditions returns the current conditions at <lat,lon>, fetched
+// like GetWeatherBundle. An error is returned only when neither an
+// observation nor an hourly forecast could be fetched; failures of the other
+// components are reported by CurrentConditions.Err.
+func GetCurrentConditions(lat string, lon string) (*CurrentConditions, error) {
+	c, err := ParseCoordinates(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	b := GetWeatherBundle(lat, lon)
+	if b.Observation == nil && b.Hourly == nil {
+		if err := b.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("no observation or hourly forecast")
+	}
+	loc := time.UTC
+	if point, err := Points(lat, lon); err == nil {
+		if l, err := time.LoadLocation(point.Timezone); err == nil {
+			loc = l
+		}
+	}
+	conditions := b.CurrentConditions(c, time.Now().In(loc))
+	conditions.Err = b.Err()
+	return conditions, nil
+}
+
+// CurrentConditions returns the snapshot of the bundle at t for the point
+// the bundle was fetched for, see GetCurrentConditions.
+func (b *WeatherBundle) CurrentConditions(c Coordinates, t time.Time) *CurrentConditions {
+	l := Locale{Units: currentConfig().Units}
+	units := "us"
+	if l.isSI() {
+		units = "si"
+	}
+	nan := math.NaN()
+	cc := &CurrentConditions{
+		Time: t, Location: c, Units: units, Alerts: b.Alerts,
+		Temperature: nan, Dewpoint: nan, RelativeHumidity: nan,
+		WindSpeed: nan, WindGust: nan, WindDirection: nan, Pressure: nan, Visibility: nan,
+		PrecipitationChance: nan,
+	}
+	_, cc.TemperatureUnit = l.Convert(0, "wmoUnit:degC")
+	_, cc.SpeedUnit = l.Convert(0, "wmoUnit:km_h-1")
+	_, cc.PressureUnit = l.Convert(0, "wmoUnit:Pa")
+	_, cc.DistanceUnit = l.Convert(0, "wmoUnit:km")
+	cc.Sunrise, cc.Sunset = SunTimes(c, t)
+	switch {
+	case !cc.Sunrise.IsZero():
+		cc.IsDaytime = !t.Before(cc.Sunrise) && t.Before(cc.Sunset)
+	default: // polar day or night, the sun is up in summer
+		cc.IsDaytime = (c.Lat > 0) == (t.Month() >= time.April && t.Month() <= time.September)
+	}
+
+	if b.Observation != nil {
+		o := b.Observation.Observation
+		cc.Observation = &o
+		cc.Station = StationID(b.Observation.Station)
+		cc.ObservedAt = o.Timestamp
+		observed := func(dst *float64, v ObservationValue) {
+			if !v.IsMissing() && v.PassedQualityControl() {
+				*dst, _ = l.Convert(v.Value, v.UnitCode)
+			}
+		}
+		observed(&cc.Temperature, o.Temperature)
+		observed(&cc.Dewpoint, o.Dewpoint)
+		observed(&cc.RelativeHumidity, o.RelativeHumidity)
+		observed(&cc.WindSpeed, o.WindSpeed)
+		observed(&cc.WindGust, o.WindGust)
+		observed(&cc.WindDirection, o.WindDirection)
+		observed(&cc.Pressure, o.SeaLevelPressure)
+		observed(&cc.Visibility, o.Visibility)
+	}
+
+	if b.Hourly != nil {
+		if p := b.Hourly.At(t); p != nil {
+			cc.Period = p
+			cc.Summary = p.Summary
+			forecast := func(dst *float64, v float64, unitCode string) {
+				if math.IsNaN(*dst) {
+					*dst, _ = l.Convert(v, unitCode)
+				}
+			}
+			forecast(&cc.Temperature, p.Temperature, p.TemperatureUnit)
+			if p.Dewpoint.UnitCode != "" {
+				forecast(&cc.Dewpoint, p.Dewpoint.Value, p.Dewpoint.UnitCode)
+			}
+			if p.RelativeHumidity.UnitCode != "" {
+				forecast(&cc.RelativeHumidity, p.RelativeHumidity.Value, p.RelativeHumidity.UnitCode)
+			}
+			if speed, unit, ok := periodWindSpeed(p.WindSpeed); ok {
+				forecast(&cc.WindSpeed, speed, unit)
+			}
+			for i, point := range englishCompassPoints {
+				if strings.EqualFold(point, p.WindDirection) && math.IsNaN(cc.WindDirection) {
+					cc.WindDirection = float64(i) * 22.5
+				}
+			}
+			if p.ProbabilityOfPrecipitation.UnitCode != "" {
+				cc.PrecipitationChance = p.ProbabilityOfPrecipitation.Value
+			}
+		}
+	}
+	cc.FeelsLike = cc.Temperature
+	if cc.Observation != nil {
+		// the heat index and wind chill apply from the same thresholds as in
+		// GridpointForecastResponse.FeelsLike
+		temperatureUnit, speedUnit := "degF", "mph"
+		if l.isSI() {
+			temperatureUnit, speedUnit = "degC", "km_h-1"
+		}
+		fahrenheit, _ := LocaleUS.Convert(cc.Temperature, temperatureUnit)
+		windy := math.IsNaN(cc.WindSpeed) || toKilometersPerHour(cc.WindSpeed, speedUnit) >= windChillMinSpeed
+		switch o := cc.Observation; {
+		case fahrenheit >= heatIndexThreshold && !o.HeatIndex.IsMissing() && o.HeatIndex.PassedQualityControl():
+			cc.FeelsLike, _ = l.Convert(o.HeatIndex.Value, o.HeatIndex.UnitCode)
+		case fahrenheit <= windChillThreshold && windy && !o.WindChill.IsMissing() && o.WindChill.PassedQualityControl():
+			cc.FeelsLike, _ = l.Convert(o.WindChill.Value, o.WindChill.UnitCode)
+		}
+	}
+	return cc
+}
+
+// periodWindSpeed returns the highest speed of the wind speed of a forecast
+// period, ex. 15 mph for "10 to 15 mph"
+func periodWindSpeed(speed string) (float64, string, bool) {
+	unit := "mph"
+	if strings.HasSuffix(speed, "km/h") {
+		unit = "km_h-1"
+	}
+	var highest float64
+	numbers := windSpeedPattern.FindAllString(speed, -1)
+	for _, n := range numbers {
+		if v, err := strconv.ParseFloat(n, 64); err == nil && v > highest {
+			highest = v
+		}
+	}
+	return highest, unit, len(numbers) > 0
+}
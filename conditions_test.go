@@ -0,0 +1,61 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestCurrentConditions(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685": `{
+			"timeZone": "America/Chicago",
+			"forecastHourly": "{api}/gridpoints/LOT/76,73/forecast/hourly",
+			"observationStations": "{api}/gridpoints/LOT/76,73/stations"
+		}`,
+		"/gridpoints/LOT/76,73/stations": `{"observationStations": ["{api}/stations/KMDW"]}`,
+		"/stations/KMDW/observations/latest": `{"timestamp": "` + time.Now().UTC().Format(time.RFC3339) + `",
+			"temperature": {"value": 30, "unitCode": "wmoUnit:degC", "qualityControl": "V"},
+			"heatIndex": {"value": 33, "unitCode": "wmoUnit:degC", "qualityControl": "V"},
+			"windSpeed": {"value": 18, "unitCode": "wmoUnit:km_h-1", "qualityControl": "X"}}`,
+		"/alerts/active": `{"@graph": [{"id": "urn:oid:1", "event": "Heat Advisory"}]}`,
+	})
+	noaa.SetUnits("si")
+
+	// the hourly forecast is missing, the observation alone is used
+	c, err := noaa.GetCurrentConditions("41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Station != "KMDW" || c.Temperature != 30 || c.FeelsLike != 33 || c.TemperatureUnit != "°C" || c.Units != "si" {
+		t.Errorf("noaa.GetCurrentConditions() should return the observed conditions, got %+v", c)
+	}
+	if !math.IsNaN(c.WindSpeed) || c.Period != nil || c.Err == nil {
+		t.Errorf("noaa.GetCurrentConditions() should leave unknown values NaN and report the hourly error, got %+v", c)
+	}
+	if len(c.Alerts) != 1 || c.Time.Location().String() != "America/Chicago" || c.Sunrise.IsZero() {
+		t.Errorf("noaa.GetCurrentConditions() should return the alerts and sun times, got %+v", c)
+	}
+
+	// the current hour fills in the values missing from the observation
+	noon := time.Date(2023, 7, 4, 17, 0, 0, 0, time.UTC)
+	b := &noaa.WeatherBundle{Hourly: &noaa.HourlyForecastResponse{}}
+	var p noaa.ForecastResponsePeriodHourly
+	p.StartTime, p.EndTime = "2023-07-04T12:00:00-05:00", "2023-07-04T13:00:00-05:00"
+	p.Temperature, p.TemperatureUnit, p.Summary = 86, "F", "Sunny"
+	p.WindSpeed, p.WindDirection = "10 to 15 mph", "SW"
+	p.ProbabilityOfPrecipitation = noaa.ForecastValue{Value: 20, UnitCode: "wmoUnit:percent"}
+	b.Hourly.Periods = append(b.Hourly.Periods, p)
+	c = b.CurrentConditions(noaa.Coordinates{Lat: 41.837, Lon: -87.685}, noon)
+	if c.Summary != "Sunny" || c.Temperature != 30 || c.FeelsLike != 30 || math.Round(c.WindSpeed) != 24 || c.WindDirection != 225 || c.PrecipitationChance != 20 {
+		t.Errorf("noaa.WeatherBundle.CurrentConditions() should use the current hour, got %+v", c)
+	}
+	if !c.IsDaytime {
+		t.Error("noaa.WeatherBundle.CurrentConditions() should report daytime at noon")
+	}
+}
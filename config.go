@@ -1,9 +1,54 @@
 package noaa
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
-// Config instance for the API calls executed by the NOAA client.
-var config = GetDefaultConfig()
+// Config instance for the API calls executed by the NOAA client. The config is
+// copied on write: the Set* functions store an updated copy, so each request
+// uses a consistent snapshot and the Get/Set functions can be called from any
+// goroutine. A request that is already in progress keeps using the config it
+// started with.
+var config atomic.Value // Config
+
+// DefaultMaxRedirects is the default number of redirects followed per
+// request, see SetMaxRedirects.
+const DefaultMaxRedirects = 10
+
+// DefaultMaxResponseSize is the default maximum size of response bodies, see
+// SetResponseLimits. The largest weather.gov responses, ex. all active alerts,
+// are a few megabytes.
+const DefaultMaxResponseSize = 32 << 20
+
+// DefaultOutOfCoverageTTL is the default time points out of coverage are
+// remembered, see SetOutOfCoverageTTL.
+const DefaultOutOfCoverageTTL = time.Hour
+
+// configMu serializes updates so concurrent Set* calls do not lose changes
+var configMu sync.Mutex
+
+func init() {
+	config.Store(NewDefaultConfig())
+}
+
+// currentConfig returns the current config
+func currentConfig() Config {
+	return config.Load().(Config)
+}
+
+// updateConfig stores a copy of the current config changed by fn
+func updateConfig(fn func(*Config)) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	c := currentConfig()
+	fn(&c)
+	config.Store(c)
+}
 
 // Config describes important values for the NOAA API and allows for
 // configuration and testing of various options. Note, the User-Agent
@@ -11,20 +56,112 @@ var config = GetDefaultConfig()
 // future weather.gov might change this behavior.
 // See http://www.weather.gov/documentation/services-web-api
 type Config struct {
-	BaseURL   string `json:"baseUrl"` // Do not include a trailing slash
-	UserAgent string `json:"apiKey"`  // ex. (myweatherapp.com, contact@myweatherapp.com)
-	Accept    string `json:"accept"`  // application/geo+json, etc. defaults to ld+json
-	Units     string `json:"units"`   // "us" (the default if blank) or "si" for metric
+	// BaseURL of the API, defaults to API. A trailing slash is removed when
+	// the config is set.
+	BaseURL string `json:"baseUrl"`
+	// UserAgent identifies the application, ex. (myweatherapp.com,
+	// contact@myweatherapp.com), see SetUserAgentInfo. Defaults to APIKey.
+	UserAgent string `json:"apiKey"`
+	// Accept is the requested response format, defaults to APIAccept
+	// (application/ld+json) which the types of this package expect.
+	Accept string `json:"accept"`
+	// Units of forecasts, "us" (the default if blank) or "si" for metric.
+	Units string `json:"units"`
+	// Language of the alerts and products preferred when weather.gov issues
+	// several, ex. es for Spanish, English if blank. See SetLanguage.
+	Language string `json:"language"`
+	// Timeout limits the time of each request including reading the
+	// response, 0 for no limit. Retries are timed separately.
+	Timeout time.Duration `json:"timeout"`
+
+	// Observations older than MaxObservationAge are stale, 0 disables the
+	// check. When StaleObservationError is set, fetching a stale observation
+	// returns an error wrapping ErrStaleObservation.
+	MaxObservationAge     time.Duration `json:"maxObservationAge"`
+	StaleObservationError bool          `json:"staleObservationError"`
+
+	// LenientDecoding skips the values of forecast responses which cannot be
+	// decoded instead of failing, see SetLenientDecoding.
+	LenientDecoding bool `json:"lenientDecoding"`
+
+	// Requests failing with ErrDataUnavailable are retried up to Retries
+	// times, waiting RetryBackoff before the first retry and doubling the
+	// wait after each attempt.
+	Retries      int           `json:"retries"`
+	RetryBackoff time.Duration `json:"retryBackoff"`
+
+	// MaxRedirects is the number of redirects followed per request, 0 for
+	// DefaultMaxRedirects. Redirected requests keep the User-Agent and Accept
+	// headers, even when they lead to another host.
+	MaxRedirects int `json:"maxRedirects"`
+
+	// MaxResponseSize limits the size of response bodies, 0 for
+	// DefaultMaxResponseSize. Larger responses fail with ErrResponseTooLarge.
+	// ReadTimeout limits the time taken to read a response body after its
+	// headers are received, 0 for no limit. Slower reads fail with
+	// ErrReadTimeout.
+	MaxResponseSize int64         `json:"maxResponseSize"`
+	ReadTimeout     time.Duration `json:"readTimeout"`
+
+	// RequireUserAgent makes requests fail with ErrDefaultUserAgent while
+	// UserAgent is the library default.
+	RequireUserAgent bool `json:"requireUserAgent"`
+
+	// OutOfCoverageTTL is how long points unknown to weather.gov keep failing
+	// with ErrOutOfCoverage without a request, 0 to not cache them.
+	OutOfCoverageTTL time.Duration `json:"outOfCoverageTTL"`
+}
+
+// normalize removes the trailing slashes of the base URL, so paths can be
+// joined to it with a slash
+func (c *Config) normalize() {
+	if base := strings.TrimRight(c.BaseURL, "/"); base != "" {
+		c.BaseURL = base
+	}
+}
+
+// Validate returns an error describing the first invalid value of the config.
+func (c Config) Validate() error {
+	switch {
+	case c.BaseURL == "":
+		return errors.New("invalid config: the api requires a base url")
+	case c.UserAgent == "":
+		return errors.New("invalid config: the api requires a user-agent")
+	case c.Accept == "":
+		return errors.New("invalid config: the api requires an accept header")
+	case c.Units != "" && c.Units != "us" && c.Units != "si":
+		return fmt.Errorf(`invalid config: units must be "us" or "si", got %q`, c.Units)
+	case c.Language != "" && !languagePattern.MatchString(c.Language):
+		return fmt.Errorf("invalid config: invalid language %q", c.Language)
+	case c.Timeout < 0:
+		return fmt.Errorf("invalid config: negative timeout %s", c.Timeout)
+	case c.MaxObservationAge < 0:
+		return fmt.Errorf("invalid config: negative maximum observation age %s", c.MaxObservationAge)
+	case c.Retries < 0:
+		return fmt.Errorf("invalid config: negative retries %d", c.Retries)
+	case c.RetryBackoff < 0:
+		return fmt.Errorf("invalid config: negative retry backoff %s", c.RetryBackoff)
+	case c.MaxRedirects < 0:
+		return fmt.Errorf("invalid config: negative maximum redirects %d", c.MaxRedirects)
+	case c.MaxResponseSize < 0:
+		return fmt.Errorf("invalid config: negative maximum response size %d", c.MaxResponseSize)
+	case c.ReadTimeout < 0:
+		return fmt.Errorf("invalid config: negative read timeout %s", c.ReadTimeout)
+	case c.OutOfCoverageTTL < 0:
+		return fmt.Errorf("invalid config: negative out of coverage ttl %s", c.OutOfCoverageTTL)
+	}
+	return nil
 }
 
 // SetUserAgent changes the string used for the User-Agent header when making
 // requests. See https://www.weather.gov/documentation/services-web-api
 // (Authentication) for details.  By default, this module uses a github.com URL.
+// SetUserAgentInfo formats the value from the application and contact details.
 func SetUserAgent(userAgent string) {
 	if len(userAgent) == 0 {
 		panic("the api requires a user-agent")
 	}
-	config.UserAgent = userAgent
+	updateConfig(func(c *Config) { c.UserAgent = userAgent })
 }
 
 // SetUnits can be used to change the units returned by the weather.gov API from
@@ -32,35 +169,147 @@ func SetUserAgent(userAgent string) {
 func SetUnits(uom string) {
 	units := strings.ToLower(uom)
 	if units != "us" && units != "si" {
-		config.Units = ""
-	} else {
-		config.Units = units
+		units = ""
+	}
+	updateConfig(func(c *Config) { c.Units = units })
+}
+
+// SetMaxObservationAge changes the age above which observations are considered
+// stale. ASOS outages can leave hours old data as the latest observation of a
+// station. A zero age disables the check.
+func SetMaxObservationAge(age time.Duration) {
+	if age < 0 {
+		panic("the maximum observation age cannot be negative")
 	}
+	updateConfig(func(c *Config) { c.MaxObservationAge = age })
+}
+
+// SetStaleObservationError changes whether fetching a stale observation
+// returns an error. By default stale observations are returned normally and
+// can be checked with Observation.IsStale.
+func SetStaleObservationError(enabled bool) {
+	updateConfig(func(c *Config) { c.StaleObservationError = enabled })
+}
+
+// SetLenientDecoding changes whether forecast, hourly forecast and gridpoint
+// responses with malformed values are decoded leniently. Values which cannot
+// be decoded, ex. a series of a gridpoint response or a forecast period, are
+// left out and recorded in the Problems of the response, so one bad series
+// does not fail a response of several megabytes. The elements of arrays are
+// skipped one by one, ex. a single value of a series. Responses which are not
+// valid JSON still fail with ErrInvalidResponse. By default decoding is
+// strict.
+func SetLenientDecoding(enabled bool) {
+	updateConfig(func(c *Config) { c.LenientDecoding = enabled })
+}
+
+// SetRetries changes how many times requests are retried when weather.gov
+// reports that data is temporarily unavailable (ErrDataUnavailable), waiting
+// backoff before the first retry and twice as long before each following one.
+// By default requests are not retried.
+func SetRetries(retries int, backoff time.Duration) {
+	if retries < 0 || backoff < 0 {
+		panic("retries and backoff cannot be negative")
+	}
+	updateConfig(func(c *Config) {
+		c.Retries = retries
+		c.RetryBackoff = backoff
+	})
+}
+
+// SetMaxRedirects changes the number of redirects followed per request, after
+// which requests fail with ErrTooManyRedirects. By default DefaultMaxRedirects
+// are followed.
+func SetMaxRedirects(max int) {
+	if max <= 0 {
+		panic("the maximum redirects must be positive")
+	}
+	updateConfig(func(c *Config) { c.MaxRedirects = max })
+}
+
+// SetResponseLimits changes the maximum size of response bodies and the time
+// allowed to read them, so a misbehaving server cannot exhaust memory or hang
+// decoding. A zero maxSize uses DefaultMaxResponseSize and a zero readTimeout
+// disables the read timeout, which is the default.
+func SetResponseLimits(maxSize int64, readTimeout time.Duration) {
+	if maxSize < 0 || readTimeout < 0 {
+		panic("the response limits cannot be negative")
+	}
+	updateConfig(func(c *Config) {
+		c.MaxResponseSize = maxSize
+		c.ReadTimeout = readTimeout
+	})
+}
+
+// SetLanguage changes the preferred language of alerts and products, an
+// ISO 639 code or language tag such as es or es-US, blank for English. Where
+// weather.gov issues the same text in several languages, ex. Spanish products
+// of the San Juan office or the Spanish info of CAP alerts, the preferred one
+// is returned and English otherwise, see Product.Language and
+// Alert.Language. It panics if the language is not a language tag.
+func SetLanguage(lang string) {
+	if lang != "" && !languagePattern.MatchString(lang) {
+		panic(fmt.Sprintf("invalid language %q", lang))
+	}
+	updateConfig(func(c *Config) { c.Language = lang })
+}
+
+// SetOutOfCoverageTTL changes how long points out of coverage, which
+// weather.gov answers with 404 Not Found, are remembered. Until then Points
+// and the functions using it fail with ErrOutOfCoverage without a request, so
+// services receiving international coordinates do not request them again and
+// again. A zero ttl disables the cache. By default points are remembered for
+// DefaultOutOfCoverageTTL.
+func SetOutOfCoverageTTL(ttl time.Duration) {
+	if ttl < 0 {
+		panic("the out of coverage ttl cannot be negative")
+	}
+	updateConfig(func(c *Config) { c.OutOfCoverageTTL = ttl })
 }
 
 // SetConfig replaces the config with all new values in one call. The individual
-// Set* functions can also be used to replace only specified values.
+// Set* functions can also be used to replace only specified values. It panics
+// if the config is invalid, see Config.Validate.
 func SetConfig(c Config) {
-	if !isConfigValid(c) {
-		panic("invalid configuration")
+	c.normalize()
+	if err := c.Validate(); err != nil {
+		panic(err.Error())
 	}
-	config = c
+	configMu.Lock()
+	defer configMu.Unlock()
+	config.Store(c)
 }
 
 // GetConfig is used to return the current configuration of the client. This allows
-// for testing and inspection as needed.
+// for testing and inspection as needed. The returned Config is a copy, changing
+// it has no effect until it is passed to SetConfig.
 func GetConfig() Config {
-	return config
+	return currentConfig()
 }
 
 // GetDefaultConfig returns a config struct that can be used as a starting point
 // for configuration changes. See examples in `example_test.go`.
 func GetDefaultConfig() Config {
+	return NewDefaultConfig()
+}
+
+// NewDefaultConfig returns the default config: the weather.gov API, the
+// library User-Agent, JSON-LD responses, US units, no timeout, observations
+// stale after DefaultMaxObservationAge, no retries, DefaultMaxRedirects,
+// responses up to DefaultMaxResponseSize and points out of coverage remembered
+// for DefaultOutOfCoverageTTL.
+func NewDefaultConfig() Config {
 	return Config{
 		BaseURL:   API,
 		UserAgent: APIKey,
 		Accept:    APIAccept,
 		Units:     "", // defaults to US units if unspecified
+
+		MaxObservationAge: DefaultMaxObservationAge,
+		RetryBackoff:      time.Second,
+		MaxRedirects:      DefaultMaxRedirects,
+		MaxResponseSize:   DefaultMaxResponseSize,
+		OutOfCoverageTTL:  DefaultOutOfCoverageTTL,
 	}
 }
 
@@ -71,7 +320,10 @@ func SetBaseURL(url string) {
 	if len(url) == 0 {
 		panic("the api requires a base url")
 	}
-	config.BaseURL = url
+	updateConfig(func(c *Config) {
+		c.BaseURL = url
+		c.normalize()
+	})
 }
 
 // SetAcceptHeader changes the format of the response. Note, this is largely a
@@ -82,17 +334,5 @@ func SetAcceptHeader(accept string) {
 	if len(accept) == 0 {
 		panic("the api requires an accept header")
 	}
-	config.Accept = accept
-}
-
-// isConfigValid determines whether the provided config might be valid. Under
-// certain conditions we can determine if the config is definitely not valid.
-func isConfigValid(c Config) bool {
-	if len(c.Units) > 0 && c.Units != "us" && c.Units != "si" {
-		return false
-	}
-	if len(c.Accept) == 0 || len(c.BaseURL) == 0 || len(c.UserAgent) == 0 {
-		return false
-	}
-	return true
+	updateConfig(func(c *Config) { c.Accept = accept })
 }
@@ -0,0 +1,253 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestConfigValidate(t *testing.T) {
+	if err := noaa.NewDefaultConfig().Validate(); err != nil {
+		t.Errorf("noaa.NewDefaultConfig() should be valid, got %v", err)
+	}
+	tests := []struct {
+		change func(*noaa.Config)
+		field  string
+	}{
+		{func(c *noaa.Config) { c.Units = "metric" }, "units"},
+		{func(c *noaa.Config) { c.BaseURL = "" }, "base url"},
+		{func(c *noaa.Config) { c.UserAgent = "" }, "user-agent"},
+		{func(c *noaa.Config) { c.Timeout = -time.Second }, "timeout"},
+		{func(c *noaa.Config) { c.Retries = -1 }, "retries"},
+	}
+	for _, test := range tests {
+		c := noaa.NewDefaultConfig()
+		test.change(&c)
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.field) {
+			t.Errorf("noaa.Config.Validate() should describe the invalid %s, got %v", test.field, err)
+		}
+	}
+}
+
+func TestSetConfigTrailingSlash(t *testing.T) {
+	t.Cleanup(func() { noaa.SetConfig(noaa.GetDefaultConfig()) })
+	c := noaa.NewDefaultConfig()
+	c.BaseURL = "https://api.weather.gov/"
+	noaa.SetConfig(c)
+	if base := noaa.GetConfig().BaseURL; base != "https://api.weather.gov" {
+		t.Errorf("noaa.SetConfig() should remove the trailing slash of the base url, got %q", base)
+	}
+	noaa.SetBaseURL("https://example.com/api/")
+	if base := noaa.GetConfig().BaseURL; base != "https://example.com/api" {
+		t.Errorf("noaa.SetBaseURL() should remove the trailing slash, got %q", base)
+	}
+}
+
+func TestConfigConcurrentUpdates(t *testing.T) {
+	t.Cleanup(func() { noaa.SetConfig(noaa.GetDefaultConfig()) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			noaa.SetUnits("si")
+		}()
+		go func() {
+			defer wg.Done()
+			noaa.SetRetries(2, time.Second)
+		}()
+		go func() {
+			defer wg.Done()
+			if c := noaa.GetConfig(); c.Validate() != nil {
+				t.Errorf("noaa.GetConfig() should return a valid config during updates, got %+v", c)
+			}
+		}()
+	}
+	wg.Wait()
+	if c := noaa.GetConfig(); c.Units != "si" || c.Retries != 2 {
+		t.Errorf("concurrent Set* calls should not lose updates, got %+v", c)
+	}
+
+	c := noaa.GetConfig()
+	c.Units = "us"
+	if noaa.GetConfig().Units != "si" {
+		t.Error("noaa.GetConfig() should return a copy of the config")
+	}
+}
+
+func TestConfigTimeout(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	c := noaa.NewDefaultConfig()
+	c.BaseURL = api.URL
+	c.Timeout = 20 * time.Millisecond
+	noaa.SetConfig(c)
+	if _, err := noaa.Office("LOT"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("noaa.Office() should time out, got %v", err)
+	}
+}
+
+func TestForecastWithUnits(t *testing.T) {
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/41.837,-87.685":
+			fmt.Fprintf(w, `{"forecast": "%s/gridpoints/LOT/76,73/forecast"}`, api.URL)
+		case "/gridpoints/LOT/76,73/forecast":
+			fmt.Fprintf(w, `{"units": "%s"}`, r.URL.Query().Get("units"))
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	noaa.SetUnits("us")
+
+	forecast, err := noaa.ForecastWithUnits("41.837", "-87.685", "si")
+	if err != nil || forecast.Units != "si" {
+		t.Errorf("noaa.ForecastWithUnits() should request si units, got %v %v", forecast, err)
+	}
+	forecast, err = noaa.Forecast("41.837", "-87.685")
+	if err != nil || forecast.Units != "us" {
+		t.Errorf("noaa.Forecast() should request the configured units, got %v %v", forecast, err)
+	}
+	if _, err := noaa.ForecastWithUnits("41.837", "-87.685", "metric"); err == nil {
+		t.Error("noaa.ForecastWithUnits() should reject invalid units")
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Cleanup(func() { noaa.SetConfig(noaa.GetDefaultConfig()) })
+	t.Setenv(noaa.EnvUserAgent, "(myapp.com, me@myapp.com)")
+	t.Setenv(noaa.EnvUnits, "si")
+	t.Setenv(noaa.EnvBaseURL, "https://example.com/api/")
+	t.Setenv(noaa.EnvTimeout, "15")
+
+	if err := noaa.LoadConfigFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	c := noaa.GetConfig()
+	if c.UserAgent != "(myapp.com, me@myapp.com)" || c.Units != "si" || c.BaseURL != "https://example.com/api" || c.Timeout != 15*time.Second {
+		t.Errorf("noaa.LoadConfigFromEnv() should set the config from the environment, got %+v", c)
+	}
+
+	t.Setenv(noaa.EnvUnits, "metric")
+	t.Setenv(noaa.EnvTimeout, "1m")
+	if err := noaa.LoadConfigFromEnv(); err == nil {
+		t.Error("noaa.LoadConfigFromEnv() should reject invalid values")
+	}
+	if c := noaa.GetConfig(); c.Units != "si" || c.Timeout != 15*time.Second {
+		t.Errorf("noaa.LoadConfigFromEnv() should not change the config when a value is invalid, got %+v", c)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	files := map[string]string{
+		"noaa.json": `{"userAgent": "(myapp.com, me@myapp.com)", "units": "si", "timeout": "10s", "retries": 2, "staleObservationError": true}`,
+		"noaa.yaml": "---\n# client config\nuser_agent: \"(myapp.com, me@myapp.com)\"\nunits: si # metric\ntimeout: 10s  \nretries: 2\nstale-observation-error: true\n",
+		"noaa.toml": "# client config\nuser_agent = \"(myapp.com, me@myapp.com)\"\nunits = 'si' \ntimeout = \"10s\"\nretries = 2\t\nstaleObservationError = true\n",
+	}
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		c := noaa.NewDefaultConfig()
+		if err := c.LoadFile(path); err != nil {
+			t.Errorf("noaa.Config.LoadFile(%q) should succeed, got %v", name, err)
+			continue
+		}
+		if c.UserAgent != "(myapp.com, me@myapp.com)" || c.Units != "si" || c.Timeout != 10*time.Second || c.Retries != 2 || !c.StaleObservationError {
+			t.Errorf("noaa.Config.LoadFile(%q) should set the values of the file, got %+v", name, c)
+		}
+		if c.BaseURL != noaa.API {
+			t.Errorf("noaa.Config.LoadFile(%q) should keep the values missing from the file, got %q", name, c.BaseURL)
+		}
+	}
+
+	for name, data := range map[string]string{
+		"unknown.toml": "color = \"blue\"\n",
+		"nested.yaml":  "client:\n  units: si\n",
+		"section.toml": "[client]\nunits = \"si\"\n",
+		"array.toml":   "units = [\"si\"]\n",
+		"block.yaml":   "units: |\n  si\n",
+		"invalid.json": `{"retries": "many"}`,
+		"noaa.ini":     "units=si\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := noaa.LoadConfigFile(path); err == nil {
+			t.Errorf("noaa.LoadConfigFile(%q) should return an error", name)
+		}
+	}
+	if c := noaa.GetConfig(); c.Units != "" {
+		t.Errorf("noaa.LoadConfigFile() should not change the config when the file is invalid, got %+v", c)
+	}
+}
+
+func TestConfigJSON(t *testing.T) {
+	c := noaa.NewDefaultConfig()
+	c.Units = "si"
+	c.Timeout = 30 * time.Second
+	c.RetryBackoff = 1500 * time.Millisecond
+	c.Retries = 2
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"timeout":"30s"`) {
+		t.Errorf("noaa.Config should marshal durations as strings, got %s", data)
+	}
+
+	path := filepath.Join(t.TempDir(), "noaa.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded := noaa.Config{}
+	if err := loaded.LoadFile(path); err != nil {
+		t.Fatalf("noaa.Config.LoadFile() should load a marshaled config, got %v", err)
+	}
+	var decoded noaa.Config
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("noaa.Config should unmarshal a marshaled config, got %v", err)
+	}
+	for _, got := range []noaa.Config{loaded, decoded} {
+		if got != c {
+			t.Errorf("noaa.Config should round trip through JSON, got %+v, want %+v", got, c)
+		}
+	}
+}
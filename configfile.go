@@ -0,0 +1,290 @@
+package noaa
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by LoadConfigFromEnv.
+const (
+	EnvUserAgent = "NOAA_USER_AGENT"
+	EnvUnits     = "NOAA_UNITS"
+	EnvBaseURL   = "NOAA_BASE_URL"
+	EnvTimeout   = "NOAA_TIMEOUT"
+)
+
+// configAliases maps alternative config file keys to the keys of the Config
+// json tags, both normalized by configKey
+var configAliases = map[string]string{
+	"useragent": "apikey",
+}
+
+// LoadConfigFromEnv changes the config values set by the NOAA_USER_AGENT,
+// NOAA_UNITS, NOAA_BASE_URL and NOAA_TIMEOUT environment variables, see
+// Config.LoadEnv. The config is unchanged if a value is invalid.
+func LoadConfigFromEnv() error {
+	return loadConfig((*Config).LoadEnv)
+}
+
+// LoadConfigFile changes the config values set in a JSON, YAML or TOML file,
+// see Config.LoadFile. The config is unchanged if the file is invalid.
+func LoadConfigFile(path string) error {
+	return loadConfig(func(c *Config) error { return c.LoadFile(path) })
+}
+
+// loadConfig stores a copy of the current config changed by fn if it is valid
+func loadConfig(fn func(*Config) error) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+	c := currentConfig()
+	if err := fn(&c); err != nil {
+		return err
+	}
+	c.normalize()
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	config.Store(c)
+	return nil
+}
+
+// LoadEnv sets the values of the NOAA_USER_AGENT, NOAA_UNITS, NOAA_BASE_URL
+// and NOAA_TIMEOUT environment variables which are set and not blank. The
+// timeout is a duration, ex. 30s, or a number of seconds.
+func (c *Config) LoadEnv() error {
+	for _, env := range []struct {
+		name string
+		key  string
+	}{
+		{EnvUserAgent, "apiKey"},
+		{EnvUnits, "units"},
+		{EnvBaseURL, "baseUrl"},
+		{EnvTimeout, "timeout"},
+	} {
+		if value := strings.TrimSpace(os.Getenv(env.name)); value != "" {
+			if err := c.set(env.key, value); err != nil {
+				return fmt.Errorf("%s: %w", env.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadFile sets the values of a config file. The format is chosen by the
+// extension: .json, .yaml or .yml, or .toml. Keys are the json names of the
+// Config fields, ex. baseUrl, compared ignoring case, hyphens and
+// underscores, so base_url also works, and userAgent can be used for apiKey.
+// Durations are strings, ex. "30s", or a number of seconds, so a Config
+// marshaled to JSON, which writes durations as strings, can be loaded back.
+//
+// YAML and TOML files are not parsed by a full YAML or TOML parser: only flat
+// files of one top-level key: value or key = value per line, with optional
+// quotes and # comments, are supported, which is all a Config needs. Nested
+// values, tables, arrays and multi-line strings are rejected; use JSON or the
+// environment instead.
+//
+//	# noaa.toml
+//	user_agent = "(myapp.com, me@myapp.com)"
+//	units = "si"
+//	timeout = "10s"
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		values, err = parseJSONConfig(data)
+	case ".yaml", ".yml":
+		values, err = parseFlatConfig(data, ":")
+	case ".toml":
+		values, err = parseFlatConfig(data, "=")
+	default:
+		return fmt.Errorf("unsupported config file format %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for key, value := range values {
+		if err := c.set(key, value); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// parseJSONConfig returns the values of a JSON object, strings unquoted
+func parseJSONConfig(data []byte) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(bytes.TrimSpace(value))
+		}
+		values[key] = s
+	}
+	return values, nil
+}
+
+// parseFlatConfig returns the key-value pairs of a flat YAML or TOML file, sep
+// being the separator of keys and values. It is a line splitter rather than a
+// parser, so anything but top-level scalar values is an error.
+func parseFlatConfig(data []byte, sep string) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, sep)
+		if !ok || strings.TrimLeft(line, " \t") != line || strings.HasPrefix(trimmed, "[") {
+			return nil, fmt.Errorf("line %d: expected a top-level key%svalue, got %q", n, sep, line)
+		}
+		if v := strings.TrimSpace(value); v == "" || strings.ContainsAny(v[:1], "[{|>") || strings.HasPrefix(v, `"""`) || strings.HasPrefix(v, "'''") {
+			return nil, fmt.Errorf("line %d: expected a scalar value, got %q", n, line)
+		}
+		values[strings.TrimSpace(key)] = unquoteConfigValue(value)
+	}
+	return values, scanner.Err()
+}
+
+// unquoteConfigValue trims a comment and the quotes of a value
+func unquoteConfigValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			if value[0] == '"' {
+				if s, err := strconv.Unquote(value[:end+2]); err == nil {
+					return s
+				}
+			}
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// configKey normalizes a config key, ex. base_url and baseUrl are baseurl
+func configKey(key string) string {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(strings.TrimSpace(key)))
+	if alias, ok := configAliases[key]; ok {
+		return alias
+	}
+	return key
+}
+
+// durationType is the type of the time.Duration fields of Config
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// set sets the field of the config whose json name matches the key
+func (c *Config) set(key string, value string) error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || configKey(name) != configKey(key) {
+			continue
+		}
+		field := v.Field(i)
+		switch {
+		case field.Type() == durationType:
+			d, err := parseConfigDuration(value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			field.SetInt(int64(d))
+		case field.Kind() == reflect.String:
+			field.SetString(value)
+		case field.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s: invalid boolean %q", key, value)
+			}
+			field.SetBool(b)
+		case field.Kind() == reflect.Int || field.Kind() == reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: invalid integer %q", key, value)
+			}
+			field.SetInt(n)
+		default:
+			return fmt.Errorf("unsupported config key %q", key)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown config key %q", key)
+}
+
+// MarshalJSON implements json.Marshaler, writing the durations as strings,
+// ex. "30s", which LoadFile and UnmarshalJSON read back.
+func (c Config) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	v := reflect.ValueOf(c)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		value := v.Field(i).Interface()
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, "%q:%s", name, data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, setting the values of a JSON
+// object like LoadFile. Values missing from the object are unchanged.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	values, err := parseJSONConfig(data)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		if err := c.set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseConfigDuration parses a duration, ex. 30s, or a number of seconds
+func parseConfigDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,65 @@
+package noaa
+
+import (
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetSize is the number of bytes of a body kept in a ContentTypeError
+const snippetSize = 200
+
+// acceptsContentType reports whether a response Content-Type matches the
+// Accept header of the request. JSON media types match each other, as do XML
+// ones, since weather.gov answers some JSON-LD requests with GeoJSON.
+// Responses without a Content-Type or labelled text/plain, ex. by servers
+// which sniff the body, are accepted so decoding can decide.
+func acceptsContentType(accept string, contentType string) bool {
+	if strings.TrimSpace(contentType) == "" {
+		return true
+	}
+	got, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if got == "text/plain" {
+		return true
+	}
+	for _, a := range strings.Split(accept, ",") {
+		want, _, err := mime.ParseMediaType(a)
+		if err != nil {
+			continue
+		}
+		switch {
+		case want == "*/*" || want == got:
+			return true
+		case strings.HasSuffix(want, "/*") && strings.HasPrefix(got, strings.TrimSuffix(want, "*")):
+			return true
+		case mediaFamily(want) != "" && mediaFamily(want) == mediaFamily(got):
+			return true
+		}
+	}
+	return false
+}
+
+// mediaFamily returns json or xml for JSON and XML media types, ex.
+// application/geo+json or text/xml, blank otherwise
+func mediaFamily(mediaType string) string {
+	_, subtype, _ := strings.Cut(mediaType, "/")
+	for _, family := range []string{"json", "xml"} {
+		if subtype == family || strings.HasSuffix(subtype, "+"+family) {
+			return family
+		}
+	}
+	return ""
+}
+
+// bodySnippet returns the start of a body with its whitespace collapsed
+func bodySnippet(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, snippetSize))
+	for len(data) > 0 && !utf8.Valid(data) {
+		data = data[:len(data)-1] // cut in the middle of a character
+	}
+	return strings.Join(strings.Fields(string(data)), " ")
+}
@@ -0,0 +1,80 @@
+package noaa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// CorrelationIDHeader is the request header carrying the correlation ID.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// correlationIDKey is the context key of the correlation ID
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID. Requests
+// made with the context send it in the X-Correlation-ID header, report it to
+// the request logger and include it in APIError, so the requests of an
+// operation can be traced in logs, ex.
+//
+//	ctx := noaa.WithCorrelationID(r.Context(), requestID)
+//	forecast, err := noaa.ForecastContext(ctx, lat, lon)
+//
+// Requests made without one get a new ID, shared by the requests of
+// operations which make several, ex. Forecast looks up the point and then
+// fetches the forecast with the same ID.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of the context, or blank if it has
+// none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// NewCorrelationID returns a random correlation ID of 16 hex digits.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// withCorrelationID returns the context with a new correlation ID if it has
+// none
+func withCorrelationID(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+	return WithCorrelationID(ctx, NewCorrelationID())
+}
+
+// RequestLog describes a request made to weather.gov, see SetRequestLogger.
+type RequestLog struct {
+	CorrelationID string
+	URL           string
+	StatusCode    int // 0 if the request failed without a response
+	Duration      time.Duration
+	Err           error
+}
+
+// requestLogger holds the func(RequestLog) set by SetRequestLogger
+var requestLogger atomic.Value
+
+// SetRequestLogger sets a function called after each request, including
+// retries, ex. to log requests with their correlation ID. It is called from
+// the goroutine making the request and must not block. Nil removes the
+// logger.
+func SetRequestLogger(logger func(RequestLog)) {
+	requestLogger.Store(logger)
+}
+
+// logRequest passes the request to the logger, if any
+func logRequest(r RequestLog) {
+	if logger, _ := requestLogger.Load().(func(RequestLog)); logger != nil {
+		logger(r)
+	}
+}
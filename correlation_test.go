@@ -0,0 +1,79 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestCorrelationID(t *testing.T) {
+	var mu sync.Mutex
+	var headers []string
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Get(noaa.CorrelationIDHeader))
+		mu.Unlock()
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/points/"):
+			fmt.Fprintf(w, `{"forecast": "%s/gridpoints/LOT/76,73/forecast%s"}`, api.URL, r.URL.Path[len("/points/"):])
+		case strings.HasPrefix(r.URL.Path, "/gridpoints/"):
+			fmt.Fprint(w, `{"periods": [{"number": 1, "temperature": 59}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	var logs []noaa.RequestLog
+	noaa.SetRequestLogger(func(r noaa.RequestLog) {
+		mu.Lock()
+		logs = append(logs, r)
+		mu.Unlock()
+	})
+	t.Cleanup(func() {
+		noaa.SetRequestLogger(nil)
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	ctx := noaa.WithCorrelationID(context.Background(), "trace-1")
+	if _, err := noaa.ForecastContext(ctx, "41.1", "-87.1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(headers) != 2 || headers[0] != "trace-1" || headers[1] != "trace-1" {
+		t.Errorf("noaa.ForecastContext() should send the correlation ID of the context, got %q", headers)
+	}
+	if len(logs) != 2 || logs[0].CorrelationID != "trace-1" || logs[1].StatusCode != http.StatusOK {
+		t.Errorf("noaa.ForecastContext() should log the requests, got %+v", logs)
+	}
+
+	headers = nil
+	if _, err := noaa.Forecast("41.2", "-87.2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(headers) != 2 || headers[0] == "" || headers[0] != headers[1] {
+		t.Errorf("noaa.Forecast() should send a new correlation ID shared by its requests, got %q", headers)
+	}
+
+	_, err := noaa.Office("LOT")
+	var apiErr *noaa.APIError
+	if !errors.As(err, &apiErr) || apiErr.CorrelationID == "" || !strings.Contains(err.Error(), apiErr.CorrelationID) {
+		t.Errorf("noaa.Office() should return the correlation ID in the error, got %v", err)
+	}
+	if last := logs[len(logs)-1]; last.Err == nil || last.StatusCode != http.StatusNotFound || last.CorrelationID != apiErr.CorrelationID {
+		t.Errorf("noaa.Office() should log the failed request, got %+v", last)
+	}
+}
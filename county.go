@@ -0,0 +1,93 @@
+package noaa
+
+import (
+	"fmt"
+	"sync"
+)
+
+// County is a county (or parish, borough, etc.) with its FIPS codes.
+type County struct {
+	ID    string // county zone ID, ex. ILC031
+	Name  string // ex. Cook
+	State string // ex. IL
+	FIPS  string // 5 digit state and county FIPS code, ex. 17031
+	SAME  string // 6 digit SAME code used in alert geocodes, ex. 017031
+	Zone  *Zone
+}
+
+// countyCache caches counties by zone URL since they rarely change
+var countyCache = map[string]*County{}
+
+// countyCacheMu guards countyCache
+var countyCacheMu sync.RWMutex
+
+// stateFIPS maps state and territory abbreviations to their FIPS codes
+var stateFIPS = map[string]string{
+	"AL": "01", "AK": "02", "AZ": "04", "AR": "05", "CA": "06", "CO": "08",
+	"CT": "09", "DE": "10", "DC": "11", "FL": "12", "GA": "13", "HI": "15",
+	"ID": "16", "IL": "17", "IN": "18", "IA": "19", "KS": "20", "KY": "21",
+	"LA": "22", "ME": "23", "MD": "24", "MA": "25", "MI": "26", "MN": "27",
+	"MS": "28", "MO": "29", "MT": "30", "NE": "31", "NV": "32", "NH": "33",
+	"NJ": "34", "NM": "35", "NY": "36", "NC": "37", "ND": "38", "OH": "39",
+	"OK": "40", "OR": "41", "PA": "42", "RI": "44", "SC": "45", "SD": "46",
+	"TN": "47", "TX": "48", "UT": "49", "VT": "50", "VA": "51", "WA": "53",
+	"WV": "54", "WI": "55", "WY": "56", "AS": "60", "GU": "66", "MP": "69",
+	"PR": "72", "VI": "78",
+}
+
+// CountyFIPS returns the 5 digit FIPS code of a county zone ID, ex. 17031 for
+// ILC031. ok is false if the ID is not a county zone ID of a known state.
+func CountyFIPS(zoneID string) (fips string, ok bool) {
+	if len(zoneID) != 6 || zoneID[2] != 'C' || !isDigits(zoneID[3:]) {
+		return "", false
+	}
+	state, ok := stateFIPS[zoneID[:2]]
+	if !ok {
+		return "", false
+	}
+	return state + zoneID[3:], true
+}
+
+// CountyForPoint returns the county containing a point. Counties are cached.
+func CountyForPoint(lat string, lon string) (*County, error) {
+	point, err := Points(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if point.County == "" {
+		return nil, fmt.Errorf("no county for %s,%s", lat, lon)
+	}
+	return countyForZone(point.County)
+}
+
+// countyForZone returns the county for a county zone URL
+func countyForZone(zoneURL string) (*County, error) {
+	countyCacheMu.RLock()
+	cached := countyCache[zoneURL]
+	countyCacheMu.RUnlock()
+	countCache(cached != nil)
+	if cached != nil {
+		return cached, nil
+	}
+	zone, err := getZone(zoneURL)
+	if err != nil {
+		return nil, err
+	}
+	county := &County{ID: zone.ID, Name: zone.Name, State: zone.State, Zone: zone}
+	if county.ID == "" {
+		county.ID = ZoneID(zoneURL)
+	}
+	if county.State == "" && len(county.ID) >= 2 {
+		county.State = county.ID[:2]
+	}
+	fips, ok := CountyFIPS(county.ID)
+	if !ok {
+		return nil, fmt.Errorf("invalid county zone ID %q", county.ID)
+	}
+	county.FIPS = fips
+	county.SAME = "0" + fips
+	countyCacheMu.Lock()
+	countyCache[zoneURL] = county
+	countyCacheMu.Unlock()
+	return county, nil
+}
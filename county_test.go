@@ -0,0 +1,43 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestCountyForPoint(t *testing.T) {
+	api := fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685": `{"county": "{api}/zones/county/ILC031"}`,
+		"/zones/county/ILC031":   `{"id": "ILC031", "type": "county", "name": "Cook", "state": "IL"}`,
+	})
+
+	county, err := noaa.CountyForPoint("41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if county.ID != "ILC031" || county.Name != "Cook" || county.State != "IL" || county.FIPS != "17031" || county.SAME != "017031" {
+		t.Errorf("noaa.CountyForPoint() should return the county and its codes, got %+v", county)
+	}
+
+	api.Close() // cached counties do not need the API
+	if cached, err := noaa.CountyForPoint("41.837", "-87.685"); err != nil || cached != county {
+		t.Errorf("noaa.CountyForPoint() should cache the county, got %v", err)
+	}
+}
+
+func TestCountyFIPS(t *testing.T) {
+	for id, want := range map[string]string{"ILC031": "17031", "PRC127": "72127", "AKC020": "02020"} {
+		if fips, ok := noaa.CountyFIPS(id); !ok || fips != want {
+			t.Errorf("noaa.CountyFIPS(%q) should return %s, got %s", id, want, fips)
+		}
+	}
+	for _, id := range []string{"ILZ014", "XXC001", "ILC03"} {
+		if _, ok := noaa.CountyFIPS(id); ok {
+			t.Errorf("noaa.CountyFIPS(%q) should not return a FIPS code", id)
+		}
+	}
+}
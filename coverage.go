@@ -0,0 +1,91 @@
+package noaa
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Regions of the NWS forecast grids. Points outside the continental United
+// States resolve to grids of their own, ex. a point in Anchorage has the grid
+// ID AER although its forecast office (CWA) is AFC.
+const (
+	RegionCONUS       = "CONUS"
+	RegionAlaska      = "Alaska"
+	RegionHawaii      = "Hawaii"
+	RegionPuertoRico  = "Puerto Rico"
+	RegionGuam        = "Guam"
+	RegionUnspecified = ""
+)
+
+// gridRegions maps the grid IDs outside the continental United States to
+// their region. Other grid IDs are the CONUS forecast offices.
+var gridRegions = map[string]string{
+	"AER": RegionAlaska, // Alaska east, issued by AFC Anchorage
+	"ALU": RegionAlaska, // Aleutians, issued by AFC Anchorage
+	"AFC": RegionAlaska,
+	"AFG": RegionAlaska, // Fairbanks
+	"AJK": RegionAlaska, // Juneau
+	"HFO": RegionHawaii, // Honolulu
+	"SJU": RegionPuertoRico,
+	"GUM": RegionGuam,
+}
+
+// Region returns the region of the point's forecast grid, or
+// RegionUnspecified if the point has no grid.
+func (p *PointsResponse) Region() string {
+	if p == nil || p.GridID == "" {
+		return RegionUnspecified
+	}
+	if region, ok := gridRegions[p.GridID]; ok {
+		return region
+	}
+	return RegionCONUS
+}
+
+// Coverage describes whether a point is inside an NWS forecast grid.
+type Coverage struct {
+	Covered bool
+	Region  string          // region of the grid, see PointsResponse.Region
+	Point   *PointsResponse // nil when the point is not covered
+	Reason  string          // why the point is not covered, from weather.gov
+}
+
+// CheckCoverage reports whether <lat,lon> is inside an NWS forecast grid, so
+// that points outside the United States and its territories or over open
+// water can be rejected before other calls fail with 404 errors. Points over
+// coastal and offshore waters are covered by marine forecasts, which are not
+// available from the gridpoint endpoints, and are reported as not covered.
+// Errors other than an unknown point are returned as is.
+func CheckCoverage(lat string, lon string) (*Coverage, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+	point, err := Points(lat, lon)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return &Coverage{Reason: apiErr.Detail}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if point.GridID == "" || point.EndpointForecast == "" {
+		return &Coverage{Region: point.Region(), Reason: "the point has no forecast grid"}, nil
+	}
+	return &Coverage{Covered: true, Region: point.Region(), Point: point}, nil
+}
+
+// validateCoordinates checks that the latitude and longitude are numbers in
+// range
+func validateCoordinates(lat string, lon string) error {
+	la, err := strconv.ParseFloat(lat, 64)
+	if err != nil || la < -90 || la > 90 {
+		return fmt.Errorf("invalid latitude %q", lat)
+	}
+	lo, err := strconv.ParseFloat(lon, 64)
+	if err != nil || lo < -180 || lo > 180 {
+		return fmt.Errorf("invalid longitude %q", lon)
+	}
+	return nil
+}
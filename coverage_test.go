@@ -0,0 +1,115 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// points responses for grids outside the continental United States
+var oconusPoints = map[string]string{
+	"/points/61.2181,-149.9003": `{"cwa": "AFC", "gridId": "AER", "gridX": 143, "gridY": 236, "forecastOffice": "{api}/offices/AFC", "forecast": "{api}/gridpoints/AER/143,236/forecast", "timeZone": "America/Anchorage"}`,
+	"/points/51.88,-176.6581":   `{"cwa": "AFC", "gridId": "ALU", "gridX": 118, "gridY": 84, "forecastOffice": "{api}/offices/AFC", "forecast": "{api}/gridpoints/ALU/118,84/forecast", "timeZone": "America/Adak"}`,
+	"/points/64.8378,-147.7164": `{"cwa": "AFG", "gridId": "AFG", "gridX": 395, "gridY": 155, "forecastOffice": "{api}/offices/AFG", "forecast": "{api}/gridpoints/AFG/395,155/forecast", "timeZone": "America/Anchorage"}`,
+	"/points/21.3069,-157.8583": `{"cwa": "HFO", "gridId": "HFO", "gridX": 153, "gridY": 144, "forecastOffice": "{api}/offices/HFO", "forecast": "{api}/gridpoints/HFO/153,144/forecast", "timeZone": "Pacific/Honolulu"}`,
+	"/points/18.4655,-66.1057":  `{"cwa": "SJU", "gridId": "SJU", "gridX": 83, "gridY": 63, "forecastOffice": "{api}/offices/SJU", "forecast": "{api}/gridpoints/SJU/83,63/forecast", "timeZone": "America/Puerto_Rico"}`,
+	"/points/13.4443,144.7937":  `{"cwa": "GUM", "gridId": "GUM", "gridX": 195, "gridY": 144, "forecastOffice": "{api}/offices/GUM", "forecast": "{api}/gridpoints/GUM/195,144/forecast", "timeZone": "Pacific/Guam"}`,
+	"/points/41.837,-87.685":    `{"cwa": "LOT", "gridId": "LOT", "gridX": 76, "gridY": 73, "forecastOffice": "{api}/offices/LOT", "forecast": "{api}/gridpoints/LOT/76,73/forecast", "timeZone": "America/Chicago"}`,
+}
+
+func TestCheckCoverage(t *testing.T) {
+	api := fakeAPI(t, oconusPoints)
+	tests := []struct {
+		lat, lon, region string
+	}{
+		{"61.2181", "-149.9003", noaa.RegionAlaska},
+		{"51.88", "-176.6581", noaa.RegionAlaska},
+		{"64.8378", "-147.7164", noaa.RegionAlaska},
+		{"21.3069", "-157.8583", noaa.RegionHawaii},
+		{"18.4655", "-66.1057", noaa.RegionPuertoRico},
+		{"13.4443", "144.7937", noaa.RegionGuam},
+		{"41.837", "-87.685", noaa.RegionCONUS},
+	}
+	for _, test := range tests {
+		coverage, err := noaa.CheckCoverage(test.lat, test.lon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !coverage.Covered || coverage.Region != test.region {
+			t.Errorf("noaa.CheckCoverage(%s, %s) should be covered by %s, got %+v", test.lat, test.lon, test.region, coverage)
+		}
+		if !strings.HasPrefix(coverage.Point.EndpointForecast, api.URL+"/gridpoints/"+coverage.Point.GridID+"/") {
+			t.Errorf("noaa.CheckCoverage(%s, %s) should use the grid ID in the forecast endpoint", test.lat, test.lon)
+		}
+	}
+}
+
+func TestCheckCoverageOutside(t *testing.T) {
+	fakeAPI(t, nil)
+	coverage, err := noaa.CheckCoverage("51.5072", "-0.1276") // London
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coverage.Covered || coverage.Point != nil {
+		t.Errorf("noaa.CheckCoverage() should not cover London, got %+v", coverage)
+	}
+	if _, err := noaa.CheckCoverage("91", "0"); err == nil {
+		t.Error("noaa.CheckCoverage() should reject an invalid latitude")
+	}
+}
+
+func TestOutOfCoverageCache(t *testing.T) {
+	var requests int32
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type": "https://api.weather.gov/problems/InvalidPoint", "title": "Invalid Point", "status": 404, "detail": "Unable to provide data for requested point 48.8566,2.3522"}`))
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	noaa.SetOutOfCoverageTTL(50 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		if _, err := noaa.Points("48.8566", "2.3522"); !errors.Is(err, noaa.ErrOutOfCoverage) {
+			t.Fatalf("noaa.Points() should fail with ErrOutOfCoverage for Paris, got %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("noaa.Points() should cache points out of coverage, got %d requests", n)
+	}
+	coverage, err := noaa.CheckCoverage("48.8566", "2.3522")
+	if err != nil || coverage.Covered || !strings.Contains(coverage.Reason, "Unable to provide data") {
+		t.Errorf("noaa.CheckCoverage() should not cover a cached point out of coverage, got %+v, %v", coverage, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := noaa.Points("48.8566", "2.3522"); !errors.Is(err, noaa.ErrOutOfCoverage) {
+		t.Fatalf("noaa.Points() should fail with ErrOutOfCoverage for Paris, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("noaa.Points() should request points out of coverage again after the ttl, got %d requests", n)
+	}
+
+	noaa.SetOutOfCoverageTTL(0)
+	noaa.Points("48.8566", "2.3523")
+	noaa.Points("48.8566", "2.3523")
+	if n := atomic.LoadInt32(&requests); n != 4 {
+		t.Errorf("noaa.Points() should not cache points out of coverage with a zero ttl, got %d requests", n)
+	}
+}
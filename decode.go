@@ -0,0 +1,133 @@
+package noaa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync/atomic"
+)
+
+// JSONCodec decodes response bodies. The default codec is encoding/json, see
+// SetJSONCodec to use a faster decoder.
+type JSONCodec interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// stdCodec is the encoding/json codec
+type stdCodec struct{}
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// jsonCodec holds the JSONCodec set by SetJSONCodec
+var jsonCodec atomic.Value
+
+// SetJSONCodec changes the decoder of response bodies, ex. to the
+// json-iterator codec of the github.com/chrisdobbins/noaa/jsoniter module,
+// which is a separate module so this package keeps no dependencies. The codec
+// must be compatible with encoding/json: it must honor json tags and call the
+// UnmarshalJSON methods of the response types. Nil restores encoding/json.
+// Alerts are always streamed with encoding/json, see DecodeAlerts.
+func SetJSONCodec(codec JSONCodec) {
+	if codec == nil {
+		codec = stdCodec{}
+	}
+	jsonCodec.Store(&codec)
+}
+
+// currentCodec returns the codec set by SetJSONCodec
+func currentCodec() JSONCodec {
+	if codec, _ := jsonCodec.Load().(*JSONCodec); codec != nil {
+		return *codec
+	}
+	return stdCodec{}
+}
+
+// ErrInvalidResponse is matched by the errors returned when a response body
+// cannot be decoded, ex. a truncated or malformed response, see
+// InvalidResponseError.
+var ErrInvalidResponse = errors.New("invalid response")
+
+// InvalidResponseError is returned when a response body cannot be decoded.
+// It matches ErrInvalidResponse with errors.Is and unwraps to the decoding
+// error, ex. a *json.SyntaxError.
+type InvalidResponseError struct {
+	Err error
+}
+
+func (e *InvalidResponseError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrInvalidResponse, e.Err)
+}
+
+func (e *InvalidResponseError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrInvalidResponse.
+func (e *InvalidResponseError) Is(target error) bool { return target == ErrInvalidResponse }
+
+// DecodePoints decodes a /points/{lat},{lon} response.
+func DecodePoints(r io.Reader) (*PointsResponse, error) { return decode[PointsResponse](r) }
+
+// DecodeOffice decodes an /offices/{id} response.
+func DecodeOffice(r io.Reader) (*OfficeResponse, error) { return decode[OfficeResponse](r) }
+
+// DecodeStations decodes an observation stations response.
+func DecodeStations(r io.Reader) (*StationsResponse, error) { return decode[StationsResponse](r) }
+
+// DecodeForecast decodes a gridpoint forecast response.
+func DecodeForecast(r io.Reader) (*ForecastResponse, error) { return decode[ForecastResponse](r) }
+
+// DecodeHourlyForecast decodes an hourly gridpoint forecast response.
+func DecodeHourlyForecast(r io.Reader) (*HourlyForecastResponse, error) {
+	return decode[HourlyForecastResponse](r)
+}
+
+// DecodeGridpointForecast decodes a raw gridpoint data response.
+func DecodeGridpointForecast(r io.Reader) (*GridpointForecastResponse, error) {
+	return decode[GridpointForecastResponse](r)
+}
+
+// DecodeObservation decodes an observation response, ex.
+// /stations/{id}/observations/latest.
+func DecodeObservation(r io.Reader) (*Observation, error) { return decode[Observation](r) }
+
+// decode decodes a JSON object into a new T
+func decode[T any](r io.Reader) (*T, error) {
+	v := new(T)
+	if err := decodeJSON(r, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decodeJSON decodes a response body, which must be a JSON object, into v
+// with the configured codec. Decoding errors, including panics of custom
+// unmarshalers, are returned as an *InvalidResponseError.
+func decodeJSON(r io.Reader, v interface{}) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = &InvalidResponseError{fmt.Errorf("panic decoding %T: %v", v, p)}
+		}
+	}()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if !isObject(data) {
+		return &InvalidResponseError{errors.New("expected a JSON object")}
+	}
+	codec := currentCodec()
+	if err := codec.Unmarshal(data, v); err != nil {
+		response, ok := v.(lenientResponse)
+		if !ok || !currentConfig().LenientDecoding {
+			return &InvalidResponseError{err}
+		}
+		var problems []DecodeProblem
+		decodeLenient(codec, data, reflect.ValueOf(v).Elem(), "", &problems)
+		if len(problems) > 0 && problems[0].Path == "" {
+			return &InvalidResponseError{problems[0].Err}
+		}
+		response.setProblems(problems)
+	}
+	return nil
+}
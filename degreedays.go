@@ -0,0 +1,201 @@
+package noaa
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Common base temperatures of degree days. Heating and cooling degree days in
+// the US use 65 °F, and 18 °C elsewhere. Growing degree days of corn and most
+// warm season crops use a base of 50 °F with highs capped at 86 °F.
+const (
+	DegreeDayBaseF        = 65.0
+	DegreeDayBaseC        = 18.0
+	GrowingDegreeDayBaseF = 50.0
+	GrowingDegreeDayBaseC = 10.0
+	GrowingDegreeDayCapF  = 86.0
+	GrowingDegreeDayCapC  = 30.0
+)
+
+// DailyTemperature is the high and low temperature of a day, in F or C,
+// observed or forecast, see the DailyTemperaturesFrom functions. Base
+// temperatures of degree days are in the same unit.
+type DailyTemperature struct {
+	Date    time.Time // midnight at the start of the day, local time
+	High    float64
+	Low     float64
+	Unit    string // F or C
+	Samples int    // number of temperatures the high and low are taken from
+}
+
+// Mean returns the average of the high and low, which degree days are
+// computed from.
+func (d DailyTemperature) Mean() float64 {
+	return (d.High + d.Low) / 2
+}
+
+// HeatingDegrees returns the heating degree days of the day, how much the mean
+// temperature is below base, or 0.
+func (d DailyTemperature) HeatingDegrees(base float64) float64 {
+	return math.Max(base-d.Mean(), 0)
+}
+
+// CoolingDegrees returns the cooling degree days of the day, how much the mean
+// temperature is above base, or 0.
+func (d DailyTemperature) CoolingDegrees(base float64) float64 {
+	return math.Max(d.Mean()-base, 0)
+}
+
+// GrowingDegrees returns the growing degree days of the day. The high and
+// low are raised to base when below, and lowered to upper when above, unless
+// upper is 0, before averaging them, ex. with GrowingDegreeDayBaseF and
+// GrowingDegreeDayCapF, a day at 90 °F and 45 °F accumulates 18 degree days.
+func (d DailyTemperature) GrowingDegrees(base float64, upper float64) float64 {
+	clamp := func(t float64) float64 {
+		if upper != 0 && t > upper {
+			t = upper
+		}
+		return math.Max(t, base)
+	}
+	return (clamp(d.High)+clamp(d.Low))/2 - base
+}
+
+// HeatingDegreeDays returns the sum of the heating degree days of the days.
+func HeatingDegreeDays(days []DailyTemperature, base float64) float64 {
+	return sumDegrees(days, func(d DailyTemperature) float64 { return d.HeatingDegrees(base) })
+}
+
+// CoolingDegreeDays returns the sum of the cooling degree days of the days.
+func CoolingDegreeDays(days []DailyTemperature, base float64) float64 {
+	return sumDegrees(days, func(d DailyTemperature) float64 { return d.CoolingDegrees(base) })
+}
+
+// GrowingDegreeDays returns the sum of the growing degree days of the days,
+// see DailyTemperature.GrowingDegrees.
+func GrowingDegreeDays(days []DailyTemperature, base float64, upper float64) float64 {
+	return sumDegrees(days, func(d DailyTemperature) float64 { return d.GrowingDegrees(base, upper) })
+}
+
+// sumDegrees returns the sum of the degrees of the days
+func sumDegrees(days []DailyTemperature, degrees func(DailyTemperature) float64) float64 {
+	sum := 0.0
+	for _, d := range days {
+		sum += degrees(d)
+	}
+	return sum
+}
+
+// DailyTemperaturesFromObservations returns the high and low of the observed
+// temperatures by day in the location, ex. of StationObservations, in unit F
+// or C. Temperatures which failed quality control are skipped. The extremes
+// of the observations can miss the true high and low by a degree or so
+// between hourly observations, and days only partly observed, like today, are
+// included: see Samples.
+func DailyTemperaturesFromObservations(observations []Observation, loc *time.Location, unit string) []DailyTemperature {
+	var days dailyExtremes
+	for _, o := range observations {
+		if o.Temperature.IsMissing() || !o.Temperature.PassedQualityControl() {
+			continue
+		}
+		days.add(o.Timestamp.In(loc), o.Temperature.Value, o.Temperature.UnitCode, unit)
+	}
+	return days.list()
+}
+
+// DailyTemperaturesFromForecast returns the forecast highs and lows by day in
+// unit F or C, each daytime period paired with the night after it. Days
+// without both, like the first day of a forecast issued in the evening, are
+// skipped.
+func DailyTemperaturesFromForecast(f *ForecastResponse, unit string) []DailyTemperature {
+	var days []DailyTemperature
+	for i := 0; i+1 < len(f.Periods); i++ {
+		day, night := f.Periods[i], f.Periods[i+1]
+		if !day.IsDaytime || night.IsDaytime {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, day.StartTime)
+		if err != nil {
+			continue
+		}
+		high, _ := degreeLocale(unit).Convert(day.Temperature, day.TemperatureUnit)
+		low, _ := degreeLocale(unit).Convert(night.Temperature, night.TemperatureUnit)
+		days = append(days, DailyTemperature{Date: midnight(start), High: high, Low: low, Unit: unit, Samples: 2})
+	}
+	return days
+}
+
+// DailyTemperaturesFromHourly returns the high and low of the hourly forecast
+// by day, in the time zone of the forecast, in unit F or C.
+func DailyTemperaturesFromHourly(f *HourlyForecastResponse, unit string) []DailyTemperature {
+	var days dailyExtremes
+	for _, p := range f.Periods {
+		start, err := time.Parse(time.RFC3339, p.StartTime)
+		if err != nil {
+			continue
+		}
+		days.add(start, p.Temperature, p.TemperatureUnit, unit)
+	}
+	return days.list()
+}
+
+// DailyTemperaturesFromSeries returns the high and low by day in the location
+// of a temperature series of a gridpoint forecast, ex. Temperature, in unit F
+// or C. Values valid for several hours count for each hour.
+func DailyTemperaturesFromSeries(s GridpointForecastTimeSeries, loc *time.Location, unit string) []DailyTemperature {
+	var days dailyExtremes
+	for _, v := range s.Values {
+		start, d, err := v.Interval()
+		if err != nil {
+			continue
+		}
+		for t := start; t == start || t.Before(start.Add(d)); t = t.Add(time.Hour) {
+			days.add(t.In(loc), v.Value, s.Uom, unit)
+		}
+	}
+	return days.list()
+}
+
+// dailyExtremes accumulates the highs and lows of temperatures by date, ex.
+// 2023-07-04, since times parsed with the same offset have distinct locations
+type dailyExtremes map[string]*DailyTemperature
+
+// add adds a temperature at t in unitCode converted to unit
+func (e *dailyExtremes) add(t time.Time, value float64, unitCode string, unit string) {
+	if *e == nil {
+		*e = dailyExtremes{}
+	}
+	value, _ = degreeLocale(unit).Convert(value, unitCode)
+	date := t.Format("2006-01-02")
+	d, ok := (*e)[date]
+	if !ok {
+		d = &DailyTemperature{Date: midnight(t), High: value, Low: value, Unit: unit}
+		(*e)[date] = d
+	}
+	d.High = math.Max(d.High, value)
+	d.Low = math.Min(d.Low, value)
+	d.Samples++
+}
+
+// list returns the days in order
+func (e dailyExtremes) list() []DailyTemperature {
+	days := make([]DailyTemperature, 0, len(e))
+	for _, d := range e {
+		days = append(days, *d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	return days
+}
+
+// degreeLocale returns the locale converting temperatures to unit, F or C
+func degreeLocale(unit string) Locale {
+	if unit == "C" {
+		return LocaleSI
+	}
+	return LocaleUS
+}
+
+// midnight returns the start of the day of t in its location
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
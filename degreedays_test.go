@@ -0,0 +1,103 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestDegreeDays(t *testing.T) {
+	days := []noaa.DailyTemperature{
+		{High: 50, Low: 30, Unit: "F"}, // mean 40
+		{High: 90, Low: 70, Unit: "F"}, // mean 80
+		{High: 90, Low: 45, Unit: "F"}, // mean 67.5
+	}
+	if got := noaa.HeatingDegreeDays(days, noaa.DegreeDayBaseF); got != 25 {
+		t.Errorf("noaa.HeatingDegreeDays() should be 25, got %v", got)
+	}
+	if got := noaa.CoolingDegreeDays(days, noaa.DegreeDayBaseF); got != 17.5 {
+		t.Errorf("noaa.CoolingDegreeDays() should be 17.5, got %v", got)
+	}
+	// 0 + (86+70)/2-50 + (86+50)/2-50
+	if got := noaa.GrowingDegreeDays(days, noaa.GrowingDegreeDayBaseF, noaa.GrowingDegreeDayCapF); got != 46 {
+		t.Errorf("noaa.GrowingDegreeDays() should be 46, got %v", got)
+	}
+	if got := days[1].GrowingDegrees(noaa.GrowingDegreeDayBaseF, 0); got != 30 {
+		t.Errorf("noaa.DailyTemperature.GrowingDegrees() should not cap highs without an upper limit, got %v", got)
+	}
+}
+
+func TestDailyTemperaturesFromObservations(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip(err)
+	}
+	at := func(hour int) time.Time { return time.Date(2023, 1, 10, hour, 0, 0, 0, time.UTC) }
+	celsius := func(v float64, qc string) noaa.ObservationValue {
+		return noaa.ObservationValue{Value: v, UnitCode: "wmoUnit:degC", QualityControl: qc}
+	}
+	observations := []noaa.Observation{
+		{Timestamp: at(3), Temperature: celsius(-5, "V")},   // January 9 in Chicago
+		{Timestamp: at(12), Temperature: celsius(-10, "V")}, // January 10
+		{Timestamp: at(20), Temperature: celsius(0, "V")},
+		{Timestamp: at(21), Temperature: celsius(40, "X")},
+	}
+	days := noaa.DailyTemperaturesFromObservations(observations, chicago, "F")
+	if len(days) != 2 {
+		t.Fatalf("noaa.DailyTemperaturesFromObservations() should return 2 days, got %v", days)
+	}
+	if days[0].Date.Day() != 9 || days[0].Samples != 1 || days[1].Date.Day() != 10 || days[1].Samples != 2 {
+		t.Errorf("noaa.DailyTemperaturesFromObservations() should group by local day, got %v", days)
+	}
+	if days[1].High != 32 || days[1].Low != 14 || days[1].Unit != "F" {
+		t.Errorf("noaa.DailyTemperaturesFromObservations() should convert to F and skip rejected values, got %+v", days[1])
+	}
+	if got := noaa.HeatingDegreeDays(days[1:], noaa.DegreeDayBaseF); got != 42 {
+		t.Errorf("noaa.HeatingDegreeDays() should be 42, got %v", got)
+	}
+}
+
+func TestDailyTemperaturesFromForecasts(t *testing.T) {
+	forecast := &noaa.ForecastResponse{Periods: []noaa.ForecastResponsePeriod{
+		{StartTime: "2023-07-04T18:00:00-05:00", IsDaytime: false, Temperature: 70, TemperatureUnit: "F"},
+		{StartTime: "2023-07-05T06:00:00-05:00", IsDaytime: true, Temperature: 86, TemperatureUnit: "F"},
+		{StartTime: "2023-07-05T18:00:00-05:00", IsDaytime: false, Temperature: 68, TemperatureUnit: "F"},
+		{StartTime: "2023-07-06T06:00:00-05:00", IsDaytime: true, Temperature: 90, TemperatureUnit: "F"},
+	}}
+	days := noaa.DailyTemperaturesFromForecast(forecast, "C")
+	if len(days) != 1 || days[0].Date.Day() != 5 || days[0].High != 30 || days[0].Low != 20 {
+		t.Errorf("noaa.DailyTemperaturesFromForecast() should pair days with the following nights, got %+v", days)
+	}
+	if got := noaa.CoolingDegreeDays(days, noaa.DegreeDayBaseC); got != 7 {
+		t.Errorf("noaa.CoolingDegreeDays() should be 7, got %v", got)
+	}
+
+	hourly := &noaa.HourlyForecastResponse{}
+	for i, temperature := range []float64{75, 80, 72, 66} {
+		var p noaa.ForecastResponsePeriodHourly
+		p.StartTime = time.Date(2023, 7, 4, 21+i, 0, 0, 0, time.FixedZone("CDT", -5*3600)).Format(time.RFC3339)
+		p.Temperature, p.TemperatureUnit = temperature, "F"
+		hourly.Periods = append(hourly.Periods, p)
+	}
+	days = noaa.DailyTemperaturesFromHourly(hourly, "F")
+	if len(days) != 2 || days[0].High != 80 || days[0].Low != 72 || days[1].Samples != 1 || days[1].Date.Day() != 5 {
+		t.Errorf("noaa.DailyTemperaturesFromHourly() should group by day in the forecast time zone, got %+v", days)
+	}
+
+	series := noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{
+		{ValidTime: "2023-07-04T22:00:00+00:00/PT3H", Value: 25},
+		{ValidTime: "2023-07-05T01:00:00+00:00/PT1H", Value: 20},
+	}}
+	days = noaa.DailyTemperaturesFromSeries(series, time.UTC, "C")
+	if len(days) != 2 || days[0].Samples != 2 || days[1].Samples != 2 || days[1].Low != 20 || days[1].High != 25 {
+		t.Errorf("noaa.DailyTemperaturesFromSeries() should count each hour of the values, got %+v", days)
+	}
+	if got := math.Round(noaa.HeatingDegreeDays(days, 25) * 10); got != 25 {
+		t.Errorf("noaa.HeatingDegreeDays() should be 2.5, got %v", got/10)
+	}
+}
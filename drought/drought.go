@@ -0,0 +1,233 @@
+// Package drought reports the US Drought Monitor (USDM) category of a point
+// and aligns it with the precipitation deficit of the observations of the
+// nearest station, for agricultural dashboards:
+//
+//	// 2.5 mm/day is the normal precipitation of the season at the point
+//	report, err := drought.ForPoint(ctx, "41.837", "-87.685", 2.5)
+//	...
+//	fmt.Println(report.Category, report.Category.Description(), report.Deficit.Deficit)
+//
+// weather.gov does not serve the Drought Monitor. The weekly map is read from
+// the USDM GeoJSON service, see BaseURL. weather.gov keeps about a week of
+// observations, so the deficit covers the last 7 days.
+package drought
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// BaseURL is the URL of the USDM GeoJSON service.
+var BaseURL = "https://droughtmonitor.unl.edu/data/json"
+
+// DeficitPeriod is the period of observations of the deficit of ForPoint.
+const DeficitPeriod = 7 * 24 * time.Hour
+
+// Category is a Drought Monitor category.
+type Category int
+
+// Drought Monitor categories, from no drought to D4, exceptional drought
+const (
+	CategoryNone Category = iota
+	CategoryD0
+	CategoryD1
+	CategoryD2
+	CategoryD3
+	CategoryD4
+)
+
+var categoryNames = []string{"None", "D0", "D1", "D2", "D3", "D4"}
+
+var categoryDescriptions = []string{"No Drought", "Abnormally Dry", "Moderate Drought", "Severe Drought", "Extreme Drought", "Exceptional Drought"}
+
+// String returns the name of the category, ex. D2.
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categoryNames) {
+		return fmt.Sprintf("Category(%d)", int(c))
+	}
+	return categoryNames[c]
+}
+
+// Description returns the description of the category, ex. Severe Drought.
+func (c Category) Description() string {
+	if c < 0 || int(c) >= len(categoryDescriptions) {
+		return c.String()
+	}
+	return categoryDescriptions[c]
+}
+
+// CategoryAt returns the category of the current Drought Monitor map at
+// <lat,lon>. Points outside the drought areas are CategoryNone.
+func CategoryAt(ctx context.Context, lat string, lon string) (Category, error) {
+	c, err := noaa.ParseCoordinates(lat, lon)
+	if err != nil {
+		return CategoryNone, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", BaseURL+"/usdm_current.json", nil)
+	if err != nil {
+		return CategoryNone, err
+	}
+	req.Header.Set("User-Agent", noaa.GetConfig().UserAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return CategoryNone, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CategoryNone, fmt.Errorf("drought: %s", res.Status)
+	}
+	var r struct {
+		Features []struct {
+			Properties map[string]interface{} `json:"properties"`
+			Geometry   struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return CategoryNone, fmt.Errorf("drought: %w", err)
+	}
+	category := CategoryNone
+	for _, f := range r.Features {
+		dm, ok := f.Properties["DM"].(float64)
+		if !ok || Category(dm+1) <= category {
+			continue
+		}
+		polygons, err := parsePolygons(f.Geometry.Type, f.Geometry.Coordinates)
+		if err != nil {
+			return CategoryNone, fmt.Errorf("drought: D%d: %w", int(dm), err)
+		}
+		for _, p := range polygons {
+			if p.Contains(c) {
+				category = Category(dm + 1)
+				break
+			}
+		}
+	}
+	return category, nil
+}
+
+// parsePolygons returns the polygons of the coordinates of a GeoJSON Polygon
+// or MultiPolygon
+func parsePolygons(kind string, coordinates json.RawMessage) ([]noaa.Polygon, error) {
+	var rings [][][][2]float64
+	switch kind {
+	case "Polygon":
+		var polygon [][][2]float64
+		if err := json.Unmarshal(coordinates, &polygon); err != nil {
+			return nil, err
+		}
+		rings = append(rings, polygon)
+	case "MultiPolygon":
+		if err := json.Unmarshal(coordinates, &rings); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", kind)
+	}
+	polygons := make([]noaa.Polygon, len(rings))
+	for i, polygon := range rings {
+		for _, ring := range polygon {
+			r := make([]noaa.Coordinates, len(ring))
+			for j, position := range ring {
+				r[j] = noaa.Coordinates{Lon: position[0], Lat: position[1]}
+			}
+			polygons[i] = append(polygons[i], r)
+		}
+	}
+	return polygons, nil
+}
+
+// Deficit is the precipitation observed during a period compared to the
+// normal precipitation of the period, in mm.
+type Deficit struct {
+	Start    time.Time // time of the first observation
+	End      time.Time // time of the last observation
+	Hours    int       // number of hourly precipitation amounts observed
+	Observed float64
+	Normal   float64
+	Deficit  float64 // Normal minus Observed, negative for a surplus
+}
+
+// Percent returns the observed precipitation in percent of normal, or 100 if
+// the normal is 0.
+func (d Deficit) Percent() float64 {
+	if d.Normal == 0 {
+		return 100
+	}
+	return 100 * d.Observed / d.Normal
+}
+
+// PrecipitationDeficit returns the deficit of the hourly precipitation of the
+// routine observations, ex. of noaa.StationObservations, compared to
+// normalPerDay mm a day during the period observed. Amounts which failed
+// quality control are skipped.
+func PrecipitationDeficit(observations []noaa.Observation, normalPerDay float64) Deficit {
+	var d Deficit
+	for _, o := range observations {
+		v := o.PrecipitationLastHour
+		if v.IsMissing() || !v.PassedQualityControl() {
+			continue
+		}
+		amount := v.Value
+		if strings.TrimPrefix(v.UnitCode, "wmoUnit:") == "m" {
+			amount *= 1000
+		}
+		d.Observed += amount
+		d.Hours++
+		if d.Start.IsZero() || o.Timestamp.Before(d.Start) {
+			d.Start = o.Timestamp
+		}
+		if o.Timestamp.After(d.End) {
+			d.End = o.Timestamp
+		}
+	}
+	d.Normal = normalPerDay * float64(d.Hours) / 24
+	d.Deficit = d.Normal - d.Observed
+	return d
+}
+
+// Report is the Drought Monitor category of a point with the precipitation
+// deficit at the nearest station.
+type Report struct {
+	Category Category
+	Station  string // ID of the observation station, ex. KMDW
+	Deficit  Deficit
+}
+
+// ForPoint returns the Drought Monitor category of <lat,lon> and the
+// precipitation deficit of the last 7 days at the nearest station reporting
+// precipitation, compared to normalPerDay mm a day, ex. the climate normal
+// of the month. The report has the category if no station near the point
+// reports precipitation, with an error.
+func ForPoint(ctx context.Context, lat string, lon string, normalPerDay float64) (*Report, error) {
+	category, err := CategoryAt(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	stations, err := noaa.NearestStations(lat, lon, 3)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Category: category}
+	start := time.Now().Add(-DeficitPeriod)
+	for _, s := range stations {
+		observations, err := noaa.StationObservations(s.ID, noaa.ObservationsOptions{Start: start})
+		if err != nil {
+			return nil, err
+		}
+		if d := PrecipitationDeficit(observations, normalPerDay); d.Hours > 0 {
+			report.Station, report.Deficit = s.ID, d
+			return report, nil
+		}
+	}
+	return report, errors.New("drought: no station near the point reports precipitation")
+}
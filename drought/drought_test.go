@@ -0,0 +1,67 @@
+package drought_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/drought"
+)
+
+func TestForPoint(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Hour)
+	var observations []string
+	for i := 0; i < 48; i++ {
+		observations = append(observations, fmt.Sprintf(`{"timestamp": "%s", "precipitationLastHour": {"value": 0.5, "unitCode": "wmoUnit:mm", "qualityControl": "V"}}`,
+			now.Add(-time.Duration(i)*time.Hour).Format(time.RFC3339)))
+	}
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/usdm_current.json":
+			fmt.Fprint(w, `{"type": "FeatureCollection", "features": [
+				{"type": "Feature", "properties": {"DM": 0}, "geometry": {"type": "MultiPolygon", "coordinates": [[[[-89, 41], [-86, 41], [-86, 43], [-89, 43], [-89, 41]]]]}},
+				{"type": "Feature", "properties": {"DM": 2}, "geometry": {"type": "Polygon", "coordinates": [[[-88, 41.5], [-87, 41.5], [-87, 42], [-88, 42], [-88, 41.5]]]}},
+				{"type": "Feature", "properties": {"DM": 4}, "geometry": {"type": "Polygon", "coordinates": [[[-100, 30], [-99, 30], [-99, 31], [-100, 30]]]}}
+			]}`)
+		case "/points/41.837,-87.685":
+			fmt.Fprintf(w, `{"observationStations": "%s/gridpoints/LOT/76,73/stations"}`, api.URL)
+		case "/gridpoints/LOT/76,73/stations":
+			fmt.Fprintf(w, `{"@graph": [{"@id": "%s/stations/KMDW", "stationIdentifier": "KMDW", "geometry": "POINT(-87.75222 41.78417)"}]}`, api.URL)
+		case "/stations/KMDW/observations":
+			fmt.Fprintf(w, `{"@graph": [%s]}`, strings.Join(observations, ", "))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client, baseURL := http.DefaultClient, drought.BaseURL
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient, drought.BaseURL = client, baseURL
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	drought.BaseURL = api.URL
+
+	report, err := drought.ForPoint(context.Background(), "41.837", "-87.685", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Category != drought.CategoryD2 || report.Category.Description() != "Severe Drought" {
+		t.Errorf("expected the highest category containing the point, got %v", report.Category)
+	}
+	d := report.Deficit
+	if report.Station != "KMDW" || d.Hours != 48 || d.Observed != 24 || d.Normal != 6 || d.Deficit != -18 || d.Percent() != 400 {
+		t.Errorf("expected a surplus of 18 mm over 2 days, got %+v", d)
+	}
+
+	if c, err := drought.CategoryAt(context.Background(), "35", "-80"); err != nil || c != drought.CategoryNone {
+		t.Errorf("expected no drought outside the areas, got %v, %v", c, err)
+	}
+}
@@ -0,0 +1,88 @@
+package noaa
+
+import (
+	"fmt"
+	"math"
+)
+
+// StandardLapseRate is the average decrease of temperature with height in the
+// lower atmosphere, in °C per km.
+const StandardLapseRate = 6.5
+
+// ElevationAdjustment adjusts forecast temperatures from the elevation of the
+// grid cell to the elevation of a site using a lapse rate, ex. for a cabin on a
+// ridge 400 m above the average elevation of its grid cell, which is about
+// 2.6 °C colder with the standard lapse rate. The adjustment is a rough
+// estimate: inversions, common on clear nights in valleys, invert it.
+type ElevationAdjustment struct {
+	GridElevation float64 // meters
+	SiteElevation float64 // meters
+	LapseRate     float64 // °C per km, 0 for StandardLapseRate
+}
+
+// NewElevationAdjustment returns the adjustment from the elevation of a
+// forecast, ex. forecast.Elevation, to a site elevation in meters. The
+// forecast elevation can be in meters or feet.
+func NewElevationAdjustment(forecast ForecastElevation, siteElevation float64) (ElevationAdjustment, error) {
+	grid := forecast.Value
+	switch UnitName(forecast.Units) {
+	case "m":
+	case "ft":
+		grid *= 0.3048
+	default:
+		return ElevationAdjustment{}, fmt.Errorf("unsupported elevation unit %q", forecast.Units)
+	}
+	return ElevationAdjustment{GridElevation: grid, SiteElevation: siteElevation}, nil
+}
+
+// Delta returns the temperature change at the site in the unit, F or C as in
+// forecast periods or a wmoUnit code as in gridpoint series, ex. -2.6 for C
+// at a site 400 m above the grid cell.
+func (a ElevationAdjustment) Delta(unit string) float64 {
+	rate := a.LapseRate
+	if rate == 0 {
+		rate = StandardLapseRate
+	}
+	delta := -(a.SiteElevation - a.GridElevation) / 1000 * rate
+	switch UnitName(unit) {
+	case "F", "degF":
+		return delta * 9 / 5
+	}
+	return delta
+}
+
+// Forecast returns a copy of the forecast with the period temperatures
+// adjusted to the site, rounded to whole degrees like the forecast.
+func (a ElevationAdjustment) Forecast(f *ForecastResponse) *ForecastResponse {
+	adjusted := *f
+	adjusted.Periods = make([]ForecastResponsePeriod, len(f.Periods))
+	for i, p := range f.Periods {
+		p.Temperature = math.Round(p.Temperature + a.Delta(p.TemperatureUnit))
+		adjusted.Periods[i] = p
+	}
+	return &adjusted
+}
+
+// Hourly returns a copy of the hourly forecast with the period temperatures
+// adjusted to the site, rounded to whole degrees like the forecast.
+func (a ElevationAdjustment) Hourly(f *HourlyForecastResponse) *HourlyForecastResponse {
+	adjusted := *f
+	adjusted.Periods = make([]ForecastResponsePeriodHourly, len(f.Periods))
+	for i, p := range f.Periods {
+		p.Temperature = math.Round(p.Temperature + a.Delta(p.TemperatureUnit))
+		adjusted.Periods[i] = p
+	}
+	return &adjusted
+}
+
+// Series returns a copy of a temperature series of a gridpoint forecast, ex.
+// Temperature, MaxTemperature or ApparentTemperature, adjusted to the site.
+func (a ElevationAdjustment) Series(s GridpointForecastTimeSeries) GridpointForecastTimeSeries {
+	adjusted := GridpointForecastTimeSeries{Uom: s.Uom, Values: make([]GridpointForecastTimeSeriesValue, len(s.Values))}
+	delta := a.Delta(s.Uom)
+	for i, v := range s.Values {
+		v.Value += delta
+		adjusted.Values[i] = v
+	}
+	return adjusted
+}
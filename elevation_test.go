@@ -0,0 +1,46 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestElevationAdjustment(t *testing.T) {
+	adjustment, err := noaa.NewElevationAdjustment(noaa.ForecastElevation{Value: 1600, Units: "wmoUnit:m"}, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := adjustment.Delta("C"); math.Abs(d+2.6) > 1e-9 {
+		t.Errorf("noaa.ElevationAdjustment.Delta() should use the standard lapse rate, got %v", d)
+	}
+	if d := adjustment.Delta("wmoUnit:degF"); math.Abs(d+4.68) > 1e-9 {
+		t.Errorf("noaa.ElevationAdjustment.Delta() should convert the change to °F, got %v", d)
+	}
+
+	forecast := &noaa.ForecastResponse{Periods: []noaa.ForecastResponsePeriod{{Temperature: 72, TemperatureUnit: "F"}, {Temperature: 10, TemperatureUnit: "C"}}}
+	adjusted := adjustment.Forecast(forecast)
+	if adjusted.Periods[0].Temperature != 67 || adjusted.Periods[1].Temperature != 7 {
+		t.Errorf("noaa.ElevationAdjustment.Forecast() should adjust the period temperatures, got %+v", adjusted.Periods)
+	}
+	if forecast.Periods[0].Temperature != 72 {
+		t.Error("noaa.ElevationAdjustment.Forecast() should not change the forecast")
+	}
+
+	series := noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{{Value: 20}}}
+	if s := adjustment.Series(series); math.Abs(s.Values[0].Value-17.4) > 1e-9 || series.Values[0].Value != 20 {
+		t.Errorf("noaa.ElevationAdjustment.Series() should adjust a copy of the series, got %+v", s)
+	}
+
+	feet, err := noaa.NewElevationAdjustment(noaa.ForecastElevation{Value: 1000, Units: "wmoUnit:ft"}, 304.8)
+	if err != nil || feet.GridElevation != 304.8 || feet.Delta("C") != 0 {
+		t.Errorf("noaa.NewElevationAdjustment() should convert feet, got %+v, %v", feet, err)
+	}
+	if _, err := noaa.NewElevationAdjustment(noaa.ForecastElevation{Value: 1, Units: "wmoUnit:km"}, 0); err == nil {
+		t.Error("noaa.NewElevationAdjustment() should reject unsupported units")
+	}
+}
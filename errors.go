@@ -0,0 +1,163 @@
+package noaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrDataUnavailable is wrapped by the error returned when weather.gov reports
+// that the data is temporarily unavailable, which happens for gridpoint
+// endpoints while the grid data is being regenerated. The request can be
+// retried later, see SetRetries.
+var ErrDataUnavailable = errors.New("data temporarily unavailable")
+
+// ErrTooManyRedirects is wrapped by the error returned when a request is
+// redirected more than the configured maximum, see SetMaxRedirects.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// ErrResponseTooLarge is wrapped by the error returned when a response body is
+// larger than the configured maximum, see SetResponseLimits.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ErrReadTimeout is wrapped by the error returned when reading a response body
+// takes longer than the configured read timeout, see SetResponseLimits.
+var ErrReadTimeout = errors.New("response read timeout")
+
+// ErrOutOfCoverage is wrapped by the error returned when weather.gov does not
+// know a point, ex. coordinates outside the United States and its territories.
+// Such points never resolve, so the error is cached, see SetOutOfCoverageTTL.
+var ErrOutOfCoverage = errors.New("point out of coverage")
+
+// ErrUnexpectedContentType is wrapped by the error returned when a response
+// is not in the requested format, ex. an HTML error page served by a proxy
+// in front of weather.gov, see ContentTypeError.
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// ContentTypeError is returned when the Content-Type of a successful response
+// does not match the Accept header of the request, instead of the error of
+// decoding the body. Snippet is the start of the body, to tell what was
+// served.
+type ContentTypeError struct {
+	Endpoint      string
+	Accept        string
+	ContentType   string
+	Snippet       string
+	CorrelationID string
+}
+
+func (e *ContentTypeError) Error() string {
+	msg := fmt.Sprintf("%s %s, requested %s", ErrUnexpectedContentType, e.ContentType, e.Accept)
+	if e.Snippet != "" {
+		msg += fmt.Sprintf(": %q", e.Snippet)
+	}
+	if e.CorrelationID != "" {
+		msg += " (correlation ID " + e.CorrelationID + ")"
+	}
+	return msg
+}
+
+// Unwrap returns ErrUnexpectedContentType.
+func (e *ContentTypeError) Unwrap() error {
+	return ErrUnexpectedContentType
+}
+
+// APIError is returned when weather.gov responds with an error status. Type,
+// Title and Detail are set from the problem details of the response, if any.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Status     string `json:"-"`
+	Endpoint   string `json:"-"`
+	// CorrelationID is the correlation ID of the request, see
+	// WithCorrelationID.
+	CorrelationID string `json:"-"`
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Detail        string `json:"detail"`
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%d %s", e.StatusCode, e.Status)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.CorrelationID != "" {
+		msg += " (correlation ID " + e.CorrelationID + ")"
+	}
+	return msg
+}
+
+// Unwrap returns ErrDataUnavailable if the error is one of the server errors
+// weather.gov returns while data is being regenerated, or ErrOutOfCoverage if
+// the point of a points request is unknown.
+func (e *APIError) Unwrap() error {
+	if e.dataUnavailable() {
+		return ErrDataUnavailable
+	}
+	if e.outOfCoverage() {
+		return ErrOutOfCoverage
+	}
+	return nil
+}
+
+// outOfCoverage reports whether the problem is an unknown point, ex.
+//
+//	{"type": "https://api.weather.gov/problems/InvalidPoint",
+//	 "title": "Invalid Point", "status": 404,
+//	 "detail": "Unable to provide data for requested point 51.5,-0.12"}
+func (e *APIError) outOfCoverage() bool {
+	if e.StatusCode != http.StatusNotFound {
+		return false
+	}
+	return strings.HasSuffix(e.Type, "/InvalidPoint") || strings.Contains(e.Endpoint, "/points/")
+}
+
+// dataUnavailable reports whether the problem is temporary missing data, ex.
+//
+//	{"type": "https://api.weather.gov/problems/UnexpectedProblem",
+//	 "title": "Unexpected Problem", "status": 500,
+//	 "detail": "An unexpected problem has occurred. ... data is unavailable"}
+//
+// or a ForecastGridDataUnavailable problem
+func (e *APIError) dataUnavailable() bool {
+	if e.StatusCode < http.StatusInternalServerError {
+		return false
+	}
+	if strings.HasSuffix(e.Type, "/ForecastGridDataUnavailable") || strings.HasSuffix(e.Type, "/DataUnavailable") {
+		return true
+	}
+	text := strings.ToLower(e.Title + " " + e.Detail)
+	return strings.Contains(text, "unavailable") || strings.Contains(text, "not available")
+}
+
+// IsNotFound reports whether the error is a 404 response, ex. for a point
+// outside the forecast grids or an unknown station.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether the error is a 429 response, returned when
+// requests exceed the weather.gov rate limit. Requests can be retried after a
+// few seconds.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsTimeout reports whether the request timed out, see Config.Timeout and
+// Config.ReadTimeout, or its context deadline passed.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrReadTimeout) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// hasStatus reports whether the error is an APIError with the status code
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
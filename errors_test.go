@@ -0,0 +1,252 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestDataUnavailableRetry(t *testing.T) {
+	var requests int
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/41.837,-87.685":
+			fmt.Fprintf(w, `{"forecastGridData": "%s/gridpoints/LOT/76,73"}`, api.URL)
+		case "/gridpoints/LOT/76,73":
+			requests++
+			if requests < 3 {
+				w.Header().Set("Content-Type", "application/problem+json")
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"type": "https://api.weather.gov/problems/ForecastGridDataUnavailable", "title": "Forecast Grid Data Unavailable", "status": 500, "detail": "Forecast grid data is not currently available"}`)
+				return
+			}
+			fmt.Fprint(w, `{"updateTime": "2023-07-04T18:00:00+00:00"}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	_, err := noaa.GridpointForecast("41.837", "-87.685")
+	var apiErr *noaa.APIError
+	if !errors.Is(err, noaa.ErrDataUnavailable) || !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+		t.Fatalf("noaa.GridpointForecast() should return ErrDataUnavailable, got %v", err)
+	}
+
+	noaa.SetRetries(2, time.Millisecond)
+	forecast, err := noaa.GridpointForecast("41.837", "-87.685")
+	if err != nil {
+		t.Fatalf("noaa.GridpointForecast() should succeed after retrying, got %v", err)
+	}
+	if forecast.Updated == "" || requests != 3 {
+		t.Errorf("noaa.GridpointForecast() should retry until the data is available, got %d requests", requests)
+	}
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host != "example.com":
+			// redirect to another host
+			http.Redirect(w, r, "https://example.com"+r.URL.Path, http.StatusMovedPermanently)
+		case r.URL.Path == "/offices/LOOP":
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+		case r.Header.Get("User-Agent") != "(test, test@example.com)" || r.Header.Get("Accept") != noaa.APIAccept:
+			http.Error(w, "missing headers", http.StatusForbidden)
+		default:
+			fmt.Fprint(w, `{"@id": "https://api.weather.gov/offices/LOT", "name": "Chicago, IL"}`)
+		}
+	}))
+	var redirects int
+	var policyErr error
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	http.DefaultClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		redirects++
+		return policyErr
+	}
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	noaa.SetUserAgent("(test, test@example.com)")
+
+	office, err := noaa.Office("LOT")
+	if err != nil {
+		t.Fatalf("noaa.Office() should keep the headers across redirects, got %v", err)
+	}
+	if office.Name != "Chicago, IL" {
+		t.Errorf("noaa.Office() should follow the redirect, got %+v", office)
+	}
+	if redirects != 1 {
+		t.Errorf("noaa.Office() should apply the redirect policy of the client, got %d calls", redirects)
+	}
+	policyErr = errors.New("redirects not allowed")
+	if _, err := noaa.Office("LOT"); !errors.Is(err, policyErr) {
+		t.Errorf("noaa.Office() should return the error of the client redirect policy, got %v", err)
+	}
+	policyErr = nil
+
+	noaa.SetMaxRedirects(3)
+	_, err = noaa.Office("LOOP")
+	if !errors.Is(err, noaa.ErrTooManyRedirects) {
+		t.Errorf("noaa.Office() should return ErrTooManyRedirects, got %v", err)
+	}
+}
+
+func TestResponseLimits(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/offices/BIG":
+			w.Header().Set("Content-Length", "4096")
+			fmt.Fprintf(w, `{"name": "%s"}`, strings.Repeat("x", 4096-12))
+		case "/offices/STREAM":
+			// chunked, without a Content-Length
+			fmt.Fprint(w, `{"name": "`)
+			w.(http.Flusher).Flush()
+			fmt.Fprintf(w, `%s"}`, strings.Repeat("x", 4096))
+		case "/offices/SLOW":
+			fmt.Fprint(w, `{"name": `)
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			fmt.Fprint(w, `"Slow"}`)
+		default:
+			fmt.Fprint(w, `{"name": "Chicago, IL"}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	noaa.SetResponseLimits(1024, 100*time.Millisecond)
+
+	if office, err := noaa.Office("LOT"); err != nil || office.Name != "Chicago, IL" {
+		t.Errorf("noaa.Office() should return responses within the limits, got %v, %v", office, err)
+	}
+	for _, id := range []string{"BIG", "STREAM"} {
+		if _, err := noaa.Office(id); !errors.Is(err, noaa.ErrResponseTooLarge) {
+			t.Errorf("noaa.Office(%q) should return ErrResponseTooLarge, got %v", id, err)
+		}
+	}
+	start := time.Now()
+	if _, err := noaa.Office("SLOW"); !errors.Is(err, noaa.ErrReadTimeout) {
+		t.Errorf("noaa.Office() should return ErrReadTimeout, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("noaa.Office() should stop reading at the read timeout, took %s", time.Since(start))
+	}
+}
+
+func TestUnexpectedContentType(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/offices/LOT":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, "<!DOCTYPE html>\n<html>\n  <title>Service Unavailable</title>\n</html>")
+		case "/offices/MPX":
+			w.Header().Set("Content-Type", "application/geo+json")
+			fmt.Fprint(w, `{"id": "MPX"}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	_, err := noaa.Office("LOT")
+	var typeErr *noaa.ContentTypeError
+	if !errors.Is(err, noaa.ErrUnexpectedContentType) || !errors.As(err, &typeErr) {
+		t.Fatalf("noaa.Office() should return ErrUnexpectedContentType for an HTML page, got %v", err)
+	}
+	if typeErr.Snippet != "<!DOCTYPE html> <html> <title>Service Unavailable</title> </html>" || !strings.HasPrefix(typeErr.ContentType, "text/html") {
+		t.Errorf("noaa.ContentTypeError should include the start of the body, got %+v", typeErr)
+	}
+	if office, err := noaa.Office("MPX"); err != nil || office.ID != "MPX" {
+		t.Errorf("noaa.Office() should accept GeoJSON for a JSON-LD request, got %+v, %v", office, err)
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/offices/MISSING":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"title": "Not Found", "status": 404, "detail": "Office MISSING not found"}`)
+		case "/offices/BUSY":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"title": "Too Many Requests", "status": 429}`)
+		case "/offices/SLOW":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	config := noaa.GetConfig()
+	config.BaseURL = api.URL
+	config.Timeout = 100 * time.Millisecond
+	noaa.SetConfig(config)
+
+	_, err := noaa.Office("MISSING")
+	if !noaa.IsNotFound(err) || noaa.IsRateLimited(err) || noaa.IsTimeout(err) {
+		t.Errorf("noaa.IsNotFound() should only match 404 responses, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "GET "+api.URL+"/offices/MISSING (attempt 1)") {
+		t.Errorf("noaa.Office() should wrap errors with the request, got %v", err)
+	}
+	var apiErr *noaa.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("noaa.Office() should wrap the APIError, got %v", err)
+	}
+
+	_, err = noaa.Office("BUSY")
+	if !noaa.IsRateLimited(err) || noaa.IsNotFound(err) {
+		t.Errorf("noaa.IsRateLimited() should match 429 responses, got %v", err)
+	}
+
+	_, err = noaa.Office("SLOW")
+	if !noaa.IsTimeout(err) || noaa.IsNotFound(err) {
+		t.Errorf("noaa.IsTimeout() should match timed out requests, got %v", err)
+	}
+	if noaa.IsTimeout(errors.New("timeout")) || noaa.IsNotFound(nil) {
+		t.Error("noaa.IsTimeout() and noaa.IsNotFound() should not match other errors")
+	}
+}
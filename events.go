@@ -0,0 +1,219 @@
+package noaa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Event is published on a Bus when data is updated. The concrete types are
+// ForecastUpdated, ObservationReceived and AlertIssued.
+type Event interface {
+	EventType() string
+}
+
+// ForecastUpdated is published when a forecast is fetched. Location only has
+// the coordinates set when the forecast was fetched by Forecast directly.
+type ForecastUpdated struct {
+	Location Location
+	Forecast *ForecastResponse
+}
+
+// ObservationReceived is published when an observation is fetched. Location is
+// empty when the observation was fetched by LatestStationObservation directly.
+type ObservationReceived struct {
+	Location    Location
+	Observation Observation
+	Backfill    bool // true if published by the backfill of a Poller, see Poller.Backfill
+}
+
+// AlertIssued is published by a Poller when an alert event is issued, updated
+// or ended.
+type AlertIssued struct {
+	Location Location
+	Event    AlertEvent
+	Backfill bool // true if published by the backfill of a Poller, see Poller.Backfill
+}
+
+// EventType implements Event.
+func (ForecastUpdated) EventType() string { return "ForecastUpdated" }
+
+// EventType implements Event.
+func (ObservationReceived) EventType() string { return "ObservationReceived" }
+
+// EventType implements Event.
+func (AlertIssued) EventType() string { return "AlertIssued" }
+
+// Bus delivers published events to its subscribers. The handlers of a Bus
+// returned by NewBus are called in the publishing goroutine in the order they
+// subscribed, so they should return quickly; use Channel to process events in
+// another goroutine, or NewAsyncBus. A Bus is safe for concurrent use.
+type Bus struct {
+	mu       sync.RWMutex
+	next     int
+	handlers map[int]func(Event)
+	order    []int
+	queue    int // events queued per subscriber of an async bus, 0 if synchronous
+}
+
+// DefaultBus receives the events published by the fetch functions, ex.
+// Forecast, and by pollers without a Bus. It is an async bus, see NewAsyncBus,
+// so a slow subscriber does not delay the fetch functions.
+var DefaultBus = NewAsyncBus(100)
+
+// NewBus returns a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{handlers: map[int]func(Event){}}
+}
+
+// NewAsyncBus returns a Bus without subscribers which calls each handler in a
+// goroutine of its own, in the order the events were published, so Publish
+// does not wait for the handlers. Up to queue events are queued per
+// subscriber, further events are dropped until the handler catches up, like
+// Channel. Events queued when a subscription is cancelled are still delivered.
+func NewAsyncBus(queue int) *Bus {
+	if queue <= 0 {
+		queue = 1
+	}
+	return &Bus{handlers: map[int]func(Event){}, queue: queue}
+}
+
+// Subscribe calls fn with every event published on DefaultBus until the
+// returned cancel function is called.
+func Subscribe(fn func(Event)) (cancel func()) {
+	return DefaultBus.Subscribe(fn)
+}
+
+// Publish delivers the event to all subscribers.
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	handlers := make([]func(Event), 0, len(b.order))
+	for _, id := range b.order {
+		handlers = append(handlers, b.handlers[id])
+	}
+	b.mu.RUnlock()
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
+
+// Subscribe calls fn with every published event until the returned cancel
+// function is called.
+func (b *Bus) Subscribe(fn func(Event)) (cancel func()) {
+	if b.queue > 0 {
+		events, cancel := b.Channel(b.queue)
+		go func() {
+			for e := range events {
+				fn(e)
+			}
+		}()
+		return cancel
+	}
+	return b.subscribe(fn)
+}
+
+// subscribe calls fn in the publishing goroutine
+func (b *Bus) subscribe(fn func(Event)) (cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.next
+	b.next++
+	b.handlers[id] = fn
+	b.order = append(b.order, id)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.handlers, id)
+			for i, v := range b.order {
+				if v == id {
+					b.order = append(b.order[:i:i], b.order[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Channel returns a channel receiving published events, buffered to hold size
+// events. Events are dropped when the buffer is full so a slow reader does not
+// block publishers. The channel is closed by the returned cancel function.
+func (b *Bus) Channel(size int) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, size)
+	var mu sync.Mutex
+	closed := false
+	unsubscribe := b.subscribe(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	})
+	return ch, func() {
+		unsubscribe()
+		mu.Lock()
+		defer mu.Unlock()
+		if !closed {
+			closed = true
+			close(ch)
+		}
+	}
+}
+
+// Webhook returns a handler which posts each event as JSON to url, ex.
+//
+//	{"type": "AlertIssued", "event": {"Location": {...}, "Event": {...}}}
+//
+// Failed requests are passed to onError, which may be nil. Requests are made
+// in the publishing goroutine, so a webhook is usually fed from Channel:
+//
+//	events, _ := noaa.DefaultBus.Channel(100)
+//	post := noaa.Webhook("https://example.com/hook", nil)
+//	go func() {
+//		for e := range events {
+//			post(e)
+//		}
+//	}()
+func Webhook(url string, onError func(Event, error)) func(Event) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	return func(e Event) {
+		err := postEvent(client, url, e)
+		if err != nil && onError != nil {
+			onError(e, err)
+		}
+	}
+}
+
+// postEvent posts the event to the webhook url
+func postEvent(client *http.Client, url string, e Event) error {
+	body, err := json.Marshal(struct {
+		Type  string `json:"type"`
+		Event Event  `json:"event"`
+	}{e.EventType(), e})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", currentConfig().UserAgent)
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", res.Status)
+	}
+	return nil
+}
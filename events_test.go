@@ -0,0 +1,102 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestBusSubscribe(t *testing.T) {
+	bus := noaa.NewBus()
+	var received []string
+	cancel := bus.Subscribe(func(e noaa.Event) { received = append(received, "first "+e.EventType()) })
+	bus.Subscribe(func(e noaa.Event) { received = append(received, "second "+e.EventType()) })
+	bus.Publish(noaa.ForecastUpdated{})
+	cancel()
+	cancel()
+	bus.Publish(noaa.AlertIssued{})
+	expected := []string{"first ForecastUpdated", "second ForecastUpdated", "second AlertIssued"}
+	if len(received) != len(expected) {
+		t.Fatalf("noaa.Bus.Publish() should deliver %v, got %v", expected, received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("noaa.Bus.Publish() should deliver %v, got %v", expected, received)
+		}
+	}
+}
+
+func TestBusChannel(t *testing.T) {
+	bus := noaa.NewBus()
+	events, cancel := bus.Channel(1)
+	bus.Publish(noaa.ObservationReceived{Location: noaa.Location{Name: "home"}})
+	bus.Publish(noaa.ObservationReceived{}) // dropped, the buffer is full
+	cancel()
+	bus.Publish(noaa.ObservationReceived{})
+	var n int
+	for e := range events {
+		if o, ok := e.(noaa.ObservationReceived); !ok || o.Location.Name != "home" {
+			t.Errorf("noaa.Bus.Channel() received an unexpected event %v", e)
+		}
+		n++
+	}
+	if n != 1 {
+		t.Errorf("noaa.Bus.Channel() should receive 1 event, got %d", n)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var body struct {
+		Type  string
+		Event noaa.AlertIssued
+	}
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer hook.Close()
+	noaa.Webhook(hook.URL, func(e noaa.Event, err error) {
+		t.Errorf("noaa.Webhook() should not fail: %v", err)
+	})(noaa.AlertIssued{Event: noaa.AlertEvent{Key: "KLOT.FA.A.0001"}})
+	if body.Type != "AlertIssued" || body.Event.Event.Key != "KLOT.FA.A.0001" {
+		t.Errorf("noaa.Webhook() posted an unexpected body %+v", body)
+	}
+}
+
+func TestAsyncBus(t *testing.T) {
+	bus := noaa.NewAsyncBus(10)
+	release := make(chan struct{})
+	received := make(chan string, 10)
+	bus.Subscribe(func(e noaa.Event) {
+		<-release // a slow subscriber
+		received <- e.EventType()
+	})
+	done := make(chan struct{})
+	go func() {
+		bus.Publish(noaa.ForecastUpdated{})
+		bus.Publish(noaa.AlertIssued{})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("noaa.Bus.Publish() should not wait for the subscribers of an async bus")
+	}
+	close(release)
+	for _, expected := range []string{"ForecastUpdated", "AlertIssued"} {
+		select {
+		case got := <-received:
+			if got != expected {
+				t.Errorf("noaa.Bus.Publish() should deliver %s, got %s", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("noaa.Bus.Publish() should deliver %s", expected)
+		}
+	}
+}
@@ -136,6 +136,38 @@ func ExampleGetChicagoHourlyForecast() {
 	// Success!
 }
 
+func ExampleForecastFormatter() {
+
+	// Cleanup global state before each example
+	beforeEachExample()
+
+	// Periods are normally obtained from noaa.Forecast or noaa.HourlyForecast
+	period := noaa.ForecastResponsePeriod{
+		Name:            "Tonight",
+		StartTime:       "2023-06-15T18:00:00-05:00",
+		Temperature:     59,
+		TemperatureUnit: "F",
+		WindSpeed:       "5 to 10 mph",
+		WindDirection:   "SW",
+		Summary:         "Partly Cloudy",
+	}
+
+	// The default English template can be replaced with SetPeriodTemplate.
+	formatter := noaa.NewForecastFormatter()
+	text, _ := formatter.FormatPeriod(period)
+	fmt.Println(text)
+
+	formatter.Units = "si"
+	formatter.Clock24 = true
+	formatter.SetPeriodTemplate("{{.Name}} from {{.Start}}: {{.Temperature}}, wind {{.Wind}}")
+	text, _ = formatter.FormatPeriod(period)
+	fmt.Println(text)
+
+	// Output:
+	// Tonight: Partly Cloudy, with a low of 59°F. Wind SW 5 to 10 mph.
+	// Tonight from 18:00: 15°C, wind SW 8 to 16 km/h
+}
+
 // beforeEachExample is used to clean up the global state of the noaa client
 // which is necessary because some global state is set at the module level
 func beforeEachExample() {
@@ -0,0 +1,55 @@
+package noaa
+
+import "time"
+
+// Thresholds of the feels like temperature in °F, as used by the NWS: the heat
+// index applies from 80 °F, and the wind chill at 50 °F and below with winds
+// of at least 3 mph.
+const (
+	heatIndexThreshold = 80.0
+	windChillThreshold = 50.0
+	windChillMinSpeed  = 3 * 1.609344 // km/h
+)
+
+// FeelsLike returns the temperature it feels like for each value of the
+// Temperature series, like weather apps display it: the heat index when hot,
+// the wind chill when cold and windy, and else the apparent temperature, or
+// the temperature itself when the series lack a value. Values are in the unit
+// of the Temperature series.
+func (g *GridpointForecastResponse) FeelsLike() GridpointForecastTimeSeries {
+	unit := UnitName(g.Temperature.Uom)
+	convert := func(v float64, uom string) float64 {
+		if unit == "degC" {
+			v, _ = LocaleSI.Convert(v, uom)
+		} else {
+			v, _ = LocaleUS.Convert(v, uom)
+		}
+		return v
+	}
+
+	feelsLike := GridpointForecastTimeSeries{Uom: g.Temperature.Uom, Values: make([]GridpointForecastTimeSeriesValue, 0, len(g.Temperature.Values))}
+	for _, v := range g.Temperature.Values {
+		start, _, err := v.Interval()
+		if err != nil {
+			continue
+		}
+		value := v.Value
+		fahrenheit, _ := LocaleUS.Convert(v.Value, g.Temperature.Uom)
+		if hi, ok := g.HeatIndex.At(start); ok && fahrenheit >= heatIndexThreshold {
+			value = convert(hi, g.HeatIndex.Uom)
+		} else if wc, ok := g.WindChill.At(start); ok && fahrenheit <= windChillThreshold && g.windy(start) {
+			value = convert(wc, g.WindChill.Uom)
+		} else if at, ok := g.ApparentTemperature.At(start); ok {
+			value = convert(at, g.ApparentTemperature.Uom)
+		}
+		feelsLike.Values = append(feelsLike.Values, GridpointForecastTimeSeriesValue{ValidTime: v.ValidTime, Value: value})
+	}
+	return feelsLike
+}
+
+// windy reports whether the wind speed at t is high enough for a wind chill,
+// true if unknown
+func (g *GridpointForecastResponse) windy(t time.Time) bool {
+	speed, ok := g.WindSpeed.At(t)
+	return !ok || toKilometersPerHour(speed, g.WindSpeed.Uom) >= windChillMinSpeed
+}
@@ -0,0 +1,52 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestFeelsLike(t *testing.T) {
+	series := func(uom string, values ...float64) noaa.GridpointForecastTimeSeries {
+		s := noaa.GridpointForecastTimeSeries{Uom: uom}
+		for i, v := range values {
+			s.Values = append(s.Values, noaa.GridpointForecastTimeSeriesValue{ValidTime: fmt.Sprintf("2023-07-04T%02d:00:00+00:00/PT1H", i), Value: v})
+		}
+		return s
+	}
+	forecast := &noaa.GridpointForecastResponse{
+		Temperature:         series("wmoUnit:degC", 32, 20, 5, 5, 8),
+		HeatIndex:           series("wmoUnit:degC", 36, 20, 5, 5, 8),
+		WindChill:           series("wmoUnit:degF", 86, 68, 35.6, 41, 40),
+		ApparentTemperature: series("wmoUnit:degC", 33, 19, 3, 5, 7),
+		WindSpeed:           series("wmoUnit:km_h-1", 10, 10, 20, 2, 10),
+	}
+	feelsLike := forecast.FeelsLike()
+	if feelsLike.Uom != "wmoUnit:degC" || len(feelsLike.Values) != 5 {
+		t.Fatalf("noaa.GridpointForecastResponse.FeelsLike() should return a value for each temperature in its unit, got %+v", feelsLike)
+	}
+	for i, want := range []float64{
+		36,                  // heat index
+		19,                  // apparent temperature
+		2,                   // wind chill converted to °C
+		5,                   // apparent temperature, too calm for a wind chill
+		(40.0 - 32) * 5 / 9, // wind chill
+	} {
+		if got := feelsLike.Values[i].Value; math.Abs(got-want) > 1e-9 {
+			t.Errorf("noaa.GridpointForecastResponse.FeelsLike() value %d should be %v, got %v", i, want, got)
+		}
+	}
+	if feelsLike.Values[2].ValidTime != forecast.Temperature.Values[2].ValidTime {
+		t.Errorf("noaa.GridpointForecastResponse.FeelsLike() should keep the valid times of the temperatures, got %s", feelsLike.Values[2].ValidTime)
+	}
+
+	forecast.HeatIndex, forecast.ApparentTemperature = noaa.GridpointForecastTimeSeries{}, noaa.GridpointForecastTimeSeries{}
+	if got := forecast.FeelsLike().Values[0].Value; got != 32 {
+		t.Errorf("noaa.GridpointForecastResponse.FeelsLike() should fall back to the temperature, got %v", got)
+	}
+}
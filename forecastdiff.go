@@ -0,0 +1,187 @@
+package noaa
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Fields of a forecast period compared by DiffForecasts.
+const (
+	ChangeTemperature   = "temperature"
+	ChangePrecipitation = "precipitation"
+	ChangeSummary       = "summary"
+	ChangeWind          = "wind"
+)
+
+// ForecastChange is a value of a forecast period which changed between two
+// issuances of a forecast, see DiffForecasts.
+type ForecastChange struct {
+	Start time.Time // start of the period
+	Field string    // ChangeTemperature, ChangePrecipitation, ChangeSummary or ChangeWind
+	Old   ForecastResponsePeriod
+	New   ForecastResponsePeriod
+}
+
+// DiffForecasts returns the changes from the periods of old to the periods
+// of updated starting at the same time, in the order of the periods of
+// updated and of the fields above. Temperatures are compared in whole
+// degrees and probabilities of precipitation in whole percents, so
+// conversions between units are not reported as changes. Periods only in one
+// of the forecasts, ex. the period which ended since old was issued, are
+// left out.
+func DiffForecasts(old *ForecastResponse, updated *ForecastResponse) []ForecastChange {
+	if old == nil || updated == nil {
+		return nil
+	}
+	previous := map[time.Time]ForecastResponsePeriod{}
+	for _, p := range old.Periods {
+		if start, _, err := p.Interval(); err == nil {
+			previous[start.UTC()] = p
+		}
+	}
+	var changes []ForecastChange
+	for _, p := range updated.Periods {
+		start, _, err := p.Interval()
+		if err != nil {
+			continue
+		}
+		o, ok := previous[start.UTC()]
+		if !ok {
+			continue
+		}
+		change := func(field string) {
+			changes = append(changes, ForecastChange{Start: start, Field: field, Old: o, New: p})
+		}
+		oldTemperature, _ := convertTemperature(o.Temperature, o.TemperatureUnit, "us")
+		newTemperature, _ := convertTemperature(p.Temperature, p.TemperatureUnit, "us")
+		if math.Round(oldTemperature) != math.Round(newTemperature) {
+			change(ChangeTemperature)
+		}
+		if math.Round(o.ProbabilityOfPrecipitation.Value) != math.Round(p.ProbabilityOfPrecipitation.Value) {
+			change(ChangePrecipitation)
+		}
+		if !strings.EqualFold(o.Summary, p.Summary) {
+			change(ChangeSummary)
+		}
+		if convertWindSpeed(o.WindSpeed, "us") != convertWindSpeed(p.WindSpeed, "us") || !strings.EqualFold(o.WindDirection, p.WindDirection) {
+			change(ChangeWind)
+		}
+	}
+	return changes
+}
+
+// DefaultChangeTemplate is the English template used by NewChangeFormatter.
+// See ChangeText for the fields available to it.
+const DefaultChangeTemplate = `{{if eq .Field "precipitation"}}rain chance{{if not .SamePeriod}} for {{.Period}}{{end}} {{.Direction}} from {{.Old}} to {{.New}}{{else}}{{.Label}}{{if not .SamePeriod}} for {{.Period}}{{end}} changed from {{.Old}} to {{.New}}{{end}}`
+
+// ChangeText holds the values of a ForecastChange as they are made available
+// to the template of a ChangeFormatter. Values have already been converted to
+// the units of the formatter.
+type ChangeText struct {
+	Period     string // name of the period, ex. Saturday
+	Field      string // ChangeTemperature, ChangePrecipitation, ChangeSummary or ChangeWind
+	Label      string // High, Low, rain chance, forecast or wind
+	Old        string // ex. 72°F, 20%, Mostly Sunny or SW 5 mph
+	New        string
+	Direction  string // up or down for temperatures and rain chances, else blank
+	SamePeriod bool   // true if the previous change of the notification is of the same period
+	Start      string // start time, ex. 6 AM or 06:00
+}
+
+// ChangeFormatter renders the changes of DiffForecasts into notifications,
+// ex. "High for Saturday changed from 72°F to 65°F; rain chance up from 20%
+// to 60%", using text/template like ForecastFormatter.
+type ChangeFormatter struct {
+	Change    *template.Template // renders each change, see ChangeText
+	Separator string             // joins the changes, "; " if blank
+	Units     string             // "us" or "si", blank keeps the units of the forecast
+	Clock24   bool               // use 24-hour times (15:00) instead of 12-hour times (3 PM)
+	Locale    Locale             // translates names, summaries, compass points and calm winds, English if zero
+}
+
+// NewChangeFormatter returns a formatter using the default English template.
+func NewChangeFormatter() *ChangeFormatter {
+	return &ChangeFormatter{
+		Change: template.Must(template.New("change").Parse(DefaultChangeTemplate)),
+	}
+}
+
+// SetChangeTemplate replaces the template used to render each change.
+func (f *ChangeFormatter) SetChangeTemplate(text string) error {
+	t, err := template.New("change").Parse(text)
+	if err != nil {
+		return err
+	}
+	f.Change = t
+	return nil
+}
+
+// Text returns the values made available to the template for a change.
+func (f *ChangeFormatter) Text(c ForecastChange) ChangeText {
+	periods := ForecastFormatter{Units: f.Units, Clock24: f.Clock24, Locale: f.Locale}
+	o, n := periods.Text(c.Old), periods.Text(c.New)
+	text := ChangeText{Period: n.Name, Field: c.Field, Start: n.Start}
+	switch c.Field {
+	case ChangeTemperature:
+		text.Label, text.Old, text.New = "Low", o.Temperature, n.Temperature
+		if c.New.IsDaytime {
+			text.Label = "High"
+		}
+		text.Direction = direction(o.TemperatureValue, n.TemperatureValue)
+	case ChangePrecipitation:
+		oldChance, newChance := c.Old.ProbabilityOfPrecipitation.Value, c.New.ProbabilityOfPrecipitation.Value
+		text.Label = "rain chance"
+		text.Old, text.New = fmt.Sprintf("%.0f%%", oldChance), fmt.Sprintf("%.0f%%", newChance)
+		text.Direction = direction(oldChance, newChance)
+	case ChangeSummary:
+		text.Label, text.Old, text.New = "forecast", o.Summary, n.Summary
+	case ChangeWind:
+		text.Label, text.Old, text.New = "wind", o.Wind, n.Wind
+	}
+	return text
+}
+
+// Format renders the changes into a notification, joined by the separator
+// and starting with an upper case letter. It returns a blank string if there
+// are no changes.
+func (f *ChangeFormatter) Format(changes []ForecastChange) (string, error) {
+	if f.Change == nil {
+		return "", fmt.Errorf("no template configured")
+	}
+	separator := f.Separator
+	if separator == "" {
+		separator = "; "
+	}
+	parts := make([]string, 0, len(changes))
+	for i, c := range changes {
+		text := f.Text(c)
+		text.SamePeriod = i > 0 && changes[i-1].Start.Equal(c.Start)
+		var buf bytes.Buffer
+		if err := f.Change.Execute(&buf, text); err != nil {
+			return "", err
+		}
+		parts = append(parts, buf.String())
+	}
+	s := strings.Join(parts, separator)
+	if r, size := utf8.DecodeRuneInString(s); size > 0 {
+		s = string(unicode.ToUpper(r)) + s[size:]
+	}
+	return s, nil
+}
+
+// direction returns up or down as the value changes from old to updated
+func direction(old float64, updated float64) string {
+	switch {
+	case updated > old:
+		return "up"
+	case updated < old:
+		return "down"
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func diffPeriod(name string, start string, daytime bool, temperature float64, chance float64, summary string) noaa.ForecastResponsePeriod {
+	return noaa.ForecastResponsePeriod{
+		Name: name, StartTime: start, EndTime: start, IsDaytime: daytime,
+		Temperature: temperature, TemperatureUnit: "F", WindSpeed: "5 mph", WindDirection: "SW", Summary: summary,
+		ProbabilityOfPrecipitation: noaa.ForecastValue{Value: chance, UnitCode: "wmoUnit:percent"},
+	}
+}
+
+func TestDiffForecasts(t *testing.T) {
+	old := &noaa.ForecastResponse{Periods: []noaa.ForecastResponsePeriod{
+		diffPeriod("Tonight", "2023-07-07T18:00:00-05:00", false, 60, 10, "Mostly Clear"),
+		diffPeriod("Saturday", "2023-07-08T06:00:00-05:00", true, 72, 20, "Mostly Sunny"),
+		diffPeriod("Saturday Night", "2023-07-08T18:00:00-05:00", false, 58, 20, "Partly Cloudy"),
+	}}
+	updated := &noaa.ForecastResponse{Periods: []noaa.ForecastResponsePeriod{
+		diffPeriod("Saturday", "2023-07-08T06:00:00-05:00", true, 65, 60, "Mostly Sunny"),
+		diffPeriod("Saturday Night", "2023-07-08T23:00:00Z", false, 14.4, 20, "Chance Showers"),
+		diffPeriod("Sunday", "2023-07-09T06:00:00-05:00", true, 80, 0, "Sunny"),
+	}}
+	updated.Periods[1].TemperatureUnit = "C"
+	updated.Periods[1].WindSpeed = "10 to 15 mph"
+
+	changes := noaa.DiffForecasts(old, updated)
+	fields := []string{noaa.ChangeTemperature, noaa.ChangePrecipitation, noaa.ChangeSummary, noaa.ChangeWind}
+	if len(changes) != len(fields) {
+		t.Fatalf("noaa.DiffForecasts() should return %d changes, got %+v", len(fields), changes)
+	}
+	for i, field := range fields {
+		if changes[i].Field != field {
+			t.Errorf("noaa.DiffForecasts() change %d should be of the %s, got %s", i, field, changes[i].Field)
+		}
+	}
+	if changes[2].New.Name != "Saturday Night" {
+		t.Errorf("noaa.DiffForecasts() should match periods by start time, got %q", changes[2].New.Name)
+	}
+
+	f := noaa.NewChangeFormatter()
+	got, err := f.Format(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "High for Saturday changed from 72°F to 65°F; rain chance up from 20% to 60%; forecast for Saturday Night changed from Partly Cloudy to Chance Showers; wind changed from SW 5 mph to SW 10 to 15 mph"
+	if got != want {
+		t.Errorf("noaa.ChangeFormatter.Format() should be %q, got %q", want, got)
+	}
+	if got, _ := f.Format(changes[1:2]); got != "Rain chance for Saturday up from 20% to 60%" {
+		t.Errorf("noaa.ChangeFormatter.Format() should name the period of the first change, got %q", got)
+	}
+	if got, _ := f.Format(nil); got != "" {
+		t.Errorf("noaa.ChangeFormatter.Format() without changes should be blank, got %q", got)
+	}
+
+	f.Units, f.Separator = "si", "\n"
+	if err := f.SetChangeTemplate(`{{.Period}} {{.Field}}: {{.Old}} -> {{.New}}`); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = f.Format(changes[:2])
+	if want := "Saturday temperature: 22°C -> 18°C\nSaturday precipitation: 20% -> 60%"; got != want {
+		t.Errorf("noaa.ChangeFormatter.Format() should render %q with the template and units, got %q", want, got)
+	}
+	if err := f.SetChangeTemplate(`{{.Old`); err == nil {
+		t.Error("noaa.ChangeFormatter.SetChangeTemplate() should fail on invalid templates")
+	}
+}
@@ -0,0 +1,148 @@
+package noaa
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Default English templates used by NewForecastFormatter. See PeriodText for
+// the fields available to templates.
+const (
+	DefaultPeriodTemplate = `{{.Name}}: {{.Summary}}, with a {{if .IsDaytime}}high{{else}}low{{end}} of {{.Temperature}}. Wind {{.Wind}}.`
+	DefaultHourlyTemplate = `{{.Start}}: {{.Summary}}, {{.Temperature}}. Wind {{.Wind}}.`
+)
+
+// Spanish templates for a ForecastFormatter whose Locale is LocaleES, ex.
+//
+//	f := noaa.NewForecastFormatter()
+//	f.Locale, f.Units = noaa.LocaleES, "si"
+//	f.SetPeriodTemplate(noaa.SpanishPeriodTemplate)
+const (
+	SpanishPeriodTemplate = `{{.Name}}: {{.Summary}}, con una {{if .IsDaytime}}máxima{{else}}mínima{{end}} de {{.Temperature}}. Viento {{.Wind}}.`
+	SpanishHourlyTemplate = `{{.Start}}: {{.Summary}}, {{.Temperature}}. Viento {{.Wind}}.`
+)
+
+// PeriodText holds the values of a forecast period as they are made available
+// to the templates of a ForecastFormatter. Values have already been converted
+// to the units of the formatter.
+type PeriodText struct {
+	Name             string  // ex. Tonight, blank for hourly periods
+	Summary          string  // ex. Mostly Sunny
+	Details          string  // the detailed forecast text, if any
+	IsDaytime        bool    // true for daytime periods
+	Temperature      string  // ex. 72°F
+	TemperatureValue float64 // ex. 72
+	TemperatureUnit  string  // F or C
+	TemperatureTrend string  // rising, falling or blank
+	Wind             string  // ex. SW 5 to 10 mph
+	WindSpeed        string  // ex. 5 to 10 mph
+	WindDirection    string  // ex. SW
+	Start            string  // start time, ex. 3 PM or 15:00
+	End              string  // end time, ex. 4 PM or 16:00
+}
+
+// ForecastFormatter renders forecast periods into consistent human readable
+// strings using text/template, which is useful for chat bots and text to
+// speech where the detailedForecast phrasing varies.
+type ForecastFormatter struct {
+	Period  *template.Template // used by FormatPeriod
+	Hourly  *template.Template // used by FormatHourly
+	Units   string             // "us" or "si", blank keeps the units of the forecast
+	Clock24 bool               // use 24-hour times (15:00) instead of 12-hour times (3 PM)
+	Locale  Locale             // translates names, summaries, compass points and calm winds, English if zero
+}
+
+// NewForecastFormatter returns a formatter using the default English templates.
+func NewForecastFormatter() *ForecastFormatter {
+	return &ForecastFormatter{
+		Period: template.Must(template.New("period").Parse(DefaultPeriodTemplate)),
+		Hourly: template.Must(template.New("hourly").Parse(DefaultHourlyTemplate)),
+	}
+}
+
+// SetPeriodTemplate replaces the template used by FormatPeriod.
+func (f *ForecastFormatter) SetPeriodTemplate(text string) error {
+	t, err := template.New("period").Parse(text)
+	if err != nil {
+		return err
+	}
+	f.Period = t
+	return nil
+}
+
+// SetHourlyTemplate replaces the template used by FormatHourly.
+func (f *ForecastFormatter) SetHourlyTemplate(text string) error {
+	t, err := template.New("hourly").Parse(text)
+	if err != nil {
+		return err
+	}
+	f.Hourly = t
+	return nil
+}
+
+// FormatPeriod renders a period of a ForecastResponse.
+func (f *ForecastFormatter) FormatPeriod(period ForecastResponsePeriod) (string, error) {
+	return f.execute(f.Period, period)
+}
+
+// FormatHourly renders a period of an HourlyForecastResponse.
+func (f *ForecastFormatter) FormatHourly(period ForecastResponsePeriodHourly) (string, error) {
+	return f.execute(f.Hourly, period.ForecastResponsePeriod)
+}
+
+// Text returns the values made available to templates for the given period.
+func (f *ForecastFormatter) Text(period ForecastResponsePeriod) PeriodText {
+	value, unit := convertTemperature(period.Temperature, period.TemperatureUnit, f.Units)
+	speed := convertWindSpeed(period.WindSpeed, f.Units)
+	direction := f.Locale.Direction(period.WindDirection)
+	wind := strings.TrimSpace(direction + " " + speed)
+	if wind == "" {
+		wind = f.Locale.calm()
+	}
+	return PeriodText{
+		Name:             f.Locale.Translate(period.Name),
+		Summary:          f.Locale.Translate(period.Summary),
+		Details:          period.Details,
+		IsDaytime:        period.IsDaytime,
+		Temperature:      fmt.Sprintf("%.0f°%s", value, unit),
+		TemperatureValue: value,
+		TemperatureUnit:  unit,
+		TemperatureTrend: period.TemperatureTrend,
+		Wind:             wind,
+		WindSpeed:        speed,
+		WindDirection:    direction,
+		Start:            f.formatTime(period.StartTime),
+		End:              f.formatTime(period.EndTime),
+	}
+}
+
+// execute renders the template for the given period
+func (f *ForecastFormatter) execute(t *template.Template, period ForecastResponsePeriod) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("no template configured")
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, f.Text(period)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatTime formats an RFC 3339 timestamp from the API in the local time of
+// the forecast (i.e. the offset included in the timestamp).
+func (f *ForecastFormatter) formatTime(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	if f.Clock24 {
+		return t.Format("15:04")
+	}
+	if t.Minute() == 0 {
+		return t.Format("3 PM")
+	}
+	return t.Format("3:04 PM")
+}
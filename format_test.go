@@ -0,0 +1,106 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestForecastFormatter(t *testing.T) {
+	night := noaa.ForecastResponsePeriod{
+		Name:            "Tonight",
+		StartTime:       "2023-06-15T18:00:00-05:00",
+		Temperature:     59,
+		TemperatureUnit: "F",
+		WindSpeed:       "5 to 10 mph",
+		WindDirection:   "SW",
+		Summary:         "Partly Cloudy",
+	}
+	day := noaa.ForecastResponsePeriod{
+		Name:            "Friday",
+		StartTime:       "2023-06-16T06:30:00-05:00",
+		IsDaytime:       true,
+		Temperature:     25,
+		TemperatureUnit: "C",
+		WindSpeed:       "16 km/h",
+		WindDirection:   "N",
+		Summary:         "Sunny",
+	}
+	calm := night
+	calm.WindSpeed, calm.WindDirection = "", ""
+
+	for _, test := range []struct {
+		name      string
+		formatter func() *noaa.ForecastFormatter
+		period    noaa.ForecastResponsePeriod
+		hourly    bool
+		want      string
+	}{
+		{"default", noaa.NewForecastFormatter, night, false,
+			"Tonight: Partly Cloudy, with a low of 59°F. Wind SW 5 to 10 mph."},
+		{"daytime", noaa.NewForecastFormatter, day, false,
+			"Friday: Sunny, with a high of 25°C. Wind N 16 km/h."},
+		{"calm", noaa.NewForecastFormatter, calm, false,
+			"Tonight: Partly Cloudy, with a low of 59°F. Wind calm."},
+		{"si", func() *noaa.ForecastFormatter {
+			f := noaa.NewForecastFormatter()
+			f.Units = "si"
+			return f
+		}, night, false, "Tonight: Partly Cloudy, with a low of 15°C. Wind SW 8 to 16 km/h."},
+		{"us", func() *noaa.ForecastFormatter {
+			f := noaa.NewForecastFormatter()
+			f.Units = "us"
+			return f
+		}, day, false, "Friday: Sunny, with a high of 77°F. Wind N 10 mph."},
+		{"hourly", noaa.NewForecastFormatter, day, true,
+			"6:30 AM: Sunny, 25°C. Wind N 16 km/h."},
+		{"hourly 24-hour", func() *noaa.ForecastFormatter {
+			f := noaa.NewForecastFormatter()
+			f.Clock24 = true
+			return f
+		}, night, true, "18:00: Partly Cloudy, 59°F. Wind SW 5 to 10 mph."},
+		{"spanish", func() *noaa.ForecastFormatter {
+			f := noaa.NewForecastFormatter()
+			f.Locale, f.Units = noaa.LocaleES, "si"
+			f.SetPeriodTemplate(noaa.SpanishPeriodTemplate)
+			return f
+		}, night, false, "Esta noche: Parcialmente nublado, con una mínima de 15°C. Viento SO 8 to 16 km/h."},
+		{"custom template", func() *noaa.ForecastFormatter {
+			f := noaa.NewForecastFormatter()
+			f.SetPeriodTemplate("{{.Name}} {{.TemperatureValue}} {{.TemperatureUnit}} from {{.Start}}")
+			return f
+		}, night, false, "Tonight 59 F from 6 PM"},
+	} {
+		f := test.formatter()
+		var got string
+		var err error
+		if test.hourly {
+			got, err = f.FormatHourly(noaa.ForecastResponsePeriodHourly{ForecastResponsePeriod: test.period})
+		} else {
+			got, err = f.FormatPeriod(test.period)
+		}
+		if err != nil || got != test.want {
+			t.Errorf("noaa.ForecastFormatter %s should format %q, got %q, %v", test.name, test.want, got, err)
+		}
+	}
+}
+
+func TestForecastFormatterErrors(t *testing.T) {
+	f := noaa.NewForecastFormatter()
+	if err := f.SetPeriodTemplate("{{.Name"); err == nil {
+		t.Error("noaa.ForecastFormatter.SetPeriodTemplate() should fail on an invalid template")
+	}
+	if err := f.SetHourlyTemplate("{{.Missing}}"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.FormatHourly(noaa.ForecastResponsePeriodHourly{}); err == nil {
+		t.Error("noaa.ForecastFormatter.FormatHourly() should fail on an unknown field")
+	}
+	f.Period = nil
+	if _, err := f.FormatPeriod(noaa.ForecastResponsePeriod{}); err == nil {
+		t.Error("noaa.ForecastFormatter.FormatPeriod() should fail without a template")
+	}
+}
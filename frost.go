@@ -0,0 +1,243 @@
+package noaa
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FrostAlertEvents are the events of the alerts about frost and freezes.
+var FrostAlertEvents = []string{
+	"Frost Advisory",
+	"Freeze Watch",
+	"Freeze Warning",
+	"Hard Freeze Watch",
+	"Hard Freeze Warning",
+}
+
+// FrostRisk is the risk to plants of a FrostWindow.
+type FrostRisk int
+
+// Frost risks by increasing severity. Frost forms on plants with air
+// temperatures of 36 °F and below when the dew point is at or below freezing,
+// a freeze kills tender plants, and a hard freeze most plants.
+const (
+	FrostRiskNone FrostRisk = iota
+	FrostRiskFrost
+	FrostRiskFreeze
+	FrostRiskHardFreeze
+)
+
+// frostRiskNames are the names of the frost risks
+var frostRiskNames = [...]string{"None", "Frost", "Freeze", "Hard Freeze"}
+
+// String returns the name of the risk, ex. Hard Freeze.
+func (r FrostRisk) String() string {
+	if r < 0 || int(r) >= len(frostRiskNames) {
+		return fmt.Sprintf("FrostRisk(%d)", int(r))
+	}
+	return frostRiskNames[r]
+}
+
+// frostRisk returns the risk of a temperature and dew point in °F
+func frostRisk(temperature float64, dewpoint float64) FrostRisk {
+	switch {
+	case temperature <= 28:
+		return FrostRiskHardFreeze
+	case temperature <= 32:
+		return FrostRiskFreeze
+	case temperature <= 36 && dewpoint <= 32:
+		return FrostRiskFrost
+	}
+	return FrostRiskNone
+}
+
+// FrostWindow is a time when frost or a freeze is expected, see FrostOutlook.
+type FrostWindow struct {
+	Start  time.Time // local time
+	End    time.Time
+	Risk   FrostRisk // highest risk of the window
+	Low    float64   // lowest temperature of the window
+	Unit   string    // unit of Low, F or C
+	Alerts []Alert   // frost and freeze alerts in effect during the window
+}
+
+// FrostReport is the frost outlook of a point, see FrostOutlook.
+type FrostReport struct {
+	Windows []FrostWindow
+	Alerts  []Alert // active frost and freeze alerts of the point
+}
+
+// Risk returns the highest risk of the windows, raised to at least frost for
+// a Frost Advisory and freeze for other alerts.
+func (r *FrostReport) Risk() FrostRisk {
+	risk := FrostRiskNone
+	for _, w := range r.Windows {
+		if w.Risk > risk {
+			risk = w.Risk
+		}
+	}
+	for _, a := range r.Alerts {
+		alertRisk := FrostRiskFreeze
+		if strings.EqualFold(a.Event, "Frost Advisory") {
+			alertRisk = FrostRiskFrost
+		} else if strings.HasPrefix(strings.ToLower(a.Event), "hard freeze") {
+			alertRisk = FrostRiskHardFreeze
+		}
+		if alertRisk > risk {
+			risk = alertRisk
+		}
+	}
+	return risk
+}
+
+// FrostOutlook returns the times frost or a freeze is expected at <lat,lon>
+// over the next days, 7 if 0, in the time zone of the point, with the active
+// frost and freeze alerts of the point. Low temperatures are in F, or C with
+// SI units. See FrostWindows.
+func FrostOutlook(lat string, lon string, days int) (*FrostReport, error) {
+	if days <= 0 {
+		days = 7
+	}
+	forecast, err := GridpointForecast(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := AlertsWithFilter(lat, lon, AlertFilter{Events: FrostAlertEvents})
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	if forecast.Point != nil && forecast.Point.Timezone != "" {
+		if loc, err = time.LoadLocation(forecast.Point.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	unit := "F"
+	if currentConfig().Units == "si" {
+		unit = "C"
+	}
+	now := time.Now().In(loc)
+	report := &FrostReport{Windows: FrostWindows(forecast, now, now.AddDate(0, 0, days), unit), Alerts: alerts}
+	for i := range report.Windows {
+		w := &report.Windows[i]
+		for _, a := range alerts {
+			start, end := alertSpan(a)
+			if (start.IsZero() || start.Before(w.End)) && (end.IsZero() || end.After(w.Start)) {
+				w.Alerts = append(w.Alerts, a)
+			}
+		}
+	}
+	return report, nil
+}
+
+// FrostWindows returns the times between from and until when the gridpoint
+// forecast expects frost or a freeze, in the location of from, with lows in
+// unit F or C. Each hour of the Temperature series is rated with the Dewpoint
+// series, and consecutive hours at risk form a window. Overnight lows of the
+// MinTemperature series at risk without any hour at risk, which happens when
+// the hourly temperatures are rounded up, add a window for the night.
+func FrostWindows(g *GridpointForecastResponse, from time.Time, until time.Time, unit string) []FrostWindow {
+	loc := from.Location()
+	fahrenheit := func(v float64, uom string) float64 {
+		f, _ := LocaleUS.Convert(v, uom)
+		return f
+	}
+	low := func(f float64) float64 {
+		v, _ := degreeLocale(unit).Convert(f, "F")
+		return math.Round(v*10) / 10
+	}
+	dewpoint := func(t time.Time) float64 {
+		if v, ok := g.Dewpoint.At(t); ok {
+			return fahrenheit(v, g.Dewpoint.Uom)
+		}
+		return math.Inf(-1) // unknown, rate on the temperature alone
+	}
+
+	var windows []FrostWindow
+	var current *FrostWindow
+	for _, v := range g.Temperature.Values {
+		start, d, err := v.Interval()
+		if err != nil {
+			continue
+		}
+		temperature := fahrenheit(v.Value, g.Temperature.Uom)
+		for t := start; t.Before(start.Add(d)) || t.Equal(start); t = t.Add(time.Hour) {
+			if t.Before(from) || !t.Before(until) {
+				continue
+			}
+			risk := frostRisk(temperature, dewpoint(t))
+			if risk == FrostRiskNone {
+				current = nil
+				continue
+			}
+			if current == nil || !current.End.Equal(t.In(loc)) {
+				windows = append(windows, FrostWindow{Start: t.In(loc), Low: math.Inf(1), Unit: unit})
+				current = &windows[len(windows)-1]
+			}
+			current.End = t.Add(time.Hour).In(loc)
+			if risk > current.Risk {
+				current.Risk = risk
+			}
+			current.Low = math.Min(current.Low, temperature)
+		}
+	}
+
+	for _, v := range g.MinTemperature.Values {
+		start, d, err := v.Interval()
+		if err != nil {
+			continue
+		}
+		end := start.Add(d)
+		if !end.After(from) || !start.Before(until) {
+			continue
+		}
+		temperature := fahrenheit(v.Value, g.MinTemperature.Uom)
+		risk := frostRisk(temperature, math.Inf(-1))
+		if risk == FrostRiskFrost {
+			dew := math.Inf(1)
+			for t := start; t.Before(end); t = t.Add(time.Hour) {
+				dew = math.Min(dew, dewpoint(t))
+			}
+			risk = frostRisk(temperature, dew)
+		}
+		if risk == FrostRiskNone {
+			continue
+		}
+		covered := false
+		for _, w := range windows {
+			if w.Start.Before(end) && w.End.After(start) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			windows = append(windows, FrostWindow{Start: start.In(loc), End: end.In(loc), Risk: risk, Low: temperature, Unit: unit})
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Start.Before(windows[j].Start) })
+	for i := range windows {
+		windows[i].Low = low(windows[i].Low)
+	}
+	return windows
+}
+
+// alertSpan returns the onset and end of an alert, zero if unknown
+func alertSpan(a Alert) (start time.Time, end time.Time) {
+	for _, s := range []string{a.Onset, a.Effective} {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			start = t
+			break
+		}
+	}
+	for _, s := range []string{a.Ends, a.Expires} {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			end = t
+			break
+		}
+	}
+	return start, end
+}
@@ -0,0 +1,84 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestFrostWindows(t *testing.T) {
+	forecast := &noaa.GridpointForecastResponse{
+		Temperature: noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-10-10T00:00:00+00:00/PT6H", Value: 5},
+			{ValidTime: "2023-10-10T06:00:00+00:00/PT2H", Value: 2},  // 35.6 °F
+			{ValidTime: "2023-10-10T08:00:00+00:00/PT1H", Value: -1}, // 30.2 °F
+			{ValidTime: "2023-10-10T09:00:00+00:00/PT1H", Value: 2},
+			{ValidTime: "2023-10-10T10:00:00+00:00/PT14H", Value: 8},
+			{ValidTime: "2023-10-11T00:00:00+00:00/PT12H", Value: 4},
+			{ValidTime: "2023-10-11T12:00:00+00:00/PT12H", Value: 3},
+		}},
+		Dewpoint: noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-10-10T00:00:00+00:00/PT7H", Value: 3},
+			{ValidTime: "2023-10-10T07:00:00+00:00/PT2D", Value: -2},
+		}},
+		MinTemperature: noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-10-10T00:00:00+00:00/PT13H", Value: -1},
+			{ValidTime: "2023-10-11T00:00:00+00:00/PT13H", Value: 1},
+			{ValidTime: "2023-10-12T00:00:00+00:00/PT13H", Value: -3},
+		}},
+	}
+	from := time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC)
+	windows := noaa.FrostWindows(forecast, from, from.AddDate(0, 0, 2), "F")
+	if len(windows) != 2 {
+		t.Fatalf("noaa.FrostWindows() should return 2 windows, got %+v", windows)
+	}
+	// 06:00 has a dew point above freezing, 07:00 frost, 08:00 a freeze, 09:00 frost
+	w := windows[0]
+	if w.Start.Hour() != 7 || w.End.Hour() != 10 || w.Risk != noaa.FrostRiskFreeze || w.Low != 30.2 || w.Unit != "F" {
+		t.Errorf("noaa.FrostWindows() should join the hours at risk, got %+v", w)
+	}
+	// the hourly temperatures stay above 36 °F the next night but not the low
+	w = windows[1]
+	if w.Start.Day() != 11 || w.End.Hour() != 13 || w.Risk != noaa.FrostRiskFrost || w.Low != 33.8 {
+		t.Errorf("noaa.FrostWindows() should add the nights with a low at risk, got %+v", w)
+	}
+	if w.Risk.String() != "Frost" || noaa.FrostRiskHardFreeze.String() != "Hard Freeze" {
+		t.Errorf("noaa.FrostRisk.String() should return the name of the risk, got %s", w.Risk)
+	}
+}
+
+func TestFrostOutlook(t *testing.T) {
+	tonight := time.Now().UTC().Truncate(time.Hour).Add(6 * time.Hour)
+	fakeAPI(t, map[string]string{
+		"/points/44.5,-89.5": `{"timeZone": "America/Chicago", "forecastGridData": "{api}/gridpoints/GRB/10,20"}`,
+		"/gridpoints/GRB/10,20": fmt.Sprintf(`{
+			"temperature": {"uom": "wmoUnit:degC", "values": [{"validTime": "%s/PT3H", "value": -3}]}
+		}`, tonight.Format(time.RFC3339)),
+		"/alerts/active": fmt.Sprintf(`{"features": [
+			{"properties": {"id": "urn:oid:1", "event": "Freeze Warning", "onset": "%s", "ends": "%s"}},
+			{"properties": {"id": "urn:oid:2", "event": "Flood Warning"}}
+		]}`, tonight.Add(-time.Hour).Format(time.RFC3339), tonight.Add(4*time.Hour).Format(time.RFC3339)),
+	})
+
+	report, err := noaa.FrostOutlook("44.5", "-89.5", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Alerts) != 1 || report.Alerts[0].Event != "Freeze Warning" {
+		t.Errorf("noaa.FrostOutlook() should keep the frost and freeze alerts, got %v", report.Alerts)
+	}
+	if len(report.Windows) != 1 || report.Windows[0].Risk != noaa.FrostRiskHardFreeze || len(report.Windows[0].Alerts) != 1 {
+		t.Fatalf("noaa.FrostOutlook() should return the hard freeze with its alert, got %+v", report.Windows)
+	}
+	if loc := report.Windows[0].Start.Location().String(); loc != "America/Chicago" {
+		t.Errorf("noaa.FrostOutlook() should return local times, got %s", loc)
+	}
+	if report.Risk() != noaa.FrostRiskHardFreeze {
+		t.Errorf("noaa.FrostReport.Risk() should be a hard freeze, got %s", report.Risk())
+	}
+}
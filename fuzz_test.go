@@ -0,0 +1,188 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// fuzzDecoder fuzzes a response decoder with the seeds and fixture files,
+// checking that it returns either a value or an *InvalidResponseError
+func fuzzDecoder[T any](f *testing.F, decode func(io.Reader) (*T, error), seeds []string, fixtures ...string) {
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+	for _, fixture := range fixtures {
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := decode(bytes.NewReader(data))
+		if err != nil {
+			if v != nil || !errors.Is(err, noaa.ErrInvalidResponse) {
+				t.Errorf("expected a nil value and an invalid response error, got %v, %v", v, err)
+			}
+			return
+		}
+		if v == nil {
+			t.Error("expected a value without an error")
+		}
+	})
+}
+
+func FuzzDecodePoints(f *testing.F) {
+	fuzzDecoder(f, noaa.DecodePoints, []string{`{"gridX": "76", "gridY": 73.0}`, `{"gridX": 1e99}`, `null`}, "testdata/points.json")
+}
+
+func FuzzDecodeForecast(f *testing.F) {
+	fuzzDecoder(f, noaa.DecodeForecast, []string{
+		`{"periods": [{"number": "1", "temperature": {"unitCode": "wmoUnit:degC", "value": null}}]}`,
+		`{"periods": [{"windSpeed": {"minValue": 1, "maxValue": 2}}]}`,
+		`{"periods": null}`,
+	}, "testdata/forecast.json", "testdata/forecast_qv.json")
+}
+
+func FuzzDecodeHourlyForecast(f *testing.F) {
+	fuzzDecoder(f, noaa.DecodeHourlyForecast, []string{
+		`{"periods": [{"number": 1, "temperature": {"unitCode": "wmoUnit:degC", "value": 30}, "windSpeed": {"unitCode": "wmoUnit:km_h-1", "value": 16.093}}]}`,
+	})
+}
+
+func FuzzDecodeGridpointForecast(f *testing.F) {
+	fuzzDecoder(f, noaa.DecodeGridpointForecast, []string{
+		`{"temperature": {"uom": "wmoUnit:degC", "values": [{"validTime": "2023-07-04T18:00:00+00:00/PT3H", "value": 30}]}}`,
+		`{"hazards": {"values": [{"validTime": "2023-07-04T18:00:00+00:00/P1D", "value": [{"phenomenon": "HT", "significance": "Y", "event_number": "12"}]}]}}`,
+	})
+}
+
+func FuzzDecodeStations(f *testing.F) {
+	fuzzDecoder(f, noaa.DecodeStations, []string{
+		`{"observationStations": ["https://api.weather.gov/stations/KMDW"]}`,
+		`{"@graph": [{"@id": "https://api.weather.gov/stations/KMDW", "stationIdentifier": "KMDW", "geometry": "POINT(-87.75 41.78)"}]}`,
+		`{"features": [{"id": "https://api.weather.gov/stations/KMDW", "geometry": {"type": "Point", "coordinates": [-87.75]}}]}`,
+	})
+}
+
+func FuzzDecodeObservation(f *testing.F) {
+	fuzzDecoder(f, noaa.DecodeObservation, []string{
+		`{"timestamp": "2023-07-04T15:53:00+00:00", "rawMessage": "KMDW 041553Z 21010KT 10SM FEW250 29/18 A2992", "temperature": {"value": 29, "unitCode": "wmoUnit:degC"}}`,
+	})
+}
+
+func FuzzDecodeOffice(f *testing.F) {
+	fuzzDecoder(f, noaa.DecodeOffice, []string{`{"@id": "https://api.weather.gov/offices/LOT", "name": "Chicago, IL"}`})
+}
+
+func FuzzDecodeAlerts(f *testing.F) {
+	f.Add([]byte(`{"@graph": [{"id": "urn:oid:1", "event": "Heat Advisory", "parameters": {"VTEC": ["/O.NEW.KLOT.HT.Y.0012.230704T1800Z-230705T0100Z/"]}}]}`))
+	f.Add([]byte(`{"features": [{"properties": {"id": "urn:oid:1"}}]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		noaa.DecodeAlerts(bytes.NewReader(data), func(a noaa.Alert) bool {
+			a.VTEC()
+			noaa.IsUrgentAlert(a)
+			return true
+		})
+	})
+}
+
+func FuzzParseMETAR(f *testing.F) {
+	f.Add("METAR KORD 041551Z 21010G18KT 10SM -RA VCSH FEW035 BKN250 29/18 A2992 RMK AO2 SLP131 T02890183")
+	f.Add("SPECI KMDW 041612Z AUTO 00000KT 1/2SM FG VV002 M01/M02 Q1013")
+	f.Fuzz(func(t *testing.T, raw string) {
+		if m, err := noaa.ParseMETAR(raw); err == nil {
+			m.Time(time.Date(2023, 7, 4, 16, 0, 0, 0, time.UTC))
+		}
+	})
+}
+
+func FuzzParseHazardousWeatherOutlook(f *testing.F) {
+	data, err := os.ReadFile("testdata/hwo.txt")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(string(data))
+	f.Fuzz(func(t *testing.T, text string) {
+		noaa.ParseHazardousWeatherOutlook(text)
+	})
+}
+
+func FuzzParseUGC(f *testing.F) {
+	f.Add("ILZ003>006-008-INZ001-051530-")
+	f.Add("LMZ740>745-779-051530-")
+	f.Fuzz(func(t *testing.T, ugc string) {
+		noaa.ParseUGC(ugc)
+	})
+}
+
+func FuzzParseVTEC(f *testing.F) {
+	f.Add("/O.NEW.KLOT.HT.Y.0012.230704T1800Z-230705T0100Z/")
+	f.Add("/O.CAN.KLOT.WS.A.0003.000000T0000Z-230115T1200Z/")
+	f.Fuzz(func(t *testing.T, s string) {
+		noaa.ParseVTEC(s)
+	})
+}
+
+func FuzzParseZoneForecastText(f *testing.F) {
+	f.Add("Highs in the upper 70s to lower 80s. Chance of rain 40 percent.")
+	f.Add("Lows 5 below to 5 above zero.")
+	f.Fuzz(func(t *testing.T, text string) {
+		d := noaa.ParseZoneForecastText(text)
+		for _, temp := range d.Temperatures {
+			if temp.Min > temp.Max {
+				t.Errorf("expected min <= max, got %+v", temp)
+			}
+		}
+	})
+}
+
+func FuzzZonePolygons(f *testing.F) {
+	f.Add([]byte(`"POLYGON((-88 41, -87 41, -87 42, -88 42, -88 41))"`))
+	f.Add([]byte(`"MULTIPOLYGON(((-88 41, -87 41, -87 42, -88 41)), ((-86 40, -85 40, -85 41, -86 40)))"`))
+	f.Add([]byte(`{"type": "Polygon", "coordinates": [[[-88, 41], [-87, 41], [-87, 42], [-88, 41]]]}`))
+	f.Add([]byte(`{"type": "MultiPolygon", "coordinates": [[[[-88, 41], [-87, 41]]]]}`))
+	f.Add([]byte(`{"type": "Polygon", "coordinates": []}`))
+	f.Add([]byte(`"POLYGON ()"`))
+	f.Fuzz(func(t *testing.T, geometry []byte) {
+		if !json.Valid(geometry) {
+			return
+		}
+		zone := noaa.Zone{Geometry: geometry}
+		polygons, err := zone.Polygons()
+		if err != nil {
+			return
+		}
+		for _, p := range polygons {
+			// PointsInZone samples the outer ring of the polygons
+			if len(p) == 0 || len(p[0]) == 0 {
+				t.Fatalf("noaa.Zone.Polygons() should not return empty polygons, got %v", polygons)
+			}
+			p.Contains(noaa.Coordinates{Lat: 41.5, Lon: -87.5})
+		}
+	})
+}
+
+func TestDecodeInvalidResponse(t *testing.T) {
+	for _, body := range []string{``, `null`, `[]`, `{"gridX": 76`, `{"gridX": "seventy"}`} {
+		points, err := noaa.DecodePoints(bytes.NewReader([]byte(body)))
+		if points != nil || !errors.Is(err, noaa.ErrInvalidResponse) {
+			t.Errorf("noaa.DecodePoints(%q) should return ErrInvalidResponse, got %v, %v", body, points, err)
+		}
+	}
+	_, err := noaa.DecodeForecast(bytes.NewReader([]byte(`{"periods": [}`)))
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("noaa.DecodeForecast() should unwrap to the JSON error, got %v", err)
+	}
+}
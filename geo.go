@@ -0,0 +1,77 @@
+package noaa
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371008.8
+
+// Coordinates is a point in decimal degrees.
+type Coordinates struct {
+	Lat float64
+	Lon float64
+}
+
+// ParseCoordinates parses latitude and longitude strings as passed to Points.
+func ParseCoordinates(lat string, lon string) (Coordinates, error) {
+	la, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("invalid latitude %q", lat)
+	}
+	lo, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("invalid longitude %q", lon)
+	}
+	return Coordinates{Lat: la, Lon: lo}, nil
+}
+
+// Strings formats the coordinates with the four decimal places accepted by
+// weather.gov, ex. for Points(c.Strings()).
+func (c Coordinates) Strings() (lat string, lon string) {
+	return strconv.FormatFloat(c.Lat, 'f', 4, 64), strconv.FormatFloat(c.Lon, 'f', 4, 64)
+}
+
+// DistanceTo returns the great-circle distance to o in meters.
+func (c Coordinates) DistanceTo(o Coordinates) float64 {
+	lat1, lat2 := radians(c.Lat), radians(o.Lat)
+	dLat, dLon := lat2-lat1, radians(o.Lon-c.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BearingTo returns the initial bearing of the great-circle path to o in
+// degrees clockwise from true north, from 0 up to 360.
+func (c Coordinates) BearingTo(o Coordinates) float64 {
+	lat1, lat2 := radians(c.Lat), radians(o.Lat)
+	dLon := radians(o.Lon - c.Lon)
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// radians converts degrees to radians
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// parseWKTPoint parses a WKT POINT, ex. POINT(-87.75 41.78)
+func parseWKTPoint(wkt string) (Coordinates, error) {
+	wkt = strings.TrimSpace(wkt)
+	if !strings.HasPrefix(strings.ToUpper(wkt), "POINT") {
+		return Coordinates{}, fmt.Errorf("invalid WKT point %q", wkt)
+	}
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(wkt[len("POINT"):]), "()"))
+	if len(fields) < 2 {
+		return Coordinates{}, fmt.Errorf("invalid WKT point %q", wkt)
+	}
+	lon, err1 := strconv.ParseFloat(fields[0], 64)
+	lat, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil {
+		return Coordinates{}, fmt.Errorf("invalid WKT point %q", wkt)
+	}
+	return Coordinates{Lat: lat, Lon: lon}, nil
+}
@@ -0,0 +1,39 @@
+package noaa
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// GetJSON requests an endpoint which is not wrapped by this package and
+// decodes the JSON response into a T, using the configured base URL, headers
+// and retries. path is relative to the base URL, ex. /zones/forecast/ILZ014,
+// or an absolute URL returned by another endpoint. For example:
+//
+//	type zone struct {
+//		Name  string `json:"name"`
+//		State string `json:"state"`
+//	}
+//	z, err := noaa.GetJSON[zone](ctx, "/zones/forecast/ILZ014", nil)
+//
+// The response format depends on the Accept header, see SetAcceptHeader.
+func GetJSON[T any](ctx context.Context, path string, params url.Values) (T, error) {
+	var v T
+	endpoint := path
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		endpoint = currentConfig().BaseURL + "/" + strings.TrimPrefix(path, "/")
+	}
+	res, err := apiCallContext(ctx, urlUnder(endpoint).withQuery(params).String())
+	if err != nil {
+		return v, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return v, err
+	}
+	err = currentCodec().Unmarshal(data, &v)
+	return v, err
+}
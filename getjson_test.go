@@ -0,0 +1,46 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestGetJSON(t *testing.T) {
+	api := fakeAPI(t, map[string]string{
+		"/zones/forecast/ILZ014": `{"id": "ILZ014", "name": "Cook", "state": "IL"}`,
+		"/products":              `{"@graph": [{"id": "1", "productCode": "AFD"}]}`,
+	})
+	type zone struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	z, err := noaa.GetJSON[zone](context.Background(), "/zones/forecast/ILZ014", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.Name != "Cook" || z.State != "IL" {
+		t.Errorf("noaa.GetJSON() decoded an unexpected zone %+v", z)
+	}
+
+	products, err := noaa.GetJSON[map[string]interface{}](context.Background(), api.URL+"/products",
+		url.Values{"type": {"AFD"}, "location": {"LOT"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := products["@graph"]; !ok {
+		t.Errorf("noaa.GetJSON() decoded an unexpected response %v", products)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := noaa.GetJSON[zone](ctx, "/zones/forecast/ILZ014", nil); err == nil {
+		t.Error("noaa.GetJSON() should fail when the context is cancelled")
+	}
+}
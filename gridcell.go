@@ -0,0 +1,160 @@
+package noaa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GridCell is a cell of the 2.5 km forecast grid of an office, ex. LOT/76,73.
+// Forecasts are the same for every point in a cell.
+type GridCell struct {
+	Office string // grid ID, ex. LOT
+	X      int
+	Y      int
+}
+
+// String returns the cell as used in /gridpoints URLs, ex. LOT/76,73.
+func (g GridCell) String() string {
+	return fmt.Sprintf("%s/%d,%d", g.Office, g.X, g.Y)
+}
+
+// ParseGridCell parses a grid cell as returned by GridCell.String, ex.
+// LOT/76,73.
+func ParseGridCell(s string) (GridCell, error) {
+	office, xy, ok := strings.Cut(strings.TrimSpace(s), "/")
+	x, y, ok2 := strings.Cut(xy, ",")
+	if !ok || !ok2 || office == "" {
+		return GridCell{}, fmt.Errorf("invalid grid cell %q", s)
+	}
+	gx, err1 := strconv.Atoi(x)
+	gy, err2 := strconv.Atoi(y)
+	if err1 != nil || err2 != nil || gx < 0 || gy < 0 {
+		return GridCell{}, fmt.Errorf("invalid grid cell %q", s)
+	}
+	return GridCell{Office: strings.ToUpper(office), X: gx, Y: gy}, nil
+}
+
+// GridCell returns the grid cell of the point.
+func (p *PointsResponse) GridCell() GridCell {
+	return GridCell{Office: p.GridID, X: int(p.GridX), Y: int(p.GridY)}
+}
+
+// GridCellForPoint returns the grid cell containing <lat,lon>.
+func GridCellForPoint(lat string, lon string) (GridCell, error) {
+	point, err := Points(lat, lon)
+	if err != nil {
+		return GridCell{}, err
+	}
+	if point.GridID == "" {
+		return GridCell{}, fmt.Errorf("no grid cell for %s,%s", lat, lon)
+	}
+	return point.GridCell(), nil
+}
+
+// GridCellBounds returns the polygon of a grid cell, ex. to display the area
+// a forecast covers, read from the geometry of the forecast of the cell.
+func GridCellBounds(wfo string, x int, y int) (Polygon, error) {
+	endpoint := apiURL("gridpoints", strings.ToUpper(wfo), fmt.Sprintf("%d,%d", x, y), "forecast").String()
+	return shared(context.Background(), endpoint+"#geometry", func(context.Context) (Polygon, error) {
+		var r struct {
+			Geometry json.RawMessage `json:"geometry"`
+		}
+		if err := getDecoded(endpoint, &r); err != nil {
+			return nil, err
+		}
+		polygons, err := parseGeometry("grid cell", r.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		if len(polygons) == 0 {
+			return nil, fmt.Errorf("grid cell %s/%d,%d has no geometry", wfo, x, y)
+		}
+		return polygons[0], nil
+	})
+}
+
+// Bounds returns the polygon of the cell, see GridCellBounds.
+func (g GridCell) Bounds() (Polygon, error) {
+	return GridCellBounds(g.Office, g.X, g.Y)
+}
+
+// geohashAlphabet is the base 32 alphabet of geohashes
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash returns the geohash of the coordinates with precision characters,
+// from 1 to 12, ex. dp3wh for 41.837,-87.685 with a precision of 5. A
+// precision of 6 gives cells of about 1.2 by 0.6 km, smaller than a grid cell.
+func (c Coordinates) Geohash(precision int) string {
+	if precision < 1 {
+		precision = 1
+	} else if precision > 12 {
+		precision = 12
+	}
+	lat := [2]float64{-90, 90}
+	lon := [2]float64{-180, 180}
+	var hash strings.Builder
+	bits, ch, even := 0, 0, true
+	for hash.Len() < precision {
+		r, v := &lat, c.Lat
+		if even {
+			r, v = &lon, c.Lon
+		}
+		mid := (r[0] + r[1]) / 2
+		ch <<= 1
+		if v >= mid {
+			ch |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+		if bits++; bits == 5 {
+			hash.WriteByte(geohashAlphabet[ch])
+			bits, ch = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+// GeohashBounds returns the south west and north east corners of a geohash.
+func GeohashBounds(hash string) (southWest Coordinates, northEast Coordinates, err error) {
+	if hash == "" {
+		return southWest, northEast, errors.New("empty geohash")
+	}
+	lat := [2]float64{-90, 90}
+	lon := [2]float64{-180, 180}
+	even := true
+	for _, r := range strings.ToLower(hash) {
+		i := strings.IndexRune(geohashAlphabet, r)
+		if i < 0 {
+			return southWest, northEast, fmt.Errorf("invalid geohash %q", hash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			rng := &lat
+			if even {
+				rng = &lon
+			}
+			mid := (rng[0] + rng[1]) / 2
+			if i>>bit&1 == 1 {
+				rng[0] = mid
+			} else {
+				rng[1] = mid
+			}
+			even = !even
+		}
+	}
+	return Coordinates{Lat: lat[0], Lon: lon[0]}, Coordinates{Lat: lat[1], Lon: lon[1]}, nil
+}
+
+// DecodeGeohash returns the center of a geohash.
+func DecodeGeohash(hash string) (Coordinates, error) {
+	sw, ne, err := GeohashBounds(hash)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	return Coordinates{Lat: (sw.Lat + ne.Lat) / 2, Lon: (sw.Lon + ne.Lon) / 2}, nil
+}
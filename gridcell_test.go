@@ -0,0 +1,64 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestGridCell(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685":         `{"gridId": "LOT", "gridX": 76, "gridY": 73}`,
+		"/gridpoints/LOT/76,73/forecast": `{"geometry": "POLYGON((-87.6966 41.8235,-87.6925 41.8452,-87.7216 41.8483,-87.7257 41.8266,-87.6966 41.8235))", "periods": []}`,
+	})
+
+	cell, err := noaa.GridCellForPoint("41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cell != (noaa.GridCell{Office: "LOT", X: 76, Y: 73}) || cell.String() != "LOT/76,73" {
+		t.Errorf("noaa.GridCellForPoint() should return the grid cell of the point, got %v", cell)
+	}
+	if parsed, err := noaa.ParseGridCell("lot/76,73"); err != nil || parsed != cell {
+		t.Errorf("noaa.ParseGridCell() should parse the cell, got %v, %v", parsed, err)
+	}
+	for _, s := range []string{"LOT", "LOT/76", "/76,73", "LOT/x,73", "LOT/76,-1"} {
+		if _, err := noaa.ParseGridCell(s); err == nil {
+			t.Errorf("noaa.ParseGridCell(%q) should return an error", s)
+		}
+	}
+
+	bounds, err := cell.Bounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bounds) != 1 || len(bounds[0]) != 5 || !bounds.Contains(noaa.Coordinates{Lat: 41.837, Lon: -87.71}) {
+		t.Errorf("noaa.GridCellBounds() should return the polygon of the cell, got %v", bounds)
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	if hash := (noaa.Coordinates{Lat: 57.64911, Lon: 10.40744}).Geohash(11); hash != "u4pruydqqvj" {
+		t.Errorf("noaa.Coordinates.Geohash() should encode the coordinates, got %q", hash)
+	}
+	c := noaa.Coordinates{Lat: 41.837, Lon: -87.685}
+	hash := c.Geohash(7)
+	if hash != "dp3wh8j" {
+		t.Errorf("noaa.Coordinates.Geohash() should encode the coordinates, got %q", hash)
+	}
+	center, err := noaa.DecodeGeohash(hash)
+	if err != nil || math.Abs(center.Lat-c.Lat) > 0.001 || math.Abs(center.Lon-c.Lon) > 0.001 {
+		t.Errorf("noaa.DecodeGeohash() should return the center of the geohash, got %v, %v", center, err)
+	}
+	sw, ne, err := noaa.GeohashBounds("DP3")
+	if err != nil || sw.Lat > c.Lat || ne.Lat < c.Lat || sw.Lon > c.Lon || ne.Lon < c.Lon {
+		t.Errorf("noaa.GeohashBounds() should return the corners of the geohash, got %v %v, %v", sw, ne, err)
+	}
+	if _, err := noaa.DecodeGeohash("dp3a"); err == nil {
+		t.Error("noaa.DecodeGeohash() should reject invalid characters")
+	}
+}
@@ -0,0 +1,213 @@
+package noaa
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidHWO is returned when product text is not a Hazardous Weather
+// Outlook with at least one segment.
+var ErrInvalidHWO = errors.New("invalid hazardous weather outlook")
+
+// HazardousWeatherOutlook is a parsed Hazardous Weather Outlook (HWO)
+// product. Offices issue one segment per group of zones, ex. land and marine
+// zones, separated by $$ in the product text.
+type HazardousWeatherOutlook struct {
+	Office   string // ex. National Weather Service Chicago/Romeoville IL
+	Issued   string // ex. 1030 AM CDT Tue Jul 4 2023
+	Segments []HWOSegment
+}
+
+// HWOSegment is a segment of a Hazardous Weather Outlook for a list of zones.
+type HWOSegment struct {
+	Zones   []string // UGC codes, ex. ILZ014
+	Expires string   // UGC expiration as DDHHMM in UTC, ex. 051530
+	Areas   string   // names of the zones
+	Intro   string   // ex. This Hazardous Weather Outlook is for ...
+
+	DayOne              HWOSection
+	DaysTwoThroughSeven HWOSection
+	Spotter             HWOSection // spotter information statement
+}
+
+// HWOSection is a section of an HWO segment. Period is the text following the
+// heading, ex. "This Afternoon and Tonight" for .DAY ONE...This Afternoon and
+// Tonight.
+type HWOSection struct {
+	Period string
+	Text   string
+}
+
+var (
+	// ugcStart matches the first line of a UGC block, ex. ILZ003>006-008-
+	ugcStart = regexp.MustCompile(`^[A-Z]{2}[CZ][0-9]{3}[->]`)
+	// ugcEnd matches the end of a UGC block with the expiration, ex. 051530-
+	ugcEnd = regexp.MustCompile(`[0-9]{6}-$`)
+	// hwoHeading matches a section heading, ex. .DAY ONE...Tonight.
+	hwoHeading = regexp.MustCompile(`^\.([A-Z0-9 ]+?)\.\.\.(.*)$`)
+)
+
+// ParseHazardousWeatherOutlook parses the text of an HWO product, ex. the
+// Text of LatestProduct(office, "HWO").
+func ParseHazardousWeatherOutlook(text string) (*HazardousWeatherOutlook, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	hwo := &HazardousWeatherOutlook{}
+	for _, segment := range strings.Split(text, "\n$$") {
+		lines := strings.Split(segment, "\n")
+		if hwo.Office == "" {
+			hwo.Office, hwo.Issued = hwoHeader(lines)
+		}
+		s, ok, err := parseHWOSegment(lines)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			hwo.Segments = append(hwo.Segments, s)
+		}
+	}
+	if len(hwo.Segments) == 0 {
+		return nil, fmt.Errorf("%w: no segments found", ErrInvalidHWO)
+	}
+	return hwo, nil
+}
+
+// hwoHeader returns the office and issuance time lines following the product
+// name
+func hwoHeader(lines []string) (office, issued string) {
+	for i, line := range lines {
+		if strings.EqualFold(strings.TrimSpace(line), "Hazardous Weather Outlook") && i+2 < len(lines) {
+			return strings.TrimSpace(lines[i+1]), strings.TrimSpace(lines[i+2])
+		}
+	}
+	return "", ""
+}
+
+// parseHWOSegment parses a segment, ok is false if it has no UGC block
+func parseHWOSegment(lines []string) (s HWOSegment, ok bool, err error) {
+	start := -1
+	for i, line := range lines {
+		if ugcStart.MatchString(strings.TrimSpace(line)) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return s, false, nil
+	}
+	ugc := ""
+	i := start
+	for ; i < len(lines); i++ {
+		ugc += strings.TrimSpace(lines[i])
+		if ugcEnd.MatchString(ugc) {
+			break
+		}
+	}
+	if s.Zones, s.Expires, err = ParseUGC(ugc); err != nil {
+		return s, false, err
+	}
+
+	// zone names follow the UGC block, each line ending with a hyphen, and are
+	// followed by the issuance time
+	var areas []string
+	for i++; i < len(lines) && strings.HasSuffix(strings.TrimSpace(lines[i]), "-"); i++ {
+		areas = append(areas, strings.TrimSpace(lines[i]))
+	}
+	s.Areas = strings.TrimSuffix(strings.Join(areas, ""), "-")
+
+	var section *HWOSection
+	var paragraph []string
+	var intro []string
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		text := strings.Join(paragraph, " ")
+		paragraph = nil
+		switch {
+		case section != nil && section.Text == "":
+			section.Text = text
+		case section != nil:
+			section.Text += "\n\n" + text
+		case strings.HasPrefix(text, "This Hazardous Weather Outlook"):
+			intro = append(intro, text)
+		}
+	}
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if m := hwoHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			section = s.section(m[1])
+			if section != nil {
+				section.Period = strings.TrimSuffix(strings.TrimSpace(m[2]), ".")
+			}
+			continue
+		}
+		if line == "" {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	flush()
+	s.Intro = strings.Join(intro, "\n\n")
+	return s, true, nil
+}
+
+// section returns the section for a heading, or nil for unknown headings
+func (s *HWOSegment) section(heading string) *HWOSection {
+	switch {
+	case strings.HasPrefix(heading, "DAY ONE"):
+		return &s.DayOne
+	case strings.HasPrefix(heading, "DAYS TWO THROUGH SEVEN"):
+		return &s.DaysTwoThroughSeven
+	case strings.HasPrefix(heading, "SPOTTER"):
+		return &s.Spotter
+	}
+	return nil
+}
+
+// ParseUGC expands a Universal Geographic Code block into zone or county
+// codes and returns its expiration, ex. ILZ003>005-INZ001-051530- returns
+// ILZ003, ILZ004, ILZ005 and INZ001, expiring 051530 (DDHHMM in UTC).
+func ParseUGC(ugc string) (codes []string, expires string, err error) {
+	ugc = strings.Join(strings.Fields(ugc), "")
+	prefix := ""
+	for _, token := range strings.Split(strings.TrimSuffix(ugc, "-"), "-") {
+		if len(token) == 6 && isDigits(token) {
+			expires = token
+			continue
+		}
+		if len(token) >= 6 && token[0] >= 'A' && token[0] <= 'Z' {
+			prefix, token = token[:3], token[3:]
+		}
+		if prefix == "" {
+			return nil, "", fmt.Errorf("invalid UGC %q", ugc)
+		}
+		first, last := token, token
+		if i := strings.Index(token, ">"); i >= 0 {
+			first, last = token[:i], token[i+1:]
+		}
+		from, err1 := strconv.Atoi(first)
+		to, err2 := strconv.Atoi(last)
+		if err1 != nil || err2 != nil || len(first) != 3 || len(last) != 3 || to < from {
+			return nil, "", fmt.Errorf("invalid UGC %q", ugc)
+		}
+		for n := from; n <= to; n++ {
+			codes = append(codes, fmt.Sprintf("%s%03d", prefix, n))
+		}
+	}
+	return codes, expires, nil
+}
+
+// isDigits reports whether s only contains ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestParseHazardousWeatherOutlook(t *testing.T) {
+	text, err := os.ReadFile("testdata/hwo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hwo, err := noaa.ParseHazardousWeatherOutlook(string(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hwo.Office != "National Weather Service Chicago/Romeoville IL" || hwo.Issued != "1030 AM CDT Tue Jul 4 2023" {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse the header, got %q %q", hwo.Office, hwo.Issued)
+	}
+	if len(hwo.Segments) != 2 {
+		t.Fatalf("noaa.ParseHazardousWeatherOutlook() should return 2 segments, got %d", len(hwo.Segments))
+	}
+
+	land := hwo.Segments[0]
+	zones := []string{"ILZ003", "ILZ004", "ILZ005", "ILZ006", "ILZ008", "ILZ010", "ILZ011", "ILZ012", "ILZ013", "ILZ014", "INZ001", "INZ002"}
+	if !reflect.DeepEqual(land.Zones, zones) || land.Expires != "051530" {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should expand the zones, got %v expiring %q", land.Zones, land.Expires)
+	}
+	if land.Areas != "Winnebago-Boone-McHenry-Lake IL-Ogle-Lee-DeKalb-Kane-DuPage-Cook-Lake IN-Porter" {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse the areas, got %q", land.Areas)
+	}
+	if land.Intro != "This Hazardous Weather Outlook is for portions of north central Illinois, northeast Illinois, and northwest Indiana." {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse the intro, got %q", land.Intro)
+	}
+	dayOne := noaa.HWOSection{
+		Period: "This Afternoon and Tonight",
+		Text:   "Scattered thunderstorms are expected to develop this afternoon. A few storms may produce damaging wind gusts.\n\nHeavy rainfall may cause localized flooding tonight.",
+	}
+	if land.DayOne != dayOne {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse day one, got %+v", land.DayOne)
+	}
+	if land.DaysTwoThroughSeven.Period != "Wednesday through Monday" || land.DaysTwoThroughSeven.Text != "No hazardous weather is expected at this time." {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse days two through seven, got %+v", land.DaysTwoThroughSeven)
+	}
+	if land.Spotter.Text != "Spotter activation may be needed this afternoon and evening." {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse the spotter statement, got %+v", land.Spotter)
+	}
+
+	marine := hwo.Segments[1]
+	if !reflect.DeepEqual(marine.Zones, []string{"LMZ740", "LMZ741", "LMZ742"}) {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse the marine zones, got %v", marine.Zones)
+	}
+	if marine.Spotter.Text != "Spotter activation is not expected at this time." {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should parse the marine spotter statement, got %+v", marine.Spotter)
+	}
+
+	if _, err := noaa.ParseHazardousWeatherOutlook("AREA FORECAST DISCUSSION"); !errors.Is(err, noaa.ErrInvalidHWO) {
+		t.Errorf("noaa.ParseHazardousWeatherOutlook() should return ErrInvalidHWO without segments, got %v", err)
+	}
+}
+
+func TestParseUGC(t *testing.T) {
+	codes, expires, err := noaa.ParseUGC("WIC059-127-ILC097>099-051530-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(codes, []string{"WIC059", "WIC127", "ILC097", "ILC098", "ILC099"}) || expires != "051530" {
+		t.Errorf("noaa.ParseUGC() should expand counties, got %v expiring %q", codes, expires)
+	}
+	for _, ugc := range []string{"059-051530-", "ILZ014>012-051530-", "ILZ1-051530-"} {
+		if _, _, err := noaa.ParseUGC(ugc); err == nil {
+			t.Errorf("noaa.ParseUGC(%q) should return an error", ugc)
+		}
+	}
+}
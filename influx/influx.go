@@ -0,0 +1,212 @@
+// Package influx encodes observations and gridpoint forecasts in the InfluxDB
+// line protocol so they can be written to InfluxDB or any time series database
+// which accepts it, ex. with Telegraf or the /api/v2/write endpoint:
+//
+//	var buf bytes.Buffer
+//	enc := influx.NewEncoder(&buf)
+//	enc.EncodeObservation(observation)
+//	http.Post(writeURL, "text/plain", &buf)
+//
+// Observations are written to the observation measurement tagged with the
+// station ID and gridpoint forecasts to the gridpoint measurement tagged with
+// the forecast office and grid coordinates. There is one field per variable in
+// the units returned by weather.gov and timestamps are in nanoseconds.
+package influx
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// Default measurement names
+const (
+	ObservationMeasurement = "observation"
+	GridpointMeasurement   = "gridpoint"
+)
+
+// Encoder writes line protocol to an io.Writer.
+type Encoder struct {
+	w io.Writer
+
+	// Measurement names, default to ObservationMeasurement and
+	// GridpointMeasurement
+	Observation string
+	Gridpoint   string
+
+	// Tags are added to every line, ex. a location name
+	Tags map[string]string
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, Observation: ObservationMeasurement, Gridpoint: GridpointMeasurement}
+}
+
+// observationFields are the observation values written as fields
+var observationFields = []struct {
+	name  string
+	value func(noaa.Observation) noaa.ObservationValue
+}{
+	{"temperature", func(o noaa.Observation) noaa.ObservationValue { return o.Temperature }},
+	{"dewpoint", func(o noaa.Observation) noaa.ObservationValue { return o.Dewpoint }},
+	{"relative_humidity", func(o noaa.Observation) noaa.ObservationValue { return o.RelativeHumidity }},
+	{"wind_direction", func(o noaa.Observation) noaa.ObservationValue { return o.WindDirection }},
+	{"wind_speed", func(o noaa.Observation) noaa.ObservationValue { return o.WindSpeed }},
+	{"wind_gust", func(o noaa.Observation) noaa.ObservationValue { return o.WindGust }},
+	{"barometric_pressure", func(o noaa.Observation) noaa.ObservationValue { return o.BarometricPressure }},
+	{"sea_level_pressure", func(o noaa.Observation) noaa.ObservationValue { return o.SeaLevelPressure }},
+	{"visibility", func(o noaa.Observation) noaa.ObservationValue { return o.Visibility }},
+	{"precipitation_last_hour", func(o noaa.Observation) noaa.ObservationValue { return o.PrecipitationLastHour }},
+	{"wind_chill", func(o noaa.Observation) noaa.ObservationValue { return o.WindChill }},
+	{"heat_index", func(o noaa.Observation) noaa.ObservationValue { return o.HeatIndex }},
+}
+
+// EncodeObservation writes the observation as a single line. Missing values,
+// null or without a unit, are omitted and nothing is written when the observation has no values.
+func (e *Encoder) EncodeObservation(o noaa.Observation) error {
+	tags := map[string]string{"station": noaa.StationID(o.Station)}
+	fields := map[string]float64{}
+	for _, f := range observationFields {
+		if v := f.value(o); !v.IsMissing() {
+			fields[f.name] = v.Value
+		}
+	}
+	return e.writeLine(e.Observation, ObservationMeasurement, tags, fields, o.Timestamp)
+}
+
+// EncodeGridpoint writes one line per valid time of the gridpoint forecast
+// with a field for each variable starting at that time, ex. temperature and
+// sky_cover. Field names are the snake case names of the API variables.
+func (e *Encoder) EncodeGridpoint(g *noaa.GridpointForecastResponse) error {
+	tags := map[string]string{}
+	if g.Point != nil {
+		tags["office"] = g.Point.GridID
+		tags["grid_x"] = strconv.FormatInt(g.Point.GridX, 10)
+		tags["grid_y"] = strconv.FormatInt(g.Point.GridY, 10)
+	}
+	lines := map[time.Time]map[string]float64{}
+	v := reflect.ValueOf(g).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		series, ok := v.Field(i).Interface().(noaa.GridpointForecastTimeSeries)
+		if !ok {
+			continue
+		}
+		name := fieldName(v.Type().Field(i).Tag.Get("json"))
+		for _, value := range series.Values {
+			start, _, err := value.Interval()
+			if err != nil {
+				return err
+			}
+			if lines[start] == nil {
+				lines[start] = map[string]float64{}
+			}
+			lines[start][name] = value.Value
+		}
+	}
+	times := make([]time.Time, 0, len(lines))
+	for t := range lines {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	for _, t := range times {
+		if err := e.writeLine(e.Gridpoint, GridpointMeasurement, tags, lines[t], t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLine writes a line with the tags and fields sorted by key
+func (e *Encoder) writeLine(measurement, defaultMeasurement string, tags map[string]string, fields map[string]float64, t time.Time) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if measurement == "" {
+		measurement = defaultMeasurement
+	}
+	var b strings.Builder
+	b.WriteString(escape(measurement, ", "))
+	all := map[string]string{}
+	for k, v := range e.Tags {
+		all[k] = v
+	}
+	for k, v := range tags {
+		all[k] = v
+	}
+	for _, k := range sortedKeys(all) {
+		if all[k] == "" {
+			continue
+		}
+		fmt.Fprintf(&b, ",%s=%s", escape(k, ",= "), escape(all[k], ",= "))
+	}
+	sep := " "
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	written := 0
+	for _, k := range names {
+		if math.IsNaN(fields[k]) || math.IsInf(fields[k], 0) {
+			continue
+		}
+		fmt.Fprintf(&b, "%s%s=%s", sep, escape(k, ",= "), strconv.FormatFloat(fields[k], 'f', -1, 64))
+		sep = ","
+		written++
+	}
+	if written == 0 {
+		return nil
+	}
+	fmt.Fprintf(&b, " %d\n", t.UnixNano())
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escape backslash escapes the special characters in s
+func escape(s, special string) string {
+	if !strings.ContainsAny(s, special+`\`) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\\' || strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// fieldName converts a camel case API variable name to snake case, ex.
+// skyCover -> sky_cover
+func fieldName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package influx_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/influx"
+)
+
+func TestEncodeObservation(t *testing.T) {
+	var buf bytes.Buffer
+	enc := influx.NewEncoder(&buf)
+	enc.Tags = map[string]string{"location": "My Home"}
+	err := enc.EncodeObservation(noaa.Observation{
+		Station:     "https://api.weather.gov/stations/KMDW",
+		Timestamp:   time.Unix(1688496780, 0),
+		Temperature: noaa.ObservationValue{Value: 25.6, UnitCode: "wmoUnit:degC"},
+		WindSpeed:   noaa.ObservationValue{Value: 11.16, UnitCode: "wmoUnit:km_h-1"},
+		WindGust:    noaa.ObservationValue{}, // missing
+		Dewpoint:    noaa.ObservationValue{UnitCode: "wmoUnit:degC", QualityControl: "Z", Null: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "observation,location=My\\ Home,station=KMDW temperature=25.6,wind_speed=11.16 1688496780000000000\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestEncodeGridpoint(t *testing.T) {
+	var buf bytes.Buffer
+	g := &noaa.GridpointForecastResponse{
+		Temperature: noaa.GridpointForecastTimeSeries{Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-07-04T18:00:00+00:00/PT1H", Value: 25},
+			{ValidTime: "2023-07-04T19:00:00+00:00/PT2H", Value: 24.5},
+		}},
+		SkyCover: noaa.GridpointForecastTimeSeries{Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-07-04T18:00:00+00:00/PT3H", Value: 40},
+		}},
+		Point: &noaa.PointsResponse{GridID: "LOT", GridX: 76, GridY: 73},
+	}
+	if err := influx.NewEncoder(&buf).EncodeGridpoint(g); err != nil {
+		t.Fatal(err)
+	}
+	expected := "gridpoint,grid_x=76,grid_y=73,office=LOT sky_cover=40,temperature=25 1688493600000000000\n" +
+		"gridpoint,grid_x=76,grid_y=73,office=LOT temperature=24.5 1688497200000000000\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/chrisdobbins/noaa/jsoniter
+
+go 1.18
+
+require (
+	github.com/chrisdobbins/noaa v0.0.0-20261016200604-450e99af3d61
+	github.com/json-iterator/go v1.1.12
+)
+
+require (
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+)
+
+// The replacement builds the module against the local checkout. It is ignored
+// when the module is required by other modules, which use the version above.
+replace github.com/chrisdobbins/noaa => ../
@@ -0,0 +1,27 @@
+// Package jsoniter decodes weather.gov responses with json-iterator, which is
+// faster than encoding/json for large responses such as gridpoint data:
+//
+//	noaa.SetJSONCodec(jsoniter.Codec)
+//
+// It uses the json-iterator configuration compatible with encoding/json, so
+// responses decode to the same values. It is a separate module so that the
+// noaa package does not depend on json-iterator.
+package jsoniter
+
+import (
+	jsonit "github.com/json-iterator/go"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// Codec is the json-iterator codec, see noaa.SetJSONCodec.
+var Codec noaa.JSONCodec = codec{jsonit.ConfigCompatibleWithStandardLibrary}
+
+// codec adapts a json-iterator API to noaa.JSONCodec
+type codec struct {
+	api jsonit.API
+}
+
+func (c codec) Unmarshal(data []byte, v interface{}) error {
+	return c.api.Unmarshal(data, v)
+}
@@ -0,0 +1,70 @@
+package jsoniter_test
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/jsoniter"
+)
+
+// decoders decode a response body with the current codec
+var decoders = map[string]func([]byte) (interface{}, error){
+	"points":      func(b []byte) (interface{}, error) { return noaa.DecodePoints(bytes.NewReader(b)) },
+	"forecast":    func(b []byte) (interface{}, error) { return noaa.DecodeForecast(bytes.NewReader(b)) },
+	"hourly":      func(b []byte) (interface{}, error) { return noaa.DecodeHourlyForecast(bytes.NewReader(b)) },
+	"gridpoint":   func(b []byte) (interface{}, error) { return noaa.DecodeGridpointForecast(bytes.NewReader(b)) },
+	"stations":    func(b []byte) (interface{}, error) { return noaa.DecodeStations(bytes.NewReader(b)) },
+	"observation": func(b []byte) (interface{}, error) { return noaa.DecodeObservation(bytes.NewReader(b)) },
+}
+
+// responses are decoded with both codecs, including fixtures of the noaa
+// package and the schema variants handled by custom unmarshalers
+var responses = []struct {
+	decoder string
+	body    string
+	fixture string
+}{
+	{decoder: "points", fixture: "../testdata/points.json"},
+	{decoder: "forecast", fixture: "../testdata/forecast.json"},
+	{decoder: "forecast", fixture: "../testdata/forecast_qv.json"},
+	{decoder: "points", body: `{"gridX": 76.0, "gridY": "73", "gridId": "LOT"}`},
+	{decoder: "hourly", body: `{"periods": [{"number": 1, "temperature": {"unitCode": "wmoUnit:degC", "value": 30}, "windSpeed": {"unitCode": "wmoUnit:km_h-1", "value": 16.093}}]}`},
+	{decoder: "gridpoint", body: `{"temperature": {"uom": "wmoUnit:degC", "values": [{"validTime": "2023-07-04T18:00:00+00:00/PT3H", "value": 30}]}, "hazards": {"values": [{"validTime": "2023-07-04T18:00:00+00:00/P1D", "value": [{"phenomenon": "HT", "significance": "Y", "event_number": "12"}]}]}}`},
+	{decoder: "stations", body: `{"@graph": [{"@id": "https://api.weather.gov/stations/KMDW", "stationIdentifier": "KMDW", "name": "Chicago Midway", "geometry": "POINT(-87.75 41.78)"}]}`},
+	{decoder: "stations", body: `{"observationStations": ["https://api.weather.gov/stations/KMDW", "https://api.weather.gov/stations/KORD"]}`},
+	{decoder: "observation", body: `{"timestamp": "2023-07-04T15:53:00+00:00", "rawMessage": "KMDW 041553Z 21010KT 10SM FEW250 29/18 A2992", "temperature": {"value": 29, "unitCode": "wmoUnit:degC", "qualityControl": "V"}}`},
+	{decoder: "forecast", body: `{"periods": [}`},
+	{decoder: "points", body: `{"gridX": 76.5}`},
+}
+
+func TestCodecMatchesEncodingJSON(t *testing.T) {
+	t.Cleanup(func() { noaa.SetJSONCodec(nil) })
+	for _, r := range responses {
+		body := []byte(r.body)
+		name := r.decoder + " " + r.body
+		if r.fixture != "" {
+			var err error
+			if body, err = os.ReadFile(r.fixture); err != nil {
+				t.Fatal(err)
+			}
+			name = r.fixture
+		}
+		decode := decoders[r.decoder]
+
+		noaa.SetJSONCodec(nil)
+		expected, expectedErr := decode(body)
+		noaa.SetJSONCodec(jsoniter.Codec)
+		got, err := decode(body)
+
+		if (err != nil) != (expectedErr != nil) {
+			t.Errorf("%s: expected error %v, got %v", name, expectedErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: expected %+v, got %+v", name, expected, got)
+		}
+	}
+}
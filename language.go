@@ -0,0 +1,50 @@
+package noaa
+
+import (
+	"regexp"
+	"strings"
+)
+
+// languagePattern matches ISO 639 codes and language tags, ex. es or es-US
+var languagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+// wmoHeadingPattern matches the WMO heading of a product text, ex. FXCA62
+// TJSJ 161800
+var wmoHeadingPattern = regexp.MustCompile(`^[A-Z]{4}[0-9]{2} [A-Z]{4} [0-9]{6}`)
+
+// maxLanguageProducts limits how many of the newest products LatestProduct
+// fetches looking for one in the preferred language
+const maxLanguageProducts = 5
+
+// preferredLanguage returns the configured language, blank for English
+func preferredLanguage() string {
+	lang := currentConfig().Language
+	if sameLanguage(lang, "en") {
+		return ""
+	}
+	return lang
+}
+
+// sameLanguage reports whether two language tags share their primary
+// language, ex. es and es-US
+func sameLanguage(a string, b string) bool {
+	a, _, _ = strings.Cut(a, "-")
+	b, _, _ = strings.Cut(b, "-")
+	return a != "" && strings.EqualFold(a, b)
+}
+
+// productLanguage returns the language of a product text from its AWIPS
+// identifier, the line after the WMO heading, whose SPN suffix marks Spanish
+// products, ex. AFDSPN for the Spanish Area Forecast Discussion of San Juan
+func productLanguage(text string) string {
+	lines := strings.Split(text, "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		if wmoHeadingPattern.MatchString(strings.TrimSpace(lines[i])) {
+			if awips := strings.TrimSpace(lines[i+1]); len(awips) == 6 && strings.HasSuffix(awips, "SPN") {
+				return "es"
+			}
+			break
+		}
+	}
+	return "en"
+}
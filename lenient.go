@@ -0,0 +1,111 @@
+package noaa
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DecodeProblem is a value of a response skipped by lenient decoding, see
+// SetLenientDecoding.
+type DecodeProblem struct {
+	Path string // JSON path of the value, ex. temperature.values[3]
+	Err  error
+}
+
+func (p DecodeProblem) Error() string {
+	return fmt.Sprintf("%s: %v", p.Path, p.Err)
+}
+
+// lenientResponse is implemented by the responses decoded leniently, which
+// keep the problems
+type lenientResponse interface {
+	setProblems(problems []DecodeProblem)
+}
+
+func (g *GridpointForecastResponse) setProblems(problems []DecodeProblem) { g.Problems = problems }
+func (f *ForecastResponse) setProblems(problems []DecodeProblem)          { f.Problems = problems }
+func (f *HourlyForecastResponse) setProblems(problems []DecodeProblem)    { f.Problems = problems }
+
+// unmarshalerType is the type of json.Unmarshaler
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decodeLenient decodes a JSON document into the value rv points to, skipping
+// the values which cannot be decoded: object members are decoded one by one,
+// and the elements of arrays which fail are left out. Values decoded by their
+// own UnmarshalJSON method are decoded whole.
+func decodeLenient(codec JSONCodec, data []byte, rv reflect.Value, path string, problems *[]DecodeProblem) {
+	err := codec.Unmarshal(data, rv.Addr().Interface())
+	if err == nil {
+		return
+	}
+	rv.Set(reflect.Zero(rv.Type()))
+	if !rv.Addr().Type().Implements(unmarshalerType) {
+		switch rv.Kind() {
+		case reflect.Ptr:
+			if string(data) != "null" {
+				rv.Set(reflect.New(rv.Type().Elem()))
+				decodeLenient(codec, data, rv.Elem(), path, problems)
+			}
+			return
+		case reflect.Struct:
+			var members map[string]json.RawMessage
+			if json.Unmarshal(data, &members) == nil {
+				decodeMembers(codec, members, rv, path, problems)
+				return
+			}
+		case reflect.Slice:
+			var elements []json.RawMessage
+			if json.Unmarshal(data, &elements) == nil {
+				slice := reflect.MakeSlice(rv.Type(), 0, len(elements))
+				for i, element := range elements {
+					v := reflect.New(rv.Type().Elem())
+					if err := codec.Unmarshal(element, v.Interface()); err != nil {
+						*problems = append(*problems, DecodeProblem{Path: fmt.Sprintf("%s[%d]", path, i), Err: err})
+						continue
+					}
+					slice = reflect.Append(slice, v.Elem())
+				}
+				rv.Set(slice)
+				return
+			}
+		}
+	}
+	*problems = append(*problems, DecodeProblem{Path: path, Err: err})
+}
+
+// decodeMembers decodes the members of a JSON object into the fields of a
+// struct, matching names like encoding/json, including the fields of embedded
+// structs
+func decodeMembers(codec JSONCodec, members map[string]json.RawMessage, rv reflect.Value, path string, problems *[]DecodeProblem) {
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case field.PkgPath != "" && !field.Anonymous, name == "-":
+			continue
+		case field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct:
+			decodeMembers(codec, members, rv.Field(i), path, problems)
+			continue
+		case name == "":
+			name = field.Name
+		}
+		raw, ok := members[name]
+		if !ok {
+			for member, value := range members {
+				if strings.EqualFold(member, name) {
+					raw, ok = value, true
+					break
+				}
+			}
+		}
+		if ok {
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			decodeLenient(codec, raw, rv.Field(i), fieldPath, problems)
+		}
+	}
+}
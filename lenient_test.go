@@ -0,0 +1,58 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+const malformedGridpoint = `{
+	"updateTime": "2023-07-04T18:00:00+00:00",
+	"temperature": {"uom": "wmoUnit:degC", "values": [
+		{"validTime": "2023-07-04T18:00:00+00:00/PT1H", "value": 21},
+		{"validTime": "2023-07-04T19:00:00+00:00/PT1H", "value": "hot"},
+		{"validTime": "2023-07-04T20:00:00+00:00/PT1H", "value": 23}
+	]},
+	"windSpeed": "unavailable",
+	"dewpoint": {"uom": "wmoUnit:degC", "values": [{"validTime": "2023-07-04T18:00:00+00:00/PT1H", "value": 15}]}
+}`
+
+func TestLenientDecoding(t *testing.T) {
+	t.Cleanup(func() { noaa.SetConfig(noaa.GetDefaultConfig()) })
+	if _, err := noaa.DecodeGridpointForecast(strings.NewReader(malformedGridpoint)); !errors.Is(err, noaa.ErrInvalidResponse) {
+		t.Fatalf("noaa.DecodeGridpointForecast() should fail by default, got %v", err)
+	}
+
+	noaa.SetLenientDecoding(true)
+	g, err := noaa.DecodeGridpointForecast(strings.NewReader(malformedGridpoint))
+	if err != nil {
+		t.Fatalf("noaa.DecodeGridpointForecast() should decode leniently, got %v", err)
+	}
+	if g.Updated == "" || len(g.Dewpoint.Values) != 1 || g.Temperature.Uom != "wmoUnit:degC" {
+		t.Errorf("noaa.DecodeGridpointForecast() should decode the valid values, got %+v", g)
+	}
+	if len(g.Temperature.Values) != 2 || g.Temperature.Values[1].Value != 23 {
+		t.Errorf("noaa.DecodeGridpointForecast() should skip the malformed value of a series, got %+v", g.Temperature.Values)
+	}
+	if len(g.Problems) != 2 || g.Problems[0].Path != "temperature.values[1]" || g.Problems[1].Path != "windSpeed" {
+		t.Errorf("noaa.DecodeGridpointForecast() should record the skipped values, got %v", g.Problems)
+	}
+
+	forecast, err := noaa.DecodeForecast(strings.NewReader(`{"updated": "2023-07-04T18:00:00+00:00", "periods": [{"number": 1, "name": "Tonight"}, {"number": "two"}]}`))
+	if err != nil || len(forecast.Periods) != 1 || len(forecast.Problems) != 1 || forecast.Problems[0].Path != "periods[1]" {
+		t.Errorf("noaa.DecodeForecast() should skip the malformed period, got %+v, %v", forecast, err)
+	}
+	bundle := &noaa.WeatherBundle{Forecast: forecast}
+	if problems := bundle.Problems(); len(problems) != 1 || problems[0].Path != "forecast.periods[1]" {
+		t.Errorf("noaa.WeatherBundle.Problems() should prefix the problems of the components, got %v", problems)
+	}
+
+	if _, err := noaa.DecodeGridpointForecast(strings.NewReader(`{"temperature": {`)); !errors.Is(err, noaa.ErrInvalidResponse) {
+		t.Errorf("noaa.DecodeGridpointForecast() should still fail on invalid JSON, got %v", err)
+	}
+}
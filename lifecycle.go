@@ -0,0 +1,275 @@
+package noaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Errors returned by the lifecycle methods of components
+var (
+	ErrAlreadyStarted = errors.New("component already started")
+	ErrStopTimeout    = errors.New("component did not stop before the timeout")
+)
+
+// State is the lifecycle state of a component.
+type State int
+
+// Lifecycle states. A component which stopped because of an error is Failed.
+const (
+	StateStopped State = iota
+	StateRunning
+	StateStopping
+	StateFailed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateStopped:
+		return "stopped"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Health describes the status of a component.
+type Health struct {
+	State       State
+	Since       time.Time // time of the last state change
+	Err         error     // the last error reported, if any
+	LastSuccess time.Time // time the component last completed its work
+}
+
+// Healthy reports whether the component is running and the last reported
+// result was a success.
+func (h Health) Healthy() bool {
+	return h.State == StateRunning && h.Err == nil
+}
+
+// Component is a background component, ex. a Poller, which runs until it is
+// stopped.
+type Component interface {
+	// Start starts the component in a new goroutine and returns immediately.
+	// The component stops when ctx is cancelled or Stop is called.
+	Start(ctx context.Context) error
+	// Stop stops the component and waits up to timeout for in-flight work to
+	// finish. ErrStopTimeout is returned if the component does not stop in
+	// time.
+	Stop(timeout time.Duration) error
+	Health() Health
+}
+
+// lifecycle implements the Component methods for a run function
+type lifecycle struct {
+	mu     sync.Mutex
+	health Health
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start calls run in a new goroutine with a context cancelled by stop
+func (l *lifecycle) start(ctx context.Context, run func(context.Context) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.health.State == StateRunning || l.health.State == StateStopping {
+		return ErrAlreadyStarted
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	l.cancel, l.done = cancel, done
+	l.health = Health{State: StateRunning, Since: time.Now(), LastSuccess: l.health.LastSuccess}
+	go func() {
+		defer close(done)
+		err := run(ctx)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if err != nil && !(ctx.Err() != nil && errors.Is(err, ctx.Err())) {
+			l.health.State, l.health.Err = StateFailed, err
+		} else {
+			l.health.State = StateStopped
+		}
+		l.health.Since = time.Now()
+		cancel()
+	}()
+	return nil
+}
+
+// stop cancels the run function and waits for it to return
+func (l *lifecycle) stop(timeout time.Duration) error {
+	l.mu.Lock()
+	if l.done == nil {
+		l.mu.Unlock()
+		return nil
+	}
+	if l.health.State == StateRunning {
+		l.health.State, l.health.Since = StateStopping, time.Now()
+	}
+	l.cancel()
+	done := l.done
+	l.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return ErrStopTimeout
+	}
+}
+
+// report records the result of a unit of work, ex. a poll
+func (l *lifecycle) report(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.health.Err = err
+	if err == nil {
+		l.health.LastSuccess = time.Now()
+	}
+}
+
+// status returns the current health
+func (l *lifecycle) status() Health {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.health
+}
+
+// Service is a Component running a function, ex. an HTTP server, so that it
+// can be managed with other components by a Runtime.
+type Service struct {
+	run  func(context.Context) error
+	life lifecycle
+}
+
+// NewService returns a Service calling run when started. run should return
+// when its context is cancelled.
+func NewService(run func(ctx context.Context) error) *Service {
+	return &Service{run: run}
+}
+
+// Start implements Component.
+func (s *Service) Start(ctx context.Context) error {
+	return s.life.start(ctx, s.run)
+}
+
+// Stop implements Component.
+func (s *Service) Stop(timeout time.Duration) error {
+	return s.life.stop(timeout)
+}
+
+// Health implements Component.
+func (s *Service) Health() Health {
+	return s.life.status()
+}
+
+// Runtime starts, stops and reports the health of a set of named components.
+// For example:
+//
+//	rt := noaa.NewRuntime()
+//	rt.Add("poller", poller)
+//	rt.Add("http", noaa.NewService(serve))
+//	rt.Start(ctx)
+//	<-ctx.Done()
+//	rt.Stop(10 * time.Second)
+type Runtime struct {
+	mu         sync.Mutex
+	names      []string
+	components map[string]Component
+}
+
+// NewRuntime returns a Runtime without components.
+func NewRuntime() *Runtime {
+	return &Runtime{components: map[string]Component{}}
+}
+
+// Add adds a component, replacing any component with the same name. Components
+// are started in the order they are added and stopped in reverse order.
+func (r *Runtime) Add(name string, c Component) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.components[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.components[name] = c
+}
+
+// Start starts all components. If a component fails to start the components
+// already started are stopped and the error is returned.
+func (r *Runtime) Start(ctx context.Context) error {
+	names, components := r.list()
+	for i, name := range names {
+		if err := components[i].Start(ctx); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				components[j].Stop(0)
+			}
+			return fmt.Errorf("starting %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Stop stops all components in reverse order. The timeout is shared by all
+// components; those which do not stop in time are named in the error.
+func (r *Runtime) Stop(timeout time.Duration) error {
+	names, components := r.list()
+	deadline := time.Now().Add(timeout)
+	var failed []string
+	for i := len(components) - 1; i >= 0; i-- {
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+		if err := components[i].Stop(remaining); err != nil {
+			failed = append(failed, names[i])
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %v", ErrStopTimeout, failed)
+	}
+	return nil
+}
+
+// Health returns the health of each component by name.
+func (r *Runtime) Health() map[string]Health {
+	names, components := r.list()
+	health := make(map[string]Health, len(names))
+	for i, name := range names {
+		health[name] = components[i].Health()
+	}
+	return health
+}
+
+// Healthy reports whether all components are healthy.
+func (r *Runtime) Healthy() bool {
+	for _, h := range r.Health() {
+		if !h.Healthy() {
+			return false
+		}
+	}
+	return true
+}
+
+// list returns the components in the order they were added
+func (r *Runtime) list() ([]string, []Component) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := append([]string(nil), r.names...)
+	components := make([]Component, len(names))
+	for i, name := range names {
+		components[i] = r.components[name]
+	}
+	return names, components
+}
@@ -0,0 +1,77 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestServiceLifecycle(t *testing.T) {
+	stopped := make(chan struct{})
+	s := noaa.NewService(func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return ctx.Err()
+	})
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); !errors.Is(err, noaa.ErrAlreadyStarted) {
+		t.Errorf("noaa.Service.Start() should return ErrAlreadyStarted, got %v", err)
+	}
+	if h := s.Health(); h.State != noaa.StateRunning || !h.Healthy() {
+		t.Errorf("noaa.Service.Health() should be running, got %v", h.State)
+	}
+	if err := s.Stop(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	<-stopped
+	if h := s.Health(); h.State != noaa.StateStopped {
+		t.Errorf("noaa.Service.Health() should be stopped, got %v", h.State)
+	}
+}
+
+func TestServiceFailure(t *testing.T) {
+	failure := errors.New("listen failed")
+	s := noaa.NewService(func(ctx context.Context) error { return failure })
+	s.Start(context.Background())
+	s.Stop(time.Second)
+	if h := s.Health(); h.State != noaa.StateFailed || h.Err != failure {
+		t.Errorf("noaa.Service.Health() should be failed, got %v %v", h.State, h.Err)
+	}
+}
+
+func TestRuntimeStopTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	rt := noaa.NewRuntime()
+	rt.Add("slow", noaa.NewService(func(ctx context.Context) error {
+		<-ctx.Done()
+		<-release
+		return nil
+	}))
+	rt.Add("fast", noaa.NewService(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}))
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !rt.Healthy() {
+		t.Error("noaa.Runtime.Healthy() should be true after Start()")
+	}
+	err := rt.Stop(50 * time.Millisecond)
+	if !errors.Is(err, noaa.ErrStopTimeout) {
+		t.Errorf("noaa.Runtime.Stop() should return ErrStopTimeout, got %v", err)
+	}
+	health := rt.Health()
+	if health["fast"].State != noaa.StateStopped || health["slow"].State != noaa.StateStopping {
+		t.Errorf("noaa.Runtime.Health() returned unexpected states %v", health)
+	}
+}
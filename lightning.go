@@ -0,0 +1,122 @@
+package noaa
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LightningRisk is the lightning risk of an hour, see LightningHours.
+type LightningRisk int
+
+// Lightning risks by increasing severity
+const (
+	LightningRiskNone LightningRisk = iota
+	LightningRiskLow
+	LightningRiskModerate
+	LightningRiskHigh
+)
+
+// lightningRiskNames are the names of the lightning risks
+var lightningRiskNames = [...]string{"None", "Low", "Moderate", "High"}
+
+// String returns the name of the risk, ex. Moderate.
+func (r LightningRisk) String() string {
+	if r < 0 || int(r) >= len(lightningRiskNames) {
+		return fmt.Sprintf("LightningRisk(%d)", int(r))
+	}
+	return lightningRiskNames[r]
+}
+
+// LightningThresholds are the minimum probability of thunder, in percent, and
+// lightning activity level (LAL) of each risk. An hour has the highest risk
+// whose probability or level it reaches. A zero threshold is not used.
+//
+// The LAL ranges from 1, no thunderstorms, to 5, numerous thunderstorms, and
+// 6, dry lightning, which is always a high risk since it strikes without the
+// rain that sends people indoors.
+type LightningThresholds struct {
+	LowProbability      float64
+	ModerateProbability float64
+	HighProbability     float64
+
+	LowLevel      int
+	ModerateLevel int
+	HighLevel     int
+}
+
+// DefaultLightningThresholds rate isolated thunderstorms (LAL 2) or a 15%
+// chance of thunder a low risk, widely scattered thunderstorms (LAL 3) or 30%
+// a moderate risk, and numerous thunderstorms (LAL 5) or 55% a high risk.
+var DefaultLightningThresholds = LightningThresholds{
+	LowProbability:      15,
+	ModerateProbability: 30,
+	HighProbability:     55,
+	LowLevel:            2,
+	ModerateLevel:       3,
+	HighLevel:           5,
+}
+
+// rate returns the risk of a probability of thunder and activity level
+func (th LightningThresholds) rate(probability float64, level int) LightningRisk {
+	reaches := func(p float64, l int) bool {
+		return (p > 0 && probability >= p) || (l > 0 && level >= l)
+	}
+	switch {
+	case level == 6 || reaches(th.HighProbability, th.HighLevel):
+		return LightningRiskHigh
+	case reaches(th.ModerateProbability, th.ModerateLevel):
+		return LightningRiskModerate
+	case reaches(th.LowProbability, th.LowLevel):
+		return LightningRiskLow
+	}
+	return LightningRiskNone
+}
+
+// LightningHour is the lightning risk of an hour.
+type LightningHour struct {
+	Time                 time.Time // start of the hour
+	Risk                 LightningRisk
+	ProbabilityOfThunder float64 // percent, 0 if not forecast
+	ActivityLevel        int     // LAL from 1 to 6, 0 if not forecast
+}
+
+// LightningForecast returns the hourly lightning risk of the gridpoint
+// forecast of <lat,lon> rated with the thresholds, see LightningHours.
+func LightningForecast(lat string, lon string, th LightningThresholds) ([]LightningHour, error) {
+	forecast, err := GridpointForecast(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	return forecast.LightningHours(th), nil
+}
+
+// LightningHours returns the lightning risk of each hour covered by the
+// ProbabilityOfThunder or LightningActivityLevel series, in order, rated with
+// the thresholds, ex. DefaultLightningThresholds. Outdoor events and pools
+// usually clear people on a moderate risk.
+func (g *GridpointForecastResponse) LightningHours(th LightningThresholds) []LightningHour {
+	hours := map[time.Time]*LightningHour{}
+	hour := func(t time.Time) *LightningHour {
+		t = t.UTC().Truncate(time.Hour)
+		h := hours[t]
+		if h == nil {
+			h = &LightningHour{Time: t}
+			hours[t] = h
+		}
+		return h
+	}
+	for _, v := range g.ProbabilityOfThunder.Series().Resample(time.Hour).Values {
+		hour(v.Start).ProbabilityOfThunder = v.Value
+	}
+	for _, v := range g.LightningActivityLevel.Series().Resample(time.Hour).Values {
+		hour(v.Start).ActivityLevel = int(v.Value)
+	}
+	result := make([]LightningHour, 0, len(hours))
+	for _, h := range hours {
+		h.Risk = th.rate(h.ProbabilityOfThunder, h.ActivityLevel)
+		result = append(result, *h)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
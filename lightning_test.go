@@ -0,0 +1,47 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestLightningHours(t *testing.T) {
+	g := &noaa.GridpointForecastResponse{
+		ProbabilityOfThunder: noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:percent", Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-07-04T18:00:00+00:00/PT2H", Value: 20},
+			{ValidTime: "2023-07-04T20:00:00+00:00/PT1H", Value: 60},
+		}},
+		LightningActivityLevel: noaa.GridpointForecastTimeSeries{Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-07-04T19:00:00+00:00/PT1H", Value: 3},
+			{ValidTime: "2023-07-04T21:00:00+00:00/PT1H", Value: 6},
+			{ValidTime: "2023-07-04T22:00:00+00:00/PT1H", Value: 1},
+		}},
+	}
+	hours := g.LightningHours(noaa.DefaultLightningThresholds)
+	want := []noaa.LightningRisk{noaa.LightningRiskLow, noaa.LightningRiskModerate, noaa.LightningRiskHigh, noaa.LightningRiskHigh, noaa.LightningRiskNone}
+	if len(hours) != len(want) {
+		t.Fatalf("noaa.GridpointForecastResponse.LightningHours() should rate each hour of either series, got %+v", hours)
+	}
+	start := time.Date(2023, 7, 4, 18, 0, 0, 0, time.UTC)
+	for i, h := range hours {
+		if !h.Time.Equal(start.Add(time.Duration(i)*time.Hour)) || h.Risk != want[i] {
+			t.Errorf("noaa.GridpointForecastResponse.LightningHours() hour %d should be %s, got %+v", i, want[i], h)
+		}
+	}
+	if hours[1].ProbabilityOfThunder != 20 || hours[1].ActivityLevel != 3 {
+		t.Errorf("noaa.GridpointForecastResponse.LightningHours() should combine both series, got %+v", hours[1])
+	}
+
+	strict := noaa.LightningThresholds{LowProbability: 10, ModerateProbability: 15, HighProbability: 20}
+	if hours := g.LightningHours(strict); hours[0].Risk != noaa.LightningRiskHigh || hours[4].Risk != noaa.LightningRiskNone {
+		t.Errorf("noaa.GridpointForecastResponse.LightningHours() should use the thresholds of the caller, got %s and %s", hours[0].Risk, hours[4].Risk)
+	}
+	if noaa.LightningRiskModerate.String() != "Moderate" {
+		t.Errorf("noaa.LightningRisk.String() = %s", noaa.LightningRiskModerate)
+	}
+}
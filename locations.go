@@ -0,0 +1,166 @@
+package noaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownLocation is returned when a location name is not registered.
+var ErrUnknownLocation = errors.New("unknown location")
+
+// LocationRegistry holds named locations, ex. home and cabin, with their
+// units and alert filter, so they can be passed by name to ForecastFor,
+// HourlyForecastFor, AlertsFor, LatestObservationFor and WeatherBundleFor,
+// and polled by a Poller, see All. Names are compared ignoring case. A
+// LocationRegistry is safe for concurrent use.
+type LocationRegistry struct {
+	mu        sync.RWMutex
+	locations map[string]Location
+}
+
+// DefaultLocations is the registry used by RegisterLocation and the functions
+// taking a location name.
+var DefaultLocations = NewLocationRegistry()
+
+// NewLocationRegistry returns an empty registry.
+func NewLocationRegistry() *LocationRegistry {
+	return &LocationRegistry{locations: map[string]Location{}}
+}
+
+// Add registers the location under its name, replacing any location with the
+// same name. The name is required and the coordinates must be valid.
+func (r *LocationRegistry) Add(loc Location) error {
+	key := locationKey(loc.Name)
+	if key == "" {
+		return errors.New("location name is required")
+	}
+	if _, err := ParseCoordinates(loc.Lat, loc.Lon); err != nil {
+		return fmt.Errorf("location %s: %w", loc.Name, err)
+	}
+	if _, err := unitsQuery(loc.Units); err != nil {
+		return fmt.Errorf("location %s: %w", loc.Name, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.locations[key] = loc
+	return nil
+}
+
+// Remove removes the location with the name, if any.
+func (r *LocationRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.locations, locationKey(name))
+}
+
+// Get returns the location with the name, or an error wrapping
+// ErrUnknownLocation.
+func (r *LocationRegistry) Get(name string) (Location, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	loc, ok := r.locations[locationKey(name)]
+	if !ok {
+		return Location{}, fmt.Errorf("%w %q", ErrUnknownLocation, name)
+	}
+	return loc, nil
+}
+
+// All returns the registered locations sorted by name, ex. to set the
+// Locations of a Poller.
+func (r *LocationRegistry) All() []Location {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	locations := make([]Location, 0, len(r.locations))
+	for _, loc := range r.locations {
+		locations = append(locations, loc)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		return locationKey(locations[i].Name) < locationKey(locations[j].Name)
+	})
+	return locations
+}
+
+// locationKey returns the registry key of a location name
+func locationKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// RegisterLocation adds the location to DefaultLocations, ex.
+// RegisterLocation(Location{Name: "home", Lat: "41.837", Lon: "-87.685"}).
+func RegisterLocation(loc Location) error {
+	return DefaultLocations.Add(loc)
+}
+
+// LookupLocation returns the location registered in DefaultLocations with
+// the name.
+func LookupLocation(name string) (Location, error) {
+	return DefaultLocations.Get(name)
+}
+
+// ForecastFor returns the forecast of a registered location in its units.
+func ForecastFor(name string) (*ForecastResponse, error) {
+	loc, err := LookupLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	return ForecastWithUnits(loc.Lat, loc.Lon, loc.Units)
+}
+
+// HourlyForecastFor returns the hourly forecast of a registered location in
+// its units.
+func HourlyForecastFor(name string) (*HourlyForecastResponse, error) {
+	loc, err := LookupLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	return HourlyForecastWithUnits(loc.Lat, loc.Lon, loc.Units)
+}
+
+// AlertsFor returns the active alerts of a registered location matching its
+// alert filter.
+func AlertsFor(name string) ([]Alert, error) {
+	loc, err := LookupLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	return loc.alerts(context.Background())
+}
+
+// LatestObservationFor returns the latest observation of the nearest station
+// of a registered location, see LatestObservationWithFallback.
+func LatestObservationFor(name string) (*StationObservation, error) {
+	loc, err := LookupLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	return LatestObservationWithFallback(loc.Lat, loc.Lon, 0)
+}
+
+// WeatherBundleFor returns the weather bundle of a registered location, with
+// the alerts filtered by its alert filter. The forecasts are in the configured
+// units, as for GetWeatherBundle. Each component holds the lookup error if
+// the location is not registered.
+func WeatherBundleFor(name string) *WeatherBundle {
+	loc, err := LookupLocation(name)
+	if err != nil {
+		return &WeatherBundle{ForecastErr: err, HourlyErr: err, ObservationErr: err, AlertsErr: err}
+	}
+	bundle := GetWeatherBundle(loc.Lat, loc.Lon)
+	if !loc.Alerts.isZero() {
+		bundle.Alerts = loc.Alerts.Apply(bundle.Alerts)
+	}
+	return bundle
+}
+
+// alerts returns the active alerts of the location matching its filter
+func (loc Location) alerts(ctx context.Context) ([]Alert, error) {
+	if loc.Alerts.isZero() {
+		return AlertsContext(ctx, loc.Lat, loc.Lon)
+	}
+	return alertsQuery(ctx, url.Values{"point": {loc.Lat + "," + loc.Lon}}, loc.Alerts)
+}
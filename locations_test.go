@@ -0,0 +1,73 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestLocationRegistry(t *testing.T) {
+	registry := noaa.NewLocationRegistry()
+	if err := registry.Add(noaa.Location{Name: "Home", Lat: "41.837", Lon: "-87.685"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Add(noaa.Location{Name: "cabin", Lat: "45.9", Lon: "-89.7", Units: "si"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, loc := range []noaa.Location{
+		{Lat: "41.837", Lon: "-87.685"},
+		{Name: "office", Lat: "north", Lon: "-87.685"},
+		{Name: "office", Lat: "41.837", Lon: "-87.685", Units: "imperial"},
+	} {
+		if err := registry.Add(loc); err == nil {
+			t.Errorf("LocationRegistry.Add() should reject %+v", loc)
+		}
+	}
+	if loc, err := registry.Get(" home "); err != nil || loc.Lat != "41.837" {
+		t.Errorf("LocationRegistry.Get() should ignore case, got %+v, %v", loc, err)
+	}
+	if all := registry.All(); len(all) != 2 || all[0].Name != "cabin" || all[1].Name != "Home" {
+		t.Errorf("LocationRegistry.All() should return the locations sorted by name, got %+v", all)
+	}
+	registry.Remove("HOME")
+	if _, err := registry.Get("home"); !errors.Is(err, noaa.ErrUnknownLocation) {
+		t.Errorf("LocationRegistry.Get() should return ErrUnknownLocation for removed locations, got %v", err)
+	}
+}
+
+func TestAlertsFor(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/alerts/active": `{"@graph": [
+			{"id": "urn:oid:1", "event": "Heat Advisory", "severity": "Moderate"},
+			{"id": "urn:oid:2", "event": "Tornado Warning", "severity": "Extreme"}
+		]}`,
+	})
+	err := noaa.RegisterLocation(noaa.Location{
+		Name:   "home",
+		Lat:    "41.837",
+		Lon:    "-87.685",
+		Alerts: noaa.AlertFilter{MinSeverity: noaa.SeveritySevere},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { noaa.DefaultLocations.Remove("home") })
+
+	alerts, err := noaa.AlertsFor("home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Event != "Tornado Warning" {
+		t.Errorf("noaa.AlertsFor() should apply the alert filter of the location, got %+v", alerts)
+	}
+	if _, err := noaa.AlertsFor("cabin"); !errors.Is(err, noaa.ErrUnknownLocation) {
+		t.Errorf("noaa.AlertsFor() should return ErrUnknownLocation for unregistered names, got %v", err)
+	}
+	if _, err := noaa.ForecastFor("cabin"); !errors.Is(err, noaa.ErrUnknownLocation) {
+		t.Errorf("noaa.ForecastFor() should return ErrUnknownLocation for unregistered names, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package noaa
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// marineAreas are the prefixes of marine zone IDs, ex. LM for Lake Michigan
+// in LMZ741
+var marineAreas = map[string]bool{
+	"AM": true, // western Atlantic, Puerto Rico and the Virgin Islands
+	"AN": true, // northwestern Atlantic
+	"GM": true, // Gulf of Mexico
+	"LC": true, // Lake St. Clair
+	"LE": true, // Lake Erie
+	"LH": true, // Lake Huron
+	"LM": true, // Lake Michigan
+	"LO": true, // Lake Ontario
+	"LS": true, // Lake Superior
+	"SL": true, // St. Lawrence River
+	"PH": true, // Hawaii
+	"PK": true, // Alaska
+	"PM": true, // Guam and the Northern Mariana Islands
+	"PS": true, // American Samoa
+	"PZ": true, // eastern Pacific
+}
+
+// IsMarineZone reports whether the zone ID is a coastal or offshore marine
+// zone, ex. LMZ741 or ANZ835.
+func IsMarineZone(zoneID string) bool {
+	return len(zoneID) == 6 && marineAreas[zoneID[:2]] && zoneID[2] == 'Z' && isDigits(zoneID[3:])
+}
+
+// MarineZonesForPoint returns the coastal and offshore zones containing a
+// point. Unlike the forecast zones of ZonesForPoint it does not look up the
+// point, which fails for points over water outside the forecast grids.
+func MarineZonesForPoint(lat string, lon string) ([]Zone, error) {
+	query := url.Values{"point": {lat + "," + lon}, "type": {ZoneTypeCoastal + "," + ZoneTypeOffshore}}
+	var r struct {
+		Zones []Zone `json:"@graph"`
+	}
+	if err := getDecoded(apiURL("zones").withQuery(query).String(), &r); err != nil {
+		return nil, err
+	}
+	return r.Zones, nil
+}
+
+// AlertsForMarineZone returns the active alerts for a marine zone, ex. small
+// craft advisories and gale warnings for LMZ741.
+func AlertsForMarineZone(zoneID string) ([]Alert, error) {
+	zoneID = strings.ToUpper(strings.TrimSpace(zoneID))
+	if !IsMarineZone(zoneID) {
+		return nil, fmt.Errorf("%q is not a marine zone ID", zoneID)
+	}
+	return AlertsForZone(zoneID)
+}
+
+// MarineAlerts returns the active alerts for the marine zones containing a
+// point, without duplicates, see MarineZonesForPoint.
+func MarineAlerts(lat string, lon string) ([]Alert, error) {
+	zones, err := MarineZonesForPoint(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	alerts := []Alert{}
+	seen := map[string]bool{}
+	for _, zone := range zones {
+		id := zone.ID
+		if id == "" {
+			id = ZoneID(zone.URI)
+		}
+		zoneAlerts, err := AlertsForMarineZone(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range zoneAlerts {
+			if !seen[a.Identifier] {
+				seen[a.Identifier] = true
+				alerts = append(alerts, a)
+			}
+		}
+	}
+	return alerts, nil
+}
+
+// GetMarineZoneForecast returns the coastal waters or offshore forecast of a
+// marine zone, ex. GetMarineZoneForecast(ZoneTypeCoastal, "LMZ741"). The text
+// of each period can be parsed with ParseZoneForecastText, though marine
+// forecasts mostly describe winds and waves.
+func GetMarineZoneForecast(zoneType string, zoneID string) (*ZoneForecastResponse, error) {
+	if zoneType != ZoneTypeCoastal && zoneType != ZoneTypeOffshore {
+		return nil, fmt.Errorf("%q is not a marine zone type", zoneType)
+	}
+	if !IsMarineZone(zoneID) {
+		return nil, fmt.Errorf("%q is not a marine zone ID", zoneID)
+	}
+	return zoneForecast(zoneType, zoneID)
+}
@@ -0,0 +1,62 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestIsMarineZone(t *testing.T) {
+	for id, marine := range map[string]bool{
+		"LMZ741": true,
+		"ANZ835": true,
+		"PZZ530": true,
+		"ILZ014": false,
+		"ILC031": false,
+		"LMZ74":  false,
+		"LMC741": false,
+	} {
+		if noaa.IsMarineZone(id) != marine {
+			t.Errorf("noaa.IsMarineZone(%q) should return %v", id, marine)
+		}
+	}
+}
+
+func TestMarineAlerts(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/zones": `{"@graph": [
+			{"@id": "{api}/zones/coastal/LMZ741", "id": "LMZ741", "type": "coastal", "name": "Wilmette Harbor to Northerly Island IL"},
+			{"@id": "{api}/zones/offshore/LMZ080", "type": "offshore", "name": "Lake Michigan North of a line"}
+		]}`,
+		"/alerts/active/zone/LMZ741": `{"@graph": [{"id": "urn:oid:1", "event": "Small Craft Advisory"}, {"id": "urn:oid:2", "event": "Gale Warning"}]}`,
+		"/alerts/active/zone/LMZ080": `{"@graph": [{"id": "urn:oid:2", "event": "Gale Warning"}]}`,
+		"/zones/coastal/LMZ741/forecast": `{"zone": "{api}/zones/coastal/LMZ741", "periods": [
+			{"number": 1, "name": "Tonight", "detailedForecast": "North winds 15 to 25 kt. Waves 3 to 5 ft."}
+		]}`,
+	})
+
+	alerts, err := noaa.MarineAlerts("41.9", "-87.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 || alerts[0].Event != "Small Craft Advisory" || alerts[1].Event != "Gale Warning" {
+		t.Errorf("noaa.MarineAlerts() should return the alerts of each marine zone once, got %+v", alerts)
+	}
+	if _, err := noaa.AlertsForMarineZone("ILZ014"); err == nil {
+		t.Error("noaa.AlertsForMarineZone() should reject land zones")
+	}
+
+	forecast, err := noaa.GetMarineZoneForecast(noaa.ZoneTypeCoastal, "LMZ741")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forecast.Periods) != 1 || forecast.Periods[0].Name != "Tonight" {
+		t.Errorf("noaa.GetMarineZoneForecast() should return the periods, got %+v", forecast)
+	}
+	if _, err := noaa.GetMarineZoneForecast(noaa.ZoneTypeForecast, "LMZ741"); err == nil {
+		t.Error("noaa.GetMarineZoneForecast() should reject land zone types")
+	}
+}
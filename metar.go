@@ -0,0 +1,283 @@
+package noaa
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMETAR is returned for reports without a station and time.
+var ErrInvalidMETAR = errors.New("invalid METAR")
+
+// METAR is a decoded METAR or SPECI report, see Observation.RawMessage.
+// Values which are missing from the report are zero or nil.
+type METAR struct {
+	Raw       string
+	Type      string // METAR or SPECI
+	Station   string // ex. KORD
+	Day       int    // day of the month of the observation, UTC
+	Hour      int
+	Minute    int
+	Auto      bool // automated observation without human intervention
+	Corrected bool // COR
+
+	Wind       *METARWind
+	Visibility *METARVisibility
+	Weather    []METARWeather
+	Clouds     []METARCloud
+
+	// Temperature and Dewpoint in degrees C, with tenths when the remarks
+	// include the T group
+	Temperature *float64
+	Dewpoint    *float64
+
+	Altimeter     float64 // in AltimeterUnit
+	AltimeterUnit string  // inHg or hPa
+
+	Remarks  string   // text after RMK
+	Unparsed []string // groups before the remarks which were not decoded
+}
+
+// METARWind is the wind group of a METAR, ex. 27015G25KT. Direction is 0 for
+// variable winds (VRB) and calm winds (00000KT). VariableFrom and VariableTo
+// are set when the direction varies, ex. 240V300.
+type METARWind struct {
+	Direction    int // degrees true
+	Variable     bool
+	Speed        int
+	Gust         int
+	Unit         string // KT or MPS
+	VariableFrom int
+	VariableTo   int
+}
+
+// METARVisibility is the prevailing visibility, ex. 1 1/2SM or 9999 meters.
+type METARVisibility struct {
+	Distance float64
+	Unit     string // SM (statute miles) or m
+	LessThan bool   // ex. M1/4SM
+	MoreThan bool   // ex. P6SM
+}
+
+// METARWeather is a present weather group, ex. -TSRA or VCSH.
+type METARWeather struct {
+	Raw        string
+	Intensity  string // - (light), + (heavy) or blank for moderate
+	InVicinity bool
+	Descriptor string   // ex. TS, SH, FZ, BL
+	Phenomena  []string // ex. RA, SN, BR
+}
+
+// METARCloud is a sky condition group, ex. BKN015CB. Base is in feet above
+// ground level and is 0 for clear skies.
+type METARCloud struct {
+	Amount CloudAmount
+	Base   int
+	Type   string // CB (cumulonimbus) or TCU (towering cumulus)
+}
+
+var (
+	metarTime        = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	metarWind        = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS)$`)
+	metarWindVary    = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	metarVisSM       = regexp.MustCompile(`^([MP])?(\d+(?:/\d+)?)SM$`)
+	metarVisMeters   = regexp.MustCompile(`^(\d{4})$`)
+	metarRVR         = regexp.MustCompile(`^R\d{2}[LRC]?/`)
+	metarWeather     = regexp.MustCompile(`^([-+]|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ)?((?:DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*)$`)
+	metarCloud       = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU)?$`)
+	metarTemperature = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	metarAltimeter   = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	metarTGroup      = regexp.MustCompile(`\bT([01])(\d{3})([01])(\d{3})\b`)
+)
+
+// ParseMETAR decodes a METAR or SPECI report, ex. the RawMessage of an
+// observation:
+//
+//	KORD 041751Z 27015G25KT 10SM -TSRA BKN035CB OVC100 26/18 A2992 RMK AO2 T02560183
+//
+// Groups which cannot be decoded are returned in Unparsed.
+func ParseMETAR(raw string) (*METAR, error) {
+	m := &METAR{Raw: strings.TrimSpace(raw)}
+	body := strings.TrimSuffix(m.Raw, "=")
+	if i := strings.Index(body, " RMK "); i >= 0 {
+		m.Remarks = strings.TrimSpace(body[i+5:])
+		body = body[:i]
+	} else if strings.HasSuffix(body, " RMK") {
+		body = strings.TrimSuffix(body, " RMK")
+	}
+	fields := strings.Fields(body)
+	if len(fields) > 0 && (fields[0] == "METAR" || fields[0] == "SPECI") {
+		m.Type, fields = fields[0], fields[1:]
+	} else {
+		m.Type = "METAR"
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMETAR, raw)
+	}
+	m.Station = fields[0]
+	t := metarTime.FindStringSubmatch(fields[1])
+	if t == nil {
+		return nil, fmt.Errorf("%w: invalid time %q", ErrInvalidMETAR, fields[1])
+	}
+	m.Day, _ = strconv.Atoi(t[1])
+	m.Hour, _ = strconv.Atoi(t[2])
+	m.Minute, _ = strconv.Atoi(t[3])
+
+	fields = fields[2:]
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == "AUTO":
+			m.Auto = true
+		case f == "COR":
+			m.Corrected = true
+		case m.Wind == nil && metarWind.MatchString(f):
+			m.Wind = parseMETARWind(metarWind.FindStringSubmatch(f))
+		case m.Wind != nil && metarWindVary.MatchString(f):
+			v := metarWindVary.FindStringSubmatch(f)
+			m.Wind.VariableFrom, _ = strconv.Atoi(v[1])
+			m.Wind.VariableTo, _ = strconv.Atoi(v[2])
+		case m.Visibility == nil && isDigits(f) && len(f) == 1 && i+1 < len(fields) && metarVisSM.MatchString(fields[i+1]):
+			// whole and fractional miles, ex. 1 1/2SM
+			whole, _ := strconv.Atoi(f)
+			m.Visibility = parseMETARVisibility(metarVisSM.FindStringSubmatch(fields[i+1]))
+			m.Visibility.Distance += float64(whole)
+			i++
+		case m.Visibility == nil && metarVisSM.MatchString(f):
+			m.Visibility = parseMETARVisibility(metarVisSM.FindStringSubmatch(f))
+		case m.Visibility == nil && m.Wind != nil && metarVisMeters.MatchString(f):
+			meters, _ := strconv.Atoi(f)
+			m.Visibility = &METARVisibility{Distance: float64(meters), Unit: "m", MoreThan: meters == 9999}
+		case f == "CAVOK":
+			m.Visibility = &METARVisibility{Distance: 10000, Unit: "m", MoreThan: true}
+		case metarRVR.MatchString(f):
+			// runway visual range is not decoded
+		case isMETARWeather(f):
+			w := metarWeather.FindStringSubmatch(f)
+			weather := METARWeather{Raw: f, Descriptor: w[2]}
+			if w[1] == "VC" {
+				weather.InVicinity = true
+			} else {
+				weather.Intensity = w[1]
+			}
+			for j := 0; j+2 <= len(w[3]); j += 2 {
+				weather.Phenomena = append(weather.Phenomena, w[3][j:j+2])
+			}
+			m.Weather = append(m.Weather, weather)
+		case f == "SKC" || f == "CLR" || f == "NSC" || f == "NCD":
+			amount := CloudAmountSkyClear
+			if f == "CLR" {
+				amount = CloudAmountClear
+			}
+			m.Clouds = append(m.Clouds, METARCloud{Amount: amount})
+		case metarCloud.MatchString(f):
+			c := metarCloud.FindStringSubmatch(f)
+			amount, _ := ParseCloudAmount(c[1])
+			base, _ := strconv.Atoi(c[2])
+			m.Clouds = append(m.Clouds, METARCloud{Amount: amount, Base: base * 100, Type: c[3]})
+		case metarTemperature.MatchString(f):
+			tt := metarTemperature.FindStringSubmatch(f)
+			m.Temperature = metarDegrees(tt[1])
+			m.Dewpoint = metarDegrees(tt[2])
+		case metarAltimeter.MatchString(f):
+			a := metarAltimeter.FindStringSubmatch(f)
+			v, _ := strconv.Atoi(a[2])
+			if a[1] == "A" {
+				m.Altimeter, m.AltimeterUnit = float64(v)/100, "inHg"
+			} else {
+				m.Altimeter, m.AltimeterUnit = float64(v), "hPa"
+			}
+		default:
+			m.Unparsed = append(m.Unparsed, f)
+		}
+	}
+
+	// the T group of the remarks has the temperature and dewpoint in tenths
+	if g := metarTGroup.FindStringSubmatch(m.Remarks); g != nil {
+		m.Temperature = metarTenths(g[1], g[2])
+		m.Dewpoint = metarTenths(g[3], g[4])
+	}
+	return m, nil
+}
+
+// Time returns the time of the observation, which only includes the day of
+// the month, in the month of ref or the month before for days after ref.
+func (m *METAR) Time(ref time.Time) time.Time {
+	ref = ref.UTC()
+	t := time.Date(ref.Year(), ref.Month(), m.Day, m.Hour, m.Minute, 0, 0, time.UTC)
+	if m.Day > ref.Day()+1 {
+		t = time.Date(ref.Year(), ref.Month()-1, m.Day, m.Hour, m.Minute, 0, 0, time.UTC)
+	}
+	return t
+}
+
+// METAR decodes the raw METAR of the observation.
+func (o Observation) METAR() (*METAR, error) {
+	if o.RawMessage == "" {
+		return nil, fmt.Errorf("%w: the observation has no raw message", ErrInvalidMETAR)
+	}
+	return ParseMETAR(o.RawMessage)
+}
+
+// isMETARWeather reports whether the group is a present weather group, which
+// has a descriptor, ex. VCSH, or phenomena, ex. -RA
+func isMETARWeather(f string) bool {
+	w := metarWeather.FindStringSubmatch(f)
+	return w != nil && (w[2] != "" || w[3] != "")
+}
+
+// parseMETARWind decodes the matches of metarWind
+func parseMETARWind(w []string) *METARWind {
+	wind := &METARWind{Unit: w[4]}
+	if w[1] == "VRB" {
+		wind.Variable = true
+	} else {
+		wind.Direction, _ = strconv.Atoi(w[1])
+	}
+	wind.Speed, _ = strconv.Atoi(w[2])
+	if w[3] != "" {
+		wind.Gust, _ = strconv.Atoi(w[3])
+	}
+	return wind
+}
+
+// parseMETARVisibility decodes the matches of metarVisSM
+func parseMETARVisibility(v []string) *METARVisibility {
+	vis := &METARVisibility{Unit: "SM", LessThan: v[1] == "M", MoreThan: v[1] == "P"}
+	if n, d, ok := strings.Cut(v[2], "/"); ok {
+		num, _ := strconv.Atoi(n)
+		den, _ := strconv.Atoi(d)
+		if den != 0 {
+			vis.Distance = float64(num) / float64(den)
+		}
+	} else {
+		vis.Distance, _ = strconv.ParseFloat(v[2], 64)
+	}
+	return vis
+}
+
+// metarDegrees decodes a temperature group, ex. M05, or nil if blank
+func metarDegrees(s string) *float64 {
+	if s == "" {
+		return nil
+	}
+	v, _ := strconv.Atoi(strings.TrimPrefix(s, "M"))
+	d := float64(v)
+	if strings.HasPrefix(s, "M") {
+		d = -d
+	}
+	return &d
+}
+
+// metarTenths decodes a T group temperature, sign 1 for negative values
+func metarTenths(sign string, tenths string) *float64 {
+	v, _ := strconv.Atoi(tenths)
+	d := float64(v) / 10
+	if sign == "1" {
+		d = -d
+	}
+	return &d
+}
@@ -0,0 +1,83 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestParseMETAR(t *testing.T) {
+	m, err := noaa.ParseMETAR("KORD 041751Z AUTO 27015G25KT 240V300 1 1/2SM R10L/2000FT -TSRA BR FEW008 BKN035CB OVC100 26/18 A2992 RMK AO2 PK WND 28032/1729 T02560183")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != "METAR" || m.Station != "KORD" || m.Day != 4 || m.Hour != 17 || m.Minute != 51 || !m.Auto {
+		t.Errorf("noaa.ParseMETAR() should decode the header, got %+v", m)
+	}
+	wind := noaa.METARWind{Direction: 270, Speed: 15, Gust: 25, Unit: "KT", VariableFrom: 240, VariableTo: 300}
+	if m.Wind == nil || *m.Wind != wind {
+		t.Errorf("noaa.ParseMETAR() should decode the wind, got %+v", m.Wind)
+	}
+	if m.Visibility == nil || m.Visibility.Distance != 1.5 || m.Visibility.Unit != "SM" {
+		t.Errorf("noaa.ParseMETAR() should decode the visibility, got %+v", m.Visibility)
+	}
+	weather := []noaa.METARWeather{
+		{Raw: "-TSRA", Intensity: "-", Descriptor: "TS", Phenomena: []string{"RA"}},
+		{Raw: "BR", Phenomena: []string{"BR"}},
+	}
+	if !reflect.DeepEqual(m.Weather, weather) {
+		t.Errorf("noaa.ParseMETAR() should decode the weather, got %+v", m.Weather)
+	}
+	clouds := []noaa.METARCloud{
+		{Amount: noaa.CloudAmountFew, Base: 800},
+		{Amount: noaa.CloudAmountBroken, Base: 3500, Type: "CB"},
+		{Amount: noaa.CloudAmountOvercast, Base: 10000},
+	}
+	if !reflect.DeepEqual(m.Clouds, clouds) {
+		t.Errorf("noaa.ParseMETAR() should decode the clouds, got %+v", m.Clouds)
+	}
+	if m.Temperature == nil || *m.Temperature != 25.6 || m.Dewpoint == nil || *m.Dewpoint != 18.3 {
+		t.Errorf("noaa.ParseMETAR() should decode the temperature in tenths from the remarks, got %v/%v", m.Temperature, m.Dewpoint)
+	}
+	if m.Altimeter != 29.92 || m.AltimeterUnit != "inHg" {
+		t.Errorf("noaa.ParseMETAR() should decode the altimeter, got %v %s", m.Altimeter, m.AltimeterUnit)
+	}
+	if m.Remarks != "AO2 PK WND 28032/1729 T02560183" || len(m.Unparsed) != 0 {
+		t.Errorf("noaa.ParseMETAR() should return the remarks, got %q, unparsed %v", m.Remarks, m.Unparsed)
+	}
+	if got := m.Time(time.Date(2023, 7, 4, 18, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2023, 7, 4, 17, 51, 0, 0, time.UTC)) {
+		t.Errorf("METAR.Time() should return the observation time, got %s", got)
+	}
+}
+
+func TestParseMETARInternational(t *testing.T) {
+	m, err := noaa.ParseMETAR("SPECI PGUM 302354Z VRB03MPS 9999 VCSH SCT020TCU M02/M05 Q1013=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != "SPECI" || !m.Wind.Variable || m.Wind.Unit != "MPS" || m.Visibility.Unit != "m" || !m.Visibility.MoreThan {
+		t.Errorf("noaa.ParseMETAR() should decode the SPECI, got %+v %+v %+v", m, m.Wind, m.Visibility)
+	}
+	if len(m.Weather) != 1 || !m.Weather[0].InVicinity || m.Clouds[0].Type != "TCU" {
+		t.Errorf("noaa.ParseMETAR() should decode the weather and clouds, got %+v %+v", m.Weather, m.Clouds)
+	}
+	if *m.Temperature != -2 || *m.Dewpoint != -5 || m.Altimeter != 1013 || m.AltimeterUnit != "hPa" {
+		t.Errorf("noaa.ParseMETAR() should decode negative temperatures and QNH, got %v/%v %v", *m.Temperature, *m.Dewpoint, m.Altimeter)
+	}
+	// the observation was on the 30th of the previous month
+	if got := m.Time(time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2023, 7, 30, 23, 54, 0, 0, time.UTC)) {
+		t.Errorf("METAR.Time() should return a time in the previous month, got %s", got)
+	}
+
+	if _, err := noaa.ParseMETAR("KORD"); err == nil {
+		t.Error("noaa.ParseMETAR() should return an error without a time")
+	}
+	if _, err := (noaa.Observation{}).METAR(); err == nil {
+		t.Error("Observation.METAR() should return an error without a raw message")
+	}
+}
@@ -0,0 +1,165 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// MQTT 3.1.1 control packet types used by the client
+const (
+	packetConnect    = 0x10
+	packetConnAck    = 0x20
+	packetPublish    = 0x30
+	packetDisconnect = 0xe0
+)
+
+// ErrConnectionRefused is returned by Dial when the broker refuses the connection.
+var ErrConnectionRefused = errors.New("mqtt connection refused")
+
+// Options configure the connection made by Dial.
+type Options struct {
+	ClientID string // defaults to noaa
+	Username string
+	Password string
+	Timeout  time.Duration // dial and connect timeout, defaults to 10 seconds
+}
+
+// Client is a minimal MQTT 3.1.1 client which publishes messages with QoS 0.
+// It reconnects once when a publish fails because the connection was lost.
+// A Client is safe for concurrent use.
+type Client struct {
+	addr string
+	opts Options
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Dial connects to the MQTT broker at addr, ex. localhost:1883.
+func Dial(addr string, opts Options) (*Client, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = "noaa"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	c := &Client{addr: addr, opts: opts}
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// connect opens the network connection and sends the CONNECT packet
+func (c *Client) connect() error {
+	conn, err := net.DialTimeout("tcp", c.addr, c.opts.Timeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(c.opts.Timeout))
+	if _, err := conn.Write(connectPacket(c.opts)); err != nil {
+		conn.Close()
+		return err
+	}
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		conn.Close()
+		return err
+	}
+	if ack[0] != packetConnAck || ack[3] != 0 {
+		conn.Close()
+		return fmt.Errorf("%w: return code %d", ErrConnectionRefused, ack[3])
+	}
+	conn.SetDeadline(time.Time{})
+	c.conn = conn
+	return nil
+}
+
+// Publish sends the payload to the topic with QoS 0.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	packet := publishPacket(topic, payload, retain)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		if _, err := c.conn.Write(packet); err == nil {
+			return nil
+		}
+		c.conn.Close()
+		c.conn = nil
+	}
+	if err := c.connect(); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+// Close disconnects from the broker.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	c.conn.Write([]byte{packetDisconnect, 0})
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// connectPacket encodes a CONNECT packet with a clean session and keep alive
+// disabled, since the client only publishes
+func connectPacket(opts Options) []byte {
+	var flags byte = 0x02 // clean session
+	body := []byte{0, 4, 'M', 'Q', 'T', 'T', 4, 0, 0, 0}
+	payload := encodeString(opts.ClientID)
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = append(payload, encodeString(opts.Username)...)
+		if opts.Password != "" {
+			flags |= 0x40
+			payload = append(payload, encodeString(opts.Password)...)
+		}
+	}
+	body[7] = flags
+	return packet(packetConnect, append(body, payload...))
+}
+
+// publishPacket encodes a PUBLISH packet with QoS 0
+func publishPacket(topic string, payload []byte, retain bool) []byte {
+	var header byte = packetPublish
+	if retain {
+		header |= 0x01
+	}
+	return packet(header, append(encodeString(topic), payload...))
+}
+
+// packet prefixes the body with the fixed header
+func packet(header byte, body []byte) []byte {
+	return append(append([]byte{header}, encodeLength(len(body))...), body...)
+}
+
+// encodeString encodes a length prefixed UTF-8 string
+func encodeString(s string) []byte {
+	return append([]byte{byte(len(s) >> 8), byte(len(s))}, s...)
+}
+
+// encodeLength encodes the remaining length of a packet
+func encodeLength(n int) []byte {
+	var b []byte
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if n == 0 {
+			return b
+		}
+	}
+}
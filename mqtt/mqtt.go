@@ -0,0 +1,300 @@
+// Package mqtt publishes forecasts, observations and alerts from a
+// noaa.Poller to MQTT topics, including Home Assistant discovery payloads so
+// that sensors are created automatically. For example:
+//
+//	client, err := mqtt.Dial("localhost:1883", mqtt.Options{})
+//	bridge := mqtt.NewBridge(client)
+//	poller := &noaa.Poller{Locations: locations, AlertInterval: time.Minute}
+//	bridge.Attach(poller)
+//	poller.Run(ctx)
+//
+// Any MQTT library can be used instead of the included Client by implementing
+// the Publisher interface.
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// Publisher sends a message to an MQTT topic.
+type Publisher interface {
+	Publish(topic string, payload []byte, retain bool) error
+}
+
+// Bridge publishes poller results to MQTT topics under Prefix:
+//
+//	<prefix>/<location>/forecast     current forecast period (retained)
+//	<prefix>/<location>/observation  latest observation (retained)
+//	<prefix>/<location>/alerts/<key> alert events, an empty payload when ended (retained)
+type Bridge struct {
+	Publisher       Publisher
+	Prefix          string      // topic prefix, defaults to noaa
+	DiscoveryPrefix string      // Home Assistant discovery prefix, defaults to homeassistant; "-" disables discovery
+	Locale          noaa.Locale // units and language of published values
+
+	OnError func(error) // called when publishing fails
+
+	discovered map[string]bool
+}
+
+// NewBridge returns a Bridge with the default prefixes.
+func NewBridge(p Publisher) *Bridge {
+	return &Bridge{Publisher: p, Prefix: "noaa", DiscoveryPrefix: "homeassistant"}
+}
+
+// ForecastState is the payload published for a forecast. The temperature and
+// wind are in the units of the bridge locale.
+type ForecastState struct {
+	Name              string  `json:"name"`
+	Summary           string  `json:"summary"`
+	Details           string  `json:"details"`
+	Temperature       float64 `json:"temperature"`
+	TemperatureUnit   string  `json:"temperature_unit"`
+	PrecipitationProb float64 `json:"precipitation_probability"`
+	Wind              string  `json:"wind"`
+	Updated           string  `json:"updated"`
+}
+
+// ObservationState is the payload published for an observation. Values are in
+// the units of the bridge locale.
+type ObservationState struct {
+	Station          string  `json:"station"`
+	Timestamp        string  `json:"timestamp"`
+	Temperature      float64 `json:"temperature"`
+	Dewpoint         float64 `json:"dewpoint"`
+	RelativeHumidity float64 `json:"humidity"`
+	WindSpeed        float64 `json:"wind_speed"`
+	WindDirection    float64 `json:"wind_direction"`
+	Pressure         float64 `json:"pressure"`
+}
+
+// AlertState is the payload published for an alert event.
+type AlertState struct {
+	Key         string `json:"key"`
+	Status      string `json:"status"`
+	Active      bool   `json:"active"`
+	Event       string `json:"event"`
+	Severity    string `json:"severity"`
+	Urgency     string `json:"urgency"`
+	Headline    string `json:"headline"`
+	Description string `json:"description"`
+	Instruction string `json:"instruction"`
+	Expires     string `json:"expires"`
+}
+
+// Attach sets the forecast, observation and alert handlers of the poller to
+// publish to MQTT. Existing handlers are still called.
+func (b *Bridge) Attach(p *noaa.Poller) {
+	onForecast, onObservation, onAlert := p.OnForecast, p.OnObservation, p.OnAlert
+	p.OnForecast = func(loc noaa.Location, f *noaa.ForecastResponse) {
+		b.report(b.PublishForecast(loc, f))
+		if onForecast != nil {
+			onForecast(loc, f)
+		}
+	}
+	p.OnObservation = func(loc noaa.Location, o noaa.Observation) {
+		b.report(b.PublishObservation(loc, o))
+		if onObservation != nil {
+			onObservation(loc, o)
+		}
+	}
+	p.OnAlert = func(loc noaa.Location, e noaa.AlertEvent) {
+		b.report(b.PublishAlert(loc, e))
+		if onAlert != nil {
+			onAlert(loc, e)
+		}
+	}
+}
+
+// Subscribe publishes the events of the bus until the returned cancel function
+// is called. Events without a location name are skipped since their topics
+// could not be told apart.
+func (b *Bridge) Subscribe(bus *noaa.Bus) (cancel func()) {
+	return bus.Subscribe(func(e noaa.Event) {
+		switch e := e.(type) {
+		case noaa.ForecastUpdated:
+			if e.Location.Name != "" {
+				b.report(b.PublishForecast(e.Location, e.Forecast))
+			}
+		case noaa.ObservationReceived:
+			if e.Location.Name != "" {
+				b.report(b.PublishObservation(e.Location, e.Observation))
+			}
+		case noaa.AlertIssued:
+			if e.Location.Name != "" {
+				b.report(b.PublishAlert(e.Location, e.Event))
+			}
+		}
+	})
+}
+
+// PublishForecast publishes the first period of the forecast.
+func (b *Bridge) PublishForecast(loc noaa.Location, f *noaa.ForecastResponse) error {
+	if f == nil || len(f.Periods) == 0 {
+		return nil
+	}
+	if err := b.discover(loc); err != nil {
+		return err
+	}
+	p := f.Periods[0]
+	temperature, unit := b.convert(p.Temperature, p.TemperatureUnit)
+	return b.publishJSON(b.topic(loc, "forecast"), ForecastState{
+		Name:              b.Locale.PeriodName(p),
+		Summary:           b.Locale.PeriodSummary(p),
+		Details:           p.Details,
+		Temperature:       temperature,
+		TemperatureUnit:   strings.TrimPrefix(unit, "°"),
+		PrecipitationProb: p.ProbabilityOfPrecipitation.Value,
+		Wind:              b.Locale.PeriodWind(p),
+		Updated:           f.Updated,
+	}, true)
+}
+
+// PublishObservation publishes the observation converted to the bridge units.
+func (b *Bridge) PublishObservation(loc noaa.Location, o noaa.Observation) error {
+	if err := b.discover(loc); err != nil {
+		return err
+	}
+	return b.publishJSON(b.topic(loc, "observation"), ObservationState{
+		Station:          o.Station,
+		Timestamp:        o.Timestamp.Format(time.RFC3339),
+		Temperature:      b.number(o.Temperature),
+		Dewpoint:         b.number(o.Dewpoint),
+		RelativeHumidity: o.RelativeHumidity.Value,
+		WindSpeed:        b.number(o.WindSpeed),
+		WindDirection:    o.WindDirection.Value,
+		Pressure:         b.number(o.BarometricPressure),
+	}, true)
+}
+
+// PublishAlert publishes the alert event, or clears its retained message when
+// the event has ended.
+func (b *Bridge) PublishAlert(loc noaa.Location, e noaa.AlertEvent) error {
+	topic := b.topic(loc, "alerts/"+topicName(e.Key))
+	if !e.IsActive() {
+		return b.Publisher.Publish(topic, nil, true)
+	}
+	a := e.Current
+	return b.publishJSON(topic, AlertState{
+		Key:         e.Key,
+		Status:      e.Status,
+		Active:      e.IsActive(),
+		Event:       a.Event,
+		Severity:    a.Severity,
+		Urgency:     a.Urgency,
+		Headline:    a.Headline,
+		Description: a.Description,
+		Instruction: a.Instruction,
+		Expires:     a.Expires,
+	}, true)
+}
+
+// discover publishes the Home Assistant discovery payloads once per location
+func (b *Bridge) discover(loc noaa.Location) error {
+	if b.DiscoveryPrefix == "-" || b.discovered[loc.Name] {
+		return nil
+	}
+	prefix := b.DiscoveryPrefix
+	if prefix == "" {
+		prefix = "homeassistant"
+	}
+	si := strings.ToLower(b.Locale.Units) == "si"
+	tempUnit, speedUnit, pressureUnit := "°F", "mph", "inHg"
+	if si {
+		tempUnit, speedUnit, pressureUnit = "°C", "km/h", "hPa"
+	}
+	id := "noaa_" + topicName(loc.Name)
+	device := map[string]interface{}{
+		"identifiers":  []string{id},
+		"name":         "NOAA " + loc.Name,
+		"manufacturer": "National Weather Service",
+	}
+	sensors := []struct {
+		key, name, topic, field, unit, class string
+	}{
+		{"temperature", "Temperature", "observation", "temperature", tempUnit, "temperature"},
+		{"dewpoint", "Dewpoint", "observation", "dewpoint", tempUnit, "temperature"},
+		{"humidity", "Humidity", "observation", "humidity", "%", "humidity"},
+		{"wind_speed", "Wind speed", "observation", "wind_speed", speedUnit, "wind_speed"},
+		{"pressure", "Pressure", "observation", "pressure", pressureUnit, "pressure"},
+		{"forecast", "Forecast", "forecast", "summary", "", ""},
+	}
+	for _, s := range sensors {
+		config := map[string]interface{}{
+			"name":           s.name,
+			"unique_id":      id + "_" + s.key,
+			"state_topic":    b.topic(loc, s.topic),
+			"value_template": fmt.Sprintf("{{ value_json.%s }}", s.field),
+			"device":         device,
+		}
+		if s.unit != "" {
+			config["unit_of_measurement"] = s.unit
+			config["state_class"] = "measurement"
+		}
+		if s.class != "" {
+			config["device_class"] = s.class
+		}
+		topic := fmt.Sprintf("%s/sensor/%s_%s/config", prefix, id, s.key)
+		if err := b.publishJSON(topic, config, true); err != nil {
+			return err
+		}
+	}
+	if b.discovered == nil {
+		b.discovered = map[string]bool{}
+	}
+	b.discovered[loc.Name] = true
+	return nil
+}
+
+// number returns the observation value converted to the bridge units
+func (b *Bridge) number(v noaa.ObservationValue) float64 {
+	n, _ := b.convert(v.Value, v.UnitCode)
+	return n
+}
+
+// convert returns the value converted to the bridge units, rounded to two
+// decimals, with the display unit, see noaa.Locale.Convert
+func (b *Bridge) convert(value float64, unitCode string) (float64, string) {
+	n, unit := b.Locale.Convert(value, unitCode)
+	return math.Round(n*100) / 100, unit
+}
+
+// topic returns the topic for the location
+func (b *Bridge) topic(loc noaa.Location, name string) string {
+	prefix := b.Prefix
+	if prefix == "" {
+		prefix = "noaa"
+	}
+	return prefix + "/" + topicName(loc.Name) + "/" + name
+}
+
+// publishJSON marshals v and publishes it
+func (b *Bridge) publishJSON(topic string, v interface{}, retain bool) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Publisher.Publish(topic, payload, retain)
+}
+
+// report passes the error to OnError, if any
+func (b *Bridge) report(err error) {
+	if err != nil && b.OnError != nil {
+		b.OnError(err)
+	}
+}
+
+// invalidTopicChars matches characters which are not safe in topic names
+var invalidTopicChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// topicName converts a name to a topic level, ex. "My Cabin" -> my_cabin
+func topicName(name string) string {
+	return strings.Trim(invalidTopicChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
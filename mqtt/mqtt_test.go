@@ -0,0 +1,107 @@
+package mqtt_test
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/mqtt"
+)
+
+type message struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+type recorder struct {
+	messages []message
+}
+
+func (r *recorder) Publish(topic string, payload []byte, retain bool) error {
+	r.messages = append(r.messages, message{topic, payload, retain})
+	return nil
+}
+
+func TestBridge(t *testing.T) {
+	rec := &recorder{}
+	bridge := mqtt.NewBridge(rec)
+	bridge.Locale = noaa.LocaleSI
+	home := noaa.Location{Name: "My Cabin", Lat: "41.837", Lon: "-87.685"}
+
+	err := bridge.PublishObservation(home, noaa.Observation{
+		Temperature: noaa.ObservationValue{Value: 21.5, UnitCode: "wmoUnit:degC"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := rec.messages[len(rec.messages)-1]
+	if last.topic != "noaa/my_cabin/observation" || !last.retain {
+		t.Errorf("unexpected observation topic %s", last.topic)
+	}
+	var state mqtt.ObservationState
+	if err := json.Unmarshal(last.payload, &state); err != nil || state.Temperature != 21.5 {
+		t.Errorf("unexpected observation payload %s", last.payload)
+	}
+	discovery := rec.messages[0]
+	if discovery.topic != "homeassistant/sensor/noaa_my_cabin_temperature/config" {
+		t.Errorf("unexpected discovery topic %s", discovery.topic)
+	}
+
+	n := len(rec.messages)
+	bridge.PublishObservation(home, noaa.Observation{})
+	if len(rec.messages) != n+1 {
+		t.Error("discovery payloads should only be published once per location")
+	}
+
+	err = bridge.PublishForecast(home, &noaa.ForecastResponse{Periods: []noaa.ForecastResponsePeriod{
+		{Temperature: 68, TemperatureUnit: "F", WindSpeed: "10 mph", WindDirection: "SW"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var forecast mqtt.ForecastState
+	if err := json.Unmarshal(rec.messages[len(rec.messages)-1].payload, &forecast); err != nil {
+		t.Fatal(err)
+	}
+	if forecast.Temperature != 20 || forecast.TemperatureUnit != "C" || forecast.Wind != "SW 16 km/h" {
+		t.Errorf("expected the forecast in the bridge units, got %+v", forecast)
+	}
+}
+
+func TestClientPublish(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen:", err)
+	}
+	defer l.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connect := make([]byte, 2+16)
+		io.ReadFull(conn, connect) // CONNECT with client id "noaa"
+		conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+		publish := make([]byte, 2+2+9+2)
+		io.ReadFull(conn, publish)
+		received <- publish
+	}()
+
+	client, err := mqtt.Dial(l.Addr().String(), mqtt.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.Publish("noaa/test", []byte("hi"), true); err != nil {
+		t.Fatal(err)
+	}
+	publish := <-received
+	if publish[0] != 0x31 || publish[1] != 13 || string(publish[4:13]) != "noaa/test" || string(publish[13:]) != "hi" {
+		t.Errorf("unexpected publish packet % x", publish)
+	}
+}
@@ -0,0 +1,322 @@
+// Package ndbc fetches the observations of National Data Buoy Center buoys
+// and C-MAN stations, whose wave and water temperature measurements
+// weather.gov observations lack, and merges them with the marine forecasts of
+// the noaa package:
+//
+//	report, err := ndbc.MarineForecast(ctx, "42.0", "-87.4", 50000)
+//	...
+//	for _, b := range report.Buoys {
+//		if b.Observation != nil {
+//			fmt.Println(b.Station.ID, b.Observation.WaveHeight, b.Observation.WaterTemperature)
+//		}
+//	}
+//
+// Buoy IDs are rejected by the station functions of the noaa package, see
+// noaa.ValidateStationID.
+package ndbc
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// BaseURL is the URL of the NDBC website.
+var BaseURL = "https://www.ndbc.noaa.gov"
+
+// ErrNoObservation is returned when a station has no recent observation.
+var ErrNoObservation = errors.New("no recent buoy observation")
+
+// stationsTTL is how long the list of active stations is cached
+const stationsTTL = 24 * time.Hour
+
+// maxBuoys is the number of stations reported by MarineForecast
+const maxBuoys = 5
+
+// Station is an active NDBC station, see Stations.
+type Station struct {
+	ID       string // ex. 45007
+	Name     string
+	Type     string // ex. buoy, fixed or dart
+	Owner    string // ex. NDBC
+	Location noaa.Coordinates
+	Distance float64 // meters from the point, set by Nearby
+}
+
+// Observation is an observation of a station. Values missing from the
+// observation are NaN.
+type Observation struct {
+	Time               time.Time
+	WindDirection      float64 // degrees
+	WindSpeed          float64 // m/s
+	WindGust           float64 // m/s
+	WaveHeight         float64 // significant wave height, m
+	DominantWavePeriod float64 // s
+	AverageWavePeriod  float64 // s
+	MeanWaveDirection  float64 // degrees the dominant waves come from
+	Pressure           float64 // hPa
+	AirTemperature     float64 // °C
+	WaterTemperature   float64 // sea surface temperature, °C
+	DewPoint           float64 // °C
+}
+
+// HasMarineData reports whether the observation has a wave height or a
+// water temperature.
+func (o Observation) HasMarineData() bool {
+	return !math.IsNaN(o.WaveHeight) || !math.IsNaN(o.WaterTemperature)
+}
+
+var (
+	stationsMu      sync.Mutex
+	stationsCache   []Station
+	stationsURL     string
+	stationsFetched time.Time
+)
+
+// Stations returns the active NDBC stations. The list is cached for a day.
+func Stations(ctx context.Context) ([]Station, error) {
+	endpoint := BaseURL + "/activestations.xml"
+	stationsMu.Lock()
+	defer stationsMu.Unlock()
+	if stationsURL == endpoint && time.Since(stationsFetched) < stationsTTL {
+		return stationsCache, nil
+	}
+	body, err := fetch(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var doc struct {
+		Stations []struct {
+			ID    string  `xml:"id,attr"`
+			Name  string  `xml:"name,attr"`
+			Type  string  `xml:"type,attr"`
+			Owner string  `xml:"owner,attr"`
+			Lat   float64 `xml:"lat,attr"`
+			Lon   float64 `xml:"lon,attr"`
+		} `xml:"station"`
+	}
+	if err := xml.NewDecoder(body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", endpoint, err)
+	}
+	stations := make([]Station, len(doc.Stations))
+	for i, s := range doc.Stations {
+		stations[i] = Station{
+			ID:       strings.ToUpper(s.ID),
+			Name:     s.Name,
+			Type:     s.Type,
+			Owner:    s.Owner,
+			Location: noaa.Coordinates{Lat: s.Lat, Lon: s.Lon},
+		}
+	}
+	stationsCache, stationsURL, stationsFetched = stations, endpoint, time.Now()
+	return stations, nil
+}
+
+// Nearby returns up to n active stations within radius meters of <lat,lon>,
+// nearest first. A negative or zero n returns all of them.
+func Nearby(ctx context.Context, lat string, lon string, radius float64, n int) ([]Station, error) {
+	origin, err := noaa.ParseCoordinates(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	stations, err := Stations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var nearby []Station
+	for _, s := range stations {
+		if s.Distance = origin.DistanceTo(s.Location); s.Distance <= radius {
+			nearby = append(nearby, s)
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].Distance < nearby[j].Distance })
+	if n > 0 && len(nearby) > n {
+		nearby = nearby[:n]
+	}
+	return nearby, nil
+}
+
+// Observations returns the observations of the last 45 days of a station,
+// newest first, from its realtime standard meteorological data.
+func Observations(ctx context.Context, id string) ([]Observation, error) {
+	endpoint := BaseURL + "/data/realtime2/" + url.PathEscape(strings.ToUpper(strings.TrimSpace(id))) + ".txt"
+	body, err := fetch(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	observations, err := parseRealtime(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", endpoint, err)
+	}
+	return observations, nil
+}
+
+// Latest returns the newest observation of a station with a wave height or a
+// water temperature, see Observation.HasMarineData.
+func Latest(ctx context.Context, id string) (*Observation, error) {
+	observations, err := Observations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range observations {
+		if observations[i].HasMarineData() {
+			return &observations[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoObservation, id)
+}
+
+// parseRealtime parses a realtime2 standard meteorological data file, ex.
+//
+//	#YY  MM DD hh mm WDIR WSPD GST  WVHT   DPD   APD MWD   PRES  ATMP  WTMP  DEWP  VIS PTDY  TIDE
+//	#yr  mo dy hr mn degT m/s  m/s     m   sec   sec degT   hPa  degC  degC  degC  nmi  hPa    ft
+//	2023 07 04 17 50 200  5.0  6.0   0.8     6   4.5 190 1015.2  25.1  26.3  20.1   MM   MM    MM
+func parseRealtime(r io.Reader) ([]Observation, error) {
+	scanner := bufio.NewScanner(r)
+	var columns map[string]int
+	var observations []Observation
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "#") {
+			if columns == nil {
+				columns = map[string]int{}
+				for i, name := range fields {
+					columns[strings.TrimPrefix(name, "#")] = i
+				}
+			}
+			continue
+		}
+		if columns == nil {
+			return nil, errors.New("missing header")
+		}
+		value := func(name string) float64 {
+			i, ok := columns[name]
+			if !ok || i >= len(fields) {
+				return math.NaN()
+			}
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil { // MM marks missing values
+				return math.NaN()
+			}
+			return v
+		}
+		date := make([]int, 5)
+		for i, name := range []string{"YY", "MM", "DD", "hh", "mm"} {
+			v := value(name)
+			if math.IsNaN(v) {
+				return nil, fmt.Errorf("invalid time in %q", scanner.Text())
+			}
+			date[i] = int(v)
+		}
+		observations = append(observations, Observation{
+			Time:               time.Date(date[0], time.Month(date[1]), date[2], date[3], date[4], 0, 0, time.UTC),
+			WindDirection:      value("WDIR"),
+			WindSpeed:          value("WSPD"),
+			WindGust:           value("GST"),
+			WaveHeight:         value("WVHT"),
+			DominantWavePeriod: value("DPD"),
+			AverageWavePeriod:  value("APD"),
+			MeanWaveDirection:  value("MWD"),
+			Pressure:           value("PRES"),
+			AirTemperature:     value("ATMP"),
+			WaterTemperature:   value("WTMP"),
+			DewPoint:           value("DEWP"),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(observations, func(i, j int) bool { return observations[i].Time.After(observations[j].Time) })
+	return observations, nil
+}
+
+// fetch returns the body of a successful response of the endpoint
+func fetch(ctx context.Context, endpoint string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", noaa.GetConfig().UserAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		if res.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%s: %s: %w", req.URL, res.Status, ErrNoObservation)
+		}
+		return nil, fmt.Errorf("%s: %s", req.URL, res.Status)
+	}
+	return res.Body, nil
+}
+
+// BuoyReport is a station near a point with its latest observation.
+type BuoyReport struct {
+	Station     Station
+	Observation *Observation // nil if the station has no recent marine data
+}
+
+// MarineReport is the marine forecast of a point with the observations of
+// the buoys near it.
+type MarineReport struct {
+	Zones     []noaa.Zone                  // coastal and offshore zones of the point
+	Forecasts []*noaa.ZoneForecastResponse // forecasts of the zones, in the same order
+	Buoys     []BuoyReport                 // nearest first
+}
+
+// MarineForecast returns the marine zone forecasts of <lat,lon>, see
+// noaa.MarineZonesForPoint and noaa.GetMarineZoneForecast, with the latest
+// observations of the nearest NDBC stations within radius meters, up to 5.
+func MarineForecast(ctx context.Context, lat string, lon string, radius float64) (*MarineReport, error) {
+	zones, err := noaa.MarineZonesForPoint(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	report := &MarineReport{Zones: zones}
+	for _, zone := range zones {
+		id := zone.ID
+		if id == "" {
+			id = noaa.ZoneID(zone.URI)
+		}
+		zoneType := zone.Type
+		if zoneType != noaa.ZoneTypeOffshore {
+			zoneType = noaa.ZoneTypeCoastal
+		}
+		forecast, err := noaa.GetMarineZoneForecast(zoneType, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get marine forecast %s: %w", id, err)
+		}
+		report.Forecasts = append(report.Forecasts, forecast)
+	}
+	stations, err := Nearby(ctx, lat, lon, radius, maxBuoys)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stations {
+		observation, err := Latest(ctx, s.ID)
+		if err != nil && !errors.Is(err, ErrNoObservation) {
+			return nil, err
+		}
+		report.Buoys = append(report.Buoys, BuoyReport{Station: s, Observation: observation})
+	}
+	return report, nil
+}
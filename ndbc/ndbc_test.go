@@ -0,0 +1,75 @@
+package ndbc_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/ndbc"
+)
+
+func TestMarineForecast(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zones":
+			fmt.Fprint(w, `{"@graph": [{"id": "LMZ741", "type": "coastal", "name": "Wilmette Harbor to Northerly Island IL"}]}`)
+		case "/zones/coastal/LMZ741/forecast":
+			fmt.Fprint(w, `{"periods": [{"number": 1, "name": "Tonight", "detailedForecast": "South winds 10 to 15 kt. Waves 1 to 3 ft."}]}`)
+		case "/activestations.xml":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+				<stations created="2023-07-04T18:00:00UTC" count="3">
+					<station id="45007" lat="42.674" lon="-87.026" name="South Michigan" owner="NDBC" type="buoy"/>
+					<station id="CHII2" lat="41.916" lon="-87.572" name="Chicago, IL" owner="NDBC" type="fixed"/>
+					<station id="41002" lat="31.760" lon="-74.840" name="South Hatteras" owner="NDBC" type="buoy"/>
+				</stations>`)
+		case "/data/realtime2/CHII2.txt":
+			fmt.Fprint(w, `#YY  MM DD hh mm WDIR WSPD GST  WVHT   DPD   APD MWD   PRES  ATMP  WTMP  DEWP  VIS PTDY  TIDE
+#yr  mo dy hr mn degT m/s  m/s     m   sec   sec degT   hPa  degC  degC  degC  nmi  hPa    ft
+2023 07 04 17 40 200  5.0  6.0    MM    MM    MM  MM 1015.2  25.1    MM  20.1   MM   MM    MM
+2023 07 04 17 50 210  5.5  7.0   0.8     6   4.5 190 1015.0  25.3  22.4  20.0   MM   MM    MM
+`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client, baseURL := http.DefaultClient, ndbc.BaseURL
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient, ndbc.BaseURL = client, baseURL
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	ndbc.BaseURL = api.URL
+
+	report, err := ndbc.MarineForecast(context.Background(), "41.9", "-87.6", 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Forecasts) != 1 || report.Forecasts[0].Periods[0].Name != "Tonight" {
+		t.Errorf("expected the forecast of the coastal zone, got %+v", report.Forecasts)
+	}
+	if len(report.Buoys) != 2 || report.Buoys[0].Station.ID != "CHII2" || report.Buoys[1].Station.ID != "45007" {
+		t.Fatalf("expected the stations within 100 km nearest first, got %+v", report.Buoys)
+	}
+	o := report.Buoys[0].Observation
+	if o == nil || !o.Time.Equal(time.Date(2023, 7, 4, 17, 50, 0, 0, time.UTC)) || o.WaveHeight != 0.8 || o.WaterTemperature != 22.4 {
+		t.Errorf("expected the newest observation with marine data, got %+v", o)
+	}
+	if report.Buoys[1].Observation != nil {
+		t.Errorf("expected no observation for a station without data, got %+v", report.Buoys[1].Observation)
+	}
+
+	observations, err := ndbc.Observations(context.Background(), "chii2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observations) != 2 || !math.IsNaN(observations[1].WaveHeight) || observations[1].AirTemperature != 25.1 {
+		t.Errorf("expected missing values to be NaN, got %+v", observations)
+	}
+}
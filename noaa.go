@@ -4,12 +4,16 @@
 package noaa
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,22 +33,44 @@ const (
 // key is expected to be PointsResponse.ID
 var pointsCache = map[string]*PointsResponse{}
 
+// uncoveredPoints caches the errors of points out of coverage until they
+// expire, see SetOutOfCoverageTTL
+var uncoveredPoints = map[string]uncoveredPoint{}
+
+// uncoveredPoint is the error of a point out of coverage
+type uncoveredPoint struct {
+	err     error
+	expires time.Time
+}
+
+// pointsCacheMu guards pointsCache and uncoveredPoints since points are looked
+// up concurrently
+var pointsCacheMu sync.RWMutex
+
 // PointsResponse holds the JSON values from /points/<lat,lon>
 type PointsResponse struct {
-	ID                          string `json:"@id"`
-	CWA                         string `json:"cwa"`
-	Office                      string `json:"forecastOffice"`
-	GridX                       int64  `json:"gridX"`
-	GridY                       int64  `json:"gridY"`
-	GridID                      string `json:"gridId"`
-	County                      string `json:"county"`
-	FireWeatherZone             string `json:"fireWeatherZone"`
-	EndpointForecast            string `json:"forecast"`
-	EndpointForecastHourly      string `json:"forecastHourly"`
-	EndpointObservationStations string `json:"observationStations"`
-	EndpointForecastGridData    string `json:"forecastGridData"`
-	Timezone                    string `json:"timeZone"`
-	RadarStation                string `json:"radarStation"`
+	ID                          string                 `json:"@id"`
+	CWA                         string                 `json:"cwa"`
+	Office                      string                 `json:"forecastOffice"`
+	GridX                       int64                  `json:"gridX"`
+	GridY                       int64                  `json:"gridY"`
+	GridID                      string                 `json:"gridId"`
+	ForecastZone                string                 `json:"forecastZone"`
+	County                      string                 `json:"county"`
+	FireWeatherZone             string                 `json:"fireWeatherZone"`
+	EndpointForecast            string                 `json:"forecast"`
+	EndpointForecastHourly      string                 `json:"forecastHourly"`
+	EndpointObservationStations string                 `json:"observationStations"`
+	EndpointForecastGridData    string                 `json:"forecastGridData"`
+	Timezone                    string                 `json:"timeZone"`
+	RadarStation                string                 `json:"radarStation"`
+	RelativeLocation            PointsRelativeLocation `json:"relativeLocation"`
+}
+
+// PointsRelativeLocation holds the nearest city of a PointsResponse.
+type PointsRelativeLocation struct {
+	City  string `json:"city"`
+	State string `json:"state"`
 }
 
 // OfficeAddress holds the JSON values for the address of an OfficeResponse
@@ -78,6 +104,122 @@ type OfficeResponse struct {
 // StationsResponse holds the JSON values from /points/<lat,lon>/stations
 type StationsResponse struct {
 	Stations []string `json:"observationStations"`
+
+	// Entries holds the ID and URL of each station, with the distance from
+	// the point passed to Stations when the station geometry is available.
+	// The station metadata is read from the @graph of JSON-LD responses or
+	// the features of GeoJSON responses.
+	Entries []StationEntry `json:"entries,omitempty"`
+
+	graph []stationGraphEntry
+}
+
+// StationEntry is a station listed in a StationsResponse.
+type StationEntry struct {
+	ID       string   `json:"id"` // ex. KORD
+	URL      string   `json:"url"`
+	Name     string   `json:"name,omitempty"`
+	Distance *float64 `json:"distance,omitempty"` // meters, nil if the station location is unknown
+}
+
+// stationGraphEntry holds the station metadata of a JSON-LD stations response,
+// or the properties and geometry of a GeoJSON station feature
+type stationGraphEntry struct {
+	URI       string            `json:"@id"`
+	ID        string            `json:"stationIdentifier"`
+	Name      string            `json:"name"`
+	TimeZone  string            `json:"timeZone"`
+	Elevation ForecastElevation `json:"elevation"`
+	Geometry  json.RawMessage   `json:"geometry"` // WKT for JSON-LD, a GeoJSON Point otherwise
+}
+
+// location returns the coordinates of the station geometry
+func (g stationGraphEntry) location() (Coordinates, error) {
+	if isObject(g.Geometry) {
+		var point struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		}
+		if err := json.Unmarshal(g.Geometry, &point); err != nil {
+			return Coordinates{}, err
+		}
+		if point.Type != "Point" || len(point.Coordinates) < 2 {
+			return Coordinates{}, fmt.Errorf("invalid station geometry %s", g.Geometry)
+		}
+		return Coordinates{Lat: point.Coordinates[1], Lon: point.Coordinates[0]}, nil
+	}
+	var wkt string
+	if err := json.Unmarshal(g.Geometry, &wkt); err != nil {
+		return Coordinates{}, fmt.Errorf("invalid station geometry %s", g.Geometry)
+	}
+	return parseWKTPoint(wkt)
+}
+
+// UnmarshalJSON decodes the station URLs and the station metadata, if any.
+func (s *StationsResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Stations []string            `json:"observationStations"`
+		Entries  []StationEntry      `json:"entries"`
+		Graph    []stationGraphEntry `json:"@graph"`
+		Features []struct {
+			Geometry   json.RawMessage   `json:"geometry"`
+			Properties stationGraphEntry `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, f := range raw.Features {
+		g := f.Properties
+		g.Geometry = f.Geometry
+		raw.Graph = append(raw.Graph, g)
+	}
+	if len(raw.Stations) == 0 {
+		for _, g := range raw.Graph {
+			raw.Stations = append(raw.Stations, g.URI)
+		}
+	}
+	*s = StationsResponse{Stations: raw.Stations, Entries: raw.Entries, graph: raw.Graph}
+	return nil
+}
+
+// StationIDs returns the IDs of the stations, ex. KORD, in the same order as
+// Stations.
+func (s *StationsResponse) StationIDs() []string {
+	ids := make([]string, len(s.Stations))
+	for i, u := range s.Stations {
+		ids[i] = StationID(u)
+	}
+	return ids
+}
+
+// StationID returns the station ID from a station URL, ex. KORD for
+// https://api.weather.gov/stations/KORD.
+func StationID(stationURL string) string {
+	return stationURL[strings.LastIndex(strings.TrimSuffix(stationURL, "/"), "/")+1:]
+}
+
+// withEntries returns a copy of the response with Entries set for the point
+func (s *StationsResponse) withEntries(lat string, lon string) *StationsResponse {
+	c := *s
+	origin, err := ParseCoordinates(lat, lon)
+	metadata := map[string]stationGraphEntry{}
+	for _, g := range s.graph {
+		metadata[g.URI] = g
+	}
+	c.Entries = make([]StationEntry, len(s.Stations))
+	for i, u := range s.Stations {
+		entry := StationEntry{ID: StationID(u), URL: u}
+		if g, ok := metadata[u]; ok {
+			entry.Name = g.Name
+			if location, perr := g.location(); perr == nil && err == nil {
+				d := origin.DistanceTo(location)
+				entry.Distance = &d
+			}
+		}
+		c.Entries[i] = entry
+	}
+	return &c
 }
 
 // ForecastElevation holds the JSON values for a forecast response's elevation.
@@ -94,6 +236,13 @@ type ForecastHourlyElevation struct {
 	QualityControl string  `json:"qualityControl"`
 }
 
+// ForecastValue holds the JSON values for a value with a unit of measure within
+// a forecast period, ex. probabilityOfPrecipitation.
+type ForecastValue struct {
+	Value    float64 `json:"value"`
+	UnitCode string  `json:"unitCode"`
+}
+
 // ForecastResponsePeriod holds the JSON values for a period within a forecast response.
 type ForecastResponsePeriod struct {
 	ID               int32   `json:"number"`
@@ -109,6 +258,14 @@ type ForecastResponsePeriod struct {
 	Icon             string  `json:"icon"`
 	Summary          string  `json:"shortForecast"`
 	Details          string  `json:"detailedForecast"`
+
+	ProbabilityOfPrecipitation ForecastValue `json:"probabilityOfPrecipitation"`
+	Dewpoint                   ForecastValue `json:"dewpoint"`
+	RelativeHumidity           ForecastValue `json:"relativeHumidity"`
+
+	// Variants lists the schema variants the period was decoded from, ex.
+	// VariantTemperatureQV, or is empty for the original schema.
+	Variants []string `json:"-"`
 }
 
 // ForecastResponsePeriodHourly provides the JSON value for a period within an hourly forecast.
@@ -119,11 +276,13 @@ type ForecastResponsePeriodHourly struct {
 // ForecastResponse holds the JSON values from /gridpoints/<cwa>/<x,y>/forecast"
 type ForecastResponse struct {
 	// capture data from the forecast
-	Updated   string                   `json:"updated"`
-	Units     string                   `json:"units"`
-	Elevation ForecastElevation        `json:"elevation"`
-	Periods   []ForecastResponsePeriod `json:"periods"`
-	Point     *PointsResponse
+	Updated    string                   `json:"updated"`
+	Units      string                   `json:"units"`
+	ValidTimes string                   `json:"validTimes"`
+	Elevation  ForecastElevation        `json:"elevation"`
+	Periods    []ForecastResponsePeriod `json:"periods"`
+	Point      *PointsResponse
+	Problems   []DecodeProblem `json:"-"` // values skipped by lenient decoding, see SetLenientDecoding
 }
 
 // WeatherValueItem holds the JSON values for a weather.values[x].value.
@@ -172,8 +331,10 @@ type HourlyForecastResponse struct {
 	GeneratedAt       string                         `json:"generatedAt"`
 	UpdateTime        string                         `json:"updateTime"`
 	ValidTimes        string                         `json:"validTimes"`
+	Elevation         ForecastElevation              `json:"elevation"`
 	Periods           []ForecastResponsePeriodHourly `json:"periods"`
 	Point             *PointsResponse
+	Problems          []DecodeProblem `json:"-"` // values skipped by lenient decoding, see SetLenientDecoding
 }
 
 // GridpointForecastResponse holds the JSON values from /gridpoints/<cwa>/<x,y>"
@@ -240,6 +401,7 @@ type GridpointForecastResponse struct {
 	Stability                        GridpointForecastTimeSeries `json:"stability"`
 	RedFlagThreatIndex               GridpointForecastTimeSeries `json:"redFlagThreatIndex"`
 	Point                            *PointsResponse
+	Problems                         []DecodeProblem `json:"-"` // values skipped by lenient decoding, see SetLenientDecoding
 }
 
 // GridpointForecastTimeSeriesValue holds the JSON value for a
@@ -258,155 +420,435 @@ type GridpointForecastTimeSeries struct {
 // Call the weather.gov API. We could just use http.Get() but
 // since we need to include some custom header values this helps.
 func apiCall(endpoint string) (res *http.Response, err error) {
+	return apiCallContext(context.Background(), endpoint)
+}
+
+// apiCallContext calls the weather.gov API, retrying requests failing with
+// ErrDataUnavailable as configured, until the context is done. Errors are
+// wrapped with the method, endpoint and number of attempts, ex.
+// GET https://api.weather.gov/points/0,0 (attempt 1): 404 Not Found.
+func apiCallContext(ctx context.Context, endpoint string) (res *http.Response, err error) {
 	endpoint = strings.Replace(endpoint, "http://", "https://", -1)
-	req, err := http.NewRequest("GET", endpoint, nil)
+	if err := checkUserAgent(); err != nil {
+		return nil, err
+	}
+	c := currentConfig()
+	ctx = withCorrelationID(ctx)
+	backoff := c.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		res, err = doRequest(ctx, c, endpoint)
+		if err == nil {
+			return res, nil
+		}
+		if attempt > c.Retries || !errors.Is(err, ErrDataUnavailable) {
+			return nil, requestError(endpoint, attempt, err)
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, requestError(endpoint, attempt, ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// requestError wraps an error of a GET request with its context
+func requestError(endpoint string, attempts int, err error) error {
+	return fmt.Errorf("GET %s (attempt %d): %w", endpoint, attempts, err)
+}
+
+// doRequest makes a single request to the endpoint with the config
+func doRequest(ctx context.Context, c Config, endpoint string) (res *http.Response, err error) {
+	cancel := context.CancelFunc(func() {})
+	if c.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+	}
+	cancelRead := context.CancelFunc(func() {})
+	if c.ReadTimeout > 0 {
+		cancelTimeout := cancel
+		ctx, cancelRead = context.WithCancel(ctx)
+		cancel = func() {
+			cancelRead()
+			cancelTimeout()
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
-	req.Header.Add("Accept", config.Accept)
-	req.Header.Add("User-Agent", config.UserAgent)
+	id := CorrelationID(ctx)
+	req.Header.Add("Accept", c.Accept)
+	req.Header.Add("User-Agent", c.UserAgent)
+	if id != "" {
+		req.Header.Add(CorrelationIDHeader, id)
+	}
 
-	res, err = http.DefaultClient.Do(req)
+	client := *httpClient(ctx)
+	client.CheckRedirect = checkRedirect(c, client.CheckRedirect)
+	start := time.Now()
+	res, err = client.Do(req)
 	if err != nil {
+		cancel()
+		countResponse(endpoint, 0)
+		logRequest(RequestLog{CorrelationID: id, URL: endpoint, Duration: time.Since(start), Err: err})
 		return nil, err
 	}
+	countResponse(endpoint, res.StatusCode)
+	maxSize := c.MaxResponseSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+	if res.StatusCode == http.StatusOK && res.ContentLength > maxSize {
+		res.Body.Close()
+		cancel()
+		err = fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrResponseTooLarge, endpoint, res.ContentLength, maxSize)
+		logRequest(RequestLog{CorrelationID: id, URL: endpoint, StatusCode: res.StatusCode, Duration: time.Since(start), Err: err})
+		return nil, err
+	}
+	// the timeout also applies to reading the body, so cancel when it is closed
+	res.Body = countingBody{cancelOnClose{newGuardedBody(res.Body, endpoint, maxSize, c.ReadTimeout, cancelRead), cancel}}
 
 	if res.StatusCode != http.StatusOK {
-		return nil, errors.New(fmt.Sprintf("%d %s", res.StatusCode, res.Status))
+		defer res.Body.Close()
+		apiErr := &APIError{StatusCode: res.StatusCode, Status: res.Status, Endpoint: endpoint, CorrelationID: id}
+		json.NewDecoder(io.LimitReader(res.Body, 1<<16)).Decode(apiErr)
+		logRequest(RequestLog{CorrelationID: id, URL: endpoint, StatusCode: res.StatusCode, Duration: time.Since(start), Err: apiErr})
+		return nil, apiErr
+	}
+	if contentType := res.Header.Get("Content-Type"); !acceptsContentType(c.Accept, contentType) {
+		defer res.Body.Close()
+		err = &ContentTypeError{Endpoint: endpoint, Accept: c.Accept, ContentType: contentType, Snippet: bodySnippet(res.Body), CorrelationID: id}
+		logRequest(RequestLog{CorrelationID: id, URL: endpoint, StatusCode: res.StatusCode, Duration: time.Since(start), Err: err})
+		return nil, err
 	}
+	logRequest(RequestLog{CorrelationID: id, URL: endpoint, StatusCode: res.StatusCode, Duration: time.Since(start)})
 
 	return res, nil
 }
 
-// Points returns a set of useful endpoints for a given <lat,lon>
-// or returns a cached object if appropriate
-func Points(lat string, lon string) (points *PointsResponse, err error) {
-	endpoint := fmt.Sprintf("%s/points/%s,%s", config.BaseURL, lat, lon)
-	if pointsCache[endpoint] != nil {
-		return pointsCache[endpoint], nil
+// checkRedirect returns the redirect policy of the config: redirects keep the
+// User-Agent and Accept headers of the original request, whichever host they
+// lead to, and fail with ErrTooManyRedirects after MaxRedirects. The policy
+// of the client, next, if any, then applies to the redirects allowed.
+func checkRedirect(c Config, next func(req *http.Request, via []*http.Request) error) func(req *http.Request, via []*http.Request) error {
+	max := c.MaxRedirects
+	if max == 0 {
+		max = DefaultMaxRedirects
 	}
-	res, err := apiCall(endpoint)
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= max {
+			return fmt.Errorf("%w: stopped after %d redirects at %s", ErrTooManyRedirects, len(via), req.URL)
+		}
+		req.Header.Set("User-Agent", c.UserAgent)
+		req.Header.Set("Accept", c.Accept)
+		if next != nil {
+			return next(req, via)
+		}
+		return nil
+	}
+}
 
-	if err != nil {
-		return nil, err
+// guardedBody limits the size of a response body and the time taken to read
+// it
+type guardedBody struct {
+	io.ReadCloser
+	endpoint string
+	limit    int64 // maximum size
+	read     int64
+	timeout  time.Duration
+	timer    *time.Timer
+	timedOut int32 // set by the timer, read atomically
+}
+
+// newGuardedBody returns the body limited to limit bytes and read in timeout,
+// 0 for no limit, calling cancel when the timeout expires
+func newGuardedBody(body io.ReadCloser, endpoint string, limit int64, timeout time.Duration, cancel context.CancelFunc) *guardedBody {
+	b := &guardedBody{ReadCloser: body, endpoint: endpoint, limit: limit, timeout: timeout}
+	if timeout > 0 {
+		b.timer = time.AfterFunc(timeout, func() {
+			atomic.StoreInt32(&b.timedOut, 1)
+			cancel()
+		})
 	}
-	defer res.Body.Close()
+	return b
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if err = decoder.Decode(&points); err != nil {
-		return nil, err
+func (b *guardedBody) Read(p []byte) (int, error) {
+	if b.limit > 0 && int64(len(p)) > b.limit-b.read+1 {
+		p = p[:b.limit-b.read+1] // read one byte past the limit to detect larger bodies
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.limit > 0 && b.read > b.limit {
+		return n - int(b.read-b.limit), fmt.Errorf("%w: %s is larger than %d bytes", ErrResponseTooLarge, b.endpoint, b.limit)
+	}
+	if err != nil && err != io.EOF && atomic.LoadInt32(&b.timedOut) == 1 {
+		return n, fmt.Errorf("%w: reading %s took longer than %s", ErrReadTimeout, b.endpoint, b.timeout)
 	}
-	pointsCache[endpoint] = points
-	return points, nil
+	return n, err
+}
+
+func (b *guardedBody) Close() error {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	return b.ReadCloser.Close()
+}
+
+// cancelOnClose cancels the request context when the body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// Points returns a set of useful endpoints for a given <lat,lon>
+// or returns a cached object if appropriate. Points out of coverage fail with
+// an error wrapping ErrOutOfCoverage, which is also cached, see
+// SetOutOfCoverageTTL.
+func Points(lat string, lon string) (points *PointsResponse, err error) {
+	return PointsContext(context.Background(), lat, lon)
+}
+
+// PointsContext is like Points, making the request with the context, see
+// WithCorrelationID and WithHTTPClient.
+func PointsContext(ctx context.Context, lat string, lon string) (points *PointsResponse, err error) {
+	endpoint := apiURL("points", lat+","+lon).String()
+	pointsCacheMu.RLock()
+	cached := pointsCache[endpoint]
+	uncovered, miss := uncoveredPoints[endpoint]
+	pointsCacheMu.RUnlock()
+	if miss && time.Now().Before(uncovered.expires) {
+		countCache(true)
+		return nil, uncovered.err
+	}
+	countCache(cached != nil)
+	if cached != nil {
+		return cached, nil
+	}
+	return shared(ctx, endpoint, func(ctx context.Context) (points *PointsResponse, err error) {
+		res, err := apiCallContext(ctx, endpoint)
+
+		if errors.Is(err, ErrOutOfCoverage) {
+			if ttl := currentConfig().OutOfCoverageTTL; ttl > 0 {
+				pointsCacheMu.Lock()
+				uncoveredPoints[endpoint] = uncoveredPoint{err: err, expires: time.Now().Add(ttl)}
+				pointsCacheMu.Unlock()
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if points, err = DecodePoints(res.Body); err != nil {
+			return nil, err
+		}
+		pointsCacheMu.Lock()
+		pointsCache[endpoint] = points
+		pointsCacheMu.Unlock()
+		return points, nil
+	})
 }
 
 // Office returns details for a specific office identified by its ID
 // For example, https://api.weather.gov/offices/LOT (Chicago)
+// An *UnknownOfficeError suggesting the offices id may be a typo of is
+// returned when the office is not found, see ValidateOfficeID.
 func Office(id string) (office *OfficeResponse, err error) {
-	endpoint := fmt.Sprintf("%s/offices/%s", config.BaseURL, id)
+	endpoint := apiURL("offices", id).String()
 
 	res, err := apiCall(endpoint)
 	if err != nil {
+		if IsNotFound(err) {
+			if unknown, ok := ValidateOfficeID(id).(*UnknownOfficeError); ok {
+				unknown.Err = err
+				return nil, unknown
+			}
+		}
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	decoder := json.NewDecoder(res.Body)
-	if err = decoder.Decode(&office); err != nil {
-		return nil, err
-	}
-	return office, nil
+	return DecodeOffice(res.Body)
 }
 
-// Stations returns an array of observation station IDs (urls)
+// Stations returns an array of observation station IDs (urls), nearest first,
+// with their parsed IDs and distances in Entries
 func Stations(lat string, lon string) (stations *StationsResponse, err error) {
-	point, err := Points(lat, lon)
-	if err != nil {
-		return nil, err
-	}
-	res, err := apiCall(point.EndpointObservationStations)
+	return StationsContext(context.Background(), lat, lon)
+}
+
+// StationsContext is like Stations, making the requests with the context.
+func StationsContext(ctx context.Context, lat string, lon string) (stations *StationsResponse, err error) {
+	point, err := PointsContext(ctx, lat, lon)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	stations, err = shared(ctx, point.EndpointObservationStations, func(ctx context.Context) (stations *StationsResponse, err error) {
+		res, err := apiCallContext(ctx, point.EndpointObservationStations)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
 
-	decoder := json.NewDecoder(res.Body)
-	if err = decoder.Decode(&stations); err != nil {
+		return DecodeStations(res.Body)
+	})
+	if err != nil {
 		return nil, err
 	}
-	return stations, nil
+	// the response may be shared by points in the same grid cell
+	return stations.withEntries(lat, lon), nil
 }
 
 // Forecast returns an array of forecast observations (14 periods and 2/day max)
+// and publishes a ForecastUpdated event on DefaultBus
 func Forecast(lat string, lon string) (forecast *ForecastResponse, err error) {
-	query := ""
-	point, err := Points(lat, lon)
+	return ForecastWithUnits(lat, lon, "")
+}
+
+// ForecastWithUnits is like Forecast but requests the forecast in the given
+// units, "us" or "si", instead of the configured units. Blank units use the
+// configured units.
+func ForecastWithUnits(lat string, lon string, units string) (forecast *ForecastResponse, err error) {
+	return ForecastContext(context.Background(), lat, lon, units)
+}
+
+// ForecastContext is like ForecastWithUnits, making the requests with the
+// context, see WithCorrelationID and WithHTTPClient.
+func ForecastContext(ctx context.Context, lat string, lon string, units string) (forecast *ForecastResponse, err error) {
+	forecast, err = fetchForecast(ctx, lat, lon, units)
 	if err != nil {
 		return nil, err
 	}
-	if config.Units != "" {
-		query = "?units=" + config.Units
-	}
-	res, err := apiCall(point.EndpointForecast + query)
+	DefaultBus.Publish(ForecastUpdated{Location: Location{Lat: lat, Lon: lon}, Forecast: forecast})
+	return forecast, nil
+}
+
+// fetchForecast returns the forecast without publishing an event. The point
+// and forecast requests share a correlation ID.
+func fetchForecast(ctx context.Context, lat string, lon string, units string) (forecast *ForecastResponse, err error) {
+	query, err := unitsQuery(units)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
-	if err = decoder.Decode(&forecast); err != nil {
+	ctx = withCorrelationID(ctx)
+	point, err := PointsContext(ctx, lat, lon)
+	if err != nil {
 		return nil, err
 	}
-	forecast.Point = point
-	return forecast, nil
+	return shared(ctx, point.EndpointForecast+query, func(ctx context.Context) (forecast *ForecastResponse, err error) {
+		res, err := apiCallContext(ctx, point.EndpointForecast+query)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if forecast, err = DecodeForecast(res.Body); err != nil {
+			return nil, err
+		}
+		forecast.Point = point
+		return forecast, nil
+	})
 }
 
 // GridpointForecast returns an array of raw forecast data
 func GridpointForecast(lat string, long string) (forecast *GridpointForecastResponse, err error) {
-	query := ""
-	point, err := Points(lat, long)
+	return GridpointForecastWithUnits(lat, long, "")
+}
+
+// GridpointForecastWithUnits is like GridpointForecast but requests the data in
+// the given units, "us" or "si", instead of the configured units.
+func GridpointForecastWithUnits(lat string, long string, units string) (forecast *GridpointForecastResponse, err error) {
+	return GridpointForecastContext(context.Background(), lat, long, units)
+}
+
+// GridpointForecastContext is like GridpointForecastWithUnits, making the
+// requests with the context.
+func GridpointForecastContext(ctx context.Context, lat string, long string, units string) (forecast *GridpointForecastResponse, err error) {
+	query, err := unitsQuery(units)
 	if err != nil {
 		return nil, err
 	}
-	if config.Units != "" {
-		query = "?units=" + config.Units
-	}
-	res, err := apiCall(point.EndpointForecastGridData + query)
+	ctx = withCorrelationID(ctx)
+	point, err := PointsContext(ctx, lat, long)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	return shared(ctx, point.EndpointForecastGridData+query, func(ctx context.Context) (forecast *GridpointForecastResponse, err error) {
+		res, err := apiCallContext(ctx, point.EndpointForecastGridData+query)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
 
-	decoder := json.NewDecoder(res.Body)
-	if err = decoder.Decode(&forecast); err != nil {
-		return nil, err
-	}
-	forecast.Point = point
-	return forecast, nil
+		if forecast, err = DecodeGridpointForecast(res.Body); err != nil {
+			return nil, err
+		}
+		forecast.Point = point
+		return forecast, nil
+	})
 }
 
 // HourlyForecast returns an array of raw hourly forecast data
 func HourlyForecast(lat string, long string) (forecast *HourlyForecastResponse, err error) {
-	query := ""
-	point, err := Points(lat, long)
+	return HourlyForecastWithUnits(lat, long, "")
+}
+
+// HourlyForecastWithUnits is like HourlyForecast but requests the forecast in
+// the given units, "us" or "si", instead of the configured units.
+func HourlyForecastWithUnits(lat string, long string, units string) (forecast *HourlyForecastResponse, err error) {
+	return HourlyForecastContext(context.Background(), lat, long, units)
+}
+
+// HourlyForecastContext is like HourlyForecastWithUnits, making the requests
+// with the context.
+func HourlyForecastContext(ctx context.Context, lat string, long string, units string) (forecast *HourlyForecastResponse, err error) {
+	query, err := unitsQuery(units)
 	if err != nil {
 		return nil, err
 	}
-	if config.Units != "" {
-		query = "?units=" + config.Units
-	}
-	res, err := apiCall(point.EndpointForecastHourly + query)
+	ctx = withCorrelationID(ctx)
+	point, err := PointsContext(ctx, lat, long)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	return shared(ctx, point.EndpointForecastHourly+query, func(ctx context.Context) (forecast *HourlyForecastResponse, err error) {
+		res, err := apiCallContext(ctx, point.EndpointForecastHourly+query)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
 
-	decoder := json.NewDecoder(res.Body)
-	if err = decoder.Decode(&forecast); err != nil {
-		return nil, err
+		if forecast, err = DecodeHourlyForecast(res.Body); err != nil {
+			return nil, err
+		}
+		forecast.Point = point
+		return forecast, nil
+	})
+}
+
+// unitsQuery returns the query string requesting the units, or the configured
+// units if blank
+func unitsQuery(units string) (string, error) {
+	if units == "" {
+		units = currentConfig().Units
 	}
-	forecast.Point = point
-	return forecast, nil
+	switch units {
+	case "":
+		return "", nil
+	case "us", "si":
+		return "?units=" + units, nil
+	}
+	return "", fmt.Errorf(`invalid units %q, expected "us" or "si"`, units)
 }
 
 type ObservationValue struct {
@@ -415,12 +857,17 @@ type ObservationValue struct {
 	MinValue       float64 `json:"minValue"`
 	UnitCode       string  `json:"unitCode"`
 	QualityControl string  `json:"qualityControl"`
+
+	// Null is true when weather.gov sent the value as null, ex. the station
+	// did not report it. Value is 0 then, see IsMissing.
+	Null bool `json:"-"`
 }
 
 type Observation struct {
 	Elevation      ObservationValue `json:"elevation"`
 	Station        string           `json:"station"`
 	Timestamp      time.Time        `json:"timestamp"`
+	RawMessage     string           `json:"rawMessage"` // METAR report, see ParseMETAR
 	PresentWeather []struct {
 		Intensity  string `json:"intensity"`
 		Modifier   string `json:"modifier"`
@@ -449,25 +896,48 @@ type Observation struct {
 	} `json:"cloudLayers"`
 }
 
+// LatestStationObservation returns the latest observation of the station and
+// publishes an ObservationReceived event on DefaultBus. If stale observations
+// are treated as errors, see SetStaleObservationError, a stale observation is
+// returned with an error wrapping ErrStaleObservation.
 func LatestStationObservation(stationID string) (observation Observation, err error) {
+	observation, err = fetchLatestStationObservation(context.Background(), stationID)
+	if err != nil {
+		return observation, err
+	}
+	DefaultBus.Publish(ObservationReceived{Observation: observation})
+	return observation, nil
+}
+
+// fetchLatestStationObservation returns the observation without publishing an
+// event, making the request with the context
+func fetchLatestStationObservation(ctx context.Context, stationID string) (observation Observation, err error) {
+	if err := ValidateStationID(stationID); err != nil {
+		return observation, err
+	}
 	// /stations/{stationId}/observations/latest
-	endpoint := fmt.Sprintf("%s/observations/latest", stationID)
+	endpoint := urlUnder(stationID, "observations", "latest").String()
 
-	res, err := apiCall(endpoint)
+	res, err := apiCallContext(ctx, endpoint)
 	if err != nil {
-		return observation, fmt.Errorf("failed to get latest observations: %v", err)
+		return observation, fmt.Errorf("failed to get latest observations: %w", err)
 	}
 	defer res.Body.Close()
-	decoder := json.NewDecoder(res.Body)
-	observation = Observation{}
-	if err = decoder.Decode(&observation); err != nil {
+	decoded, err := DecodeObservation(res.Body)
+	if err != nil {
 		return Observation{}, err
 	}
-	return observation, err
+	observation = *decoded
+	if currentConfig().StaleObservationError && observation.IsStale() {
+		return observation, fmt.Errorf("%w: %s is %s old", ErrStaleObservation, stationID, observation.Age().Round(time.Minute))
+	}
+	return observation, nil
 }
 
 type Alert struct {
 	ID          string `json:"@id"`
+	Identifier  string `json:"id"`
+	MessageType string `json:"messageType"` // Alert, Update or Cancel
 	Sent        string `json:"sent"`
 	Effective   string `json:"effective"`
 	Onset       string `json:"onset"`
@@ -484,28 +954,81 @@ type Alert struct {
 	Description string `json:"description"`
 	Instruction string `json:"instruction"`
 	Response    string `json:"response"`
+	// Language of the text of alerts decoded by DecodeCAP, ex. en-US or
+	// es-US, blank when unknown, ex. for weather.gov JSON alerts, which are
+	// in English.
+	Language string `json:"language,omitempty"`
+
+	References []AlertReference    `json:"references"`
+	Parameters map[string][]string `json:"parameters"`
+	EventCode  AlertEventCode      `json:"eventCode"`
+	Geocode    AlertGeocode        `json:"geocode"`
+
+	// AffectedZones are the URLs of the zones of the alert. Geometry is the
+	// polygon of the alert, a WKT string for JSON-LD responses and a GeoJSON
+	// geometry for GeoJSON responses, or null for alerts issued for whole
+	// zones, see Covers.
+	AffectedZones []string        `json:"affectedZones"`
+	Geometry      json.RawMessage `json:"geometry"`
+}
+
+// AlertReference identifies an earlier alert that is updated or cancelled by
+// the alert containing the reference.
+type AlertReference struct {
+	ID         string `json:"@id"`
+	Identifier string `json:"identifier"`
+	Sender     string `json:"sender"`
+	Sent       string `json:"sent"`
+}
+
+// VTEC returns the P-VTEC values found in the alert parameters, if any. Invalid
+// VTEC strings are skipped.
+func (a Alert) VTEC() []VTEC {
+	var vtecs []VTEC
+	for _, s := range a.Parameters["VTEC"] {
+		if vtec, err := ParseVTEC(s); err == nil {
+			vtecs = append(vtecs, vtec)
+		}
+	}
+	return vtecs
 }
 
 func Alerts(lat string, long string) ([]Alert, error) {
-	u := fmt.Sprintf("%s%s%s,%s", config.BaseURL, "/alerts/active?point=", lat, long)
-	res, err := apiCall(u)
+	return AlertsContext(context.Background(), lat, long)
+}
+
+// AlertsContext is like Alerts, making the request with the context.
+func AlertsContext(ctx context.Context, lat string, long string) ([]Alert, error) {
+	u := apiURL("alerts", "active").withQuery(url.Values{"point": {lat + "," + long}}).String()
+	return activeAlerts(ctx, u)
+}
+
+// AlertsForZone returns the active alerts for a zone ID, ex. ILZ014 or ILC031
+func AlertsForZone(zoneID string) ([]Alert, error) {
+	return AlertsForZoneContext(context.Background(), zoneID)
+}
+
+// AlertsForZoneContext is like AlertsForZone, making the request with the
+// context.
+func AlertsForZoneContext(ctx context.Context, zoneID string) ([]Alert, error) {
+	u := apiURL("alerts", "active", "zone", zoneID).String()
+	return activeAlerts(ctx, u)
+}
+
+// activeAlerts returns the alerts from an /alerts/active endpoint
+func activeAlerts(ctx context.Context, u string) ([]Alert, error) {
+	res, err := apiCallContext(ctx, u)
 	if err != nil {
 		return []Alert{}, err
 	}
 	defer res.Body.Close()
-	type Response struct {
-		Data []Alert `json:"@graph"`
-	}
-	r := Response{}
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		fmt.Println("error reading response: ", err)
-		return []Alert{}, err
-	}
-	err = json.Unmarshal(data, &r)
+	alerts := []Alert{}
+	err = DecodeAlerts(res.Body, func(a Alert) bool {
+		alerts = append(alerts, a)
+		return true
+	})
 	if err != nil {
-		fmt.Println("error unmarshaling response: ", err)
 		return []Alert{}, err
 	}
-	return r.Data, err
+	return alerts, nil
 }
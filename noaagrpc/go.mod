@@ -0,0 +1,20 @@
+module github.com/chrisdobbins/noaa/noaagrpc
+
+go 1.23.0
+
+require (
+	github.com/chrisdobbins/noaa v0.0.0-20261016200604-450e99af3d61
+	google.golang.org/grpc v1.75.0
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+)
+
+// The replacement builds the module against the local checkout. It is ignored
+// when the module is required by other modules, which use the version above.
+replace github.com/chrisdobbins/noaa => ../
@@ -0,0 +1,757 @@
+// Protocol buffer definitions for the noaa gRPC service which wraps the
+// weather.gov API for use as a sidecar in polyglot environments.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: noaapb/weather.proto
+
+package noaapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PointRequest identifies a point by latitude and longitude.
+type PointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PointRequest) Reset() {
+	*x = PointRequest{}
+	mi := &file_noaapb_weather_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointRequest) ProtoMessage() {}
+
+func (x *PointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_noaapb_weather_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointRequest.ProtoReflect.Descriptor instead.
+func (*PointRequest) Descriptor() ([]byte, []int) {
+	return file_noaapb_weather_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PointRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *PointRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+// Forecast holds the periods of a forecast or hourly forecast.
+type Forecast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updated       string                 `protobuf:"bytes,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	Units         string                 `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	Periods       []*Period              `protobuf:"bytes,3,rep,name=periods,proto3" json:"periods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Forecast) Reset() {
+	*x = Forecast{}
+	mi := &file_noaapb_weather_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Forecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Forecast) ProtoMessage() {}
+
+func (x *Forecast) ProtoReflect() protoreflect.Message {
+	mi := &file_noaapb_weather_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Forecast.ProtoReflect.Descriptor instead.
+func (*Forecast) Descriptor() ([]byte, []int) {
+	return file_noaapb_weather_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Forecast) GetUpdated() string {
+	if x != nil {
+		return x.Updated
+	}
+	return ""
+}
+
+func (x *Forecast) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *Forecast) GetPeriods() []*Period {
+	if x != nil {
+		return x.Periods
+	}
+	return nil
+}
+
+// Period is a single period within a forecast.
+type Period struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Number                     int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Name                       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartTime                  string                 `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime                    string                 `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	IsDaytime                  bool                   `protobuf:"varint,5,opt,name=is_daytime,json=isDaytime,proto3" json:"is_daytime,omitempty"`
+	Temperature                float64                `protobuf:"fixed64,6,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	TemperatureUnit            string                 `protobuf:"bytes,7,opt,name=temperature_unit,json=temperatureUnit,proto3" json:"temperature_unit,omitempty"`
+	TemperatureTrend           string                 `protobuf:"bytes,8,opt,name=temperature_trend,json=temperatureTrend,proto3" json:"temperature_trend,omitempty"`
+	WindSpeed                  string                 `protobuf:"bytes,9,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindDirection              string                 `protobuf:"bytes,10,opt,name=wind_direction,json=windDirection,proto3" json:"wind_direction,omitempty"`
+	Icon                       string                 `protobuf:"bytes,11,opt,name=icon,proto3" json:"icon,omitempty"`
+	ShortForecast              string                 `protobuf:"bytes,12,opt,name=short_forecast,json=shortForecast,proto3" json:"short_forecast,omitempty"`
+	DetailedForecast           string                 `protobuf:"bytes,13,opt,name=detailed_forecast,json=detailedForecast,proto3" json:"detailed_forecast,omitempty"`
+	ProbabilityOfPrecipitation float64                `protobuf:"fixed64,14,opt,name=probability_of_precipitation,json=probabilityOfPrecipitation,proto3" json:"probability_of_precipitation,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *Period) Reset() {
+	*x = Period{}
+	mi := &file_noaapb_weather_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Period) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Period) ProtoMessage() {}
+
+func (x *Period) ProtoReflect() protoreflect.Message {
+	mi := &file_noaapb_weather_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Period.ProtoReflect.Descriptor instead.
+func (*Period) Descriptor() ([]byte, []int) {
+	return file_noaapb_weather_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Period) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Period) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Period) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *Period) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *Period) GetIsDaytime() bool {
+	if x != nil {
+		return x.IsDaytime
+	}
+	return false
+}
+
+func (x *Period) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *Period) GetTemperatureUnit() string {
+	if x != nil {
+		return x.TemperatureUnit
+	}
+	return ""
+}
+
+func (x *Period) GetTemperatureTrend() string {
+	if x != nil {
+		return x.TemperatureTrend
+	}
+	return ""
+}
+
+func (x *Period) GetWindSpeed() string {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return ""
+}
+
+func (x *Period) GetWindDirection() string {
+	if x != nil {
+		return x.WindDirection
+	}
+	return ""
+}
+
+func (x *Period) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Period) GetShortForecast() string {
+	if x != nil {
+		return x.ShortForecast
+	}
+	return ""
+}
+
+func (x *Period) GetDetailedForecast() string {
+	if x != nil {
+		return x.DetailedForecast
+	}
+	return ""
+}
+
+func (x *Period) GetProbabilityOfPrecipitation() float64 {
+	if x != nil {
+		return x.ProbabilityOfPrecipitation
+	}
+	return 0
+}
+
+// StreamAlertsRequest selects the alerts to stream by point or zone.
+type StreamAlertsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Area:
+	//
+	//	*StreamAlertsRequest_Point
+	//	*StreamAlertsRequest_Zone
+	Area isStreamAlertsRequest_Area `protobuf_oneof:"area"`
+	// How often to poll weather.gov, defaults to 60 seconds.
+	PollIntervalSeconds int32 `protobuf:"varint,3,opt,name=poll_interval_seconds,json=pollIntervalSeconds,proto3" json:"poll_interval_seconds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *StreamAlertsRequest) Reset() {
+	*x = StreamAlertsRequest{}
+	mi := &file_noaapb_weather_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAlertsRequest) ProtoMessage() {}
+
+func (x *StreamAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_noaapb_weather_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAlertsRequest.ProtoReflect.Descriptor instead.
+func (*StreamAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_noaapb_weather_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamAlertsRequest) GetArea() isStreamAlertsRequest_Area {
+	if x != nil {
+		return x.Area
+	}
+	return nil
+}
+
+func (x *StreamAlertsRequest) GetPoint() *PointRequest {
+	if x != nil {
+		if x, ok := x.Area.(*StreamAlertsRequest_Point); ok {
+			return x.Point
+		}
+	}
+	return nil
+}
+
+func (x *StreamAlertsRequest) GetZone() string {
+	if x != nil {
+		if x, ok := x.Area.(*StreamAlertsRequest_Zone); ok {
+			return x.Zone
+		}
+	}
+	return ""
+}
+
+func (x *StreamAlertsRequest) GetPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.PollIntervalSeconds
+	}
+	return 0
+}
+
+type isStreamAlertsRequest_Area interface {
+	isStreamAlertsRequest_Area()
+}
+
+type StreamAlertsRequest_Point struct {
+	Point *PointRequest `protobuf:"bytes,1,opt,name=point,proto3,oneof"`
+}
+
+type StreamAlertsRequest_Zone struct {
+	Zone string `protobuf:"bytes,2,opt,name=zone,proto3,oneof"`
+}
+
+func (*StreamAlertsRequest_Point) isStreamAlertsRequest_Area() {}
+
+func (*StreamAlertsRequest_Zone) isStreamAlertsRequest_Area() {}
+
+// AlertEvent is sent when an alert is issued, updated or ended.
+type AlertEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Key identifying the event across its updates, ex. KLOT.WI.Y.0005
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Most recent action, ex. NEW, CON, EXT, CAN
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Active        bool   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	Alert         *Alert `protobuf:"bytes,4,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertEvent) Reset() {
+	*x = AlertEvent{}
+	mi := &file_noaapb_weather_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertEvent) ProtoMessage() {}
+
+func (x *AlertEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_noaapb_weather_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertEvent.ProtoReflect.Descriptor instead.
+func (*AlertEvent) Descriptor() ([]byte, []int) {
+	return file_noaapb_weather_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AlertEvent) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *AlertEvent) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+// Alert holds the values of the most recent alert message for an event.
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MessageType   string                 `protobuf:"bytes,2,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	Event         string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Severity      string                 `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	Certainty     string                 `protobuf:"bytes,6,opt,name=certainty,proto3" json:"certainty,omitempty"`
+	Urgency       string                 `protobuf:"bytes,7,opt,name=urgency,proto3" json:"urgency,omitempty"`
+	Sent          string                 `protobuf:"bytes,8,opt,name=sent,proto3" json:"sent,omitempty"`
+	Effective     string                 `protobuf:"bytes,9,opt,name=effective,proto3" json:"effective,omitempty"`
+	Onset         string                 `protobuf:"bytes,10,opt,name=onset,proto3" json:"onset,omitempty"`
+	Expires       string                 `protobuf:"bytes,11,opt,name=expires,proto3" json:"expires,omitempty"`
+	Ends          string                 `protobuf:"bytes,12,opt,name=ends,proto3" json:"ends,omitempty"`
+	SenderName    string                 `protobuf:"bytes,13,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`
+	Headline      string                 `protobuf:"bytes,14,opt,name=headline,proto3" json:"headline,omitempty"`
+	Description   string                 `protobuf:"bytes,15,opt,name=description,proto3" json:"description,omitempty"`
+	Instruction   string                 `protobuf:"bytes,16,opt,name=instruction,proto3" json:"instruction,omitempty"`
+	Vtec          []string               `protobuf:"bytes,17,rep,name=vtec,proto3" json:"vtec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_noaapb_weather_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_noaapb_weather_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_noaapb_weather_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Alert) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Alert) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *Alert) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *Alert) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Alert) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Alert) GetCertainty() string {
+	if x != nil {
+		return x.Certainty
+	}
+	return ""
+}
+
+func (x *Alert) GetUrgency() string {
+	if x != nil {
+		return x.Urgency
+	}
+	return ""
+}
+
+func (x *Alert) GetSent() string {
+	if x != nil {
+		return x.Sent
+	}
+	return ""
+}
+
+func (x *Alert) GetEffective() string {
+	if x != nil {
+		return x.Effective
+	}
+	return ""
+}
+
+func (x *Alert) GetOnset() string {
+	if x != nil {
+		return x.Onset
+	}
+	return ""
+}
+
+func (x *Alert) GetExpires() string {
+	if x != nil {
+		return x.Expires
+	}
+	return ""
+}
+
+func (x *Alert) GetEnds() string {
+	if x != nil {
+		return x.Ends
+	}
+	return ""
+}
+
+func (x *Alert) GetSenderName() string {
+	if x != nil {
+		return x.SenderName
+	}
+	return ""
+}
+
+func (x *Alert) GetHeadline() string {
+	if x != nil {
+		return x.Headline
+	}
+	return ""
+}
+
+func (x *Alert) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Alert) GetInstruction() string {
+	if x != nil {
+		return x.Instruction
+	}
+	return ""
+}
+
+func (x *Alert) GetVtec() []string {
+	if x != nil {
+		return x.Vtec
+	}
+	return nil
+}
+
+var File_noaapb_weather_proto protoreflect.FileDescriptor
+
+const file_noaapb_weather_proto_rawDesc = "" +
+	"\n" +
+	"\x14noaapb/weather.proto\x12\anoaa.v1\"H\n" +
+	"\fPointRequest\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"e\n" +
+	"\bForecast\x12\x18\n" +
+	"\aupdated\x18\x01 \x01(\tR\aupdated\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\tR\x05units\x12)\n" +
+	"\aperiods\x18\x03 \x03(\v2\x0f.noaa.v1.PeriodR\aperiods\"\xf7\x03\n" +
+	"\x06Period\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\tR\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\tR\aendTime\x12\x1d\n" +
+	"\n" +
+	"is_daytime\x18\x05 \x01(\bR\tisDaytime\x12 \n" +
+	"\vtemperature\x18\x06 \x01(\x01R\vtemperature\x12)\n" +
+	"\x10temperature_unit\x18\a \x01(\tR\x0ftemperatureUnit\x12+\n" +
+	"\x11temperature_trend\x18\b \x01(\tR\x10temperatureTrend\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\t \x01(\tR\twindSpeed\x12%\n" +
+	"\x0ewind_direction\x18\n" +
+	" \x01(\tR\rwindDirection\x12\x12\n" +
+	"\x04icon\x18\v \x01(\tR\x04icon\x12%\n" +
+	"\x0eshort_forecast\x18\f \x01(\tR\rshortForecast\x12+\n" +
+	"\x11detailed_forecast\x18\r \x01(\tR\x10detailedForecast\x12@\n" +
+	"\x1cprobability_of_precipitation\x18\x0e \x01(\x01R\x1aprobabilityOfPrecipitation\"\x96\x01\n" +
+	"\x13StreamAlertsRequest\x12-\n" +
+	"\x05point\x18\x01 \x01(\v2\x15.noaa.v1.PointRequestH\x00R\x05point\x12\x14\n" +
+	"\x04zone\x18\x02 \x01(\tH\x00R\x04zone\x122\n" +
+	"\x15poll_interval_seconds\x18\x03 \x01(\x05R\x13pollIntervalSecondsB\x06\n" +
+	"\x04area\"t\n" +
+	"\n" +
+	"AlertEvent\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\x12$\n" +
+	"\x05alert\x18\x04 \x01(\v2\x0e.noaa.v1.AlertR\x05alert\"\xc7\x03\n" +
+	"\x05Alert\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fmessage_type\x18\x02 \x01(\tR\vmessageType\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1a\n" +
+	"\bseverity\x18\x05 \x01(\tR\bseverity\x12\x1c\n" +
+	"\tcertainty\x18\x06 \x01(\tR\tcertainty\x12\x18\n" +
+	"\aurgency\x18\a \x01(\tR\aurgency\x12\x12\n" +
+	"\x04sent\x18\b \x01(\tR\x04sent\x12\x1c\n" +
+	"\teffective\x18\t \x01(\tR\teffective\x12\x14\n" +
+	"\x05onset\x18\n" +
+	" \x01(\tR\x05onset\x12\x18\n" +
+	"\aexpires\x18\v \x01(\tR\aexpires\x12\x12\n" +
+	"\x04ends\x18\f \x01(\tR\x04ends\x12\x1f\n" +
+	"\vsender_name\x18\r \x01(\tR\n" +
+	"senderName\x12\x1a\n" +
+	"\bheadline\x18\x0e \x01(\tR\bheadline\x12 \n" +
+	"\vdescription\x18\x0f \x01(\tR\vdescription\x12 \n" +
+	"\vinstruction\x18\x10 \x01(\tR\vinstruction\x12\x12\n" +
+	"\x04vtec\x18\x11 \x03(\tR\x04vtec2\xbe\x01\n" +
+	"\aWeather\x127\n" +
+	"\vGetForecast\x12\x15.noaa.v1.PointRequest\x1a\x11.noaa.v1.Forecast\x125\n" +
+	"\tGetHourly\x12\x15.noaa.v1.PointRequest\x1a\x11.noaa.v1.Forecast\x12C\n" +
+	"\fStreamAlerts\x12\x1c.noaa.v1.StreamAlertsRequest\x1a\x13.noaa.v1.AlertEvent0\x01B.Z,github.com/chrisdobbins/noaa/noaagrpc/noaapbb\x06proto3"
+
+var (
+	file_noaapb_weather_proto_rawDescOnce sync.Once
+	file_noaapb_weather_proto_rawDescData []byte
+)
+
+func file_noaapb_weather_proto_rawDescGZIP() []byte {
+	file_noaapb_weather_proto_rawDescOnce.Do(func() {
+		file_noaapb_weather_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_noaapb_weather_proto_rawDesc), len(file_noaapb_weather_proto_rawDesc)))
+	})
+	return file_noaapb_weather_proto_rawDescData
+}
+
+var file_noaapb_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_noaapb_weather_proto_goTypes = []any{
+	(*PointRequest)(nil),        // 0: noaa.v1.PointRequest
+	(*Forecast)(nil),            // 1: noaa.v1.Forecast
+	(*Period)(nil),              // 2: noaa.v1.Period
+	(*StreamAlertsRequest)(nil), // 3: noaa.v1.StreamAlertsRequest
+	(*AlertEvent)(nil),          // 4: noaa.v1.AlertEvent
+	(*Alert)(nil),               // 5: noaa.v1.Alert
+}
+var file_noaapb_weather_proto_depIdxs = []int32{
+	2, // 0: noaa.v1.Forecast.periods:type_name -> noaa.v1.Period
+	0, // 1: noaa.v1.StreamAlertsRequest.point:type_name -> noaa.v1.PointRequest
+	5, // 2: noaa.v1.AlertEvent.alert:type_name -> noaa.v1.Alert
+	0, // 3: noaa.v1.Weather.GetForecast:input_type -> noaa.v1.PointRequest
+	0, // 4: noaa.v1.Weather.GetHourly:input_type -> noaa.v1.PointRequest
+	3, // 5: noaa.v1.Weather.StreamAlerts:input_type -> noaa.v1.StreamAlertsRequest
+	1, // 6: noaa.v1.Weather.GetForecast:output_type -> noaa.v1.Forecast
+	1, // 7: noaa.v1.Weather.GetHourly:output_type -> noaa.v1.Forecast
+	4, // 8: noaa.v1.Weather.StreamAlerts:output_type -> noaa.v1.AlertEvent
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_noaapb_weather_proto_init() }
+func file_noaapb_weather_proto_init() {
+	if File_noaapb_weather_proto != nil {
+		return
+	}
+	file_noaapb_weather_proto_msgTypes[3].OneofWrappers = []any{
+		(*StreamAlertsRequest_Point)(nil),
+		(*StreamAlertsRequest_Zone)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_noaapb_weather_proto_rawDesc), len(file_noaapb_weather_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_noaapb_weather_proto_goTypes,
+		DependencyIndexes: file_noaapb_weather_proto_depIdxs,
+		MessageInfos:      file_noaapb_weather_proto_msgTypes,
+	}.Build()
+	File_noaapb_weather_proto = out.File
+	file_noaapb_weather_proto_goTypes = nil
+	file_noaapb_weather_proto_depIdxs = nil
+}
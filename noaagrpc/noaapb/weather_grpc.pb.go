@@ -0,0 +1,216 @@
+// Protocol buffer definitions for the noaa gRPC service which wraps the
+// weather.gov API for use as a sidecar in polyglot environments.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: noaapb/weather.proto
+
+package noaapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Weather_GetForecast_FullMethodName  = "/noaa.v1.Weather/GetForecast"
+	Weather_GetHourly_FullMethodName    = "/noaa.v1.Weather/GetHourly"
+	Weather_StreamAlerts_FullMethodName = "/noaa.v1.Weather/StreamAlerts"
+)
+
+// WeatherClient is the client API for Weather service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Weather serves forecasts and alerts from api.weather.gov.
+type WeatherClient interface {
+	// GetForecast returns the forecast (two periods per day) for a point.
+	GetForecast(ctx context.Context, in *PointRequest, opts ...grpc.CallOption) (*Forecast, error)
+	// GetHourly returns the hourly forecast for a point.
+	GetHourly(ctx context.Context, in *PointRequest, opts ...grpc.CallOption) (*Forecast, error)
+	// StreamAlerts polls the active alerts for a point or zone and streams
+	// every new or updated alert until the client cancels the call.
+	StreamAlerts(ctx context.Context, in *StreamAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AlertEvent], error)
+}
+
+type weatherClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWeatherClient(cc grpc.ClientConnInterface) WeatherClient {
+	return &weatherClient{cc}
+}
+
+func (c *weatherClient) GetForecast(ctx context.Context, in *PointRequest, opts ...grpc.CallOption) (*Forecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Forecast)
+	err := c.cc.Invoke(ctx, Weather_GetForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherClient) GetHourly(ctx context.Context, in *PointRequest, opts ...grpc.CallOption) (*Forecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Forecast)
+	err := c.cc.Invoke(ctx, Weather_GetHourly_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherClient) StreamAlerts(ctx context.Context, in *StreamAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AlertEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Weather_ServiceDesc.Streams[0], Weather_StreamAlerts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamAlertsRequest, AlertEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Weather_StreamAlertsClient = grpc.ServerStreamingClient[AlertEvent]
+
+// WeatherServer is the server API for Weather service.
+// All implementations must embed UnimplementedWeatherServer
+// for forward compatibility.
+//
+// Weather serves forecasts and alerts from api.weather.gov.
+type WeatherServer interface {
+	// GetForecast returns the forecast (two periods per day) for a point.
+	GetForecast(context.Context, *PointRequest) (*Forecast, error)
+	// GetHourly returns the hourly forecast for a point.
+	GetHourly(context.Context, *PointRequest) (*Forecast, error)
+	// StreamAlerts polls the active alerts for a point or zone and streams
+	// every new or updated alert until the client cancels the call.
+	StreamAlerts(*StreamAlertsRequest, grpc.ServerStreamingServer[AlertEvent]) error
+	mustEmbedUnimplementedWeatherServer()
+}
+
+// UnimplementedWeatherServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWeatherServer struct{}
+
+func (UnimplementedWeatherServer) GetForecast(context.Context, *PointRequest) (*Forecast, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetForecast not implemented")
+}
+func (UnimplementedWeatherServer) GetHourly(context.Context, *PointRequest) (*Forecast, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHourly not implemented")
+}
+func (UnimplementedWeatherServer) StreamAlerts(*StreamAlertsRequest, grpc.ServerStreamingServer[AlertEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAlerts not implemented")
+}
+func (UnimplementedWeatherServer) mustEmbedUnimplementedWeatherServer() {}
+func (UnimplementedWeatherServer) testEmbeddedByValue()                 {}
+
+// UnsafeWeatherServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WeatherServer will
+// result in compilation errors.
+type UnsafeWeatherServer interface {
+	mustEmbedUnimplementedWeatherServer()
+}
+
+func RegisterWeatherServer(s grpc.ServiceRegistrar, srv WeatherServer) {
+	// If the following call pancis, it indicates UnimplementedWeatherServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Weather_ServiceDesc, srv)
+}
+
+func _Weather_GetForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServer).GetForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Weather_GetForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServer).GetForecast(ctx, req.(*PointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Weather_GetHourly_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServer).GetHourly(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Weather_GetHourly_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServer).GetHourly(ctx, req.(*PointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Weather_StreamAlerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamAlertsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WeatherServer).StreamAlerts(m, &grpc.GenericServerStream[StreamAlertsRequest, AlertEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Weather_StreamAlertsServer = grpc.ServerStreamingServer[AlertEvent]
+
+// Weather_ServiceDesc is the grpc.ServiceDesc for Weather service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Weather_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "noaa.v1.Weather",
+	HandlerType: (*WeatherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetForecast",
+			Handler:    _Weather_GetForecast_Handler,
+		},
+		{
+			MethodName: "GetHourly",
+			Handler:    _Weather_GetHourly_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAlerts",
+			Handler:       _Weather_StreamAlerts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "noaapb/weather.proto",
+}
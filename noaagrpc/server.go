@@ -0,0 +1,200 @@
+// Package noaagrpc implements the gRPC Weather service defined in
+// noaapb/weather.proto on top of the noaa client, so that services written in
+// other languages can consume weather.gov data through a sidecar. For example:
+//
+//	s := grpc.NewServer()
+//	noaapb.RegisterWeatherServer(s, noaagrpc.NewServer())
+//	s.Serve(listener)
+//
+// It is a separate module so that the noaa package does not depend on gRPC.
+// The generated code can be updated with `go generate` (requires protoc with
+// the protoc-gen-go and protoc-gen-go-grpc plugins).
+package noaagrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative noaapb/weather.proto
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/noaagrpc/noaapb"
+)
+
+// DefaultPollInterval is used by StreamAlerts when the request does not
+// specify an interval. Intervals below MinPollInterval are raised to it.
+const (
+	DefaultPollInterval = time.Minute
+	MinPollInterval     = 15 * time.Second
+)
+
+// Server implements noaapb.WeatherServer using the noaa package.
+type Server struct {
+	noaapb.UnimplementedWeatherServer
+
+	// fetch functions which are replaced in tests
+	forecast func(ctx context.Context, lat, lon string) (*noaa.ForecastResponse, error)
+	hourly   func(ctx context.Context, lat, lon string) (*noaa.HourlyForecastResponse, error)
+	alerts   func(lat, lon string) ([]noaa.Alert, error)
+	zone     func(zoneID string) ([]noaa.Alert, error)
+}
+
+// NewServer returns a Server using the global noaa configuration. Forecast
+// requests are cancelled with their RPC.
+func NewServer() *Server {
+	return &Server{
+		forecast: func(ctx context.Context, lat, lon string) (*noaa.ForecastResponse, error) {
+			return noaa.ForecastContext(ctx, lat, lon, "")
+		},
+		hourly: func(ctx context.Context, lat, lon string) (*noaa.HourlyForecastResponse, error) {
+			return noaa.HourlyForecastContext(ctx, lat, lon, "")
+		},
+		alerts: noaa.Alerts,
+		zone:   noaa.AlertsForZone,
+	}
+}
+
+// GetForecast implements noaapb.WeatherServer.
+func (s *Server) GetForecast(ctx context.Context, req *noaapb.PointRequest) (*noaapb.Forecast, error) {
+	lat, lon, err := coordinates(req)
+	if err != nil {
+		return nil, err
+	}
+	forecast, err := s.forecast(ctx, lat, lon)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	res := &noaapb.Forecast{Updated: forecast.Updated, Units: forecast.Units}
+	for _, p := range forecast.Periods {
+		res.Periods = append(res.Periods, period(p))
+	}
+	return res, nil
+}
+
+// GetHourly implements noaapb.WeatherServer.
+func (s *Server) GetHourly(ctx context.Context, req *noaapb.PointRequest) (*noaapb.Forecast, error) {
+	lat, lon, err := coordinates(req)
+	if err != nil {
+		return nil, err
+	}
+	forecast, err := s.hourly(ctx, lat, lon)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	res := &noaapb.Forecast{Updated: forecast.Updated, Units: forecast.Units}
+	for _, p := range forecast.Periods {
+		res.Periods = append(res.Periods, period(p.ForecastResponsePeriod))
+	}
+	return res, nil
+}
+
+// StreamAlerts implements noaapb.WeatherServer. Alerts are deduplicated with
+// a noaa.AlertTracker so each event is only sent when it changes.
+func (s *Server) StreamAlerts(req *noaapb.StreamAlertsRequest, stream noaapb.Weather_StreamAlertsServer) error {
+	var fetch func() ([]noaa.Alert, error)
+	switch area := req.Area.(type) {
+	case *noaapb.StreamAlertsRequest_Zone:
+		zone := area.Zone
+		if err := noaa.ValidateZoneID(zone); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		fetch = func() ([]noaa.Alert, error) { return s.zone(zone) }
+	case *noaapb.StreamAlertsRequest_Point:
+		lat, lon, err := coordinates(area.Point)
+		if err != nil {
+			return err
+		}
+		fetch = func() ([]noaa.Alert, error) { return s.alerts(lat, lon) }
+	default:
+		return status.Error(codes.InvalidArgument, "a point or zone is required")
+	}
+
+	interval := time.Duration(req.PollIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	} else if interval < MinPollInterval {
+		interval = MinPollInterval
+	}
+
+	tracker := noaa.NewAlertTracker()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		alerts, err := fetch()
+		if err == nil {
+			for _, event := range tracker.Update(alerts...) {
+				if err := stream.Send(alertEvent(event)); err != nil {
+					return err
+				}
+			}
+		}
+		select {
+		case <-stream.Context().Done():
+			if errors.Is(stream.Context().Err(), context.Canceled) {
+				return nil
+			}
+			return stream.Context().Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// coordinates validates the point and formats it for the noaa package
+func coordinates(p *noaapb.PointRequest) (lat, lon string, err error) {
+	if p == nil || p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+		return "", "", status.Error(codes.InvalidArgument, "a valid latitude and longitude are required")
+	}
+	return strconv.FormatFloat(p.Latitude, 'f', 4, 64), strconv.FormatFloat(p.Longitude, 'f', 4, 64), nil
+}
+
+// period converts a forecast period to its protobuf message
+func period(p noaa.ForecastResponsePeriod) *noaapb.Period {
+	return &noaapb.Period{
+		Number:                     p.ID,
+		Name:                       p.Name,
+		StartTime:                  p.StartTime,
+		EndTime:                    p.EndTime,
+		IsDaytime:                  p.IsDaytime,
+		Temperature:                p.Temperature,
+		TemperatureUnit:            p.TemperatureUnit,
+		TemperatureTrend:           p.TemperatureTrend,
+		WindSpeed:                  p.WindSpeed,
+		WindDirection:              p.WindDirection,
+		Icon:                       p.Icon,
+		ShortForecast:              p.Summary,
+		DetailedForecast:           p.Details,
+		ProbabilityOfPrecipitation: p.ProbabilityOfPrecipitation.Value,
+	}
+}
+
+// alertEvent converts a tracked alert event to its protobuf message
+func alertEvent(e noaa.AlertEvent) *noaapb.AlertEvent {
+	a := e.Current
+	alert := &noaapb.Alert{
+		Id:          a.Identifier,
+		MessageType: a.MessageType,
+		Event:       a.Event,
+		Status:      a.Status,
+		Severity:    a.Severity,
+		Certainty:   a.Certainty,
+		Urgency:     a.Urgency,
+		Sent:        a.Sent,
+		Effective:   a.Effective,
+		Onset:       a.Onset,
+		Expires:     a.Expires,
+		Ends:        a.Ends,
+		SenderName:  a.SenderName,
+		Headline:    a.Headline,
+		Description: a.Description,
+		Instruction: a.Instruction,
+	}
+	for _, v := range a.VTEC() {
+		alert.Vtec = append(alert.Vtec, v.String())
+	}
+	return &noaapb.AlertEvent{Key: e.Key, Status: e.Status, Active: e.IsActive(), Alert: alert}
+}
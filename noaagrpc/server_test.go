@@ -0,0 +1,91 @@
+package noaagrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/noaagrpc/noaapb"
+)
+
+func TestGetForecast(t *testing.T) {
+	s := NewServer()
+	s.forecast = func(_ context.Context, lat, lon string) (*noaa.ForecastResponse, error) {
+		if lat != "41.8370" || lon != "-87.6850" {
+			t.Errorf("unexpected coordinates %s,%s", lat, lon)
+		}
+		return &noaa.ForecastResponse{Units: "us", Periods: []noaa.ForecastResponsePeriod{
+			{ID: 1, Name: "Tonight", Temperature: 59, TemperatureUnit: "F", Summary: "Clear"},
+		}}, nil
+	}
+	res, err := s.GetForecast(context.Background(), &noaapb.PointRequest{Latitude: 41.837, Longitude: -87.685})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Periods) != 1 || res.Periods[0].Name != "Tonight" || res.Periods[0].ShortForecast != "Clear" {
+		t.Errorf("unexpected forecast: %v", res)
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	s := NewServer()
+	_, err := s.GetForecast(context.Background(), &noaapb.PointRequest{Latitude: 91})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+	err = s.StreamAlerts(&noaapb.StreamAlertsRequest{}, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestStreamAlerts(t *testing.T) {
+	s := NewServer()
+	s.zone = func(zoneID string) ([]noaa.Alert, error) {
+		return []noaa.Alert{{Identifier: "urn:oid:1", MessageType: "Alert", Event: "Winter Storm Warning", Sent: "2023-01-15T10:00:00-06:00"}}, nil
+	}
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	noaapb.RegisterWeatherServer(server, s)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := noaapb.NewWeatherClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamAlerts(ctx, &noaapb.StreamAlertsRequest{Area: &noaapb.StreamAlertsRequest_Zone{Zone: "ILZ014"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Alert.GetId() != "urn:oid:1" || event.Alert.GetEvent() != "Winter Storm Warning" {
+		t.Errorf("unexpected alert event: %v", event)
+	}
+
+	stream, err = client.StreamAlerts(ctx, &noaapb.StreamAlertsRequest{Area: &noaapb.StreamAlertsRequest_Zone{Zone: "../alerts"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an invalid zone, got %v", err)
+	}
+}
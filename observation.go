@@ -0,0 +1,268 @@
+package noaa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxObservationAge is the default age above which observations are
+// considered stale, see SetMaxObservationAge.
+const DefaultMaxObservationAge = 2 * time.Hour
+
+var (
+	// ErrNoObservation is returned by LatestObservationWithFallback when none
+	// of the stations tried has a recent observation which passed quality
+	// control.
+	ErrNoObservation = errors.New("no recent observation")
+
+	// ErrStaleObservation is wrapped by the error returned when a stale
+	// observation is fetched and stale observations are treated as errors.
+	ErrStaleObservation = errors.New("stale observation")
+)
+
+// StationObservation is an observation together with the station it was
+// chosen from.
+type StationObservation struct {
+	Station     string // station URL
+	Observation Observation
+	Age         time.Duration // time since the observation was made
+}
+
+// Age returns the time since the observation was made.
+func (o Observation) Age() time.Duration {
+	return time.Since(o.Timestamp)
+}
+
+// IsStale reports whether the observation is older than the configured maximum
+// observation age, see SetMaxObservationAge.
+func (o Observation) IsStale() bool {
+	maxAge := currentConfig().MaxObservationAge
+	return maxAge > 0 && o.Age() > maxAge
+}
+
+// failedQualityControl are the MADIS quality control codes of values which
+// failed checks, see https://madis.ncep.noaa.gov/madis_sfc_qc_notes.shtml
+var failedQualityControl = map[string]bool{
+	"X": true, // rejected
+	"Q": true, // questioned
+	"B": true, // subjective bad
+}
+
+// PassedQualityControl reports whether the value did not fail quality control.
+// Values which were not checked are considered to have passed.
+func (v ObservationValue) PassedQualityControl() bool {
+	return !failedQualityControl[v.QualityControl]
+}
+
+// IsMissing reports whether the value was not reported: it is null or has
+// no unit. weather.gov sends missing values with a unit and a null value,
+// which would otherwise read as 0.
+func (v ObservationValue) IsMissing() bool {
+	return v.Null || v.UnitCode == ""
+}
+
+// observationValueJSON is the JSON form of an ObservationValue, whose value
+// is null when it is missing
+type observationValueJSON struct {
+	Value          *float64 `json:"value"`
+	MaxValue       float64  `json:"maxValue"`
+	MinValue       float64  `json:"minValue"`
+	UnitCode       string   `json:"unitCode"`
+	QualityControl string   `json:"qualityControl"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, setting Null for null values.
+func (v *ObservationValue) UnmarshalJSON(data []byte) error {
+	var j observationValueJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*v = ObservationValue{MaxValue: j.MaxValue, MinValue: j.MinValue, UnitCode: j.UnitCode, QualityControl: j.QualityControl, Null: j.Value == nil}
+	if j.Value != nil {
+		v.Value = *j.Value
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing null values as null.
+func (v ObservationValue) MarshalJSON() ([]byte, error) {
+	j := observationValueJSON{MaxValue: v.MaxValue, MinValue: v.MinValue, UnitCode: v.UnitCode, QualityControl: v.QualityControl}
+	if !v.Null {
+		j.Value = &v.Value
+	}
+	return json.Marshal(j)
+}
+
+// LatestObservationWithFallback returns the latest observation of the nearest
+// station to <lat,lon> which is not stale, see SetMaxObservationAge, and whose
+// temperature was reported and passed quality control. Stations are tried in order of distance
+// up to maxStations; 0 tries all stations for the point. The chosen
+// observation is published as an ObservationReceived event on DefaultBus.
+func LatestObservationWithFallback(lat string, lon string, maxStations int) (*StationObservation, error) {
+	return LatestObservationWithFallbackContext(context.Background(), lat, lon, maxStations)
+}
+
+// LatestObservationWithFallbackContext is like LatestObservationWithFallback,
+// making the requests with the context. No more stations are tried once the
+// context is done.
+func LatestObservationWithFallbackContext(ctx context.Context, lat string, lon string, maxStations int) (*StationObservation, error) {
+	ctx = withCorrelationID(ctx)
+	stations, err := StationsContext(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	candidates := stations.Stations
+	if maxStations > 0 && len(candidates) > maxStations {
+		candidates = candidates[:maxStations]
+	}
+	var lastErr error
+	for _, station := range candidates {
+		observation, err := fetchLatestStationObservation(ctx, station)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		age := observation.Age()
+		if observation.IsStale() {
+			lastErr = fmt.Errorf("%w: %s is %s old", ErrStaleObservation, station, age.Round(time.Minute))
+			continue
+		}
+		if observation.Temperature.IsMissing() {
+			lastErr = fmt.Errorf("observation from %s has no temperature", station)
+			continue
+		}
+		if !observation.Temperature.PassedQualityControl() {
+			lastErr = fmt.Errorf("observation from %s failed quality control", station)
+			continue
+		}
+		DefaultBus.Publish(ObservationReceived{Location: Location{Lat: lat, Lon: lon}, Observation: observation})
+		return &StationObservation{Station: station, Observation: observation, Age: age}, nil
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("%w for %s,%s: %v", ErrNoObservation, lat, lon, lastErr)
+	}
+	return nil, fmt.Errorf("%w for %s,%s: no stations", ErrNoObservation, lat, lon)
+}
+
+// ObservationsOptions select the observations returned by StationObservations.
+// Zero values are not sent, weather.gov then returns the most recent
+// observations.
+type ObservationsOptions struct {
+	Start time.Time
+	End   time.Time
+	Limit int
+
+	// IncludeSpecial includes special (SPECI) observations, which stations
+	// report between their routine hourly observations when the weather
+	// changes significantly. By default only routine observations are
+	// returned.
+	IncludeSpecial bool
+}
+
+// StationObservations returns the observations of a station, newest first.
+// The station is an ID, ex. KORD, or a station URL as returned by Stations.
+// Buoy and COOP stations are rejected, see ValidateStationID.
+func StationObservations(station string, opts ObservationsOptions) ([]Observation, error) {
+	return stationObservations(context.Background(), station, opts)
+}
+
+// stationObservations is StationObservations with the request made with the
+// context
+func stationObservations(ctx context.Context, station string, opts ObservationsOptions) ([]Observation, error) {
+	if err := ValidateStationID(station); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if !opts.Start.IsZero() {
+		query.Set("start", opts.Start.UTC().Format(time.RFC3339))
+	}
+	if !opts.End.IsZero() {
+		query.Set("end", opts.End.UTC().Format(time.RFC3339))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	endpoint := stationURL(station, "observations").withQuery(query).String()
+	var r struct {
+		Observations []Observation `json:"@graph"`
+	}
+	if err := getDecodedContext(ctx, endpoint, &r); err != nil {
+		return nil, err
+	}
+	if opts.IncludeSpecial {
+		return r.Observations, nil
+	}
+	routine, _ := SplitSpecialObservations(r.Observations)
+	return routine, nil
+}
+
+// StationObservationAt returns the observation of a station made at t, ex. to
+// backfill a missing hour. The station is an ID or a station URL, as for
+// StationObservations. The time must match the observation time exactly,
+// ex. 17:51 for a station observing at 51 minutes past the hour, otherwise
+// weather.gov responds 404 Not Found, see IsNotFound.
+func StationObservationAt(station string, t time.Time) (*Observation, error) {
+	if err := ValidateStationID(station); err != nil {
+		return nil, err
+	}
+	endpoint := stationURL(station, "observations", t.UTC().Format(time.RFC3339)).String()
+	res, err := apiCall(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	return DecodeObservation(res.Body)
+}
+
+// stationURL returns the URL of the path segments under a station ID or
+// station URL
+func stationURL(station string, segments ...string) *requestURL {
+	if strings.Contains(station, "://") {
+		return urlUnder(station, segments...)
+	}
+	return apiURL(append([]string{"stations", station}, segments...)...)
+}
+
+// IsSpecial reports whether the raw message of the observation is a SPECI
+// report. weather.gov usually omits the report type, see
+// SplitSpecialObservations to also detect special observations by time.
+func (o Observation) IsSpecial() bool {
+	return strings.HasPrefix(strings.TrimSpace(o.RawMessage), "SPECI ")
+}
+
+// SplitSpecialObservations splits observations of a station into routine and
+// special observations, keeping their order. Stations make routine
+// observations at the same minute of each hour, ex. 17:51, 18:51, so
+// observations at any other minute are special, as are SPECI reports. The
+// routine minute is the most common minute of the observations.
+func SplitSpecialObservations(observations []Observation) (routine []Observation, special []Observation) {
+	minutes := map[int]int{}
+	for _, o := range observations {
+		if !o.IsSpecial() {
+			minutes[o.Timestamp.UTC().Minute()]++
+		}
+	}
+	routineMinute, most := -1, 0
+	for minute, n := range minutes {
+		if n > most || (n == most && minute < routineMinute) {
+			routineMinute, most = minute, n
+		}
+	}
+	for _, o := range observations {
+		if o.IsSpecial() || o.Timestamp.UTC().Minute() != routineMinute {
+			special = append(special, o)
+		} else {
+			routine = append(routine, o)
+		}
+	}
+	return routine, special
+}
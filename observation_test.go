@@ -0,0 +1,193 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// fakeAPI serves routes, keyed by path, as a fake weather.gov API for the
+// duration of the test. {api} in a response is replaced by the API URL.
+func fakeAPI(t *testing.T, routes map[string]string) *httptest.Server {
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, strings.ReplaceAll(body, "{api}", api.URL))
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	return api
+}
+
+// observation returns an observation JSON document made at t
+func observation(t time.Time, qc string) string {
+	return fmt.Sprintf(`{"timestamp": "%s", "temperature": {"value": 21, "unitCode": "wmoUnit:degC", "qualityControl": "%s"}}`,
+		t.Format(time.RFC3339), qc)
+}
+
+func TestLatestObservationWithFallback(t *testing.T) {
+	now := time.Now()
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685":             `{"observationStations": "{api}/gridpoints/LOT/76,73/stations"}`,
+		"/gridpoints/LOT/76,73/stations":     `{"observationStations": ["{api}/stations/KMDW", "{api}/stations/KORD", "{api}/stations/KLOT", "{api}/stations/KPWK"]}`,
+		"/stations/KMDW/observations/latest": observation(now.Add(-5*time.Hour), "V"),
+		"/stations/KORD/observations/latest": observation(now.Add(-10*time.Minute), "X"),
+		"/stations/KLOT/observations/latest": observation(now.Add(-20*time.Minute), "V"),
+		"/stations/KPWK/observations/latest": observation(now, "V"),
+	})
+
+	result, err := noaa.LatestObservationWithFallback("41.837", "-87.685", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Station[len(result.Station)-4:] != "KLOT" {
+		t.Errorf("noaa.LatestObservationWithFallback() should skip stale and rejected observations, got %s", result.Station)
+	}
+	if result.Age < 19*time.Minute || result.Age > 21*time.Minute {
+		t.Errorf("noaa.LatestObservationWithFallback() should return the observation age, got %s", result.Age)
+	}
+
+	_, err = noaa.LatestObservationWithFallback("41.837", "-87.685", 2)
+	if !errors.Is(err, noaa.ErrNoObservation) {
+		t.Errorf("noaa.LatestObservationWithFallback() should return ErrNoObservation, got %v", err)
+	}
+}
+
+func TestLatestObservationWithFallbackMissing(t *testing.T) {
+	now := time.Now().Format(time.RFC3339)
+	fakeAPI(t, map[string]string{
+		"/points/41.9,-87.7":                 `{"observationStations": "{api}/gridpoints/LOT/75,74/stations"}`,
+		"/gridpoints/LOT/75,74/stations":     `{"observationStations": ["{api}/stations/KMDW", "{api}/stations/KORD"]}`,
+		"/stations/KMDW/observations/latest": `{"timestamp": "` + now + `", "temperature": {"value": null, "unitCode": "wmoUnit:degC", "qualityControl": "Z"}}`,
+		"/stations/KORD/observations/latest": observation(time.Now(), "V"),
+	})
+
+	result, err := noaa.LatestObservationWithFallback("41.9", "-87.7", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if noaa.StationID(result.Station) != "KORD" || result.Observation.Temperature.Value != 21 {
+		t.Errorf("noaa.LatestObservationWithFallback() should skip observations without a temperature, got %s", result.Station)
+	}
+	_, err = noaa.LatestObservationWithFallback("41.9", "-87.7", 1)
+	if !errors.Is(err, noaa.ErrNoObservation) {
+		t.Errorf("noaa.LatestObservationWithFallback() should return ErrNoObservation for a missing temperature, got %v", err)
+	}
+}
+
+func TestStaleObservation(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/stations/KMDW/observations/latest": observation(time.Now().Add(-3*time.Hour), "V"),
+	})
+	station := noaa.GetConfig().BaseURL + "/stations/KMDW"
+
+	o, err := noaa.LatestStationObservation(station)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.IsStale() {
+		t.Error("noaa.Observation.IsStale() should be true for a 3 hour old observation")
+	}
+
+	noaa.SetStaleObservationError(true)
+	o, err = noaa.LatestStationObservation(station)
+	if !errors.Is(err, noaa.ErrStaleObservation) || o.Timestamp.IsZero() {
+		t.Errorf("noaa.LatestStationObservation() should return the observation and ErrStaleObservation, got %v", err)
+	}
+
+	noaa.SetMaxObservationAge(4 * time.Hour)
+	if _, err = noaa.LatestStationObservation(station); err != nil {
+		t.Errorf("noaa.LatestStationObservation() should not fail below the maximum age, got %v", err)
+	}
+}
+
+func TestStationObservations(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/stations/KORD/observations": `{"@graph": [
+			{"timestamp": "2023-07-04T18:51:00+00:00", "rawMessage": "KORD 041851Z 27010KT 10SM FEW250 27/18 A2990"},
+			{"timestamp": "2023-07-04T18:12:00+00:00", "rawMessage": "KORD 041812Z 29025G40KT 1SM +TSRA OVC015CB 21/19 A2995"},
+			{"timestamp": "2023-07-04T17:51:00+00:00", "rawMessage": "KORD 041751Z 27015G25KT 10SM BKN035CB 26/18 A2992"},
+			{"timestamp": "2023-07-04T17:51:00+00:00", "rawMessage": "SPECI KORD 041751Z 27015G25KT 10SM BKN035CB 26/18 A2992"},
+			{"timestamp": "2023-07-04T16:51:00+00:00", "rawMessage": "KORD 041651Z 25008KT 10SM SCT040 25/17 A2993"}
+		]}`,
+	})
+
+	routine, err := noaa.StationObservations("KORD", noaa.ObservationsOptions{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routine) != 3 || routine[0].Timestamp.Hour() != 18 || routine[2].Timestamp.Hour() != 16 {
+		t.Errorf("noaa.StationObservations() should return the routine observations, got %v", routine)
+	}
+
+	all, err := noaa.StationObservations("KORD", noaa.ObservationsOptions{IncludeSpecial: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Errorf("noaa.StationObservations() should include special observations, got %d", len(all))
+	}
+	_, special := noaa.SplitSpecialObservations(all)
+	if len(special) != 2 || special[0].Timestamp.Minute() != 12 || !special[1].IsSpecial() {
+		t.Errorf("noaa.SplitSpecialObservations() should return the off-hour and SPECI observations, got %v", special)
+	}
+}
+
+func TestStationObservationAt(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/stations/KORD/observations/2023-07-04T17:51:00Z": `{"timestamp": "2023-07-04T17:51:00+00:00", "rawMessage": "KORD 041751Z 27015G25KT 10SM BKN035CB 26/18 A2992"}`,
+	})
+
+	at := time.Date(2023, 7, 4, 12, 51, 0, 0, time.FixedZone("CDT", -5*60*60))
+	observation, err := noaa.StationObservationAt("KORD", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !observation.Timestamp.Equal(at) {
+		t.Errorf("noaa.StationObservationAt() should return the observation at the time, got %v", observation.Timestamp)
+	}
+	if _, err := noaa.StationObservationAt("KORD", at.Add(time.Hour)); !noaa.IsNotFound(err) {
+		t.Errorf("noaa.StationObservationAt() should return a not found error without an observation, got %v", err)
+	}
+}
+
+func TestObservationValueNull(t *testing.T) {
+	o, err := noaa.DecodeObservation(strings.NewReader(`{
+		"temperature": {"unitCode": "wmoUnit:degC", "value": null, "qualityControl": "Z"},
+		"dewpoint": {"unitCode": "wmoUnit:degC", "value": 0, "qualityControl": "V"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !o.Temperature.IsMissing() || o.Dewpoint.IsMissing() || !o.WindGust.IsMissing() {
+		t.Errorf("noaa.ObservationValue.IsMissing() should be true for null values and values without a unit, got %+v", o)
+	}
+	data, err := json.Marshal(o.Temperature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v noaa.ObservationValue
+	if err := json.Unmarshal(data, &v); err != nil || !v.Null || v.UnitCode != "wmoUnit:degC" {
+		t.Errorf("noaa.ObservationValue should encode null values as null, got %s", data)
+	}
+}
@@ -0,0 +1,51 @@
+package noaa
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// officeAlertZones is the number of zones requested at once by
+// ActiveAlertsByOffice, keeping the query URLs short
+const officeAlertZones = 50
+
+// ActiveAlertsByOffice returns the active alerts of the forecast zones,
+// counties and fire weather zones an office is responsible for, ex.
+// ActiveAlertsByOffice("LOT"). Alerts covering several of the zones are
+// returned once, in the order they are first returned by weather.gov.
+func ActiveAlertsByOffice(officeID string) ([]Alert, error) {
+	office, err := Office(strings.ToUpper(strings.TrimSpace(officeID)))
+	if err != nil {
+		return nil, err
+	}
+	var zones []string
+	seenZones := map[string]bool{}
+	for _, list := range [][]string{office.ResponsibleForecastZones, office.ResponsibleCounties, office.ResponsibleFireZones} {
+		for _, u := range list {
+			if id := ZoneID(u); id != "" && !seenZones[id] {
+				seenZones[id] = true
+				zones = append(zones, id)
+			}
+		}
+	}
+	alerts := []Alert{}
+	seen := map[string]bool{}
+	for start := 0; start < len(zones); start += officeAlertZones {
+		end := start + officeAlertZones
+		if end > len(zones) {
+			end = len(zones)
+		}
+		zoneAlerts, err := AlertsQuery(url.Values{"zone": {strings.Join(zones[start:end], ",")}}, AlertFilter{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alerts of office %s: %w", office.ID, err)
+		}
+		for _, a := range zoneAlerts {
+			if !seen[a.Identifier] {
+				seen[a.Identifier] = true
+				alerts = append(alerts, a)
+			}
+		}
+	}
+	return alerts, nil
+}
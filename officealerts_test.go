@@ -0,0 +1,36 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestActiveAlertsByOffice(t *testing.T) {
+	// enough zones for two requests, which both return the same alerts
+	var zones []string
+	for i := 1; i <= 60; i++ {
+		zones = append(zones, fmt.Sprintf(`"{api}/zones/forecast/ILZ%03d"`, i))
+	}
+	fakeAPI(t, map[string]string{
+		"/offices/LOT": fmt.Sprintf(`{"id": "LOT", "responsibleForecastZones": [%s], "responsibleCounties": ["{api}/zones/county/ILC031"], "responsibleFireZones": ["{api}/zones/fire/ILZ001"]}`,
+			strings.Join(zones, ", ")),
+		"/alerts/active": `{"@graph": [{"id": "urn:oid:1", "event": "Heat Advisory"}, {"id": "urn:oid:2", "event": "Flood Watch"}]}`,
+	})
+
+	alerts, err := noaa.ActiveAlertsByOffice("lot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 || alerts[0].Event != "Heat Advisory" || alerts[1].Event != "Flood Watch" {
+		t.Errorf("noaa.ActiveAlertsByOffice() should return each alert once, got %+v", alerts)
+	}
+	if _, err := noaa.ActiveAlertsByOffice("XXX"); err == nil {
+		t.Error("noaa.ActiveAlertsByOffice() should return an error for unknown offices")
+	}
+}
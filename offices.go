@@ -0,0 +1,182 @@
+package noaa
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownOffice is matched by the errors returned for office IDs which are
+// not Weather Forecast Offices, see UnknownOfficeError.
+var ErrUnknownOffice = errors.New("unknown office")
+
+// OfficeInfo is a Weather Forecast Office (WFO) of the directory returned by
+// Offices.
+type OfficeInfo struct {
+	ID     string // ex. LOT
+	Name   string // ex. Chicago, IL
+	Region string // NWS region: ER, CR, SR, WR, AR (Alaska) or PR (Pacific)
+}
+
+// offices is the directory of Weather Forecast Offices by ID
+var offices = map[string]OfficeInfo{}
+
+func init() {
+	for _, o := range []OfficeInfo{
+		// Eastern Region
+		{"AKQ", "Wakefield, VA", "ER"}, {"ALY", "Albany, NY", "ER"}, {"BGM", "Binghamton, NY", "ER"},
+		{"BOX", "Boston/Norton, MA", "ER"}, {"BTV", "Burlington, VT", "ER"}, {"BUF", "Buffalo, NY", "ER"},
+		{"CAE", "Columbia, SC", "ER"}, {"CAR", "Caribou, ME", "ER"}, {"CHS", "Charleston, SC", "ER"},
+		{"CLE", "Cleveland, OH", "ER"}, {"CTP", "State College, PA", "ER"}, {"GSP", "Greenville-Spartanburg, SC", "ER"},
+		{"GYX", "Gray/Portland, ME", "ER"}, {"ILM", "Wilmington, NC", "ER"}, {"ILN", "Wilmington, OH", "ER"},
+		{"LWX", "Baltimore/Washington", "ER"}, {"MHX", "Newport/Morehead City, NC", "ER"}, {"OKX", "New York/Upton, NY", "ER"},
+		{"PBZ", "Pittsburgh, PA", "ER"}, {"PHI", "Mount Holly/Philadelphia", "ER"}, {"RAH", "Raleigh, NC", "ER"},
+		{"RLX", "Charleston, WV", "ER"}, {"RNK", "Blacksburg, VA", "ER"},
+		// Central Region
+		{"ABR", "Aberdeen, SD", "CR"}, {"APX", "Gaylord, MI", "CR"}, {"ARX", "La Crosse, WI", "CR"},
+		{"BIS", "Bismarck, ND", "CR"}, {"BOU", "Denver/Boulder, CO", "CR"}, {"CYS", "Cheyenne, WY", "CR"},
+		{"DDC", "Dodge City, KS", "CR"}, {"DLH", "Duluth, MN", "CR"}, {"DMX", "Des Moines, IA", "CR"},
+		{"DTX", "Detroit/Pontiac, MI", "CR"}, {"DVN", "Quad Cities, IA/IL", "CR"}, {"EAX", "Kansas City/Pleasant Hill, MO", "CR"},
+		{"FGF", "Grand Forks, ND", "CR"}, {"FSD", "Sioux Falls, SD", "CR"}, {"GID", "Hastings, NE", "CR"},
+		{"GJT", "Grand Junction, CO", "CR"}, {"GLD", "Goodland, KS", "CR"}, {"GRB", "Green Bay, WI", "CR"},
+		{"GRR", "Grand Rapids, MI", "CR"}, {"ICT", "Wichita, KS", "CR"}, {"ILX", "Lincoln, IL", "CR"},
+		{"IND", "Indianapolis, IN", "CR"}, {"IWX", "Northern Indiana", "CR"}, {"JKL", "Jackson, KY", "CR"},
+		{"LBF", "North Platte, NE", "CR"}, {"LMK", "Louisville, KY", "CR"}, {"LOT", "Chicago, IL", "CR"},
+		{"LSX", "St. Louis, MO", "CR"}, {"MKX", "Milwaukee/Sullivan, WI", "CR"}, {"MPX", "Twin Cities/Chanhassen, MN", "CR"},
+		{"MQT", "Marquette, MI", "CR"}, {"OAX", "Omaha/Valley, NE", "CR"}, {"PAH", "Paducah, KY", "CR"},
+		{"PUB", "Pueblo, CO", "CR"}, {"RIW", "Riverton, WY", "CR"}, {"SGF", "Springfield, MO", "CR"},
+		{"TOP", "Topeka, KS", "CR"}, {"UNR", "Rapid City, SD", "CR"},
+		// Southern Region
+		{"ABQ", "Albuquerque, NM", "SR"}, {"AMA", "Amarillo, TX", "SR"}, {"BMX", "Birmingham, AL", "SR"},
+		{"BRO", "Brownsville, TX", "SR"}, {"CRP", "Corpus Christi, TX", "SR"}, {"EPZ", "El Paso, TX", "SR"},
+		{"EWX", "Austin/San Antonio, TX", "SR"}, {"FFC", "Peachtree City, GA", "SR"}, {"FWD", "Dallas/Fort Worth, TX", "SR"},
+		{"HGX", "Houston/Galveston, TX", "SR"}, {"HUN", "Huntsville, AL", "SR"}, {"JAN", "Jackson, MS", "SR"},
+		{"JAX", "Jacksonville, FL", "SR"}, {"KEY", "Key West, FL", "SR"}, {"LCH", "Lake Charles, LA", "SR"},
+		{"LIX", "New Orleans, LA", "SR"}, {"LUB", "Lubbock, TX", "SR"}, {"LZK", "Little Rock, AR", "SR"},
+		{"MAF", "Midland/Odessa, TX", "SR"}, {"MEG", "Memphis, TN", "SR"}, {"MFL", "Miami, FL", "SR"},
+		{"MLB", "Melbourne, FL", "SR"}, {"MOB", "Mobile, AL", "SR"}, {"MRX", "Morristown, TN", "SR"},
+		{"OHX", "Nashville, TN", "SR"}, {"OUN", "Norman, OK", "SR"}, {"SHV", "Shreveport, LA", "SR"},
+		{"SJT", "San Angelo, TX", "SR"}, {"SJU", "San Juan, PR", "SR"}, {"TAE", "Tallahassee, FL", "SR"},
+		{"TBW", "Tampa Bay, FL", "SR"}, {"TSA", "Tulsa, OK", "SR"},
+		// Western Region
+		{"BOI", "Boise, ID", "WR"}, {"BYZ", "Billings, MT", "WR"}, {"EKA", "Eureka, CA", "WR"},
+		{"FGZ", "Flagstaff, AZ", "WR"}, {"GGW", "Glasgow, MT", "WR"}, {"HNX", "San Joaquin Valley/Hanford, CA", "WR"},
+		{"LKN", "Elko, NV", "WR"}, {"LOX", "Los Angeles/Oxnard, CA", "WR"}, {"MFR", "Medford, OR", "WR"},
+		{"MSO", "Missoula, MT", "WR"}, {"MTR", "San Francisco Bay Area, CA", "WR"}, {"OTX", "Spokane, WA", "WR"},
+		{"PDT", "Pendleton, OR", "WR"}, {"PIH", "Pocatello/Idaho Falls, ID", "WR"}, {"PQR", "Portland, OR", "WR"},
+		{"PSR", "Phoenix, AZ", "WR"}, {"REV", "Reno, NV", "WR"}, {"SEW", "Seattle, WA", "WR"},
+		{"SGX", "San Diego, CA", "WR"}, {"SLC", "Salt Lake City, UT", "WR"}, {"STO", "Sacramento, CA", "WR"},
+		{"TFX", "Great Falls, MT", "WR"}, {"TWC", "Tucson, AZ", "WR"}, {"VEF", "Las Vegas, NV", "WR"},
+		// Alaska and Pacific Regions
+		{"AFC", "Anchorage, AK", "AR"}, {"AFG", "Fairbanks, AK", "AR"}, {"AJK", "Juneau, AK", "AR"},
+		{"GUM", "Guam", "PR"}, {"HFO", "Honolulu, HI", "PR"}, {"PPG", "Pago Pago, AS", "PR"},
+	} {
+		offices[o.ID] = o
+	}
+}
+
+// Offices returns the directory of the Weather Forecast Offices, sorted by
+// ID, ex. to let users pick their office.
+func Offices() []OfficeInfo {
+	list := make([]OfficeInfo, 0, len(offices))
+	for _, o := range offices {
+		list = append(list, o)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// UnknownOfficeError is returned for an office ID which is not a Weather
+// Forecast Office, with the offices it may be a typo of. It matches
+// ErrUnknownOffice with errors.Is and unwraps to the error of the request, if
+// any.
+type UnknownOfficeError struct {
+	ID          string
+	Suggestions []OfficeInfo
+	Err         error
+}
+
+func (e *UnknownOfficeError) Error() string {
+	msg := fmt.Sprintf("%s %q", ErrUnknownOffice, e.ID)
+	if len(e.Suggestions) > 0 {
+		names := make([]string, len(e.Suggestions))
+		for i, o := range e.Suggestions {
+			names[i] = fmt.Sprintf("%s (%s)", o.ID, o.Name)
+		}
+		msg += ", did you mean " + strings.Join(names, " or ") + "?"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *UnknownOfficeError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrUnknownOffice.
+func (e *UnknownOfficeError) Is(target error) bool { return target == ErrUnknownOffice }
+
+// ValidateOfficeID returns nil if the ID, in any case, is a Weather Forecast
+// Office, and an *UnknownOfficeError suggesting the offices within one typo
+// otherwise, ex. LOT for LTO, or for the ICAO form of the ID, ex. KLOT.
+func ValidateOfficeID(id string) error {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	if _, ok := offices[id]; ok {
+		return nil
+	}
+	return &UnknownOfficeError{ID: id, Suggestions: officeSuggestions(id)}
+}
+
+// officeSuggestions returns the offices an unknown ID may be a typo of
+func officeSuggestions(id string) []OfficeInfo {
+	if len(id) == 4 && (id[0] == 'K' || id[0] == 'P' || id[0] == 'T') {
+		if o, ok := offices[id[1:]]; ok {
+			return []OfficeInfo{o}
+		}
+	}
+	var suggestions []OfficeInfo
+	for _, o := range Offices() {
+		if oneTypo(id, o.ID) {
+			suggestions = append(suggestions, o)
+		}
+	}
+	if len(suggestions) > 3 {
+		suggestions = suggestions[:3]
+	}
+	return suggestions
+}
+
+// oneTypo reports whether a differs from b by one substituted, inserted or
+// deleted letter, or two swapped adjacent letters
+func oneTypo(a string, b string) bool {
+	switch len(a) - len(b) {
+	case 0:
+		var diffs []int
+		for i := 0; i < len(a); i++ {
+			if a[i] != b[i] {
+				diffs = append(diffs, i)
+			}
+		}
+		switch len(diffs) {
+		case 1:
+			return true
+		case 2:
+			i, j := diffs[0], diffs[1]
+			return j == i+1 && a[i] == b[j] && a[j] == b[i]
+		}
+		return false
+	case 1:
+		a, b = b, a
+	case -1:
+	default:
+		return false
+	}
+	// b is a with one more letter
+	for i := 0; i < len(b); i++ {
+		if b[:i]+b[i+1:] == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestOffices(t *testing.T) {
+	offices := noaa.Offices()
+	if len(offices) < 120 {
+		t.Fatalf("noaa.Offices() should return every forecast office, got %d", len(offices))
+	}
+	for i := 1; i < len(offices); i++ {
+		if offices[i-1].ID >= offices[i].ID {
+			t.Fatalf("noaa.Offices() should be sorted by ID, got %s before %s", offices[i-1].ID, offices[i].ID)
+		}
+	}
+	offices[0].ID = "changed"
+	if noaa.Offices()[0].ID == "changed" {
+		t.Error("noaa.Offices() should return a copy of the directory")
+	}
+}
+
+func TestValidateOfficeID(t *testing.T) {
+	for _, id := range []string{"LOT", "lot", " afc "} {
+		if err := noaa.ValidateOfficeID(id); err != nil {
+			t.Errorf("noaa.ValidateOfficeID(%q) should accept the office, got %v", id, err)
+		}
+	}
+	for id, want := range map[string]string{
+		"LTO":  "did you mean LOT (Chicago, IL) or STO (Sacramento, CA)?",
+		"KLOT": "did you mean LOT (Chicago, IL)?",
+		"PAFC": "did you mean AFC (Anchorage, AK)?",
+		"QQQQ": `unknown office "QQQQ"`,
+	} {
+		err := noaa.ValidateOfficeID(id)
+		if !errors.Is(err, noaa.ErrUnknownOffice) || !strings.HasSuffix(err.Error(), want) {
+			t.Errorf("noaa.ValidateOfficeID(%q) should return %q, got %v", id, want, err)
+		}
+	}
+}
+
+func TestOfficeUnknown(t *testing.T) {
+	fakeAPI(t, map[string]string{})
+
+	_, err := noaa.Office("LTO")
+	var unknown *noaa.UnknownOfficeError
+	if !errors.As(err, &unknown) || len(unknown.Suggestions) == 0 || unknown.Suggestions[0].ID != "LOT" {
+		t.Fatalf("noaa.Office() should suggest LOT for LTO, got %v", err)
+	}
+	if !noaa.IsNotFound(err) {
+		t.Errorf("noaa.Office() should keep the not found error, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package noaa
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Interval returns the start and end times of the period. They have the UTC
+// offset of the point's timezone, ex. 2023-07-04T18:00:00-05:00.
+func (p ForecastResponsePeriod) Interval() (start time.Time, end time.Time, err error) {
+	start, err = time.Parse(time.RFC3339, p.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("period %d: invalid start time %q", p.ID, p.StartTime)
+	}
+	end, err = time.Parse(time.RFC3339, p.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("period %d: invalid end time %q", p.ID, p.EndTime)
+	}
+	return start, end, nil
+}
+
+// Covers reports whether t is within the period, from its start time
+// included to its end time excluded.
+func (p ForecastResponsePeriod) Covers(t time.Time) bool {
+	start, end, err := p.Interval()
+	return err == nil && !t.Before(start) && t.Before(end)
+}
+
+// At returns the period covering t, or nil if no period does. Period times
+// have the UTC offset of the point, so t can be in any timezone.
+func (f *ForecastResponse) At(t time.Time) *ForecastResponsePeriod {
+	for i := range f.Periods {
+		if f.Periods[i].Covers(t) {
+			return &f.Periods[i]
+		}
+	}
+	return nil
+}
+
+// Current returns the period covering the current time, ex. This Afternoon,
+// or nil if no period does.
+func (f *ForecastResponse) Current() *ForecastResponsePeriod {
+	return f.At(time.Now())
+}
+
+// Tonight returns the first night period which has not ended, ex. Tonight in
+// the afternoon or Overnight after midnight, or nil if there is none.
+func (f *ForecastResponse) Tonight() *ForecastResponsePeriod {
+	now := time.Now()
+	for i, p := range f.Periods {
+		if _, end, err := p.Interval(); err == nil && !p.IsDaytime && now.Before(end) {
+			return &f.Periods[i]
+		}
+	}
+	return nil
+}
+
+// PeriodNamed returns the period with the name, ex. Saturday Night, ignoring
+// case and surrounding spaces, or nil if there is none.
+func (f *ForecastResponse) PeriodNamed(name string) *ForecastResponsePeriod {
+	name = strings.TrimSpace(name)
+	for i := range f.Periods {
+		if strings.EqualFold(f.Periods[i].Name, name) {
+			return &f.Periods[i]
+		}
+	}
+	return nil
+}
+
+// At returns the hour covering t, or nil if no hour does. Period times have
+// the UTC offset of the point, so t can be in any timezone.
+func (f *HourlyForecastResponse) At(t time.Time) *ForecastResponsePeriodHourly {
+	for i := range f.Periods {
+		if f.Periods[i].Covers(t) {
+			return &f.Periods[i]
+		}
+	}
+	return nil
+}
+
+// Current returns the hour covering the current time, or nil if no hour
+// does.
+func (f *HourlyForecastResponse) Current() *ForecastResponsePeriodHourly {
+	return f.At(time.Now())
+}
@@ -0,0 +1,79 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// periodsAround returns a forecast with 12 hour periods, the first starting 6
+// hours ago, with the times of a point in Chicago
+func periodsAround(now time.Time) *noaa.ForecastResponse {
+	chicago := time.FixedZone("CDT", -5*60*60)
+	start := now.Add(-6 * time.Hour).In(chicago).Truncate(time.Hour)
+	f := &noaa.ForecastResponse{}
+	for i, name := range []string{"This Afternoon", "Tonight", "Saturday", "Saturday Night"} {
+		f.Periods = append(f.Periods, noaa.ForecastResponsePeriod{
+			ID:        int32(i + 1),
+			Name:      name,
+			StartTime: start.Add(time.Duration(i) * 12 * time.Hour).Format(time.RFC3339),
+			EndTime:   start.Add(time.Duration(i+1) * 12 * time.Hour).Format(time.RFC3339),
+			IsDaytime: i%2 == 0,
+		})
+	}
+	return f
+}
+
+func TestForecastPeriodLookup(t *testing.T) {
+	now := time.Now()
+	f := periodsAround(now)
+	if p := f.Current(); p == nil || p.Name != "This Afternoon" {
+		t.Errorf("noaa.ForecastResponse.Current() should return the period covering now, got %+v", p)
+	}
+	if p := f.Tonight(); p == nil || p.Name != "Tonight" {
+		t.Errorf("noaa.ForecastResponse.Tonight() should return the next night period, got %+v", p)
+	}
+	if p := f.At(now.UTC().Add(20 * time.Hour)); p == nil || p.Name != "Saturday" {
+		t.Errorf("noaa.ForecastResponse.At() should return the period covering a UTC time, got %+v", p)
+	}
+	if p := f.At(now.Add(-7 * time.Hour)); p != nil {
+		t.Errorf("noaa.ForecastResponse.At() should return nil before the first period, got %+v", p)
+	}
+	if p := f.PeriodNamed(" saturday night "); p == nil || p.ID != 4 {
+		t.Errorf("noaa.ForecastResponse.PeriodNamed() should ignore case and spaces, got %+v", p)
+	}
+	if p := f.PeriodNamed("Sunday"); p != nil {
+		t.Errorf("noaa.ForecastResponse.PeriodNamed() should return nil for unknown names, got %+v", p)
+	}
+	if p := f.PeriodNamed("Tonight"); p != nil {
+		p.Summary = "Clear"
+		if f.Periods[1].Summary != "Clear" {
+			t.Error("noaa.ForecastResponse.PeriodNamed() should return a pointer to the period")
+		}
+	}
+}
+
+func TestHourlyForecastAt(t *testing.T) {
+	now := time.Now().Truncate(time.Hour)
+	f := &noaa.HourlyForecastResponse{}
+	for i := 0; i < 3; i++ {
+		p := noaa.ForecastResponsePeriodHourly{}
+		p.ID = int32(i + 1)
+		p.StartTime = now.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		p.EndTime = now.Add(time.Duration(i+1) * time.Hour).Format(time.RFC3339)
+		f.Periods = append(f.Periods, p)
+	}
+	if p := f.Current(); p == nil || p.ID != 1 {
+		t.Errorf("noaa.HourlyForecastResponse.Current() should return the current hour, got %+v", p)
+	}
+	if p := f.At(now.Add(150 * time.Minute)); p == nil || p.ID != 3 {
+		t.Errorf("noaa.HourlyForecastResponse.At() should return the hour covering the time, got %+v", p)
+	}
+	if p := f.At(now.Add(3 * time.Hour)); p != nil {
+		t.Errorf("noaa.HourlyForecastResponse.At() should exclude the end of the last hour, got %+v", p)
+	}
+}
@@ -0,0 +1,379 @@
+package noaa
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Location is a named point, ex. home, which is polled by a Poller or
+// registered in a LocationRegistry.
+type Location struct {
+	Name   string
+	Lat    string
+	Lon    string
+	Units  string      // forecast units, "us" or "si", blank for the configured units
+	Alerts AlertFilter // alerts to keep, the zero filter keeps all alerts
+}
+
+// Poller periodically fetches forecasts, observations and alerts for a set of
+// locations and passes the results to its handlers. Alerts are deduplicated
+// with an AlertTracker per location so OnAlert is only called when an alert
+// event is issued or changes. Handlers are called from the Run goroutine.
+//
+// Results are also published on Bus, or DefaultBus if it is nil, as
+// ForecastUpdated, ObservationReceived and AlertIssued events.
+type Poller struct {
+	Locations []Location
+	Bus       *Bus
+
+	// Intervals between polls of each type of data. A zero interval disables
+	// polling of that type. Note, forecasts are updated and most stations
+	// report observations about once an hour, so polling those more often
+	// than every few minutes is not useful.
+	ForecastInterval    time.Duration
+	ObservationInterval time.Duration
+	AlertInterval       time.Duration
+
+	// FastAlertInterval replaces AlertInterval while an Extreme or Immediate
+	// alert, ex. a snow squall or tornado warning, is active for any of the
+	// locations, so updates to short-fused warnings are received sooner. The
+	// poller returns to AlertInterval once those alerts end. Zero disables fast
+	// polling. Note, every location is polled at this interval, so keep it
+	// well above the weather.gov rate limit, ex. 30 seconds.
+	FastAlertInterval time.Duration
+
+	// Schedules replace ForecastInterval and ObservationInterval when set, so
+	// forecasts and observations are polled when they are usually updated,
+	// ex. &ForecastSchedule and &ObservationSchedule. When a poll returns no
+	// new forecast or observation for any location, it is retried with an
+	// exponential delay, see Schedule. Either way, forecasts are polled again
+	// as soon as the validTimes of one of them ends, see
+	// ForecastResponse.FreshUntil.
+	ForecastSchedule    *Schedule
+	ObservationSchedule *Schedule
+
+	// AlertStore returns the store of the alert events of a location, so
+	// events already reported are not reported again after a restart, see
+	// NewPersistentAlertTracker. Each location needs its own store, ex.
+	//
+	//	p.AlertStore = func(loc noaa.Location) (noaa.AlertStore, error) {
+	//		return noaa.NewFileAlertStore(filepath.Join(dir, loc.Name+".json")), nil
+	//	}
+	//
+	// Alerts are tracked in memory if it is nil or fails. Errors of the
+	// stores are passed to OnError.
+	AlertStore func(Location) (AlertStore, error)
+
+	// Watermarks stores the time of the last observation and of the last
+	// alert poll of each location. With Backfill, Run first reports the
+	// observations of the nearest station and the alert history of each
+	// location since its watermarks, up to MaxBackfill (DefaultMaxBackfill
+	// if 0) ago, so archives and alert trackers have no gap after downtime.
+	// Backfilled data is passed to the handlers like polled data, so OnAlert
+	// may receive events which already ended, and published with Backfill
+	// set. Use an AlertStore too so alerts reported before the downtime are
+	// not reported again.
+	Watermarks  WatermarkStore
+	Backfill    bool
+	MaxBackfill time.Duration
+
+	OnForecast    func(Location, *ForecastResponse)
+	OnObservation func(Location, Observation)
+	OnAlert       func(Location, AlertEvent)
+	OnError       func(Location, error)
+
+	trackers     map[string]*AlertTracker
+	forecasts    map[string]string    // update time of the last forecast by location
+	freshUntil   map[string]time.Time // end of the validTimes of the last forecast by location
+	observations map[string]time.Time // time of the last observation by location
+	errors       int                  // errors reported since Run was called
+	life         lifecycle
+}
+
+// Start runs the poller in a new goroutine. It implements Component.
+func (p *Poller) Start(ctx context.Context) error {
+	return p.life.start(ctx, p.Run)
+}
+
+// Stop stops the poller, waiting up to timeout for a poll in progress to
+// finish. It implements Component.
+func (p *Poller) Stop(timeout time.Duration) error {
+	return p.life.stop(timeout)
+}
+
+// Health returns the state of the poller and the last error of its polls,
+// which is kept until every type of data was polled again without errors for
+// all locations. It implements Component.
+func (p *Poller) Health() Health {
+	return p.life.status()
+}
+
+// Run polls until the context is cancelled and then returns the context error.
+// Each type of data is fetched for all locations as soon as Run is called.
+// Requests in progress are cancelled with the context. The locations must have
+// distinct names, which key their alerts and watermarks, otherwise Run fails
+// without polling.
+func (p *Poller) Run(ctx context.Context) error {
+	names := map[string]bool{}
+	for _, loc := range p.Locations {
+		switch {
+		case loc.Name == "":
+			return fmt.Errorf("poller location %s,%s has no name", loc.Lat, loc.Lon)
+		case names[loc.Name]:
+			return fmt.Errorf("poller location %q is duplicated", loc.Name)
+		}
+		names[loc.Name] = true
+	}
+	p.trackers = map[string]*AlertTracker{}
+	for _, loc := range p.Locations {
+		p.trackers[loc.Name] = p.newTracker(loc)
+	}
+	p.forecasts = map[string]string{}
+	p.freshUntil = map[string]time.Time{}
+	p.observations = map[string]time.Time{}
+	if p.Backfill && p.Watermarks != nil {
+		for _, loc := range p.Locations {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			p.backfill(ctx, loc)
+		}
+	}
+	type task struct {
+		interval func() time.Duration
+		schedule *Schedule
+		poll     func(context.Context, Location) bool
+		until    func() time.Time // when the data polled expires, zero if never
+		next     time.Time
+		attempt  int  // polls in a row without new data
+		failed   bool // whether the last poll failed for any location
+	}
+	fixed := func(d time.Duration) func() time.Duration {
+		return func() time.Duration { return d }
+	}
+	tasks := []*task{
+		{interval: fixed(p.ForecastInterval), schedule: p.ForecastSchedule, poll: p.pollForecast, until: p.forecastsExpire},
+		{interval: fixed(p.ObservationInterval), schedule: p.ObservationSchedule, poll: p.pollObservation},
+		{interval: p.alertInterval, poll: p.pollAlerts},
+	}
+	for {
+		now := time.Now()
+		var wait time.Duration = -1
+		for _, t := range tasks {
+			if t.schedule == nil && t.interval() <= 0 {
+				continue
+			}
+			if !now.Before(t.next) {
+				updated := false
+				reported := p.errors
+				for _, loc := range p.Locations {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					if t.poll(ctx, loc) {
+						updated = true
+					}
+				}
+				// the error of another location or type of data is kept
+				// until its next poll succeeds too
+				t.failed = p.errors != reported
+				failed := false
+				for _, other := range tasks {
+					failed = failed || other.failed
+				}
+				if !failed {
+					p.life.report(nil)
+				}
+				switch {
+				case t.schedule == nil:
+					t.next = now.Add(t.interval())
+				case updated:
+					t.attempt = 0
+					t.next = t.schedule.Next(time.Now())
+				default:
+					t.next = t.schedule.Retry(time.Now(), t.attempt)
+					t.attempt++
+				}
+				if t.until != nil {
+					if until := t.until(); until.After(now) && until.Before(t.next) {
+						t.next = until
+					}
+				}
+			}
+			d := time.Until(t.next)
+			if d < 0 {
+				d = 0 // the polls took longer than the interval
+			}
+			if wait < 0 || d < wait {
+				wait = d
+			}
+		}
+		if wait < 0 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pollForecast fetches the forecast for the location and reports whether it
+// was updated since the last poll
+func (p *Poller) pollForecast(ctx context.Context, loc Location) bool {
+	forecast, err := fetchForecast(ctx, loc.Lat, loc.Lon, loc.Units)
+	if err != nil {
+		p.requestError(ctx, loc, err)
+		return false
+	}
+	updated := forecast.Updated == "" || forecast.Updated != p.forecasts[loc.Name]
+	p.forecasts[loc.Name] = forecast.Updated
+	p.freshUntil[loc.Name] = forecast.FreshUntil()
+	p.bus().Publish(ForecastUpdated{Location: loc, Forecast: forecast})
+	if p.OnForecast != nil {
+		p.OnForecast(loc, forecast)
+	}
+	return updated
+}
+
+// forecastsExpire returns the earliest end of the validTimes of the last
+// forecasts, so forecasts are polled again when they no longer cover the
+// current time without waiting for the interval or schedule. Forecasts which
+// were already expired when fetched are polled at the usual times.
+func (p *Poller) forecastsExpire() time.Time {
+	var earliest time.Time
+	for _, until := range p.freshUntil {
+		if !until.IsZero() && (earliest.IsZero() || until.Before(earliest)) {
+			earliest = until
+		}
+	}
+	return earliest
+}
+
+// pollObservation fetches the latest observation from the nearest station and
+// reports whether it is newer than the last observation
+func (p *Poller) pollObservation(ctx context.Context, loc Location) bool {
+	stations, err := StationsContext(ctx, loc.Lat, loc.Lon)
+	if err != nil {
+		p.requestError(ctx, loc, err)
+		return false
+	}
+	if len(stations.Stations) == 0 {
+		return false
+	}
+	observation, err := fetchLatestStationObservation(ctx, stations.Stations[0])
+	if err != nil {
+		p.requestError(ctx, loc, err)
+		return false
+	}
+	updated := observation.Timestamp.After(p.observations[loc.Name])
+	if updated {
+		p.observations[loc.Name] = observation.Timestamp
+		key, _ := watermarkKeys(loc)
+		p.setWatermark(loc, key, observation.Timestamp)
+	}
+	p.bus().Publish(ObservationReceived{Location: loc, Observation: observation})
+	if p.OnObservation != nil {
+		p.OnObservation(loc, observation)
+	}
+	return updated
+}
+
+// pollAlerts fetches the active alerts and reports the changed events. It
+// reports whether any event changed.
+func (p *Poller) pollAlerts(ctx context.Context, loc Location) bool {
+	polled := time.Now()
+	alerts, err := loc.alerts(ctx)
+	if err != nil {
+		p.requestError(ctx, loc, err)
+		return false
+	}
+	tracker := p.trackers[loc.Name]
+	events := tracker.Update(alerts...)
+	if err := tracker.Err(); err != nil {
+		p.error(loc, err)
+	}
+	_, key := watermarkKeys(loc)
+	p.setWatermark(loc, key, polled)
+	for _, event := range events {
+		p.bus().Publish(AlertIssued{Location: loc, Event: event})
+		if p.OnAlert != nil {
+			p.OnAlert(loc, event)
+		}
+	}
+	return len(events) > 0
+}
+
+// newTracker returns the alert tracker of the location, restored from its
+// store if any
+func (p *Poller) newTracker(loc Location) *AlertTracker {
+	if p.AlertStore == nil {
+		return NewAlertTracker()
+	}
+	store, err := p.AlertStore(loc)
+	if err == nil {
+		var tracker *AlertTracker
+		if tracker, err = NewPersistentAlertTracker(store); err == nil {
+			return tracker
+		}
+	}
+	p.error(loc, err)
+	return NewAlertTracker()
+}
+
+// alertInterval returns FastAlertInterval while an urgent alert is active and
+// otherwise AlertInterval
+func (p *Poller) alertInterval() time.Duration {
+	if p.AlertInterval <= 0 || p.FastAlertInterval <= 0 {
+		return p.AlertInterval
+	}
+	now := time.Now()
+	for _, tracker := range p.trackers {
+		for _, event := range tracker.Active(now) {
+			if IsUrgentAlert(event.Current) {
+				return p.FastAlertInterval
+			}
+		}
+	}
+	return p.AlertInterval
+}
+
+// IsUrgentAlert reports whether the alert has Extreme severity or Immediate
+// urgency, ex. tornado, snow squall and extreme wind warnings.
+func IsUrgentAlert(a Alert) bool {
+	severity, _ := ParseSeverity(a.Severity)
+	urgency, _ := ParseUrgency(a.Urgency)
+	return severity == SeverityExtreme || urgency == UrgencyImmediate
+}
+
+// error records the error in the poller health and reports it to the OnError
+// handler, if any
+func (p *Poller) error(loc Location, err error) {
+	p.errors++
+	p.life.report(fmt.Errorf("%s: %w", loc.Name, err))
+	if p.OnError != nil {
+		p.OnError(loc, err)
+	}
+}
+
+// requestError reports the error of a request unless the context is done, so
+// the requests cancelled by Stop are not reported
+func (p *Poller) requestError(ctx context.Context, loc Location, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	p.error(loc, err)
+}
+
+// bus returns the bus events are published on
+func (p *Poller) bus() *Bus {
+	if p.Bus != nil {
+		return p.Bus
+	}
+	return DefaultBus
+}
@@ -0,0 +1,231 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// alertsAPI serves an active alert with the given severity and urgency, counts
+// the alert requests and calls cancel after the third request
+func alertsAPI(t *testing.T, severity, urgency string, cancel func()) *int32 {
+	var requests int32
+	expires := time.Now().Add(time.Hour).Format(time.RFC3339)
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 3 {
+			cancel()
+		}
+		fmt.Fprintf(w, `{"@graph": [{"id": "urn:oid:1", "messageType": "Alert", "sent": "2023-01-15T10:00:00-06:00", "expires": "%s", "severity": "%s", "urgency": "%s", "event": "Snow Squall Warning"}]}`,
+			expires, severity, urgency)
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	return &requests
+}
+
+func TestPollerFastAlertInterval(t *testing.T) {
+	for _, tc := range []struct {
+		severity, urgency string
+		fast              bool
+	}{
+		{"Severe", "Immediate", true},
+		{"Extreme", "Expected", true},
+		{"Moderate", "Expected", false},
+	} {
+		timeout := 300 * time.Millisecond
+		if tc.fast {
+			timeout = 5 * time.Second // cancelled after the third request
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		requests := alertsAPI(t, tc.severity, tc.urgency, cancel)
+		p := &noaa.Poller{
+			Locations:         []noaa.Location{{Name: "home", Lat: "41.837", Lon: "-87.685"}},
+			Bus:               noaa.NewBus(),
+			AlertInterval:     time.Hour,
+			FastAlertInterval: 10 * time.Millisecond,
+		}
+		p.Run(ctx)
+		cancel()
+		n := atomic.LoadInt32(requests)
+		if tc.fast && n < 3 {
+			t.Errorf("noaa.Poller should poll %s/%s alerts at FastAlertInterval, got %d requests", tc.severity, tc.urgency, n)
+		}
+		if !tc.fast && n != 1 {
+			t.Errorf("noaa.Poller should poll %s/%s alerts at AlertInterval, got %d requests", tc.severity, tc.urgency, n)
+		}
+	}
+}
+
+func TestIsUrgentAlert(t *testing.T) {
+	if !noaa.IsUrgentAlert(noaa.Alert{Severity: "Extreme"}) || !noaa.IsUrgentAlert(noaa.Alert{Urgency: "Immediate"}) {
+		t.Error("noaa.IsUrgentAlert() should report Extreme and Immediate alerts")
+	}
+	if noaa.IsUrgentAlert(noaa.Alert{Severity: "Severe", Urgency: "Expected"}) {
+		t.Error("noaa.IsUrgentAlert() should not report Severe/Expected alerts")
+	}
+}
+
+func TestPollerRefetchesExpiredForecasts(t *testing.T) {
+	var requests int32
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/points/40.2,-88.3" {
+			fmt.Fprintf(w, `{"forecast": "%s/forecast"}`, api.URL)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		now := time.Now()
+		fmt.Fprintf(w, `{"updated": "%s", "validTimes": "%s/%s", "periods": [{"number": 1}]}`,
+			now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano), now.Add(100*time.Millisecond).Format(time.RFC3339Nano))
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 350*time.Millisecond)
+	defer cancel()
+	p := &noaa.Poller{
+		Locations:        []noaa.Location{{Name: "home", Lat: "40.2", Lon: "-88.3"}},
+		Bus:              noaa.NewBus(),
+		ForecastInterval: time.Hour,
+	}
+	p.Run(ctx)
+	if n := atomic.LoadInt32(&requests); n < 2 {
+		t.Errorf("noaa.Poller should poll forecasts again when their validTimes end, got %d requests", n)
+	}
+}
+
+func TestPollerHealth(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("point") == "41.9,-87.6" {
+			http.Error(w, `{"title": "Bad Request"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"@graph": []}`)
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	p := &noaa.Poller{
+		Locations:     []noaa.Location{{Name: "work", Lat: "41.9", Lon: "-87.6"}, {Name: "home", Lat: "41.837", Lon: "-87.685"}},
+		Bus:           noaa.NewBus(),
+		AlertInterval: 10 * time.Millisecond,
+	}
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := p.Stop(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if h := p.Health(); h.Err == nil {
+		t.Error("noaa.Poller.Health() should keep the error of a location when the next location succeeds")
+	}
+}
+
+func TestPollerStopCancelsPoll(t *testing.T) {
+	cancelled := make(chan struct{})
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	p := &noaa.Poller{
+		Locations:        []noaa.Location{{Name: "home", Lat: "40.3", Lon: "-88.3"}},
+		Bus:              noaa.NewBus(),
+		ForecastInterval: time.Hour,
+	}
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := p.Stop(time.Second); err != nil {
+		t.Errorf("noaa.Poller.Stop() should cancel the poll in progress, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("noaa.Poller.Stop() should cancel the request in progress")
+	}
+}
+
+func TestPollerRun(t *testing.T) {
+	requests := alertsAPI(t, "Moderate", "Expected", func() {})
+	var alerts int
+	p := &noaa.Poller{
+		Locations:     []noaa.Location{{Name: "home", Lat: "41.837", Lon: "-87.685"}},
+		Bus:           noaa.NewBus(),
+		AlertInterval: 10 * time.Millisecond,
+		OnAlert:       func(noaa.Location, noaa.AlertEvent) { alerts++ },
+	}
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(requests) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := p.Stop(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(requests); n < 3 {
+		t.Fatalf("noaa.Poller should poll alerts every AlertInterval, got %d requests", n)
+	}
+	if alerts != 1 {
+		t.Errorf("noaa.Poller should report an alert polled repeatedly once, got %d", alerts)
+	}
+	if h := p.Health(); h.State != noaa.StateStopped || h.Err != nil {
+		t.Errorf("noaa.Poller.Health() should be stopped without errors, got %v %v", h.State, h.Err)
+	}
+
+	for _, locations := range [][]noaa.Location{
+		{{Name: "home", Lat: "41.837", Lon: "-87.685"}, {Name: "home", Lat: "41.9", Lon: "-87.6"}},
+		{{Lat: "41.837", Lon: "-87.685"}},
+	} {
+		p := &noaa.Poller{Locations: locations, Bus: noaa.NewBus(), AlertInterval: time.Hour}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := p.Run(ctx)
+		cancel()
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("noaa.Poller.Run() should reject locations without distinct names, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package noaa
+
+import (
+	"time"
+)
+
+// PrecipitationType is the type of precipitation expected at the ground, see
+// GridpointForecastResponse.PrecipitationTimeline. Types are ordered by the
+// hazard they pose to travel.
+type PrecipitationType int
+
+const (
+	PrecipitationNone PrecipitationType = iota
+	PrecipitationRain
+	PrecipitationRainAndSnow
+	PrecipitationSnow
+	PrecipitationSleet
+	PrecipitationFreezingRain
+)
+
+var precipitationTypeNames = []string{"none", "rain", "rain_and_snow", "snow", "sleet", "freezing_rain"}
+
+// ParsePrecipitationType parses a precipitation type, ex. freezing_rain.
+func ParsePrecipitationType(s string) (PrecipitationType, error) {
+	i, err := parseCode("precipitation type", precipitationTypeNames, s)
+	return PrecipitationType(i), err
+}
+
+func (p PrecipitationType) String() string { return codeName(precipitationTypeNames, int(p)) }
+
+// weatherPrecipitation maps the weather of the gridpoint Weather layer to the
+// precipitation types they produce
+var weatherPrecipitation = map[string]PrecipitationType{
+	"rain":             PrecipitationRain,
+	"rain_showers":     PrecipitationRain,
+	"drizzle":          PrecipitationRain,
+	"thunderstorms":    PrecipitationRain,
+	"snow":             PrecipitationSnow,
+	"snow_showers":     PrecipitationSnow,
+	"sleet":            PrecipitationSleet,
+	"hail":             PrecipitationSleet,
+	"freezing_rain":    PrecipitationFreezingRain,
+	"freezing_drizzle": PrecipitationFreezingRain,
+}
+
+// snowLevelMargin is how far in meters the snow level must be from the
+// elevation of the grid cell to turn a rain and snow mix into rain or snow
+const snowLevelMargin = 150.0
+
+// PrecipitationPeriod is a period of a precipitation timeline.
+type PrecipitationPeriod struct {
+	Start     time.Time
+	End       time.Time
+	Type      PrecipitationType
+	Intensity WeatherIntensity // highest intensity of the precipitation
+	Coverage  WeatherCoverage  // coverage of the precipitation, ex. chance
+}
+
+// PrecipitationTypes returns the precipitation timeline of the gridpoint
+// forecast of <lat,lon>, see GridpointForecastResponse.PrecipitationTimeline.
+func PrecipitationTypes(lat string, lon string) ([]PrecipitationPeriod, error) {
+	forecast, err := GridpointForecast(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	return forecast.PrecipitationTimeline(), nil
+}
+
+// PrecipitationTimeline returns the periods of the forecast with precipitation
+// and its type, merging consecutive periods of the same type, intensity and
+// coverage. The type is taken from the Weather layer, which forecasters set
+// with the temperature profile of the whole atmosphere in mind, rather than
+// guessed from the surface temperature: above freezing at the ground is often
+// still snow, and below freezing can be rain freezing on contact. The
+// temperature and snow level series refine it where the weather is vague:
+//
+//   - a rain and snow mix is snow when the snow level is 150 m or more below
+//     the elevation of the grid cell, and rain when it is 150 m or more above
+//   - thunderstorms without other precipitation are snow at or below freezing
+//
+// When several types are forecast at once the most hazardous wins, ex.
+// freezing rain over sleet over snow.
+func (g *GridpointForecastResponse) PrecipitationTimeline() []PrecipitationPeriod {
+	elevation, hasElevation := g.Elevation.Value, g.Elevation.Units != ""
+	if UnitName(g.Elevation.Units) == "ft" {
+		elevation *= 0.3048
+	}
+
+	var timeline []PrecipitationPeriod
+	for _, v := range g.Weather.Values {
+		start, d, err := ParseValidTime(v.ValidTime)
+		if err != nil {
+			continue
+		}
+		p := PrecipitationPeriod{Start: start, End: start.Add(d)}
+		rain, snow, thunder := false, false, false
+		for _, item := range v.Value {
+			ptype, ok := weatherPrecipitation[item.Weather]
+			if !ok {
+				continue
+			}
+			switch item.Weather {
+			case "thunderstorms":
+				thunder = true
+			case "rain", "rain_showers", "drizzle":
+				rain = true
+			case "snow", "snow_showers":
+				snow = true
+			}
+			if ptype > p.Type {
+				p.Type = ptype
+			}
+			if intensity, _ := ParseWeatherIntensity(item.Intensity); intensity > p.Intensity {
+				p.Intensity = intensity
+			}
+			if p.Coverage == WeatherCoverageUnknown {
+				p.Coverage, _ = ParseWeatherCoverage(item.Coverage)
+			}
+		}
+		switch {
+		case p.Type == PrecipitationNone:
+			continue
+		case rain && snow && p.Type == PrecipitationSnow:
+			p.Type = PrecipitationRainAndSnow
+			if level, ok := g.SnowLevel.At(start); ok && hasElevation {
+				if UnitName(g.SnowLevel.Uom) == "ft" {
+					level *= 0.3048
+				}
+				if level <= elevation-snowLevelMargin {
+					p.Type = PrecipitationSnow
+				} else if level >= elevation+snowLevelMargin {
+					p.Type = PrecipitationRain
+				}
+			}
+		case thunder && !rain && p.Type == PrecipitationRain:
+			if t, ok := g.Temperature.At(start); ok {
+				if c, _ := LocaleSI.Convert(t, g.Temperature.Uom); c <= 0 {
+					p.Type = PrecipitationSnow
+				}
+			}
+		}
+
+		if n := len(timeline); n > 0 {
+			last := &timeline[n-1]
+			if last.End.Equal(p.Start) && last.Type == p.Type && last.Intensity == p.Intensity && last.Coverage == p.Coverage {
+				last.End = p.End
+				continue
+			}
+		}
+		timeline = append(timeline, p)
+	}
+	return timeline
+}
@@ -0,0 +1,61 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestPrecipitationTimeline(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/45,-93": `{"forecastGridData": "{api}/gridpoints/MPX/100,70"}`,
+		"/gridpoints/MPX/100,70": `{
+			"elevation": {"unitCode": "wmoUnit:m", "value": 300},
+			"temperature": {"uom": "wmoUnit:degC", "values": [
+				{"validTime": "2023-12-01T00:00:00+00:00/PT12H", "value": 1},
+				{"validTime": "2023-12-01T12:00:00+00:00/PT12H", "value": -2}
+			]},
+			"snowLevel": {"uom": "wmoUnit:m", "values": [
+				{"validTime": "2023-12-01T00:00:00+00:00/PT6H", "value": 600},
+				{"validTime": "2023-12-01T06:00:00+00:00/PT6H", "value": 300},
+				{"validTime": "2023-12-01T12:00:00+00:00/PT12H", "value": 0}
+			]},
+			"weather": {"values": [
+				{"validTime": "2023-12-01T00:00:00+00:00/PT3H", "value": [{"coverage": "chance", "weather": "rain", "intensity": "light"}, {"coverage": "chance", "weather": "snow", "intensity": "light"}]},
+				{"validTime": "2023-12-01T03:00:00+00:00/PT3H", "value": [{"coverage": null, "weather": null, "intensity": null}]},
+				{"validTime": "2023-12-01T06:00:00+00:00/PT3H", "value": [{"coverage": "likely", "weather": "rain", "intensity": "light"}, {"coverage": "likely", "weather": "snow", "intensity": "moderate"}]},
+				{"validTime": "2023-12-01T09:00:00+00:00/PT3H", "value": [{"coverage": "likely", "weather": "sleet", "intensity": "light"}, {"coverage": "likely", "weather": "freezing_rain", "intensity": "light"}]},
+				{"validTime": "2023-12-01T12:00:00+00:00/PT3H", "value": [{"coverage": "definite", "weather": "snow", "intensity": "heavy"}]},
+				{"validTime": "2023-12-01T15:00:00+00:00/PT3H", "value": [{"coverage": "definite", "weather": "snow", "intensity": "heavy"}, {"coverage": "definite", "weather": "fog"}]},
+				{"validTime": "2023-12-01T18:00:00+00:00/PT1H", "value": [{"coverage": "isolated", "weather": "thunderstorms"}]}
+			]}
+		}`,
+	})
+
+	timeline, err := noaa.PrecipitationTypes("45", "-93")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range timeline {
+		got = append(got, p.Start.Format("15")+"-"+p.End.Format("15")+" "+p.Type.String()+" "+p.Intensity.String()+" "+p.Coverage.String())
+	}
+	want := []string{
+		"00-03 rain light chance", // the snow level is well above the grid cell
+		"06-09 rain_and_snow moderate likely",
+		"09-12 freezing_rain light likely",
+		"12-18 snow heavy definite",
+		"18-19 snow unknown isolated", // thundersnow
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("noaa.PrecipitationTypes() should return\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if p, err := noaa.ParsePrecipitationType("Freezing_Rain"); err != nil || p != noaa.PrecipitationFreezingRain {
+		t.Errorf("noaa.ParsePrecipitationType() should parse freezing_rain, got %v, %v", p, err)
+	}
+}
@@ -0,0 +1,131 @@
+package noaa
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxProductPages limits how many pages of a product list are followed
+const maxProductPages = 50
+
+// Product holds the JSON values of a text product, ex. a Hazardous Weather
+// Outlook (HWO) or Area Forecast Discussion (AFD). Text and Language are only
+// set by GetProduct and LatestProduct, product lists do not include them.
+type Product struct {
+	URI             string    `json:"@id"`
+	ID              string    `json:"id"`
+	WMOCollectiveID string    `json:"wmoCollectiveId"`
+	IssuingOffice   string    `json:"issuingOffice"`
+	IssuanceTime    time.Time `json:"issuanceTime"`
+	Code            string    `json:"productCode"`
+	Name            string    `json:"productName"`
+	Text            string    `json:"productText"`
+	Language        string    `json:"language,omitempty"` // en, or es for Spanish products
+}
+
+// ProductType is a type of text product, ex. HWO Hazardous Weather Outlook.
+type ProductType struct {
+	Code string `json:"productCode"`
+	Name string `json:"productName"`
+}
+
+// ProductsByOfficeAndType returns the recent products of a type issued by an
+// office, newest first, ex. ProductsByOfficeAndType("LOT", "HWO"). All pages
+// of the list are returned.
+func ProductsByOfficeAndType(office string, typeCode string) ([]Product, error) {
+	endpoint := apiURL("products", "types", typeCode, "locations", office).String()
+	var products []Product
+	for page := 0; endpoint != "" && page < maxProductPages; page++ {
+		var r struct {
+			Products   []Product `json:"@graph"`
+			Pagination struct {
+				Next string `json:"next"`
+			} `json:"pagination"`
+		}
+		if err := getDecoded(endpoint, &r); err != nil {
+			return nil, err
+		}
+		products = append(products, r.Products...)
+		if len(r.Products) == 0 || r.Pagination.Next == endpoint {
+			break
+		}
+		endpoint = r.Pagination.Next
+	}
+	return products, nil
+}
+
+// ProductTypesByOffice returns the types of products issued by an office.
+func ProductTypesByOffice(office string) ([]ProductType, error) {
+	endpoint := apiURL("products", "locations", office, "types").String()
+	var r struct {
+		Types []ProductType `json:"@graph"`
+	}
+	if err := getDecoded(endpoint, &r); err != nil {
+		return nil, err
+	}
+	return r.Types, nil
+}
+
+// GetProduct returns a product including its text.
+func GetProduct(id string) (*Product, error) {
+	var product Product
+	if err := getDecoded(apiURL("products", id).String(), &product); err != nil {
+		return nil, err
+	}
+	product.Language = productLanguage(product.Text)
+	return &product, nil
+}
+
+// LatestProduct returns the newest product of a type issued by an office,
+// including its text, ex. LatestProduct("LOT", "HWO"). When a language other
+// than English is preferred, see SetLanguage, the newest product in that
+// language among the few newest is returned, and the newest product
+// otherwise, so offices issuing only English products still answer.
+func LatestProduct(office string, typeCode string) (*Product, error) {
+	endpoint := apiURL("products", "types", typeCode, "locations", office).String()
+	var r struct {
+		Products []Product `json:"@graph"`
+	}
+	if err := getDecoded(endpoint, &r); err != nil {
+		return nil, err
+	}
+	if len(r.Products) == 0 {
+		return nil, fmt.Errorf("no %s products from %s", typeCode, office)
+	}
+	sort.SliceStable(r.Products, func(i, j int) bool { return r.Products[i].IssuanceTime.After(r.Products[j].IssuanceTime) })
+	lang := preferredLanguage()
+	if lang == "" {
+		return GetProduct(r.Products[0].ID)
+	}
+	var newest *Product
+	for i := 0; i < len(r.Products) && i < maxLanguageProducts; i++ {
+		p, err := GetProduct(r.Products[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		if sameLanguage(p.Language, lang) {
+			return p, nil
+		}
+		if newest == nil {
+			newest = p
+		}
+	}
+	return newest, nil
+}
+
+// getDecoded calls the endpoint and decodes the JSON response into v
+func getDecoded(endpoint string, v interface{}) error {
+	return getDecodedContext(context.Background(), endpoint, v)
+}
+
+// getDecodedContext is getDecoded with the request made with the context
+func getDecodedContext(ctx context.Context, endpoint string, v interface{}) error {
+	res, err := apiCallContext(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return decodeJSON(res.Body, v)
+}
@@ -0,0 +1,79 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestProducts(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/products/types/HWO/locations/LOT": `{"@graph": [
+			{"id": "b1", "issuingOffice": "KLOT", "issuanceTime": "2023-07-04T08:30:00+00:00", "productCode": "HWO", "productName": "Hazardous Weather Outlook"},
+			{"id": "a2", "issuingOffice": "KLOT", "issuanceTime": "2023-07-04T20:15:00+00:00", "productCode": "HWO", "productName": "Hazardous Weather Outlook"}
+		], "pagination": {"next": "{api}/products/types/HWO/locations/LOT/page2"}}`,
+		"/products/types/HWO/locations/LOT/page2": `{"@graph": [
+			{"id": "c3", "issuingOffice": "KLOT", "issuanceTime": "2023-07-03T08:30:00+00:00", "productCode": "HWO", "productName": "Hazardous Weather Outlook"}
+		]}`,
+		"/products/locations/LOT/types": `{"@graph": [{"productCode": "AFD", "productName": "Area Forecast Discussion"}, {"productCode": "HWO", "productName": "Hazardous Weather Outlook"}]}`,
+		"/products/a2":                  `{"id": "a2", "productCode": "HWO", "productText": "HAZARDOUS WEATHER OUTLOOK"}`,
+	})
+
+	products, err := noaa.ProductsByOfficeAndType("LOT", "HWO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != 3 || products[2].ID != "c3" {
+		t.Errorf("noaa.ProductsByOfficeAndType() should follow pagination, got %+v", products)
+	}
+
+	types, err := noaa.ProductTypesByOffice("LOT")
+	if err != nil || len(types) != 2 || types[1].Code != "HWO" {
+		t.Errorf("noaa.ProductTypesByOffice() returned %+v, %v", types, err)
+	}
+
+	latest, err := noaa.LatestProduct("LOT", "HWO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.ID != "a2" || !strings.HasPrefix(latest.Text, "HAZARDOUS WEATHER OUTLOOK") {
+		t.Errorf("noaa.LatestProduct() should return the newest product with its text, got %+v", latest)
+	}
+}
+
+func TestLatestProductLanguage(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/products/types/AFD/locations/SJU": `{"@graph": [
+			{"id": "en2", "issuingOffice": "TJSJ", "issuanceTime": "2023-07-04T20:15:00+00:00", "productCode": "AFD"},
+			{"id": "es1", "issuingOffice": "TJSJ", "issuanceTime": "2023-07-04T20:10:00+00:00", "productCode": "AFD"},
+			{"id": "en1", "issuingOffice": "TJSJ", "issuanceTime": "2023-07-04T08:30:00+00:00", "productCode": "AFD"}
+		]}`,
+		"/products/types/AFD/locations/LOT": `{"@graph": [{"id": "lot", "issuingOffice": "KLOT", "issuanceTime": "2023-07-04T20:15:00+00:00", "productCode": "AFD"}]}`,
+		"/products/en2":                     `{"id": "en2", "productText": "\n000\nFXCA62 TJSJ 042015\nAFDSJU\n\nArea Forecast Discussion"}`,
+		"/products/es1":                     `{"id": "es1", "productText": "\n000\nFXCA72 TJSJ 042010\nAFDSPN\n\nDiscusion de Pronostico del Area"}`,
+		"/products/lot":                     `{"id": "lot", "productText": "\n000\nFXUS63 KLOT 042015\nAFDLOT\n\nArea Forecast Discussion"}`,
+	})
+
+	latest, err := noaa.LatestProduct("SJU", "AFD")
+	if err != nil || latest.ID != "en2" || latest.Language != "en" {
+		t.Errorf("noaa.LatestProduct() should return the newest product in English by default, got %+v, %v", latest, err)
+	}
+	noaa.SetLanguage("es-US")
+	if latest, err = noaa.LatestProduct("SJU", "AFD"); err != nil || latest.ID != "es1" || latest.Language != "es" {
+		t.Errorf("noaa.LatestProduct() should prefer the Spanish product, got %+v, %v", latest, err)
+	}
+	if latest, err = noaa.LatestProduct("LOT", "AFD"); err != nil || latest.ID != "lot" || latest.Language != "en" {
+		t.Errorf("noaa.LatestProduct() should fall back to English, got %+v, %v", latest, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("noaa.SetLanguage() should panic on an invalid language")
+		}
+	}()
+	noaa.SetLanguage("español")
+}
@@ -0,0 +1,207 @@
+// Package render produces plain text output of forecast data for terminals,
+// such as tables and Unicode sparkline charts of hourly forecasts. It is pure
+// Go and does not depend on any external programs.
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// sparks are the Unicode block characters used by Sparkline, lowest first
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Options control how hourly forecasts are rendered.
+type Options struct {
+	Hours   int         // number of hourly periods to render, 0 renders all of them
+	Locale  noaa.Locale // units, compass points and summaries used for values
+	Clock24 bool        // use 24-hour times (15:00) instead of 12-hour times (3 PM)
+}
+
+// Sparkline returns a Unicode sparkline with one character per value scaled
+// between the minimum and maximum values. NaN and infinite values, ex. missing
+// data, are skipped and rendered as a space.
+func Sparkline(values []float64) string {
+	min, max, ok := valueRange(values)
+	if !ok {
+		return strings.Repeat(" ", len(values))
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if !finite(v) {
+			b.WriteRune(' ')
+			continue
+		}
+		i := 0
+		if max > min {
+			i = int(math.Round((v - min) / (max - min) * float64(len(sparks)-1)))
+		}
+		b.WriteRune(sparks[i])
+	}
+	return b.String()
+}
+
+// Table is a simple text table with left aligned columns.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Render writes the table to w with columns padded to the widest cell.
+func (t Table) Render(w io.Writer) error {
+	widths := make([]int, len(t.Header))
+	for _, row := range append([][]string{t.Header}, t.Rows...) {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	writeRow := func(row []string) error {
+		var b strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			}
+		}
+		b.WriteString("\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	if len(t.Header) > 0 {
+		if err := writeRow(t.Header); err != nil {
+			return err
+		}
+		rule := make([]string, len(t.Header))
+		for i := range rule {
+			rule[i] = strings.Repeat("-", widths[i])
+		}
+		if err := writeRow(rule); err != nil {
+			return err
+		}
+	}
+	for _, row := range t.Rows {
+		if err := writeRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HourlyTable returns a table of the hourly forecast with the time,
+// temperature, chance of precipitation, wind and short forecast.
+func HourlyTable(forecast *noaa.HourlyForecastResponse, opts Options) Table {
+	table := Table{Header: []string{"Time", "Temp", "Precip", "Wind", "Forecast"}}
+	for _, p := range periods(forecast, opts) {
+		table.Rows = append(table.Rows, []string{
+			formatTime(p.StartTime, opts.Clock24),
+			opts.Locale.PeriodTemperature(p.ForecastResponsePeriod),
+			fmt.Sprintf("%.0f%%", p.ProbabilityOfPrecipitation.Value),
+			opts.Locale.PeriodWind(p.ForecastResponsePeriod),
+			opts.Locale.Translate(p.Summary),
+		})
+	}
+	return table
+}
+
+// HourlySparklines writes sparklines of the hourly temperature, chance of
+// precipitation and wind speed to w, each labelled with its range.
+func HourlySparklines(w io.Writer, forecast *noaa.HourlyForecastResponse, opts Options) error {
+	var temps, pops, winds []float64
+	// the units of the locale, unless the forecast has other units
+	_, tempUnit := opts.Locale.Convert(0, "F")
+	_, windUnit := opts.Locale.Convert(0, "mph")
+	for _, p := range periods(forecast, opts) {
+		temp, unit := opts.Locale.Convert(p.Temperature, p.TemperatureUnit)
+		temps = append(temps, temp)
+		if unit != "" {
+			tempUnit = unit
+		}
+		pops = append(pops, p.ProbabilityOfPrecipitation.Value)
+		speed, unit, _ := noaa.ParseWindSpeed(p.WindSpeed)
+		speed, unit = opts.Locale.Convert(speed, unit)
+		winds = append(winds, speed)
+		if unit != "" {
+			windUnit = unit
+		}
+	}
+	if len(temps) == 0 {
+		return nil
+	}
+	lines := []struct {
+		label  string
+		values []float64
+		unit   string
+	}{
+		{"Temp", temps, tempUnit},
+		{"Precip", pops, "%"},
+		{"Wind", winds, " " + windUnit},
+	}
+	for _, line := range lines {
+		min, max, _ := valueRange(line.values)
+		_, err := fmt.Fprintf(w, "%-6s %s  %.0f-%.0f%s\n", line.label, Sparkline(line.values), min, max, line.unit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// periods returns the periods of the forecast limited by opts.Hours
+func periods(forecast *noaa.HourlyForecastResponse, opts Options) []noaa.ForecastResponsePeriodHourly {
+	if forecast == nil {
+		return nil
+	}
+	if opts.Hours > 0 && opts.Hours < len(forecast.Periods) {
+		return forecast.Periods[:opts.Hours]
+	}
+	return forecast.Periods
+}
+
+// formatTime formats an RFC 3339 timestamp keeping the offset of the forecast
+func formatTime(s string, clock24 bool) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	if clock24 {
+		return t.Format("Mon 15:04")
+	}
+	return t.Format("Mon 3 PM")
+}
+
+// valueRange returns the minimum and maximum of the finite values, ok is false
+// if there are none
+func valueRange(values []float64) (min float64, max float64, ok bool) {
+	for _, v := range values {
+		if !finite(v) {
+			continue
+		}
+		if !ok || v < min {
+			min = v
+		}
+		if !ok || v > max {
+			max = v
+		}
+		ok = true
+	}
+	return min, max, ok
+}
+
+// finite reports whether v is neither NaN nor infinite
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
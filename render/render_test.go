@@ -0,0 +1,68 @@
+package render_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/render"
+)
+
+func TestSparkline(t *testing.T) {
+	if s := render.Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}); s != "▁▂▃▄▅▆▇█" {
+		t.Errorf("unexpected sparkline: %s", s)
+	}
+	if s := render.Sparkline([]float64{5, 5}); s != "▁▁" {
+		t.Errorf("a flat series should render at the lowest level: %s", s)
+	}
+}
+
+func TestHourly(t *testing.T) {
+	forecast := &noaa.HourlyForecastResponse{}
+	for i, temp := range []float64{50, 55, 60} {
+		p := noaa.ForecastResponsePeriodHourly{}
+		p.StartTime = "2023-06-15T1" + string(rune('3'+i)) + ":00:00-05:00"
+		p.Temperature = temp
+		p.TemperatureUnit = "F"
+		p.WindSpeed = "10 mph"
+		p.WindDirection = "S"
+		p.Summary = "Sunny"
+		forecast.Periods = append(forecast.Periods, p)
+	}
+
+	var buf bytes.Buffer
+	if err := render.HourlyTable(forecast, render.Options{Hours: 2}).Render(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "Thu 1 PM  50°F") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := render.HourlySparklines(&buf, forecast, render.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "Temp   ▁▅█  50-60°F\n") {
+		t.Errorf("unexpected sparklines:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := render.HourlySparklines(&buf, forecast, render.Options{Locale: noaa.LocaleSI}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "Temp   ▁▅█  10-16°C\n") || !strings.Contains(buf.String(), "16-16 km/h") {
+		t.Errorf("unexpected metric sparklines:\n%s", buf.String())
+	}
+}
+
+func TestSparklineNotFinite(t *testing.T) {
+	if s := render.Sparkline([]float64{0, math.NaN(), 7, math.Inf(1)}); s != "▁ █ " {
+		t.Errorf("non-finite values should be skipped: %q", s)
+	}
+	if s := render.Sparkline([]float64{math.NaN()}); s != " " {
+		t.Errorf("a series without finite values should be blank: %q", s)
+	}
+}
@@ -0,0 +1,74 @@
+package noaa
+
+import (
+	"net/url"
+	"strings"
+)
+
+// requestURL builds the URL of a request from a base URL, path segments and
+// query parameters. Segments are escaped and the query encoded, so station,
+// zone or product IDs containing /, ?, # or .. cannot change which endpoint is
+// requested or add parameters to it.
+type requestURL struct {
+	base     string
+	segments []string
+	query    url.Values
+}
+
+// apiURL returns the URL of the path segments under the configured base URL,
+// ex. apiURL("zones", "forecast", "ILZ014")
+func apiURL(segments ...string) *requestURL {
+	return urlUnder(currentConfig().BaseURL, segments...)
+}
+
+// urlUnder returns the URL of the path segments under a URL, ex. a station
+// URL returned by the API
+func urlUnder(base string, segments ...string) *requestURL {
+	return &requestURL{base: base, segments: segments}
+}
+
+// withQuery adds the parameters to the query of the URL
+func (r *requestURL) withQuery(query url.Values) *requestURL {
+	if r.query == nil {
+		r.query = url.Values{}
+	}
+	for key, values := range query {
+		r.query[key] = append(r.query[key], values...)
+	}
+	return r
+}
+
+// String returns the URL. Parameters are added to those of the base URL.
+func (r *requestURL) String() string {
+	u, err := url.Parse(r.base)
+	if err != nil {
+		return r.base // the request fails with the parse error
+	}
+	if len(r.segments) > 0 {
+		path := strings.TrimSuffix(u.EscapedPath(), "/")
+		for _, segment := range r.segments {
+			path += "/" + escapeSegment(segment)
+		}
+		if u.Path, err = url.PathUnescape(path); err == nil {
+			u.RawPath = path
+		}
+	}
+	if len(r.query) > 0 {
+		query := u.Query()
+		for key, values := range r.query {
+			query[key] = append(query[key], values...)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// escapeSegment escapes a path segment. Commas, which weather.gov uses to
+// separate coordinates, ex. /points/41.837,-87.685, are kept, while . and ..
+// segments are escaped so they are not resolved as relative paths.
+func escapeSegment(segment string) string {
+	if strings.Trim(segment, ".") == "" && segment != "" {
+		return strings.Repeat("%2E", len(segment))
+	}
+	return strings.ReplaceAll(url.PathEscape(segment), "%2C", ",")
+}
@@ -0,0 +1,52 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestRequestURLEscaping(t *testing.T) {
+	var requested []string
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		http.NotFound(w, r)
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	noaa.GetProduct("../offices/LOT?x=1")
+	noaa.Office("LOT#fragment")
+	noaa.AlertsForZone("ILZ014/../..")
+	noaa.GetZone(noaa.ZoneTypeForecast, "..")
+	noaa.StationObservationAt("KORD", time.Date(2023, 7, 4, 17, 51, 0, 0, time.UTC))
+	noaa.Points("41.837", "-87.685&x=1")
+	want := []string{
+		"/products/..%2Foffices%2FLOT%3Fx=1",
+		"/offices/LOT%23fragment",
+		"/alerts/active/zone/ILZ014%2F..%2F..",
+		"/zones/forecast/%2E%2E",
+		"/stations/KORD/observations/2023-07-04T17:51:00Z",
+		"/points/41.837,-87.685&x=1",
+	}
+	if len(requested) != len(want) {
+		t.Fatalf("each request should reach the server, got %q", requested)
+	}
+	for i, uri := range requested {
+		if uri != want[i] {
+			t.Errorf("request %d should be %s, got %s", i, want[i], uri)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package noaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultRouteSpeed is the speed used by RouteForecast when no speed is
+// given, in km/h (about 55 mph).
+const DefaultRouteSpeed = 88.0
+
+// routeSegmentLength is the maximum length of a RouteSegment in meters,
+// about the distance covered in half an hour at highway speeds
+const routeSegmentLength = 50000.0
+
+// RouteSegment is a part of a route, see RouteForecast. Period is the hourly
+// forecast period at the middle of the segment at the time it is passed. Err
+// is set if the forecast could not be fetched, ex. for points outside the
+// forecast grids or times beyond the hourly forecast.
+type RouteSegment struct {
+	From     Coordinates
+	To       Coordinates
+	Distance float64   // length in meters
+	Start    time.Time // time at From
+	End      time.Time // time at To
+	Period   *ForecastResponsePeriod
+	Err      error
+}
+
+// RouteForecast samples the hourly forecasts along a route at the times each
+// part of it is passed, ex. for a road trip. The route goes straight between
+// the waypoints and is split into segments of at most 50 km. speeds are the
+// average speeds between consecutive waypoints in km/h; when fewer speeds
+// than legs are given the last speed is used for the remaining legs, and
+// DefaultRouteSpeed if none are given. Forecasts are fetched like
+// ForecastBatch, points in the same grid cell sharing requests. An error is
+// only returned for invalid arguments, see the Err of each segment.
+func RouteForecast(waypoints []Coordinates, departure time.Time, speeds ...float64) ([]RouteSegment, error) {
+	if len(waypoints) < 2 {
+		return nil, errors.New("a route needs at least 2 waypoints")
+	}
+	for _, speed := range speeds {
+		if !(speed > 0) || math.IsInf(speed, 1) {
+			return nil, fmt.Errorf("invalid route speed %v", speed)
+		}
+	}
+	var segments []RouteSegment
+	at := departure
+	for i := 0; i+1 < len(waypoints); i++ {
+		speed := DefaultRouteSpeed
+		if len(speeds) > 0 {
+			speed = speeds[len(speeds)-1]
+			if i < len(speeds) {
+				speed = speeds[i]
+			}
+		}
+		from, to := waypoints[i], waypoints[i+1]
+		n := int(math.Ceil(from.DistanceTo(to) / routeSegmentLength))
+		if n < 1 {
+			n = 1
+		}
+		for j := 0; j < n; j++ {
+			s := RouteSegment{
+				From:  interpolate(from, to, float64(j)/float64(n)),
+				To:    interpolate(from, to, float64(j+1)/float64(n)),
+				Start: at,
+			}
+			s.Distance = s.From.DistanceTo(s.To)
+			at = at.Add(time.Duration(s.Distance / (speed * 1000) * float64(time.Hour)))
+			s.End = at
+			segments = append(segments, s)
+		}
+	}
+
+	locations := make([]Location, len(segments))
+	for i, s := range segments {
+		lat, lon := interpolate(s.From, s.To, 0.5).Strings()
+		locations[i] = Location{Name: lat + "," + lon, Lat: lat, Lon: lon}
+	}
+	results := batch(context.Background(), locations, 0, func(loc Location) (*HourlyForecastResponse, error) {
+		return HourlyForecast(loc.Lat, loc.Lon)
+	})
+	for i, r := range results {
+		s := &segments[i]
+		if r.Err != nil {
+			s.Err = r.Err
+			continue
+		}
+		mid := s.Start.Add(s.End.Sub(s.Start) / 2)
+		hour := r.Value.At(mid)
+		if hour == nil {
+			s.Err = fmt.Errorf("no hourly forecast for %s at %s", locations[i].Name, mid.Format(time.RFC3339))
+			continue
+		}
+		s.Period = &hour.ForecastResponsePeriod
+	}
+	return segments, nil
+}
+
+// interpolate returns the point at fraction f of the straight line from a to
+// b in degrees, which is close enough to the great circle for route segments
+func interpolate(a, b Coordinates, f float64) Coordinates {
+	return Coordinates{Lat: a.Lat + (b.Lat-a.Lat)*f, Lon: a.Lon + (b.Lon-a.Lon)*f}
+}
@@ -0,0 +1,61 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestRouteForecast(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.8000,-87.9000": `{"forecastHourly": "{api}/gridpoints/LOT/60,70/forecast/hourly"}`,
+		"/points/41.8000,-88.5000": `{"forecastHourly": "{api}/gridpoints/LOT/40,70/forecast/hourly"}`,
+		"/gridpoints/LOT/60,70/forecast/hourly": `{"periods": [
+			{"number": 1, "startTime": "2023-07-04T10:00:00-05:00", "endTime": "2023-07-04T11:00:00-05:00", "temperature": 80, "shortForecast": "Sunny"}
+		]}`,
+		"/gridpoints/LOT/40,70/forecast/hourly": `{"periods": [
+			{"number": 1, "startTime": "2023-07-04T10:00:00-05:00", "endTime": "2023-07-04T11:00:00-05:00", "temperature": 78, "shortForecast": "Showers"},
+			{"number": 2, "startTime": "2023-07-04T11:00:00-05:00", "endTime": "2023-07-04T12:00:00-05:00", "temperature": 79, "shortForecast": "Thunderstorms"}
+		]}`,
+	})
+
+	route := []noaa.Coordinates{{Lat: 41.8, Lon: -87.6}, {Lat: 41.8, Lon: -88.8}}
+	departure := time.Date(2023, 7, 4, 15, 0, 0, 0, time.UTC)
+	segments, err := noaa.RouteForecast(route, departure, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("noaa.RouteForecast() should split the route into segments of at most 50 km, got %d", len(segments))
+	}
+	first, second := segments[0], segments[1]
+	if first.Err != nil || first.Period == nil || first.Period.Summary != "Sunny" {
+		t.Errorf("noaa.RouteForecast() should return the forecast of the first segment, got %+v", first)
+	}
+	if second.Err != nil || second.Period == nil || second.Period.Summary != "Showers" {
+		t.Errorf("noaa.RouteForecast() should return the forecast of the second segment, got %+v", second)
+	}
+	if d := second.End.Sub(departure); d < 59*time.Minute || d > 61*time.Minute {
+		t.Errorf("noaa.RouteForecast() should time the segments with the speed, arriving after %s", d)
+	}
+
+	// at 50 km/h the second segment is passed during the next hour
+	segments, err = noaa.RouteForecast(route, departure, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segments[0].Period == nil || segments[1].Period == nil || segments[1].Period.Summary != "Thunderstorms" {
+		t.Errorf("noaa.RouteForecast() should use the period at the time each segment is passed, got %+v", segments)
+	}
+
+	if _, err := noaa.RouteForecast(route[:1], departure); err == nil {
+		t.Error("noaa.RouteForecast() should reject routes with a single waypoint")
+	}
+	if _, err := noaa.RouteForecast(route, departure, 0); err == nil {
+		t.Error("noaa.RouteForecast() should reject speeds which are not positive")
+	}
+}
@@ -0,0 +1,200 @@
+package noaa
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertEventCode holds the event codes of an alert, ex. SAME TOR and
+// NationalWeatherService TOW for a Tornado Warning.
+type AlertEventCode struct {
+	SAME                   []string `json:"SAME"`
+	NationalWeatherService []string `json:"NationalWeatherService"`
+}
+
+// AlertGeocode holds the areas of an alert as SAME location codes, ex. 017031
+// for Cook County, IL, and UGC zone and county codes, ex. ILZ014.
+type AlertGeocode struct {
+	SAME []string `json:"SAME"`
+	UGC  []string `json:"UGC"`
+}
+
+// sameEvents maps SAME event codes to the event names used by weather.gov.
+// Codes of non-weather events relayed by NOAA Weather Radio are included so
+// they can be bridged too.
+var sameEvents = map[string]string{
+	"ADR": "Administrative Message",
+	"AVA": "Avalanche Watch",
+	"AVW": "Avalanche Warning",
+	"BZW": "Blizzard Warning",
+	"CAE": "Child Abduction Emergency",
+	"CDW": "Civil Danger Warning",
+	"CEM": "Civil Emergency Message",
+	"CFA": "Coastal Flood Watch",
+	"CFW": "Coastal Flood Warning",
+	"DMO": "Practice/Demo Warning",
+	"DSW": "Dust Storm Warning",
+	"EAN": "Emergency Action Notification",
+	"EQW": "Earthquake Warning",
+	"EVI": "Evacuation Immediate",
+	"EWW": "Extreme Wind Warning",
+	"FFA": "Flash Flood Watch",
+	"FFS": "Flash Flood Statement",
+	"FFW": "Flash Flood Warning",
+	"FLA": "Flood Watch",
+	"FLS": "Flood Statement",
+	"FLW": "Flood Warning",
+	"FRW": "Fire Warning",
+	"HLS": "Hurricane Local Statement",
+	"HMW": "Hazardous Materials Warning",
+	"HUA": "Hurricane Watch",
+	"HUW": "Hurricane Warning",
+	"HWA": "High Wind Watch",
+	"HWW": "High Wind Warning",
+	"LAE": "Local Area Emergency",
+	"LEW": "Law Enforcement Warning",
+	"NPT": "National Periodic Test",
+	"NUW": "Nuclear Power Plant Warning",
+	"RHW": "Radiological Hazard Warning",
+	"RMT": "Required Monthly Test",
+	"RWT": "Required Weekly Test",
+	"SMW": "Special Marine Warning",
+	"SPS": "Special Weather Statement",
+	"SPW": "Shelter In Place Warning",
+	"SQW": "Snow Squall Warning",
+	"SSA": "Storm Surge Watch",
+	"SSW": "Storm Surge Warning",
+	"SVA": "Severe Thunderstorm Watch",
+	"SVR": "Severe Thunderstorm Warning",
+	"SVS": "Severe Weather Statement",
+	"TOA": "Tornado Watch",
+	"TOE": "911 Telephone Outage Emergency",
+	"TOR": "Tornado Warning",
+	"TRA": "Tropical Storm Watch",
+	"TRW": "Tropical Storm Warning",
+	"TSA": "Tsunami Watch",
+	"TSW": "Tsunami Warning",
+	"VOW": "Volcano Warning",
+	"WSA": "Winter Storm Watch",
+	"WSW": "Winter Storm Warning",
+}
+
+// sameCodes maps lowercase event names to SAME event codes
+var sameCodes = func() map[string]string {
+	codes := make(map[string]string, len(sameEvents))
+	for code, event := range sameEvents {
+		codes[strings.ToLower(event)] = code
+	}
+	return codes
+}()
+
+// SAMECode returns the SAME event code of an event name, ex. TOR for Tornado
+// Warning. Names are compared ignoring case. Most events, ex. advisories, have
+// no SAME code and are not broadcast.
+func SAMECode(event string) (code string, ok bool) {
+	code, ok = sameCodes[strings.ToLower(strings.TrimSpace(event))]
+	return code, ok
+}
+
+// SAMEEvent returns the event name of a SAME event code, ex. Tornado Warning
+// for TOR.
+func SAMEEvent(code string) (event string, ok bool) {
+	event, ok = sameEvents[strings.ToUpper(strings.TrimSpace(code))]
+	return event, ok
+}
+
+// SAMECode returns the SAME event code of the alert, from its event codes or
+// else from its event name, or a blank string if it has none.
+func (a Alert) SAMECode() string {
+	if len(a.EventCode.SAME) > 0 && a.EventCode.SAME[0] != "" {
+		return a.EventCode.SAME[0]
+	}
+	code, _ := SAMECode(a.Event)
+	return code
+}
+
+// EASMessage holds the values of the SAME header used by the Emergency Alert
+// System to broadcast an alert, see Alert.EAS.
+type EASMessage struct {
+	Originator string        // WXR for the National Weather Service
+	EventCode  string        // ex. TOR
+	Locations  []string      // SAME location codes, ex. 017031
+	Purge      time.Duration // valid time of the message, see Alert.EAS
+	Issued     time.Time
+	Station    string // ex. KLOT/NWS
+}
+
+// maxEASLocations and maxEASPurge are the limits of a SAME header
+const (
+	maxEASLocations = 31
+	maxEASPurge     = 99*time.Hour + 30*time.Minute
+)
+
+// EAS returns the EAS metadata of the alert. The originator is the EAS-ORG
+// parameter, WXR by default, the station is the VTEC office followed by /NWS,
+// and the purge time is the time from Sent to Expires, rounded up to 15 minute
+// increments up to an hour and 30 minute increments beyond, as required by
+// SAME. An error is returned for alerts without a SAME event code or
+// location.
+func (a Alert) EAS() (EASMessage, error) {
+	m := EASMessage{
+		Originator: "WXR",
+		EventCode:  a.SAMECode(),
+		Locations:  a.Geocode.SAME,
+		Station:    "NWS",
+	}
+	if m.EventCode == "" {
+		return m, fmt.Errorf("no SAME event code for %q", a.Event)
+	}
+	if len(m.Locations) == 0 {
+		return m, fmt.Errorf("no SAME locations for %s", m.EventCode)
+	}
+	if org := a.Parameters["EAS-ORG"]; len(org) > 0 && org[0] != "" {
+		m.Originator = org[0]
+	}
+	if vtecs := a.VTEC(); len(vtecs) > 0 && vtecs[0].Office != "" {
+		m.Station = vtecs[0].Office + "/NWS"
+	}
+	sent, err := time.Parse(time.RFC3339, a.Sent)
+	if err != nil {
+		return m, fmt.Errorf("invalid sent time %q", a.Sent)
+	}
+	m.Issued = sent.UTC()
+	if expires, err := time.Parse(time.RFC3339, a.Expires); err == nil {
+		m.Purge = easPurge(expires.Sub(sent))
+	}
+	return m, nil
+}
+
+// easPurge rounds a valid time up to a SAME purge time
+func easPurge(d time.Duration) time.Duration {
+	step := 15 * time.Minute
+	if d > time.Hour {
+		step = 30 * time.Minute
+	}
+	if d <= 0 {
+		return step
+	}
+	d = (d + step - 1) / step * step
+	if d > maxEASPurge {
+		return maxEASPurge
+	}
+	return d
+}
+
+// Header returns the SAME header of the message, ex.
+// ZCZC-WXR-TOR-017031-017043+0045-1851530-KLOT/NWS-, without the preamble.
+// Only the first 31 locations are included, the maximum of a header.
+func (m EASMessage) Header() string {
+	locations := m.Locations
+	if len(locations) > maxEASLocations {
+		locations = locations[:maxEASLocations]
+	}
+	purge := m.Purge.Round(time.Minute)
+	issued := m.Issued.UTC()
+	return fmt.Sprintf("ZCZC-%s-%s-%s+%02d%02d-%03d%02d%02d-%s-",
+		m.Originator, m.EventCode, strings.Join(locations, "-"),
+		int(purge.Hours()), int(purge.Minutes())%60,
+		issued.YearDay(), issued.Hour(), issued.Minute(), m.Station)
+}
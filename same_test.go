@@ -0,0 +1,61 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestSAMECodes(t *testing.T) {
+	if code, ok := noaa.SAMECode("tornado warning"); !ok || code != "TOR" {
+		t.Errorf("noaa.SAMECode() should map Tornado Warning to TOR, got %q", code)
+	}
+	if event, ok := noaa.SAMEEvent("ffw"); !ok || event != "Flash Flood Warning" {
+		t.Errorf("noaa.SAMEEvent() should map FFW to Flash Flood Warning, got %q", event)
+	}
+	if _, ok := noaa.SAMECode("Heat Advisory"); ok {
+		t.Error("noaa.SAMECode() should not map events without a SAME code")
+	}
+	alert := noaa.Alert{Event: "Severe Weather Statement", EventCode: noaa.AlertEventCode{SAME: []string{"SVS"}}}
+	if alert.SAMECode() != "SVS" || (noaa.Alert{Event: "Blizzard Warning"}).SAMECode() != "BZW" {
+		t.Error("noaa.Alert.SAMECode() should use the event code or the event name")
+	}
+}
+
+func TestAlertEAS(t *testing.T) {
+	var alert noaa.Alert
+	err := json.Unmarshal([]byte(`{
+		"sent": "2023-07-04T15:30:00-00:00",
+		"expires": "2023-07-04T16:15:00-00:00",
+		"event": "Tornado Warning",
+		"geocode": {"SAME": ["017031", "017043"], "UGC": ["ILC031", "ILC043"]},
+		"eventCode": {"SAME": ["TOR"], "NationalWeatherService": ["TOW"]},
+		"parameters": {"EAS-ORG": ["WXR"], "VTEC": ["/O.NEW.KLOT.TO.W.0042.230704T1530Z-230704T1615Z/"]}
+	}`), &alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := alert.EAS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Purge != 45*time.Minute || m.Station != "KLOT/NWS" || m.EventCode != "TOR" {
+		t.Errorf("noaa.Alert.EAS() should return the EAS metadata, got %+v", m)
+	}
+	if header := m.Header(); header != "ZCZC-WXR-TOR-017031-017043+0045-1851530-KLOT/NWS-" {
+		t.Errorf("noaa.EASMessage.Header() should return the SAME header, got %q", header)
+	}
+
+	alert.Expires = "2023-07-04T17:40:00-00:00"
+	if m, _ := alert.EAS(); m.Purge != 2*time.Hour+30*time.Minute {
+		t.Errorf("noaa.Alert.EAS() should round the purge time to 30 minutes beyond an hour, got %s", m.Purge)
+	}
+	if _, err := (noaa.Alert{Event: "Heat Advisory", Sent: alert.Sent}).EAS(); err == nil {
+		t.Error("noaa.Alert.EAS() should return an error for alerts without a SAME code")
+	}
+}
@@ -0,0 +1,69 @@
+package noaa
+
+import "time"
+
+// Schedule is a polling schedule aligned to the update cycle of an NWS
+// product. Products are polled shortly after they are usually updated and, if
+// they were not updated yet, polled again after RetryDelay, doubling the delay
+// on each retry until the next cycle. This avoids polls between updates, which
+// only return data which was already fetched.
+type Schedule struct {
+	// Offset is the time after the start of each period at which the product
+	// is usually available, ex. 45 minutes past the hour.
+	Offset time.Duration
+
+	// Period is the time between updates, defaults to an hour.
+	Period time.Duration
+
+	// RetryDelay is the delay before polling again when the product was not
+	// updated. Zero waits for the next cycle.
+	RetryDelay time.Duration
+}
+
+var (
+	// ForecastSchedule polls forecasts, which are usually updated around 45
+	// minutes past the hour.
+	ForecastSchedule = Schedule{Offset: 45 * time.Minute, RetryDelay: 2 * time.Minute}
+
+	// ObservationSchedule polls observations. Most stations make their routine
+	// observation at about 53 minutes past the hour and it is available on
+	// weather.gov a few minutes later.
+	ObservationSchedule = Schedule{Offset: 55 * time.Minute, RetryDelay: 2 * time.Minute}
+)
+
+// period returns the period of the schedule
+func (s Schedule) period() time.Duration {
+	if s.Period <= 0 {
+		return time.Hour
+	}
+	return s.Period
+}
+
+// Next returns the first time after now at which the product is usually
+// updated, ex. 13:45 at 13:10 and 14:45 at 13:50 for ForecastSchedule.
+func (s Schedule) Next(now time.Time) time.Time {
+	period := s.period()
+	next := now.Truncate(period).Add(s.Offset % period)
+	if !next.After(now) {
+		next = next.Add(period)
+	}
+	return next
+}
+
+// Retry returns the time at which to poll again after the attempt, counted
+// from 0, found the product not yet updated. The delay doubles with each
+// attempt and is capped at the next cycle.
+func (s Schedule) Retry(now time.Time, attempt int) time.Time {
+	next := s.Next(now)
+	if s.RetryDelay <= 0 {
+		return next
+	}
+	delay := s.RetryDelay
+	for i := 0; i < attempt && delay < s.period(); i++ {
+		delay *= 2
+	}
+	if retry := now.Add(delay); retry.Before(next) {
+		return retry
+	}
+	return next
+}
@@ -0,0 +1,45 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestScheduleNext(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2023, 7, 4, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		now  time.Time
+		next time.Time
+	}{
+		{at(13, 10), at(13, 45)},
+		{at(13, 45), at(14, 45)},
+		{at(13, 50), at(14, 45)},
+		{at(23, 50), time.Date(2023, 7, 5, 0, 45, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		if next := noaa.ForecastSchedule.Next(test.now); !next.Equal(test.next) {
+			t.Errorf("noaa.Schedule.Next(%s) should return %s, got %s", test.now.Format("15:04"), test.next.Format("15:04"), next.Format("15:04"))
+		}
+	}
+}
+
+func TestScheduleRetry(t *testing.T) {
+	s := noaa.Schedule{Offset: 45 * time.Minute, RetryDelay: 2 * time.Minute}
+	now := time.Date(2023, 7, 4, 13, 46, 0, 0, time.UTC)
+	expected := []time.Duration{2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute, 32 * time.Minute, 59 * time.Minute}
+	for attempt, delay := range expected {
+		if retry := s.Retry(now, attempt); retry.Sub(now) != delay {
+			t.Errorf("noaa.Schedule.Retry() should wait %s on attempt %d, got %s", delay, attempt, retry.Sub(now))
+		}
+	}
+	if retry := (noaa.Schedule{Offset: 45 * time.Minute}).Retry(now, 0); retry.Sub(now) != 59*time.Minute {
+		t.Errorf("noaa.Schedule.Retry() should wait for the next cycle without a RetryDelay, got %s", retry.Sub(now))
+	}
+}
@@ -0,0 +1,198 @@
+package noaa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Schema variants of forecast periods. weather.gov rolls out response changes
+// behind feature flags, which can be requested with the Feature-Flags header
+// and may later become the default. Both variants are decoded into the same
+// ForecastResponsePeriod fields.
+const (
+	// temperature is a QuantitativeValue instead of a number with a separate
+	// temperatureUnit
+	VariantTemperatureQV = "forecast_temperature_qv"
+	// windSpeed is a QuantitativeValue, possibly a range, instead of a string
+	// such as "10 to 15 mph"
+	VariantWindSpeedQV = "forecast_wind_speed_qv"
+)
+
+// quantitativeValue is the QuantitativeValue schema of weather.gov. Value is
+// null for ranges, which have a min and max value instead.
+type quantitativeValue struct {
+	Value    *float64 `json:"value"`
+	MinValue *float64 `json:"minValue"`
+	MaxValue *float64 `json:"maxValue"`
+	UnitCode string   `json:"unitCode"`
+}
+
+// UnmarshalJSON decodes a forecast period in any of the known schema variants
+// and records the variants found in Variants. Note, this method is promoted to
+// ForecastResponsePeriodHourly so fields added to that type would not be
+// decoded.
+func (p *ForecastResponsePeriod) UnmarshalJSON(data []byte) error {
+	type period ForecastResponsePeriod // without the UnmarshalJSON method
+	var raw struct {
+		period
+		Number      json.RawMessage `json:"number"`
+		Temperature json.RawMessage `json:"temperature"`
+		WindSpeed   json.RawMessage `json:"windSpeed"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = ForecastResponsePeriod(raw.period)
+	number, err := flexibleInt(raw.Number, 32)
+	if err != nil {
+		return fmt.Errorf("invalid period number: %w", err)
+	}
+	p.ID = int32(number)
+
+	if isObject(raw.Temperature) {
+		var qv quantitativeValue
+		if err := json.Unmarshal(raw.Temperature, &qv); err != nil {
+			return fmt.Errorf("invalid temperature: %w", err)
+		}
+		if qv.Value != nil {
+			p.Temperature = *qv.Value
+		}
+		p.TemperatureUnit = temperatureUnit(qv.UnitCode)
+		p.Variants = append(p.Variants, VariantTemperatureQV)
+	} else if len(raw.Temperature) > 0 {
+		if err := json.Unmarshal(raw.Temperature, &p.Temperature); err != nil {
+			return fmt.Errorf("invalid temperature: %w", err)
+		}
+	}
+
+	if isObject(raw.WindSpeed) {
+		var qv quantitativeValue
+		if err := json.Unmarshal(raw.WindSpeed, &qv); err != nil {
+			return fmt.Errorf("invalid wind speed: %w", err)
+		}
+		p.WindSpeed = windSpeedText(qv)
+		p.Variants = append(p.Variants, VariantWindSpeedQV)
+	} else if len(raw.WindSpeed) > 0 {
+		if err := json.Unmarshal(raw.WindSpeed, &p.WindSpeed); err != nil {
+			return fmt.Errorf("invalid wind speed: %w", err)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the points, accepting grid coordinates encoded as
+// strings or floats, see flexibleInt.
+func (p *PointsResponse) UnmarshalJSON(data []byte) error {
+	type points PointsResponse // without the UnmarshalJSON method
+	var raw struct {
+		points
+		GridX json.RawMessage `json:"gridX"`
+		GridY json.RawMessage `json:"gridY"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = PointsResponse(raw.points)
+	var err error
+	if p.GridX, err = flexibleInt(raw.GridX, 64); err != nil {
+		return fmt.Errorf("invalid gridX: %w", err)
+	}
+	if p.GridY, err = flexibleInt(raw.GridY, 64); err != nil {
+		return fmt.Errorf("invalid gridY: %w", err)
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the hazard, accepting an event number encoded as a
+// string or float, see flexibleInt.
+func (h *HazardValueItem) UnmarshalJSON(data []byte) error {
+	type item HazardValueItem // without the UnmarshalJSON method
+	var raw struct {
+		item
+		EventNumber json.RawMessage `json:"event_number"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*h = HazardValueItem(raw.item)
+	number, err := flexibleInt(raw.EventNumber, 32)
+	if err != nil {
+		return fmt.Errorf("invalid event number: %w", err)
+	}
+	h.EventNumber = int32(number)
+	return nil
+}
+
+// flexibleInt decodes an integer which weather.gov occasionally encodes as a
+// string, ex. "76", or a float, ex. 76.0. Missing and null values are 0.
+// Fractional values and values which do not fit in bitSize bits are errors.
+func flexibleInt(raw json.RawMessage, bitSize int) (int64, error) {
+	value := strings.TrimSpace(string(raw))
+	if value == "" || value == "null" {
+		return 0, nil
+	}
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return 0, err
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return 0, nil
+		}
+	}
+	if n, err := strconv.ParseInt(value, 10, bitSize); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f != math.Trunc(f) {
+		return 0, fmt.Errorf("%s is not an integer", raw)
+	}
+	n, err := strconv.ParseInt(strconv.FormatFloat(f, 'f', 0, 64), 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s is out of range", raw)
+	}
+	return n, nil
+}
+
+// isObject reports whether the raw JSON value is an object
+func isObject(raw json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{"))
+}
+
+// temperatureUnit converts a unit code to the temperatureUnit values, F or C
+func temperatureUnit(unitCode string) string {
+	switch strings.TrimPrefix(unitCode, "wmoUnit:") {
+	case "degF":
+		return "F"
+	case "degC":
+		return "C"
+	}
+	return unitCode
+}
+
+// windSpeedText formats a wind speed QuantitativeValue like the string
+// variant, ex. "10 mph" or "10 to 15 mph"
+func windSpeedText(qv quantitativeValue) string {
+	unit := "mph"
+	if strings.TrimPrefix(qv.UnitCode, "wmoUnit:") == "km_h-1" {
+		unit = "km/h"
+	}
+	format := func(v float64) string {
+		return fmt.Sprintf("%.0f", math.Round(v))
+	}
+	switch {
+	case qv.Value != nil:
+		return format(*qv.Value) + " " + unit
+	case qv.MinValue != nil && qv.MaxValue != nil && *qv.MinValue != *qv.MaxValue:
+		return format(*qv.MinValue) + " to " + format(*qv.MaxValue) + " " + unit
+	case qv.MaxValue != nil:
+		return format(*qv.MaxValue) + " " + unit
+	case qv.MinValue != nil:
+		return format(*qv.MinValue) + " " + unit
+	}
+	return ""
+}
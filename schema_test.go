@@ -0,0 +1,125 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestForecastSchemaVariants(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		windSpeed string
+		variants  int
+	}{
+		{"testdata/forecast.json", "10 to 15 mph", 0},
+		{"testdata/forecast_qv.json", "16 to 24 km/h", 2},
+	}
+	for _, test := range tests {
+		data, err := os.ReadFile(test.fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var forecast noaa.ForecastResponse
+		if err := json.Unmarshal(data, &forecast); err != nil {
+			t.Fatalf("%s: %v", test.fixture, err)
+		}
+		p := forecast.Periods[0]
+		if p.Temperature != 86 || p.TemperatureUnit != "F" {
+			t.Errorf("%s: expected a temperature of 86 F, got %v %s", test.fixture, p.Temperature, p.TemperatureUnit)
+		}
+		if p.WindSpeed != test.windSpeed {
+			t.Errorf("%s: expected a wind speed of %s, got %s", test.fixture, test.windSpeed, p.WindSpeed)
+		}
+		if len(p.Variants) != test.variants {
+			t.Errorf("%s: expected %d schema variants, got %v", test.fixture, test.variants, p.Variants)
+		}
+		if p.Summary == "" || p.ProbabilityOfPrecipitation.Value != 20 {
+			t.Errorf("%s: expected the other fields to be decoded, got %+v", test.fixture, p)
+		}
+	}
+}
+
+func TestHourlyForecastSchemaVariants(t *testing.T) {
+	var hourly noaa.HourlyForecastResponse
+	data := `{"periods": [{"number": 1, "temperature": {"unitCode": "wmoUnit:degC", "value": 30}, "windSpeed": {"unitCode": "wmoUnit:km_h-1", "value": 16.093}}]}`
+	if err := json.Unmarshal([]byte(data), &hourly); err != nil {
+		t.Fatal(err)
+	}
+	p := hourly.Periods[0]
+	if p.ID != 1 || p.Temperature != 30 || p.TemperatureUnit != "C" || p.WindSpeed != "16 km/h" {
+		t.Errorf("unexpected hourly period %+v", p)
+	}
+}
+
+func TestPointsNumberEncodings(t *testing.T) {
+	data, err := os.ReadFile("testdata/points.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var points noaa.PointsResponse
+	if err := json.Unmarshal(data, &points); err != nil {
+		t.Fatal(err)
+	}
+	if points.GridX != 74 || points.GridY != 70 {
+		t.Errorf("expected grid 74,70, got %d,%d", points.GridX, points.GridY)
+	}
+	if points.GridID != "LOT" || points.RadarStation != "KLOT" {
+		t.Errorf("expected the other fields to be decoded, got %+v", points)
+	}
+
+	tests := []struct {
+		data  string
+		gridX int64
+		err   bool
+	}{
+		{`{"gridX": 76}`, 76, false},
+		{`{"gridX": "76"}`, 76, false},
+		{`{"gridX": 76.0}`, 76, false},
+		{`{"gridX": " 76 "}`, 76, false},
+		{`{"gridX": null}`, 0, false},
+		{`{}`, 0, false},
+		{`{"gridX": 76.5}`, 0, true},
+		{`{"gridX": "seventy"}`, 0, true},
+	}
+	for _, test := range tests {
+		var p noaa.PointsResponse
+		err := json.Unmarshal([]byte(test.data), &p)
+		if (err != nil) != test.err {
+			t.Errorf("%s: unexpected error %v", test.data, err)
+		}
+		if err == nil && p.GridX != test.gridX {
+			t.Errorf("%s: expected gridX %d, got %d", test.data, test.gridX, p.GridX)
+		}
+	}
+}
+
+func TestEventNumberEncodings(t *testing.T) {
+	data := `[{"phenomenon": "HT", "significance": "Y", "event_number": "12"}, {"phenomenon": "WS", "significance": "A", "event_number": 3.0}, {"phenomenon": "FG", "significance": "Y", "event_number": null}]`
+	var hazards []noaa.HazardValueItem
+	if err := json.Unmarshal([]byte(data), &hazards); err != nil {
+		t.Fatal(err)
+	}
+	if hazards[0].EventNumber != 12 || hazards[1].EventNumber != 3 || hazards[2].EventNumber != 0 {
+		t.Errorf("expected event numbers 12, 3 and 0, got %+v", hazards)
+	}
+	if hazards[0].Phenomenon != "HT" || hazards[1].Significance != "A" {
+		t.Errorf("expected the other fields to be decoded, got %+v", hazards)
+	}
+
+	var period noaa.ForecastResponsePeriod
+	if err := json.Unmarshal([]byte(`{"number": "2", "temperature": 70}`), &period); err != nil {
+		t.Fatal(err)
+	}
+	if period.ID != 2 || period.Temperature != 70 {
+		t.Errorf("expected period 2, got %+v", period)
+	}
+	if err := json.Unmarshal([]byte(`{"number": 4294967296}`), &period); err == nil {
+		t.Error("noaa.ForecastResponsePeriod should reject a period number out of range")
+	}
+}
@@ -0,0 +1,127 @@
+//go:build go1.23
+
+package noaa
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// page is a page of a paginated collection, ex. the observations of a station
+type page[T any] struct {
+	Items      []T `json:"@graph"`
+	Pagination struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+// pages returns an iterator over the pages of a paginated collection, which
+// requests each page once the previous one was consumed, so only one page is
+// held in memory and breaking out of the loop stops the requests. The
+// iterator yields the error of a failed request and stops.
+func pages[T any](ctx context.Context, endpoint string) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		next := endpoint
+		for next != "" {
+			p, err := GetJSON[page[T]](ctx, next, nil)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(p.Items) == 0 || !yield(p.Items, nil) || p.Pagination.Next == next {
+				return
+			}
+			next = p.Pagination.Next
+		}
+	}
+}
+
+// items returns an iterator over the items of the pages, converted by fn
+func items[T any, V any](pages iter.Seq2[[]T, error], fn func([]T) []V) iter.Seq2[V, error] {
+	return func(yield func(V, error) bool) {
+		for page, err := range pages {
+			if err != nil {
+				var zero V
+				yield(zero, err)
+				return
+			}
+			for _, item := range fn(page) {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ObservationsSeq returns an iterator over the observations of a station,
+// newest first, which requests the pages of observations as the loop needs
+// them instead of loading every observation in memory:
+//
+//	start := time.Now().AddDate(0, -1, 0)
+//	for o, err := range noaa.ObservationsSeq(ctx, "KORD", noaa.ObservationsOptions{Start: start}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// The options are those of StationObservations, except that Limit is the
+// size of each page. Special observations are detected in each page, see
+// SplitSpecialObservations. A failed request is yielded as an error and ends
+// the iteration. Requires Go 1.23.
+func ObservationsSeq(ctx context.Context, station string, opts ObservationsOptions) iter.Seq2[Observation, error] {
+	if err := ValidateStationID(station); err != nil {
+		return func(yield func(Observation, error) bool) { yield(Observation{}, err) }
+	}
+	query := url.Values{}
+	if !opts.Start.IsZero() {
+		query.Set("start", opts.Start.UTC().Format(time.RFC3339))
+	}
+	if !opts.End.IsZero() {
+		query.Set("end", opts.End.UTC().Format(time.RFC3339))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	endpoint := stationURL(station, "observations").withQuery(query).String()
+	return items(pages[Observation](ctx, endpoint), func(observations []Observation) []Observation {
+		if opts.IncludeSpecial {
+			return observations
+		}
+		routine, _ := SplitSpecialObservations(observations)
+		return routine
+	})
+}
+
+// AlertsSeq returns an iterator over the alerts matching the /alerts query
+// parameters, ex. url.Values{"area": {"IL"}, "start": {"2023-01-01T00:00:00Z"}},
+// including alerts which are no longer active, newest first. Pages of alerts
+// are requested as the loop needs them, see ObservationsSeq. Requires Go
+// 1.23.
+func AlertsSeq(ctx context.Context, query url.Values) iter.Seq2[Alert, error] {
+	endpoint := apiURL("alerts").withQuery(query).String()
+	return items(pages[Alert](ctx, endpoint), func(alerts []Alert) []Alert { return alerts })
+}
+
+// StationsSeq returns an iterator over the observation stations matching the
+// /stations query parameters, ex. url.Values{"state": {"IL"}}. Distance and
+// Bearing are not set. Pages of stations are requested as the loop needs
+// them, see ObservationsSeq. Requires Go 1.23.
+func StationsSeq(ctx context.Context, query url.Values) iter.Seq2[Station, error] {
+	endpoint := apiURL("stations").withQuery(query).String()
+	return items(pages[stationGraphEntry](ctx, endpoint), func(entries []stationGraphEntry) []Station {
+		stations := make([]Station, len(entries))
+		for i, g := range entries {
+			stations[i] = Station{ID: g.ID, URL: g.URI, Name: g.Name, TimeZone: g.TimeZone, Elevation: g.Elevation}
+			if stations[i].ID == "" {
+				stations[i].ID = StationID(g.URI)
+			}
+			stations[i].Location, _ = g.location()
+		}
+		return stations
+	})
+}
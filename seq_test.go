@@ -0,0 +1,127 @@
+//go:build go1.23 && !examples
+// +build go1.23,!examples
+
+package noaa_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// pagedAPI serves the items of each page of a collection, following the
+// cursor query parameter, and counts the requests
+func pagedAPI(t *testing.T, path string, pages [][]string) *int32 {
+	var requests int32
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		var cursor int
+		fmt.Sscan(r.URL.Query().Get("cursor"), &cursor)
+		items := "[]"
+		if cursor < len(pages) {
+			items = "["
+			for i, item := range pages[cursor] {
+				if i > 0 {
+					items += ","
+				}
+				items += item
+			}
+			items += "]"
+		}
+		q := r.URL.Query()
+		q.Set("cursor", fmt.Sprint(cursor+1))
+		fmt.Fprintf(w, `{"@graph": %s, "pagination": {"next": "%s%s?%s"}}`, items, api.URL, path, q.Encode())
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	return &requests
+}
+
+func TestObservationsSeq(t *testing.T) {
+	now := time.Now().Truncate(time.Hour).Add(51 * time.Minute)
+	requests := pagedAPI(t, "/stations/KORD/observations", [][]string{
+		{observation(now, "V"), observation(now.Add(-time.Hour), "V"), observation(now.Add(-90*time.Minute), "V")},
+		{observation(now.Add(-2*time.Hour), "V")},
+	})
+	var times []time.Time
+	for o, err := range noaa.ObservationsSeq(context.Background(), "KORD", noaa.ObservationsOptions{Limit: 3}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		times = append(times, o.Timestamp)
+	}
+	if len(times) != 3 || !times[2].Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("noaa.ObservationsSeq() should yield the routine observations of every page, got %v", times)
+	}
+	if n := atomic.LoadInt32(requests); n != 3 {
+		t.Errorf("noaa.ObservationsSeq() should request pages until an empty one, got %d requests", n)
+	}
+
+	atomic.StoreInt32(requests, 0)
+	for range noaa.ObservationsSeq(context.Background(), "KORD", noaa.ObservationsOptions{IncludeSpecial: true}) {
+		break
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("noaa.ObservationsSeq() should stop requesting pages when the loop breaks, got %d requests", n)
+	}
+}
+
+func TestAlertsSeq(t *testing.T) {
+	pagedAPI(t, "/alerts", [][]string{
+		{`{"id": "urn:oid:1", "event": "Winter Storm Warning"}`},
+		{`{"id": "urn:oid:2", "event": "Wind Advisory"}`},
+	})
+	var events []string
+	for a, err := range noaa.AlertsSeq(context.Background(), url.Values{"area": {"IL"}}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, a.Event)
+	}
+	if len(events) != 2 || events[1] != "Wind Advisory" {
+		t.Errorf("noaa.AlertsSeq() should yield the alerts of every page, got %v", events)
+	}
+}
+
+func TestStationsSeq(t *testing.T) {
+	pagedAPI(t, "/stations", [][]string{
+		{`{"@id": "https://api.weather.gov/stations/KORD", "stationIdentifier": "KORD", "name": "Chicago O'Hare", "geometry": "POINT(-87.93 41.96)"}`},
+	})
+	var stations []noaa.Station
+	for s, err := range noaa.StationsSeq(context.Background(), url.Values{"state": {"IL"}}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		stations = append(stations, s)
+	}
+	if len(stations) != 1 || stations[0].ID != "KORD" || stations[0].Location.Lat != 41.96 {
+		t.Errorf("noaa.StationsSeq() should yield the stations with their location, got %+v", stations)
+	}
+
+	noaa.SetBaseURL("https://127.0.0.1:1")
+	var errs []error
+	for _, err := range noaa.StationsSeq(context.Background(), nil) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || errs[0] == nil {
+		t.Errorf("noaa.StationsSeq() should yield the error of a failed request, got %v", errs)
+	}
+}
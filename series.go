@@ -0,0 +1,239 @@
+package noaa
+
+import (
+	"sort"
+	"time"
+)
+
+// SeriesValue is a value of a Series, valid from Start for Duration. A zero
+// Duration is an instant, ex. an observation.
+type SeriesValue[T any] struct {
+	Start    time.Time
+	Duration time.Duration
+	Value    T
+}
+
+// End returns the end of the interval of the value, excluded.
+func (v SeriesValue[T]) End() time.Time {
+	return v.Start.Add(v.Duration)
+}
+
+// Covers reports whether t is within the interval of the value. An instant
+// only covers its own time.
+func (v SeriesValue[T]) Covers(t time.Time) bool {
+	if v.Duration == 0 {
+		return t.Equal(v.Start)
+	}
+	return !t.Before(v.Start) && t.Before(v.End())
+}
+
+// Series is a typed time series with the unit of its values, sorted by
+// start time. Gridpoint series are converted with
+// GridpointForecastTimeSeries.Series, and series are transformed with the
+// methods and MapSeries, ReduceSeries and ZipSeries, so derived series, ex.
+// the dewpoint depression, compose with the gridpoint ones:
+//
+//	temperature := forecast.Temperature.Series()
+//	dewpoint := forecast.Dewpoint.Series()
+//	depression := noaa.ZipSeries(temperature, dewpoint, func(t, d float64) float64 { return t - d })
+//	tonight := depression.Window(sunset, sunrise)
+//	lowest, ok := noaa.SeriesMin(tonight)
+type Series[T any] struct {
+	Unit   string // unit code, ex. wmoUnit:degC, blank if the values have none
+	Values []SeriesValue[T]
+}
+
+// Series returns the gridpoint series as a typed series. Values with an
+// invalid validTime are skipped.
+func (s GridpointForecastTimeSeries) Series() Series[float64] {
+	series := Series[float64]{Unit: s.Uom, Values: make([]SeriesValue[float64], 0, len(s.Values))}
+	for _, v := range s.Values {
+		start, d, err := v.Interval()
+		if err != nil {
+			continue
+		}
+		series.Values = append(series.Values, SeriesValue[float64]{Start: start, Duration: d, Value: v.Value})
+	}
+	sort.SliceStable(series.Values, func(i, j int) bool { return series.Values[i].Start.Before(series.Values[j].Start) })
+	return series
+}
+
+// At returns the value covering t. The second return value is false if no
+// value covers the time.
+func (s Series[T]) At(t time.Time) (T, bool) {
+	for _, v := range s.Values {
+		if v.Covers(t) {
+			return v.Value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Filter returns the series with the values for which keep returns true.
+func (s Series[T]) Filter(keep func(SeriesValue[T]) bool) Series[T] {
+	filtered := Series[T]{Unit: s.Unit}
+	for _, v := range s.Values {
+		if keep(v) {
+			filtered.Values = append(filtered.Values, v)
+		}
+	}
+	return filtered
+}
+
+// Window returns the values overlapping the window from from to until,
+// excluded, cut to the window. Instants are kept if they are in the window.
+func (s Series[T]) Window(from time.Time, until time.Time) Series[T] {
+	window := Series[T]{Unit: s.Unit}
+	for _, v := range s.Values {
+		if v.Duration == 0 {
+			if !v.Start.Before(from) && v.Start.Before(until) {
+				window.Values = append(window.Values, v)
+			}
+			continue
+		}
+		start, end := v.Start, v.End()
+		if !start.Before(until) || !end.After(from) {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(until) {
+			end = until
+		}
+		window.Values = append(window.Values, SeriesValue[T]{Start: start, Duration: end.Sub(start), Value: v.Value})
+	}
+	return window
+}
+
+// Resample returns the series with its values split into intervals of step,
+// aligned to step since the zero time (ex. whole hours in UTC for an hour),
+// each with the value covering it. Gridpoint values last from one hour to
+// several days, so resampling them hourly aligns them with hourly forecasts
+// and observations. Instants are kept as is. A step of 0 or less returns the
+// series unchanged.
+func (s Series[T]) Resample(step time.Duration) Series[T] {
+	if step <= 0 {
+		return s
+	}
+	resampled := Series[T]{Unit: s.Unit}
+	for _, v := range s.Values {
+		if v.Duration == 0 {
+			resampled.Values = append(resampled.Values, v)
+			continue
+		}
+		end := v.End()
+		for start := v.Start; start.Before(end); {
+			next := start.Truncate(step).Add(step)
+			if next.After(end) {
+				next = end
+			}
+			resampled.Values = append(resampled.Values, SeriesValue[T]{Start: start, Duration: next.Sub(start), Value: v.Value})
+			start = next
+		}
+	}
+	return resampled
+}
+
+// MapSeries returns the series with fn applied to each value, with the unit
+// of the new values, ex. a temperature series converted to a frost flag.
+func MapSeries[T any, V any](s Series[T], unit string, fn func(T) V) Series[V] {
+	mapped := Series[V]{Unit: unit, Values: make([]SeriesValue[V], len(s.Values))}
+	for i, v := range s.Values {
+		mapped.Values[i] = SeriesValue[V]{Start: v.Start, Duration: v.Duration, Value: fn(v.Value)}
+	}
+	return mapped
+}
+
+// ReduceSeries folds the values of the series into an accumulator, starting
+// from init, ex. to count the hours below freezing.
+func ReduceSeries[T any, A any](s Series[T], init A, fn func(A, SeriesValue[T]) A) A {
+	acc := init
+	for _, v := range s.Values {
+		acc = fn(acc, v)
+	}
+	return acc
+}
+
+// ZipSeries derives a series from two series, calling fn with the value of a
+// and the value of b covering the start of each value of a. Values of a not
+// covered by b are skipped. The derived series has the unit of a.
+func ZipSeries[A any, B any, V any](a Series[A], b Series[B], fn func(A, B) V) Series[V] {
+	zipped := Series[V]{Unit: a.Unit}
+	for _, v := range a.Values {
+		if w, ok := b.At(v.Start); ok {
+			zipped.Values = append(zipped.Values, SeriesValue[V]{Start: v.Start, Duration: v.Duration, Value: fn(v.Value, w)})
+		}
+	}
+	return zipped
+}
+
+// Number is the constraint of the values of the series aggregated by
+// SeriesMin, SeriesMax and SeriesMean.
+type Number interface {
+	~int | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// SeriesMin returns the lowest value of the series, the first one if several
+// are equal. The second return value is false if the series is empty. Use
+// Window to get the lowest value of a period.
+func SeriesMin[T Number](s Series[T]) (SeriesValue[T], bool) {
+	return extreme(s, func(a, b T) bool { return a < b })
+}
+
+// SeriesMax returns the highest value of the series, the first one if several
+// are equal. The second return value is false if the series is empty.
+func SeriesMax[T Number](s Series[T]) (SeriesValue[T], bool) {
+	return extreme(s, func(a, b T) bool { return a > b })
+}
+
+// extreme returns the first value of the series which no other value beats
+func extreme[T Number](s Series[T], beats func(a, b T) bool) (SeriesValue[T], bool) {
+	if len(s.Values) == 0 {
+		return SeriesValue[T]{}, false
+	}
+	best := s.Values[0]
+	for _, v := range s.Values[1:] {
+		if beats(v.Value, best.Value) {
+			best = v
+		}
+	}
+	return best, true
+}
+
+// SeriesMean returns the mean of the series weighted by the duration of each
+// value, so a value lasting 6 hours counts as much as 6 hourly values. Series
+// of instants, ex. observations, have the arithmetic mean. The second return
+// value is false if the series is empty.
+func SeriesMean[T Number](s Series[T]) (float64, bool) {
+	if len(s.Values) == 0 {
+		return 0, false
+	}
+	var sum, weights, plain float64
+	for _, v := range s.Values {
+		w := v.Duration.Hours()
+		sum += float64(v.Value) * w
+		weights += w
+		plain += float64(v.Value)
+	}
+	if weights == 0 {
+		return plain / float64(len(s.Values)), true
+	}
+	return sum / weights, true
+}
+
+// ConvertSeries converts the values of the series to the units of the locale,
+// see Locale.Convert. The unit of the converted series is the display unit,
+// ex. °F.
+func ConvertSeries(s Series[float64], l Locale) Series[float64] {
+	converted := Series[float64]{Values: make([]SeriesValue[float64], len(s.Values))}
+	for i, v := range s.Values {
+		v.Value, converted.Unit = l.Convert(v.Value, s.Unit)
+		converted.Values[i] = v
+	}
+	if len(s.Values) == 0 {
+		_, converted.Unit = l.Convert(0, s.Unit)
+	}
+	return converted
+}
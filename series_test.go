@@ -0,0 +1,102 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// gridSeries is a temperature series of 3 values lasting 2, 1 and 3 hours
+var gridSeries = noaa.GridpointForecastTimeSeries{
+	Uom: "wmoUnit:degC",
+	Values: []noaa.GridpointForecastTimeSeriesValue{
+		{ValidTime: "2023-07-04T21:00:00+00:00/PT1H", Value: 18},
+		{ValidTime: "2023-07-04T18:00:00+00:00/PT2H", Value: 24},
+		{ValidTime: "invalid", Value: 99},
+		{ValidTime: "2023-07-04T22:00:00+00:00/PT3H", Value: 15},
+	},
+}
+
+func TestGridpointSeries(t *testing.T) {
+	s := gridSeries.Series()
+	if s.Unit != "wmoUnit:degC" || len(s.Values) != 3 || s.Values[0].Value != 24 || s.Values[0].Duration != 2*time.Hour {
+		t.Fatalf("noaa.GridpointForecastTimeSeries.Series() should return the valid values sorted by time, got %+v", s)
+	}
+	if v, ok := s.At(time.Date(2023, 7, 4, 23, 30, 0, 0, time.UTC)); !ok || v != 15 {
+		t.Errorf("noaa.Series.At() should return the value covering the time, got %v, %v", v, ok)
+	}
+	if _, ok := s.At(time.Date(2023, 7, 4, 20, 30, 0, 0, time.UTC)); ok {
+		t.Error("noaa.Series.At() should not return a value in a gap")
+	}
+}
+
+func TestSeriesWindowAndResample(t *testing.T) {
+	s := gridSeries.Series()
+	from := time.Date(2023, 7, 4, 19, 0, 0, 0, time.UTC)
+	window := s.Window(from, from.Add(4*time.Hour))
+	if len(window.Values) != 3 || !window.Values[0].Start.Equal(from) || window.Values[0].Duration != time.Hour || window.Values[2].Duration != time.Hour {
+		t.Errorf("noaa.Series.Window() should cut the values to the window, got %+v", window.Values)
+	}
+	hourly := s.Resample(time.Hour)
+	if len(hourly.Values) != 6 {
+		t.Fatalf("noaa.Series.Resample() should split the values into hours, got %d values", len(hourly.Values))
+	}
+	for _, v := range hourly.Values {
+		if v.Duration != time.Hour || v.Start.Minute() != 0 {
+			t.Errorf("noaa.Series.Resample() should return whole hours, got %+v", v)
+		}
+	}
+	if hourly.Values[5].Value != 15 || !hourly.Values[5].Start.Equal(time.Date(2023, 7, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("noaa.Series.Resample() should keep the value of each interval, got %+v", hourly.Values[5])
+	}
+}
+
+func TestSeriesAggregates(t *testing.T) {
+	s := gridSeries.Series()
+	if lowest, ok := noaa.SeriesMin(s); !ok || lowest.Value != 15 {
+		t.Errorf("noaa.SeriesMin() = %+v, %v", lowest, ok)
+	}
+	if highest, ok := noaa.SeriesMax(s); !ok || highest.Value != 24 {
+		t.Errorf("noaa.SeriesMax() = %+v, %v", highest, ok)
+	}
+	// (24*2 + 18*1 + 15*3) / 6 hours
+	if mean, ok := noaa.SeriesMean(s); !ok || math.Abs(mean-18.5) > 1e-9 {
+		t.Errorf("noaa.SeriesMean() should weight the values by duration, got %v", mean)
+	}
+	instants := noaa.Series[int]{Values: []noaa.SeriesValue[int]{{Value: 1}, {Value: 2}}}
+	if mean, ok := noaa.SeriesMean(instants); !ok || mean != 1.5 {
+		t.Errorf("noaa.SeriesMean() should average instants, got %v", mean)
+	}
+	if _, ok := noaa.SeriesMin(noaa.Series[float64]{}); ok {
+		t.Error("noaa.SeriesMin() should report an empty series")
+	}
+}
+
+func TestSeriesCompose(t *testing.T) {
+	temperature := gridSeries.Series()
+	dewpoint := noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{
+		{ValidTime: "2023-07-04T18:00:00+00:00/PT6H", Value: 14},
+	}}.Series()
+	depression := noaa.ZipSeries(temperature, dewpoint, func(t, d float64) float64 { return t - d })
+	if len(depression.Values) != 3 || depression.Values[0].Value != 10 || depression.Values[2].Value != 1 {
+		t.Errorf("noaa.ZipSeries() should derive the values where both series have one, got %+v", depression.Values)
+	}
+	foggy := depression.Filter(func(v noaa.SeriesValue[float64]) bool { return v.Value <= 2 })
+	hours := noaa.ReduceSeries(foggy, 0.0, func(hours float64, v noaa.SeriesValue[float64]) float64 { return hours + v.Duration.Hours() })
+	if hours != 3 {
+		t.Errorf("noaa.ReduceSeries() should sum the filtered durations, got %v", hours)
+	}
+	warm := noaa.MapSeries(temperature, "", func(c float64) bool { return c >= 20 })
+	if len(warm.Values) != 3 || !warm.Values[0].Value || warm.Values[1].Value {
+		t.Errorf("noaa.MapSeries() should map each value, got %+v", warm.Values)
+	}
+	f := noaa.ConvertSeries(temperature, noaa.LocaleUS)
+	if f.Unit != "°F" || math.Abs(f.Values[0].Value-75.2) > 1e-9 {
+		t.Errorf("noaa.ConvertSeries() should convert to the locale units, got %s %+v", f.Unit, f.Values[0])
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// PushPath is the path of the endpoint accepting pushed alert feeds, see
+// PushOptions.
+const PushPath = "/alerts/push"
+
+// DefaultMaxPushSize is the maximum size of a pushed feed when
+// PushOptions.MaxSize is 0.
+const DefaultMaxPushSize = 10 << 20
+
+// SignatureHeader is the header of the WebSub signature of pushed feeds.
+const SignatureHeader = "X-Hub-Signature-256"
+
+// PushOptions enable the /alerts/push endpoint, see Options.Push, which
+// accepts CAP alerts and Atom feeds of CAP alerts pushed by external sources,
+// ex. a WebSub hub or an IPAWS feed relay. Pushed alerts are decoded with noaa.DecodeCAP and fed to
+// Tracker, and the events they issue, update or end are published on Bus as
+// noaa.AlertIssued events, like the alerts polled by a noaa.Poller.
+//
+// Subscription requests of WebSub hubs, GET requests with hub.mode and
+// hub.challenge parameters, are answered with the challenge for the allowed
+// topics. When Secret is set, pushed feeds must be signed with it in the
+// X-Hub-Signature-256 header, sha256= followed by the hex HMAC-SHA256 of the
+// body, and others are rejected with 403 Forbidden.
+type PushOptions struct {
+	Tracker *noaa.AlertTracker // tracker of the pushed alerts, a new tracker if nil
+	Bus     *noaa.Bus          // bus of the AlertIssued events, noaa.DefaultBus if nil
+	Secret  string             // WebSub secret, unsigned feeds are accepted if blank
+	Topics  []string           // topics of allowed subscriptions, all if empty
+	MaxSize int64              // maximum body size in bytes, DefaultMaxPushSize if 0
+}
+
+// pushResponse is the JSON body of accepted pushes
+type pushResponse struct {
+	Alerts int `json:"alerts"` // alerts decoded
+	Events int `json:"events"` // events issued, updated or ended
+}
+
+// push handles /alerts/push
+func (s *Server) push(w http.ResponseWriter, r *http.Request) {
+	opts := *s.opts.Push
+	if r.Method != http.MethodPost {
+		s.verifySubscription(w, r)
+		return
+	}
+	limit := opts.MaxSize
+	if limit <= 0 {
+		limit = DefaultMaxPushSize
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "feed too large")
+		return
+	}
+	if opts.Secret != "" && !validSignature(opts.Secret, r.Header.Get(SignatureHeader), body) {
+		writeError(w, http.StatusForbidden, "invalid signature")
+		return
+	}
+	alerts, err := noaa.DecodeCAP(bytes.NewReader(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bus := opts.Bus
+	if bus == nil {
+		bus = noaa.DefaultBus
+	}
+	events := s.tracker.Update(alerts...)
+	for _, event := range events {
+		bus.Publish(noaa.AlertIssued{Event: event})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(pushResponse{Alerts: len(alerts), Events: len(events)})
+}
+
+// verifySubscription answers the intent verification of a WebSub hub
+func (s *Server) verifySubscription(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	mode, challenge := q.Get("hub.mode"), q.Get("hub.challenge")
+	if (mode != "subscribe" && mode != "unsubscribe") || challenge == "" {
+		writeError(w, http.StatusBadRequest, "hub.mode and hub.challenge are required")
+		return
+	}
+	if topics := s.opts.Push.Topics; len(topics) > 0 {
+		allowed := false
+		for _, topic := range topics {
+			allowed = allowed || topic == q.Get("hub.topic")
+		}
+		if !allowed {
+			writeError(w, http.StatusNotFound, "unknown topic")
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	io.WriteString(w, challenge)
+}
+
+// validSignature reports whether the signature header, sha256=<hex>, is the
+// HMAC-SHA256 of the body with the secret
+func validSignature(secret string, header string, body []byte) bool {
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil || !strings.HasPrefix(header, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
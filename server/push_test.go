@@ -0,0 +1,90 @@
+package server_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/server"
+)
+
+// pushedAlert is a CAP alert as pushed by a feed
+const pushedAlert = `<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+	<identifier>urn:oid:1</identifier>
+	<sender>w-nws.webmaster@noaa.gov</sender>
+	<sent>2023-07-04T14:40:00-05:00</sent>
+	<status>Actual</status>
+	<msgType>Alert</msgType>
+	<info>
+		<event>Tornado Warning</event>
+		<parameter><valueName>VTEC</valueName><value>/O.NEW.KLOT.TO.W.0042.230704T1940Z-230704T2030Z/</value></parameter>
+	</info>
+</alert>`
+
+// sign returns the WebSub signature of the body
+func sign(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPush(t *testing.T) {
+	bus := noaa.NewBus()
+	var issued []noaa.AlertIssued
+	bus.Subscribe(func(e noaa.Event) {
+		if a, ok := e.(noaa.AlertIssued); ok {
+			issued = append(issued, a)
+		}
+	})
+	tracker := noaa.NewAlertTracker()
+	handler := server.New(server.Options{Push: &server.PushOptions{Tracker: tracker, Bus: bus, Secret: "s3cret", Topics: []string{"https://hub.example.com/il"}}})
+
+	push := func(body string, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", server.PushPath, strings.NewReader(body))
+		req.Header.Set(server.SignatureHeader, signature)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	if res := push(pushedAlert, sign("s3cret", pushedAlert)); res.Code != http.StatusAccepted || !strings.Contains(res.Body.String(), `"events":1`) {
+		t.Fatalf("expected 202 with 1 event, got %d: %s", res.Code, res.Body.String())
+	}
+	if len(issued) != 1 || issued[0].Event.Current.Event != "Tornado Warning" || len(tracker.Events()) != 1 {
+		t.Errorf("expected the alert to be tracked and published, got %+v", issued)
+	}
+	if res := push(pushedAlert, sign("s3cret", pushedAlert)); res.Code != http.StatusAccepted || len(issued) != 1 {
+		t.Errorf("expected the same alert pushed again not to publish an event, got %d events", len(issued))
+	}
+	if res := push(pushedAlert, sign("other", pushedAlert)); res.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for an invalid signature, got %d", res.Code)
+	}
+	if res := push("<html/>", sign("s3cret", "<html/>")); res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a document which is not CAP, got %d", res.Code)
+	}
+
+	for _, test := range []struct {
+		query string
+		code  int
+	}{
+		{"hub.mode=subscribe&hub.challenge=abc&hub.topic=https://hub.example.com/il", http.StatusOK},
+		{"hub.mode=subscribe&hub.challenge=abc&hub.topic=https://hub.example.com/in", http.StatusNotFound},
+		{"hub.challenge=abc", http.StatusBadRequest},
+	} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest("GET", server.PushPath+"?"+test.query, nil))
+		if res.Code != test.code || (test.code == http.StatusOK && res.Body.String() != "abc") {
+			t.Errorf("%s should return %d, got %d: %s", test.query, test.code, res.Code, res.Body.String())
+		}
+	}
+
+	res := httptest.NewRecorder()
+	server.New(server.Options{}).ServeHTTP(res, httptest.NewRequest("POST", server.PushPath, strings.NewReader(pushedAlert)))
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 when pushes are not enabled, got %d", res.Code)
+	}
+}
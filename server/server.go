@@ -0,0 +1,439 @@
+// Package server implements an http.Handler which serves weather.gov data as
+// JSON using the noaa client, with built-in caching and rate limiting so that
+// small applications can run a local weather API proxy. For example:
+//
+//	http.ListenAndServe("localhost:8080", server.New(server.Options{}))
+//
+// The following endpoints are served:
+//
+//	/forecast?lat=41.837&lon=-87.685
+//	/hourly?lat=41.837&lon=-87.685
+//	/gridpoint?lat=41.837&lon=-87.685
+//	/alerts?lat=41.837&lon=-87.685 or /alerts?zone=ILZ014
+//	/stations?lat=41.837&lon=-87.685
+//	/alerts/push, if enabled by Options.Push
+//
+// The forecast endpoints accept units=us or units=si to override the
+// configured units. Forecasts are cached by forecast office grid cell rather
+// than by coordinates, so nearby points in the same cell share cache entries.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// Default values used for blank Options.
+const (
+	DefaultCacheTTL  = 5 * time.Minute
+	DefaultRateLimit = 1.0 // upstream requests per second
+	DefaultBurst     = 5
+)
+
+// zonePattern matches forecast, county, fire and marine zone IDs
+var zonePattern = regexp.MustCompile(`^[A-Z]{2}[CZ]\d{3}$`)
+
+// Options configure the Server. Zero values use the defaults.
+type Options struct {
+	CacheTTL  time.Duration // how long responses are cached, negative disables caching
+	RateLimit float64       // upstream requests per second, negative disables rate limiting
+	Burst     int           // upstream requests allowed at once before rate limiting applies
+	Push      *PushOptions  // enables /alerts/push if set
+	Client    *http.Client  // client of the weather.gov requests, http.DefaultClient if nil
+	ErrorLog  *log.Logger   // logger of the upstream errors, the standard logger if nil
+}
+
+// Server is an http.Handler serving weather.gov data as JSON. Responses are
+// cached and requests that need to call weather.gov (cache misses) are rate
+// limited with a token bucket, returning 429 Too Many Requests when exceeded.
+type Server struct {
+	opts    Options
+	mux     *http.ServeMux
+	tracker *noaa.AlertTracker // tracker of pushed alerts
+
+	mu      sync.Mutex
+	cache   map[string]cacheEntry
+	points  map[string]pointEntry // resolved coordinates, by lat,lon
+	tokens  float64
+	updated time.Time
+	now     func() time.Time
+}
+
+// cacheEntry holds a marshaled response and when it expires. Forecasts cached
+// by grid cell also hold the value, which is marshaled with the point of each
+// request.
+type cacheEntry struct {
+	body    []byte
+	value   interface{}
+	expires time.Time
+}
+
+// pointEntry holds resolved coordinates and when they expire
+type pointEntry struct {
+	point   *noaa.PointsResponse
+	expires time.Time
+}
+
+// errorResponse is the JSON body of all error responses
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// New returns a Server using the given options.
+func New(opts Options) *Server {
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = DefaultCacheTTL
+	}
+	if opts.RateLimit == 0 {
+		opts.RateLimit = DefaultRateLimit
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = DefaultBurst
+	}
+	s := &Server{
+		opts:   opts,
+		mux:    http.NewServeMux(),
+		cache:  map[string]cacheEntry{},
+		points: map[string]pointEntry{},
+		tokens: float64(opts.Burst),
+		now:    time.Now,
+	}
+	s.updated = s.now()
+	s.mux.HandleFunc("/forecast", s.grid(func(ctx context.Context, lat, lon, units string) (interface{}, error) {
+		return noaa.ForecastContext(ctx, lat, lon, units)
+	}))
+	s.mux.HandleFunc("/hourly", s.grid(func(ctx context.Context, lat, lon, units string) (interface{}, error) {
+		return noaa.HourlyForecastContext(ctx, lat, lon, units)
+	}))
+	s.mux.HandleFunc("/gridpoint", s.grid(func(ctx context.Context, lat, lon, units string) (interface{}, error) {
+		return noaa.GridpointForecastContext(ctx, lat, lon, units)
+	}))
+	s.mux.HandleFunc("/stations", s.point(func(ctx context.Context, lat, lon, units string) (interface{}, error) {
+		return noaa.StationsContext(ctx, lat, lon)
+	}))
+	s.mux.HandleFunc("/alerts", s.alerts)
+	if opts.Push != nil {
+		s.tracker = opts.Push.Tracker
+		if s.tracker == nil {
+			s.tracker = noaa.NewAlertTracker()
+		}
+		s.mux.HandleFunc(PushPath, s.push)
+	}
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	post := r.Method == http.MethodPost && r.URL.Path == PushPath && s.opts.Push != nil
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && !post {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// context returns the context of the weather.gov requests made for the
+// request, with the client of the options
+func (s *Server) context(r *http.Request) context.Context {
+	if s.opts.Client == nil {
+		return r.Context()
+	}
+	return noaa.WithHTTPClient(r.Context(), s.opts.Client)
+}
+
+// point returns a handler for endpoints taking lat, lon and optional units
+// query parameters
+func (s *Server) point(fetch func(ctx context.Context, lat, lon, units string) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lat, lon, units, ok := pointParams(w, r)
+		if !ok {
+			return
+		}
+		ctx := s.context(r)
+		s.serve(w, r.URL.Path+"?"+lat+","+lon+"&"+units, func() (interface{}, error) {
+			return fetch(ctx, lat, lon, units)
+		})
+	}
+}
+
+// grid returns a handler for forecast endpoints like point, but caching the
+// forecasts by the grid cell of the coordinates
+func (s *Server) grid(fetch func(ctx context.Context, lat, lon, units string) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lat, lon, units, ok := pointParams(w, r)
+		if !ok {
+			return
+		}
+		ctx := s.context(r)
+		if s.opts.CacheTTL < 0 {
+			s.serve(w, r.URL.Path+"?"+lat+","+lon+"&"+units, func() (interface{}, error) {
+				return fetch(ctx, lat, lon, units)
+			})
+			return
+		}
+		point, ok := s.resolve(ctx, w, lat, lon)
+		if !ok {
+			return
+		}
+		key := fmt.Sprintf("%s?grid=%s/%d,%d&%s", r.URL.Path, point.GridID, point.GridX, point.GridY, units)
+		if point.GridID == "" {
+			key = r.URL.Path + "?" + lat + "," + lon + "&" + units
+		}
+		s.serveValue(w, key, point, func() (interface{}, error) {
+			return fetch(ctx, lat, lon, units)
+		})
+	}
+}
+
+// resolve returns the point of the coordinates, calling weather.gov only for
+// coordinates which have not been resolved within the cache TTL. Resolved
+// coordinates expire like cached responses, so arbitrary coordinates do not
+// grow the server without bound.
+func (s *Server) resolve(ctx context.Context, w http.ResponseWriter, lat, lon string) (*noaa.PointsResponse, bool) {
+	s.mu.Lock()
+	e, ok := s.points[lat+","+lon]
+	s.mu.Unlock()
+	if ok && s.now().Before(e.expires) {
+		return e.point, true
+	}
+	if !s.allow(w) {
+		return nil, false
+	}
+	point, err := noaa.PointsContext(ctx, lat, lon)
+	if err != nil {
+		s.writeFetchError(w, err)
+		return nil, false
+	}
+	s.mu.Lock()
+	now := s.now()
+	for k, e := range s.points {
+		if !now.Before(e.expires) {
+			delete(s.points, k)
+		}
+	}
+	s.points[lat+","+lon] = pointEntry{point: point, expires: now.Add(s.opts.CacheTTL)}
+	s.mu.Unlock()
+	return point, true
+}
+
+// pointParams returns the validated lat, lon and units query parameters or
+// writes a bad request response
+func pointParams(w http.ResponseWriter, r *http.Request) (lat, lon, units string, ok bool) {
+	lat, lon, err := coordinates(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", "", "", false
+	}
+	units = r.URL.Query().Get("units")
+	if units != "" && units != "us" && units != "si" {
+		writeError(w, http.StatusBadRequest, `units must be "us" or "si"`)
+		return "", "", "", false
+	}
+	return lat, lon, units, true
+}
+
+// alerts handles /alerts?zone= and /alerts?lat=&lon=
+func (s *Server) alerts(w http.ResponseWriter, r *http.Request) {
+	if zone := r.URL.Query().Get("zone"); zone != "" {
+		if !zonePattern.MatchString(zone) {
+			writeError(w, http.StatusBadRequest, "invalid zone")
+			return
+		}
+		s.serve(w, "/alerts?zone="+zone, func() (interface{}, error) {
+			return noaa.AlertsForZoneContext(s.context(r), zone)
+		})
+		return
+	}
+	lat, lon, err := coordinates(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "zone or "+err.Error())
+		return
+	}
+	s.serve(w, "/alerts?"+lat+","+lon, func() (interface{}, error) {
+		return noaa.AlertsContext(s.context(r), lat, lon)
+	})
+}
+
+// serve writes the cached response for key or fetches, caches and writes it
+func (s *Server) serve(w http.ResponseWriter, key string, fetch func() (interface{}, error)) {
+	e, ok := s.cached(key)
+	if !ok {
+		if !s.allow(w) {
+			return
+		}
+		v, err := fetch()
+		if err != nil {
+			s.writeFetchError(w, err)
+			return
+		}
+		if e.body, err = json.Marshal(v); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.store(key, e)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(e.body)
+}
+
+// serveValue is like serve but caches the forecast value, which is written
+// with the point of the request since the cache entry is shared by all points
+// in the grid cell
+func (s *Server) serveValue(w http.ResponseWriter, key string, point *noaa.PointsResponse, fetch func() (interface{}, error)) {
+	e, ok := s.cached(key)
+	if !ok {
+		if !s.allow(w) {
+			return
+		}
+		v, err := fetch()
+		if err != nil {
+			s.writeFetchError(w, err)
+			return
+		}
+		e.value = v
+		s.store(key, e)
+	}
+	body, err := json.Marshal(withPoint(e.value, point))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// withPoint returns a copy of the forecast with its Point set to point
+func withPoint(v interface{}, point *noaa.PointsResponse) interface{} {
+	switch f := v.(type) {
+	case *noaa.ForecastResponse:
+		c := *f
+		c.Point = point
+		return &c
+	case *noaa.HourlyForecastResponse:
+		c := *f
+		c.Point = point
+		return &c
+	case *noaa.GridpointForecastResponse:
+		c := *f
+		c.Point = point
+		return &c
+	}
+	return v
+}
+
+// allow takes a rate limit token or writes a 429 response
+func (s *Server) allow(w http.ResponseWriter) bool {
+	if wait := s.take(); wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return false
+	}
+	return true
+}
+
+// writeFetchError logs an error returned by weather.gov and writes its
+// response, which does not include the error since it may contain the
+// upstream URLs and responses
+func (s *Server) writeFetchError(w http.ResponseWriter, err error) {
+	if s.opts.ErrorLog != nil {
+		s.opts.ErrorLog.Printf("server: %v", err)
+	} else {
+		log.Printf("server: %v", err)
+	}
+	if errors.Is(err, noaa.ErrDataUnavailable) {
+		w.Header().Set("Retry-After", "60")
+		writeError(w, http.StatusServiceUnavailable, "weather.gov is unavailable")
+		return
+	}
+	writeError(w, http.StatusBadGateway, "weather.gov request failed")
+}
+
+// cached returns the unexpired cached response for key
+func (s *Server) cached(key string) (cacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.cache[key]
+	if !ok || !s.now().Before(e.expires) {
+		delete(s.cache, key)
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+// store caches the response for key, also removing expired entries
+func (s *Server) store(key string, e cacheEntry) {
+	if s.opts.CacheTTL < 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for k, e := range s.cache {
+		if !now.Before(e.expires) {
+			delete(s.cache, k)
+		}
+	}
+	e.expires = now.Add(s.opts.CacheTTL)
+	s.cache[key] = e
+}
+
+// take removes a token from the bucket or returns how long until one is available
+func (s *Server) take() time.Duration {
+	if s.opts.RateLimit < 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.tokens = math.Min(float64(s.opts.Burst), s.tokens+now.Sub(s.updated).Seconds()*s.opts.RateLimit)
+	s.updated = now
+	if s.tokens < 1 {
+		return time.Duration((1 - s.tokens) / s.opts.RateLimit * float64(time.Second))
+	}
+	s.tokens--
+	return 0
+}
+
+// coordinates returns the validated lat and lon query parameters
+func coordinates(r *http.Request) (lat, lon string, err error) {
+	q := r.URL.Query()
+	lat, lon = q.Get("lat"), q.Get("lon")
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil || !finite(latitude) || latitude < -90 || latitude > 90 {
+		return "", "", fmt.Errorf("lat and lon are required")
+	}
+	longitude, err := strconv.ParseFloat(lon, 64)
+	if err != nil || !finite(longitude) || longitude < -180 || longitude > 180 {
+		return "", "", fmt.Errorf("lat and lon are required")
+	}
+	// the API redirects requests with more than 4 decimal places
+	return formatCoordinate(latitude), formatCoordinate(longitude), nil
+}
+
+// finite reports whether v is neither NaN nor infinite
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// formatCoordinate rounds the coordinate to 4 decimal places
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
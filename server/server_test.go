@@ -0,0 +1,152 @@
+package server_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/server"
+)
+
+// upstream returns a fake weather.gov API counting the requests it receives
+func upstream(t *testing.T, requests *int) *httptest.Server {
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		switch r.URL.Path {
+		case "/points/41.837,-87.685", "/points/41.838,-87.686":
+			fmt.Fprintf(w, `{"@id": "%s%s", "gridId": "LOT", "gridX": 76, "gridY": 73, "forecast": "%s/gridpoints/LOT/76,73/forecast"}`, api.URL, r.URL.Path, api.URL)
+		case "/gridpoints/LOT/76,73/forecast":
+			fmt.Fprint(w, `{"units": "us", "periods": [{"number": 1, "name": "Tonight", "temperature": 59}]}`)
+		case "/alerts/active/zone/ILZ014":
+			fmt.Fprint(w, `{"@graph": [{"id": "urn:oid:1", "event": "Wind Advisory"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(func() {
+		api.Close()
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	return api
+}
+
+func TestForecast(t *testing.T) {
+	var requests int
+	api := upstream(t, &requests)
+	handler := server.New(server.Options{Client: api.Client()})
+
+	for i := 0; i < 2; i++ {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest("GET", "/forecast?lat=41.837&lon=-87.685", nil))
+		if res.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+		}
+		var forecast noaa.ForecastResponse
+		if err := json.Unmarshal(res.Body.Bytes(), &forecast); err != nil || len(forecast.Periods) != 1 {
+			t.Fatalf("unexpected forecast response: %s", res.Body.String())
+		}
+	}
+	if requests != 2 {
+		t.Errorf("the second request should be served from the cache, got %d upstream requests", requests)
+	}
+}
+
+func TestForecastGridCache(t *testing.T) {
+	var requests int
+	api := upstream(t, &requests)
+	handler := server.New(server.Options{Client: api.Client()})
+
+	for _, point := range []string{"lat=41.837&lon=-87.685", "lat=41.838&lon=-87.686"} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest("GET", "/forecast?"+point, nil))
+		if res.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+		}
+		var forecast noaa.ForecastResponse
+		if err := json.Unmarshal(res.Body.Bytes(), &forecast); err != nil || len(forecast.Periods) != 1 || forecast.Point == nil {
+			t.Fatalf("unexpected forecast response: %s", res.Body.String())
+		}
+		if want := "/points/41.838,-87.686"; point == "lat=41.838&lon=-87.686" && !strings.HasSuffix(forecast.Point.ID, want) {
+			t.Errorf("the forecast should include the point of the request, got %s", forecast.Point.ID)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("points in the same grid cell should share the cached forecast, got %d upstream requests", requests)
+	}
+}
+
+func TestPointsExpire(t *testing.T) {
+	var requests int
+	api := upstream(t, &requests)
+	handler := server.New(server.Options{CacheTTL: 50 * time.Millisecond, RateLimit: -1, Client: api.Client()})
+
+	// the noaa package caches the points too, so count its cache lookups:
+	// one to resolve the point and one to fetch the forecast per request
+	noaa.ResetStats()
+	for i := 0; i < 2; i++ {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest("GET", "/forecast?lat=41.837&lon=-87.685", nil))
+		if res.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+		}
+		time.Sleep(60 * time.Millisecond)
+	}
+	if stats := noaa.Stats(); stats.CacheHits+stats.CacheMisses != 4 {
+		t.Errorf("resolved points should expire with the cache TTL, got %d point lookups", stats.CacheHits+stats.CacheMisses)
+	}
+}
+
+func TestAlertsRateLimit(t *testing.T) {
+	var requests int
+	api := upstream(t, &requests)
+	handler := server.New(server.Options{CacheTTL: -1, RateLimit: 0.001, Burst: 1, Client: api.Client()})
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest("GET", "/alerts?zone=ILZ014", nil))
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest("GET", "/alerts?zone=ILZ014", nil))
+	if res.Code != http.StatusTooManyRequests || res.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 429 with Retry-After, got %d", res.Code)
+	}
+}
+
+func TestUpstreamError(t *testing.T) {
+	var requests int
+	api := upstream(t, &requests)
+	var logged strings.Builder
+	handler := server.New(server.Options{Client: api.Client(), ErrorLog: log.New(&logged, "", 0)})
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest("GET", "/alerts?zone=ILZ015", nil))
+	if res.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", res.Code, res.Body.String())
+	}
+	if body := res.Body.String(); strings.Contains(body, api.URL) || strings.Contains(body, "404") {
+		t.Errorf("unexpected upstream details in the response: %s", body)
+	}
+	if !strings.Contains(logged.String(), "/alerts/active/zone/ILZ015") {
+		t.Errorf("expected the upstream error to be logged, got %q", logged.String())
+	}
+}
+
+func TestBadRequest(t *testing.T) {
+	handler := server.New(server.Options{})
+	for _, u := range []string{"/forecast", "/forecast?lat=91&lon=0", "/forecast?lat=NaN&lon=0", "/alerts?lat=0&lon=-Inf", "/alerts?zone=bad"} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest("GET", u, nil))
+		if res.Code != http.StatusBadRequest {
+			t.Errorf("%s should return 400, got %d", u, res.Code)
+		}
+	}
+}
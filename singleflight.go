@@ -0,0 +1,91 @@
+package noaa
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// call is an in-flight or completed request shared by concurrent callers
+type call struct {
+	done    chan struct{} // closed when val and err are set
+	val     interface{}
+	err     error
+	waiters int                // callers waiting for the result, guarded by flights
+	cancel  context.CancelFunc // cancels the request once no caller waits for it
+}
+
+// flights holds the in-flight requests by key
+var flights = struct {
+	sync.Mutex
+	calls map[string]*call
+}{calls: map[string]*call{}}
+
+// shared calls fn once for concurrent callers with the same key, typically
+// the endpoint, and returns its result to all of them. This collapses the
+// identical requests made when many goroutines start at once into a single
+// upstream call. Callers receive the same value, so pointers returned by the
+// fetch functions must not be modified.
+//
+// fn runs with the values of the context of the first caller, ex. its
+// correlation ID, but not its deadline or cancellation: a caller whose context
+// ends returns the context error while the others keep waiting, and the
+// request is cancelled once every caller has returned. A panic of fn is
+// returned to the callers as an error.
+func shared[T any](ctx context.Context, key string, fn func(context.Context) (T, error)) (T, error) {
+	flights.Lock()
+	c, ok := flights.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(detachedContext{ctx})
+		c = &call{done: make(chan struct{}), cancel: cancel}
+		flights.calls[key] = c
+		go c.run(callCtx, key, func(ctx context.Context) (interface{}, error) { return fn(ctx) })
+	}
+	c.waiters++
+	flights.Unlock()
+
+	select {
+	case <-c.done:
+		v, _ := c.val.(T)
+		return v, c.err
+	case <-ctx.Done():
+		flights.Lock()
+		if c.waiters--; c.waiters == 0 {
+			// later callers start a new request instead of joining this one
+			if flights.calls[key] == c {
+				delete(flights.calls, key)
+			}
+			c.cancel()
+		}
+		flights.Unlock()
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// run calls fn and records its result, or its panic as an error
+func (c *call) run(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) {
+	defer func() {
+		if p := recover(); p != nil {
+			c.val, c.err = nil, fmt.Errorf("panic requesting %s: %v", key, p)
+		}
+		flights.Lock()
+		if flights.calls[key] == c {
+			delete(flights.calls, key)
+		}
+		flights.Unlock()
+		c.cancel()
+		close(c.done)
+	}()
+	c.val, c.err = fn(ctx)
+}
+
+// detachedContext has the values of its parent but is never done, like
+// context.WithoutCancel of Go 1.21
+type detachedContext struct{ parent context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (d detachedContext) Value(key interface{}) interface{} { return d.parent.Value(key) }
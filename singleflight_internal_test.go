@@ -0,0 +1,19 @@
+//go:build !examples
+// +build !examples
+
+package noaa
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSharedPanic(t *testing.T) {
+	v, err := shared(context.Background(), "panic", func(context.Context) (*PointsResponse, error) {
+		panic("boom")
+	})
+	if v != nil || err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("shared() should return the panic of the request as an error, got %v, %v", v, err)
+	}
+}
@@ -0,0 +1,95 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestConcurrentRequestsAreShared(t *testing.T) {
+	var points, forecasts int32
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		switch r.URL.Path {
+		case "/points/41.837,-87.685":
+			atomic.AddInt32(&points, 1)
+			fmt.Fprintf(w, `{"forecast": "%s/gridpoints/LOT/76,73/forecast"}`, api.URL)
+		case "/gridpoints/LOT/76,73/forecast":
+			atomic.AddInt32(&forecasts, 1)
+			fmt.Fprint(w, `{"periods": [{"number": 1, "name": "Tonight"}]}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			forecast, err := noaa.Forecast("41.837", "-87.685")
+			if err != nil || len(forecast.Periods) != 1 {
+				t.Errorf("noaa.Forecast() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if points != 1 || forecasts != 1 {
+		t.Errorf("concurrent noaa.Forecast() calls should make 1 request per endpoint, got %d points and %d forecast requests", points, forecasts)
+	}
+}
+
+func TestSharedRequestOutlivesCancelledCaller(t *testing.T) {
+	var api *httptest.Server
+	api = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		switch r.URL.Path {
+		case "/points/41.9,-87.6":
+			fmt.Fprintf(w, `{"forecast": "%s/gridpoints/LOT/77,74/forecast"}`, api.URL)
+		case "/gridpoints/LOT/77,74/forecast":
+			fmt.Fprint(w, `{"periods": [{"number": 1, "name": "Tonight"}]}`)
+		}
+	}))
+	client := http.DefaultClient
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient = client
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := noaa.ForecastContext(ctx, "41.9", "-87.6", "")
+		errs <- err
+	}()
+	time.Sleep(5 * time.Millisecond) // join the request of the first caller
+	forecast, err := noaa.ForecastContext(context.Background(), "41.9", "-87.6", "")
+	if err != nil || len(forecast.Periods) != 1 {
+		t.Errorf("noaa.ForecastContext() should not fail when another caller sharing the request is cancelled, got %v", err)
+	}
+	if err := <-errs; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("noaa.ForecastContext() should return the error of its own context, got %v", err)
+	}
+}
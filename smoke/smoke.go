@@ -0,0 +1,216 @@
+// Package smoke retrieves near-surface smoke guidance for a point, ex. from
+// the HRRR-Smoke model, and aligns it with the visibility and dispersion
+// series of the gridpoint forecast, for air quality during wildfire season:
+//
+//	src := smoke.NOMADS{Decode: decodeGRIB2} // or any Source
+//	hours, err := smoke.Forecast(ctx, src, "45.52", "-122.68")
+//	...
+//	for _, h := range hours {
+//		fmt.Println(h.Time, h.Smoke, smoke.Category(h.Smoke), h.Visibility)
+//	}
+//
+// weather.gov does not serve smoke guidance. NOMADS serves the HRRR-Smoke
+// fields in GRIB2, which needs a GRIB2 decoder outside of this module, see
+// NOMADS.Decode. Other providers can be used by implementing Source.
+package smoke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// Concentration is the smoke concentration near the surface at a time, in
+// µg/m³.
+type Concentration struct {
+	Time  time.Time
+	Value float64
+}
+
+// Source provides near-surface smoke guidance.
+type Source interface {
+	// Smoke returns the hourly smoke concentrations forecast at <lat,lon>,
+	// sorted by time.
+	Smoke(ctx context.Context, lat float64, lon float64) ([]Concentration, error)
+}
+
+// ErrNoDecoder is returned by NOMADS without a Decode function.
+var ErrNoDecoder = errors.New("smoke: no GRIB2 decoder")
+
+// NOMADSURL is the URL of the NOMADS HRRR grib filter.
+var NOMADSURL = "https://nomads.ncep.noaa.gov/cgi-bin/filter_hrrr_2d.pl"
+
+// NOMADS is a Source fetching the MASSDEN field 8 m above ground of the HRRR
+// from NOMADS, cut to a small region around the point.
+type NOMADS struct {
+	// Decode returns the value of the GRIB2 field nearest to <lat,lon>, in
+	// kg/m³ as in the HRRR. Required.
+	Decode func(r io.Reader, lat float64, lon float64) (float64, error)
+
+	// Cycle is the run of the model to use. If zero, the run of 2 hours ago
+	// is used, the latest which is complete most of the time.
+	Cycle time.Time
+
+	// Hours is the number of forecast hours, 18 if 0. Runs at 00, 06, 12 and
+	// 18 UTC go up to 48 hours.
+	Hours int
+}
+
+// Smoke implements Source.
+func (n NOMADS) Smoke(ctx context.Context, lat float64, lon float64) ([]Concentration, error) {
+	if n.Decode == nil {
+		return nil, ErrNoDecoder
+	}
+	cycle := n.Cycle.UTC().Truncate(time.Hour)
+	if n.Cycle.IsZero() {
+		cycle = time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Hour)
+	}
+	hours := n.Hours
+	if hours <= 0 {
+		hours = 18
+	}
+	concentrations := make([]Concentration, 0, hours)
+	for hour := 1; hour <= hours; hour++ {
+		value, err := n.fetch(ctx, cycle, hour, lat, lon)
+		if err != nil {
+			return nil, err
+		}
+		concentrations = append(concentrations, Concentration{Time: cycle.Add(time.Duration(hour) * time.Hour), Value: value * 1e9})
+	}
+	return concentrations, nil
+}
+
+// fetch returns the concentration in kg/m³ of a forecast hour of a run
+func (n NOMADS) fetch(ctx context.Context, cycle time.Time, hour int, lat float64, lon float64) (float64, error) {
+	query := url.Values{}
+	query.Set("dir", "/hrrr."+cycle.Format("20060102")+"/conus")
+	query.Set("file", fmt.Sprintf("hrrr.t%02dz.wrfsfcf%02d.grib2", cycle.Hour(), hour))
+	query.Set("var_MASSDEN", "on")
+	query.Set("lev_8_m_above_ground", "on")
+	query.Set("subregion", "")
+	query.Set("toplat", fmt.Sprint(lat+0.1))
+	query.Set("bottomlat", fmt.Sprint(lat-0.1))
+	query.Set("leftlon", fmt.Sprint(lon-0.1))
+	query.Set("rightlon", fmt.Sprint(lon+0.1))
+	req, err := http.NewRequestWithContext(ctx, "GET", NOMADSURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", noaa.GetConfig().UserAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("smoke: %s f%02d: %s", cycle.Format("2006010215"), hour, res.Status)
+	}
+	value, err := n.Decode(res.Body, lat, lon)
+	if err != nil {
+		return 0, fmt.Errorf("smoke: %s f%02d: %w", cycle.Format("2006010215"), hour, err)
+	}
+	return value, nil
+}
+
+// Hour is the smoke and gridpoint forecast of an hour, see Align. Values
+// missing from the forecast are NaN.
+type Hour struct {
+	Time       time.Time
+	Smoke      float64 // µg/m³
+	Visibility float64 // in the unit of the Visibility series, usually m
+	Dispersion float64 // dispersion index, higher values clear smoke faster
+}
+
+// Forecast returns the smoke forecast of the source for <lat,lon> aligned with
+// its gridpoint forecast, see Align.
+func Forecast(ctx context.Context, src Source, lat string, lon string) ([]Hour, error) {
+	c, err := noaa.ParseCoordinates(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	concentrations, err := src.Smoke(ctx, c.Lat, c.Lon)
+	if err != nil {
+		return nil, err
+	}
+	forecast, err := noaa.GridpointForecast(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	return Align(forecast, concentrations), nil
+}
+
+// Align returns the concentrations with the visibility and dispersion index
+// of the gridpoint forecast at their time. The dispersion index is the
+// DispersionIndex series, or the AtmosphericDispersionIndex when missing.
+func Align(forecast *noaa.GridpointForecastResponse, concentrations []Concentration) []Hour {
+	at := func(s noaa.GridpointForecastTimeSeries, t time.Time) float64 {
+		if v, ok := s.At(t); ok {
+			return v
+		}
+		return math.NaN()
+	}
+	hours := make([]Hour, len(concentrations))
+	for i, c := range concentrations {
+		h := Hour{Time: c.Time, Smoke: c.Value, Visibility: at(forecast.Visibility, c.Time), Dispersion: at(forecast.DispersionIndex, c.Time)}
+		if math.IsNaN(h.Dispersion) {
+			h.Dispersion = at(forecast.AtmosphericDispersionIndex, c.Time)
+		}
+		hours[i] = h
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Time.Before(hours[j].Time) })
+	return hours
+}
+
+// Category returns the AQI category of a fine particulate (PM2.5)
+// concentration in µg/m³, ex. Unhealthy for Sensitive Groups. Smoke is mostly
+// fine particulates; the AQI uses 24 hour averages, so hourly values only
+// approximate it.
+func Category(value float64) string {
+	switch {
+	case value <= 9:
+		return "Good"
+	case value <= 35.4:
+		return "Moderate"
+	case value <= 55.4:
+		return "Unhealthy for Sensitive Groups"
+	case value <= 125.4:
+		return "Unhealthy"
+	case value <= 225.4:
+		return "Very Unhealthy"
+	}
+	return "Hazardous"
+}
+
+// Provider is a noaa.SupplementaryDataProvider of the smoke forecast of a
+// Source, named smoke, with a single series named smoke.surface in µg/m³.
+type Provider struct {
+	Source Source
+}
+
+// Name returns smoke.
+func (Provider) Name() string { return "smoke" }
+
+// Series returns the smoke forecast of <lat,lon>.
+func (p Provider) Series(ctx context.Context, lat string, lon string) ([]noaa.SupplementarySeries, error) {
+	c, err := noaa.ParseCoordinates(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	concentrations, err := p.Source.Smoke(ctx, c.Lat, c.Lon)
+	if err != nil {
+		return nil, err
+	}
+	series := noaa.SupplementarySeries{Name: "smoke.surface", Unit: "µg/m3", Values: make([]noaa.SupplementaryValue, len(concentrations))}
+	for i, c := range concentrations {
+		series.Values[i] = noaa.SupplementaryValue{Time: c.Time, Value: c.Value, Category: Category(c.Value)}
+	}
+	return []noaa.SupplementarySeries{series}, nil
+}
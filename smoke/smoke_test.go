@@ -0,0 +1,95 @@
+package smoke_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/smoke"
+)
+
+func TestNOMADS(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("dir") != "/hrrr.20230607/conus" || q.Get("var_MASSDEN") != "on" || q.Get("toplat") == "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		// the fake GRIB2 file is the forecast hour
+		fmt.Fprint(w, strings.TrimSuffix(strings.TrimPrefix(q.Get("file"), "hrrr.t12z.wrfsfcf"), ".grib2"))
+	}))
+	client, nomadsURL := http.DefaultClient, smoke.NOMADSURL
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient, smoke.NOMADSURL = client, nomadsURL
+	})
+	smoke.NOMADSURL = api.URL
+
+	cycle := time.Date(2023, 6, 7, 12, 0, 0, 0, time.UTC)
+	src := smoke.NOMADS{Cycle: cycle, Hours: 3, Decode: func(r io.Reader, lat, lon float64) (float64, error) {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+		hour, err := strconv.Atoi(string(data))
+		return float64(hour) * 50e-9, err
+	}}
+	concentrations, err := src.Smoke(context.Background(), 40.7, -74)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(concentrations) != 3 || !concentrations[0].Time.Equal(cycle.Add(time.Hour)) || math.Abs(concentrations[2].Value-150) > 1e-9 {
+		t.Errorf("expected 3 hours of concentrations in µg/m³, got %+v", concentrations)
+	}
+
+	if _, err := (smoke.NOMADS{}).Smoke(context.Background(), 40.7, -74); !errors.Is(err, smoke.ErrNoDecoder) {
+		t.Errorf("expected ErrNoDecoder without a decoder, got %v", err)
+	}
+	src.Cycle = cycle.Add(24 * time.Hour)
+	if _, err := src.Smoke(context.Background(), 40.7, -74); err == nil {
+		t.Error("expected an error for a run NOMADS does not have")
+	}
+}
+
+// fixed is a Source returning fixed concentrations
+type fixed []smoke.Concentration
+
+func (f fixed) Smoke(ctx context.Context, lat float64, lon float64) ([]smoke.Concentration, error) {
+	return f, nil
+}
+
+func TestAlign(t *testing.T) {
+	at := time.Date(2023, 6, 7, 18, 0, 0, 0, time.UTC)
+	forecast := &noaa.GridpointForecastResponse{
+		Visibility:                 noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:m", Values: []noaa.GridpointForecastTimeSeriesValue{{ValidTime: "2023-06-07T18:00:00+00:00/PT1H", Value: 1600}}},
+		AtmosphericDispersionIndex: noaa.GridpointForecastTimeSeries{Values: []noaa.GridpointForecastTimeSeriesValue{{ValidTime: "2023-06-07T12:00:00+00:00/PT12H", Value: 25}}},
+	}
+	hours := smoke.Align(forecast, []smoke.Concentration{{Time: at.Add(time.Hour), Value: 80}, {Time: at, Value: 140}})
+	if len(hours) != 2 || !hours[0].Time.Equal(at) || hours[0].Visibility != 1600 || hours[0].Dispersion != 25 {
+		t.Errorf("expected the hours in order with the gridpoint values, got %+v", hours)
+	}
+	if !math.IsNaN(hours[1].Visibility) || hours[1].Dispersion != 25 {
+		t.Errorf("expected NaN for values missing from the forecast, got %+v", hours[1])
+	}
+	if smoke.Category(hours[0].Smoke) != "Very Unhealthy" || smoke.Category(5) != "Good" || smoke.Category(300) != "Hazardous" {
+		t.Errorf("expected the AQI category, got %s", smoke.Category(hours[0].Smoke))
+	}
+
+	series, err := smoke.Provider{Source: fixed{{Time: at, Value: 40}}}.Series(context.Background(), "40.7", "-74")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0].Values[0].Value != 40 || series[0].Values[0].Category != "Unhealthy for Sensitive Groups" {
+		t.Errorf("expected the provider to return the concentrations as a series, got %+v", series)
+	}
+}
@@ -0,0 +1,96 @@
+package noaa
+
+import (
+	"math"
+	"time"
+)
+
+// lapseRate is the standard decrease of the temperature with height, in °C
+// per meter
+const lapseRate = 0.0065
+
+// ElevationHour is the precipitation expected during an hour at an elevation
+// of a grid cell, see GridpointForecastResponse.AtElevation.
+type ElevationHour struct {
+	Time        time.Time
+	Type        PrecipitationType // PrecipitationNone if no precipitation is expected
+	SnowLevel   float64           // m, NaN if not forecast
+	Temperature float64           // °C at the elevation
+}
+
+// Snow reports whether snow is expected, alone or mixed with rain.
+func (h ElevationHour) Snow() bool {
+	return h.Type == PrecipitationSnow || h.Type == PrecipitationRainAndSnow
+}
+
+// SnowAtElevation returns the precipitation expected each hour at an
+// elevation in meters of the grid cell of <lat,lon>, ex. a mountain pass,
+// see GridpointForecastResponse.AtElevation.
+func SnowAtElevation(lat string, lon string, elevation float64) ([]ElevationHour, error) {
+	forecast, err := GridpointForecast(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	return forecast.AtElevation(elevation), nil
+}
+
+// AtElevation returns the precipitation expected each hour of the Temperature
+// series at an elevation in meters, for points whose elevation differs from
+// the mean elevation of their grid cell, such as mountain passes. Whether
+// precipitation falls is taken from the Weather layer, see
+// PrecipitationTimeline, and its type at the elevation from the SnowLevel
+// series:
+//
+//   - snow 150 m or more above the snow level
+//   - rain 150 m or more below it, and a rain and snow mix in between
+//
+// Without a snow level, the temperature of the grid cell is lowered by the
+// standard lapse rate of 6.5 °C per km to the elevation: snow at or below 0
+// °C, rain at or above 2 °C, and a mix in between. Freezing rain and sleet,
+// which come from warm layers aloft, are kept as forecast for the grid cell.
+func (g *GridpointForecastResponse) AtElevation(elevation float64) []ElevationHour {
+	cell := g.Elevation.Value
+	switch {
+	case g.Elevation.Units == "":
+		cell = elevation // unknown, the temperature is not adjusted
+	case UnitName(g.Elevation.Units) == "ft":
+		cell *= 0.3048
+	}
+	timeline := g.PrecipitationTimeline()
+	levels := g.SnowLevel.Series()
+	temperatures := ConvertSeries(g.Temperature.Series(), LocaleSI).Resample(time.Hour)
+
+	hours := make([]ElevationHour, 0, len(temperatures.Values))
+	for _, v := range temperatures.Values {
+		h := ElevationHour{Time: v.Start, SnowLevel: math.NaN(), Temperature: v.Value - (elevation-cell)*lapseRate}
+		if level, ok := levels.At(v.Start); ok {
+			if UnitName(levels.Unit) == "ft" {
+				level *= 0.3048
+			}
+			h.SnowLevel = level
+		}
+		for _, p := range timeline {
+			if !v.Start.Before(p.Start) && v.Start.Before(p.End) {
+				h.Type = p.Type
+				break
+			}
+		}
+		switch {
+		case h.Type == PrecipitationNone || h.Type == PrecipitationSleet || h.Type == PrecipitationFreezingRain:
+		case !math.IsNaN(h.SnowLevel) && elevation >= h.SnowLevel+snowLevelMargin:
+			h.Type = PrecipitationSnow
+		case !math.IsNaN(h.SnowLevel) && elevation <= h.SnowLevel-snowLevelMargin:
+			h.Type = PrecipitationRain
+		case !math.IsNaN(h.SnowLevel):
+			h.Type = PrecipitationRainAndSnow
+		case h.Temperature <= 0:
+			h.Type = PrecipitationSnow
+		case h.Temperature >= 2:
+			h.Type = PrecipitationRain
+		default:
+			h.Type = PrecipitationRainAndSnow
+		}
+		hours = append(hours, h)
+	}
+	return hours
+}
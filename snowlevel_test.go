@@ -0,0 +1,54 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestAtElevation(t *testing.T) {
+	g := &noaa.GridpointForecastResponse{
+		Elevation: noaa.ForecastElevation{Value: 1000, Units: "wmoUnit:m"},
+		Temperature: noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:degC", Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-12-01T00:00:00+00:00/PT7H", Value: 8},
+		}},
+		SnowLevel: noaa.GridpointForecastTimeSeries{Uom: "wmoUnit:m", Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-12-01T00:00:00+00:00/PT2H", Value: 1500},
+			{ValidTime: "2023-12-01T02:00:00+00:00/PT1H", Value: 1950},
+			{ValidTime: "2023-12-01T03:00:00+00:00/PT1H", Value: 2500},
+		}},
+		Weather: noaa.Weather{Values: []noaa.WeatherValue{
+			{ValidTime: "2023-12-01T00:00:00+00:00/PT5H", Value: []noaa.WeatherValueItem{{Coverage: "likely", Weather: "rain"}}},
+			{ValidTime: "2023-12-01T05:00:00+00:00/PT1H", Value: []noaa.WeatherValueItem{{Coverage: "chance", Weather: "freezing_rain"}}},
+		}},
+	}
+	hours := g.AtElevation(2000)
+	want := []noaa.PrecipitationType{
+		noaa.PrecipitationSnow, // well above the snow level
+		noaa.PrecipitationSnow,
+		noaa.PrecipitationRainAndSnow, // near the snow level
+		noaa.PrecipitationRain,        // well below the snow level
+		noaa.PrecipitationRainAndSnow, // no snow level, 1.5 °C at the elevation
+		noaa.PrecipitationFreezingRain,
+		noaa.PrecipitationNone,
+	}
+	if len(hours) != len(want) {
+		t.Fatalf("noaa.GridpointForecastResponse.AtElevation() should return each hour of the temperature series, got %+v", hours)
+	}
+	start := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	for i, h := range hours {
+		if !h.Time.Equal(start.Add(time.Duration(i)*time.Hour)) || h.Type != want[i] {
+			t.Errorf("noaa.GridpointForecastResponse.AtElevation() hour %d should be %s, got %+v", i, want[i], h)
+		}
+	}
+	if hours[0].Temperature != 1.5 || hours[0].SnowLevel != 1500 || !hours[0].Snow() || hours[3].Snow() {
+		t.Errorf("noaa.GridpointForecastResponse.AtElevation() should adjust the temperature to the elevation, got %+v", hours[0])
+	}
+	if hours := g.AtElevation(1000); hours[0].Type != noaa.PrecipitationRain || hours[0].Temperature != 8 {
+		t.Errorf("noaa.GridpointForecastResponse.AtElevation() should be rain below the snow level, got %+v", hours[0])
+	}
+}
@@ -0,0 +1,86 @@
+package noaa
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrUnsupportedStation is returned for station IDs of networks whose
+// observations weather.gov does not serve, see ValidateStationID.
+var ErrUnsupportedStation = errors.New("unsupported station")
+
+// StationType is the network of a station, see ClassifyStation.
+type StationType int
+
+const (
+	// StationOther is a station of another network, ex. the CWOP station
+	// D6459, which weather.gov may serve
+	StationOther StationType = iota
+	// StationMETAR is an airport station reporting METARs, identified by its
+	// ICAO ID, ex. KORD or PANC
+	StationMETAR
+	// StationCMAN is a Coastal-Marine Automated Network station, ex. BUZM3.
+	// Other stations with a five character NWS location ID share the format.
+	StationCMAN
+	// StationBuoy is a National Data Buoy Center buoy, ex. 41002
+	StationBuoy
+	// StationCOOP is a Cooperative Observer Program station, ex. 110338
+	StationCOOP
+)
+
+var stationTypeNames = []string{"other", "metar", "cman", "buoy", "coop"}
+
+func (t StationType) String() string { return codeName(stationTypeNames, int(t)) }
+
+// Supported reports whether weather.gov serves the observations of stations of
+// the type.
+func (t StationType) Supported() bool {
+	return t != StationBuoy && t != StationCOOP
+}
+
+var (
+	metarStationPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	cmanStationPattern  = regexp.MustCompile(`^[A-Z]{4}[0-9]$`)
+	buoyStationPattern  = regexp.MustCompile(`^[0-9]{2}[A-Z0-9][0-9]{2}$`)
+	coopStationPattern  = regexp.MustCompile(`^[0-9]{6}$`)
+)
+
+// ClassifyStation returns the network of a station ID, in any case, from its
+// format.
+func ClassifyStation(id string) StationType {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	switch {
+	case metarStationPattern.MatchString(id):
+		return StationMETAR
+	case cmanStationPattern.MatchString(id):
+		return StationCMAN
+	case buoyStationPattern.MatchString(id):
+		return StationBuoy
+	case coopStationPattern.MatchString(id):
+		return StationCOOP
+	}
+	return StationOther
+}
+
+// ValidateStationID returns an error wrapping ErrUnsupportedStation for the
+// IDs of buoys and COOP stations, whose observations are published by the
+// National Data Buoy Center and NCEI rather than weather.gov, and for blank
+// IDs. Station URLs are validated by their ID.
+func ValidateStationID(id string) error {
+	if strings.Contains(id, "://") {
+		id = StationID(id)
+	}
+	id = strings.ToUpper(strings.TrimSpace(id))
+	switch t := ClassifyStation(id); {
+	case id == "":
+		return fmt.Errorf("%w: blank station ID", ErrUnsupportedStation)
+	case t == StationBuoy:
+		return fmt.Errorf("%w: %s is an NDBC buoy, see https://www.ndbc.noaa.gov/station_page.php?station=%s",
+			ErrUnsupportedStation, id, strings.ToLower(id))
+	case t == StationCOOP:
+		return fmt.Errorf("%w: %s is a COOP station, see https://www.ncei.noaa.gov", ErrUnsupportedStation, id)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestClassifyStation(t *testing.T) {
+	for id, want := range map[string]noaa.StationType{
+		"KORD":   noaa.StationMETAR,
+		"panc":   noaa.StationMETAR,
+		"BUZM3":  noaa.StationCMAN,
+		"41002":  noaa.StationBuoy,
+		"46A35":  noaa.StationBuoy,
+		"110338": noaa.StationCOOP,
+		"D6459":  noaa.StationOther,
+	} {
+		if got := noaa.ClassifyStation(id); got != want {
+			t.Errorf("noaa.ClassifyStation(%q) should return %v, got %v", id, want, got)
+		}
+	}
+}
+
+func TestValidateStationID(t *testing.T) {
+	for _, id := range []string{"KORD", "BUZM3", "D6459", "https://api.weather.gov/stations/KORD"} {
+		if err := noaa.ValidateStationID(id); err != nil {
+			t.Errorf("noaa.ValidateStationID(%q) should accept the station, got %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "41002", "110338", "https://api.weather.gov/stations/41002"} {
+		if err := noaa.ValidateStationID(id); !errors.Is(err, noaa.ErrUnsupportedStation) {
+			t.Errorf("noaa.ValidateStationID(%q) should return ErrUnsupportedStation, got %v", id, err)
+		}
+	}
+	if err := noaa.ValidateStationID("41002"); !strings.Contains(err.Error(), "ndbc.noaa.gov") {
+		t.Errorf("noaa.ValidateStationID() should point buoys to NDBC, got %v", err)
+	}
+}
+
+func TestStationObservationsUnsupported(t *testing.T) {
+	fakeAPI(t, map[string]string{"/stations/41002/observations": `{"@graph": []}`})
+	noaa.ResetStats()
+
+	if _, err := noaa.StationObservations("41002", noaa.ObservationsOptions{}); !errors.Is(err, noaa.ErrUnsupportedStation) {
+		t.Errorf("noaa.StationObservations() should reject buoys, got %v", err)
+	}
+	if _, err := noaa.StationObservationAt("110338", time.Now()); !errors.Is(err, noaa.ErrUnsupportedStation) {
+		t.Errorf("noaa.StationObservationAt() should reject COOP stations, got %v", err)
+	}
+	if requests := noaa.Stats().Requests; requests != 0 {
+		t.Errorf("noaa.StationObservations() should not request unsupported stations, got %d requests", requests)
+	}
+}
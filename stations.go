@@ -0,0 +1,58 @@
+package noaa
+
+import (
+	"sort"
+)
+
+// Station is an observation station with its distance and bearing from a
+// point, see NearestStations.
+type Station struct {
+	ID        string // ex. KMDW
+	URL       string
+	Name      string
+	TimeZone  string
+	Elevation ForecastElevation
+	Location  Coordinates
+	Distance  float64 // meters
+	Bearing   float64 // degrees clockwise from true north, from the point to the station
+}
+
+// NearestStations returns up to n observation stations of the point's grid
+// sorted by great-circle distance, nearest first. A negative or zero n returns
+// all stations. Stations without a location in the response are skipped.
+func NearestStations(lat string, lon string, n int) ([]Station, error) {
+	origin, err := ParseCoordinates(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	response, err := Stations(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	var stations []Station
+	for _, g := range response.graph {
+		location, err := g.location()
+		if err != nil {
+			continue
+		}
+		id := g.ID
+		if id == "" {
+			id = StationID(g.URI)
+		}
+		stations = append(stations, Station{
+			ID:        id,
+			URL:       g.URI,
+			Name:      g.Name,
+			TimeZone:  g.TimeZone,
+			Elevation: g.Elevation,
+			Location:  location,
+			Distance:  origin.DistanceTo(location),
+			Bearing:   origin.BearingTo(location),
+		})
+	}
+	sort.SliceStable(stations, func(i, j int) bool { return stations[i].Distance < stations[j].Distance })
+	if n > 0 && len(stations) > n {
+		stations = stations[:n]
+	}
+	return stations, nil
+}
@@ -0,0 +1,99 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestStationsEntries(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685": `{"observationStations": "{api}/gridpoints/LOT/76,73/stations"}`,
+		"/points/41.9,-87.685":   `{"observationStations": "{api}/gridpoints/LOT/76,73/stations"}`,
+		"/gridpoints/LOT/76,73/stations": `{
+			"@graph": [
+				{"@id": "{api}/stations/KMDW", "stationIdentifier": "KMDW", "name": "Chicago Midway Airport", "geometry": "POINT(-87.75222 41.78417)"},
+				{"@id": "{api}/stations/KORD", "stationIdentifier": "KORD", "name": "Chicago O'Hare International Airport", "geometry": "POINT(-87.93444 41.96)"}
+			],
+			"observationStations": ["{api}/stations/KMDW", "{api}/stations/KORD", "{api}/stations/KLOT"]
+		}`,
+	})
+
+	stations, err := noaa.Stations("41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := stations.StationIDs(); !reflect.DeepEqual(ids, []string{"KMDW", "KORD", "KLOT"}) {
+		t.Errorf("StationsResponse.StationIDs() should return the station IDs, got %v", ids)
+	}
+	if len(stations.Entries) != 3 {
+		t.Fatalf("noaa.Stations() should return 3 entries, got %d", len(stations.Entries))
+	}
+	mdw := stations.Entries[0]
+	if mdw.ID != "KMDW" || mdw.URL != stations.Stations[0] || mdw.Name != "Chicago Midway Airport" {
+		t.Errorf("noaa.Stations() should return the station ID, URL and name, got %+v", mdw)
+	}
+	if mdw.Distance == nil || math.Abs(*mdw.Distance-8096) > 10 {
+		t.Errorf("noaa.Stations() should return the distance to KMDW, got %v", mdw.Distance)
+	}
+	if stations.Entries[2].ID != "KLOT" || stations.Entries[2].Distance != nil {
+		t.Errorf("noaa.Stations() should not return a distance without geometry, got %+v", stations.Entries[2])
+	}
+
+	// the stations of the grid cell are shared, but not the distances
+	other, err := noaa.Stations("41.9", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *other.Entries[0].Distance == *mdw.Distance {
+		t.Error("noaa.Stations() should return distances from the point passed in")
+	}
+}
+
+func TestCoordinatesDistanceTo(t *testing.T) {
+	ord := noaa.Coordinates{Lat: 41.9786, Lon: -87.9048}
+	lax := noaa.Coordinates{Lat: 33.9425, Lon: -118.4081}
+	if d := ord.DistanceTo(lax); math.Abs(d-2802000) > 5000 {
+		t.Errorf("Coordinates.DistanceTo() should return the great-circle distance, got %f", d)
+	}
+	if _, err := noaa.ParseCoordinates("41.8", "west"); err == nil {
+		t.Error("noaa.ParseCoordinates() should return an error for an invalid longitude")
+	}
+}
+
+func TestNearestStations(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685": `{"observationStations": "{api}/gridpoints/LOT/76,73/stations"}`,
+		"/gridpoints/LOT/76,73/stations": `{"type": "FeatureCollection", "features": [
+			{"geometry": {"type": "Point", "coordinates": [-87.93444, 41.96]}, "properties": {"@id": "{api}/stations/KORD", "stationIdentifier": "KORD", "name": "Chicago O'Hare International Airport", "timeZone": "America/Chicago", "elevation": {"unitCode": "wmoUnit:m", "value": 201.8}}},
+			{"geometry": {"type": "Point", "coordinates": [-88.08, 41.5]}, "properties": {"@id": "{api}/stations/KLOT", "stationIdentifier": "KLOT"}},
+			{"geometry": {"type": "Point", "coordinates": [-87.75222, 41.78417]}, "properties": {"@id": "{api}/stations/KMDW", "stationIdentifier": "KMDW"}},
+			{"geometry": null, "properties": {"@id": "{api}/stations/KXYZ", "stationIdentifier": "KXYZ"}}
+		]}`,
+	})
+
+	stations, err := noaa.NearestStations("41.837", "-87.685", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stations) != 2 || stations[0].ID != "KMDW" || stations[1].ID != "KORD" {
+		t.Fatalf("noaa.NearestStations() should return the 2 nearest stations, got %+v", stations)
+	}
+	ord := stations[1]
+	if ord.Name != "Chicago O'Hare International Airport" || ord.TimeZone != "America/Chicago" || ord.Elevation.Value != 201.8 {
+		t.Errorf("noaa.NearestStations() should return the station metadata, got %+v", ord)
+	}
+	if math.Abs(ord.Distance-24680) > 500 || ord.Bearing < 290 || ord.Bearing > 310 {
+		t.Errorf("noaa.NearestStations() should return the distance and bearing to KORD, got %f m at %f°", ord.Distance, ord.Bearing)
+	}
+
+	all, err := noaa.NearestStations("41.837", "-87.685", 0)
+	if err != nil || len(all) != 3 {
+		t.Errorf("noaa.NearestStations() should return all stations with a location, got %d, %v", len(all), err)
+	}
+}
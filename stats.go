@@ -0,0 +1,132 @@
+package noaa
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// RequestStats counts the requests made to weather.gov since the process
+// started or ResetStats was called, to audit usage against the rate limits.
+// Retries and redirects are counted as the requests which caused them.
+type RequestStats struct {
+	Requests   int64
+	ByEndpoint map[string]int64 // requests by endpoint, ex. /gridpoints/{id}/{id}/forecast
+	BytesRead  int64            // bytes of response bodies read
+
+	ClientErrors    int64 // 4xx responses
+	ServerErrors    int64 // 5xx responses
+	TransportErrors int64 // requests which failed without a response
+
+	// Lookups of cached points and counties
+	CacheHits   int64
+	CacheMisses int64
+}
+
+// CacheHitRate returns the fraction of cache lookups which were hits, 0 if
+// there were none.
+func (s RequestStats) CacheHitRate() float64 {
+	if s.CacheHits+s.CacheMisses == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.CacheHits+s.CacheMisses)
+}
+
+// stats are the counters returned by Stats
+var stats = struct {
+	sync.Mutex
+	RequestStats
+}{RequestStats: RequestStats{ByEndpoint: map[string]int64{}}}
+
+// Stats returns a copy of the request counters.
+func Stats() RequestStats {
+	stats.Lock()
+	defer stats.Unlock()
+	s := stats.RequestStats
+	s.ByEndpoint = make(map[string]int64, len(stats.ByEndpoint))
+	for k, v := range stats.ByEndpoint {
+		s.ByEndpoint[k] = v
+	}
+	return s
+}
+
+// ResetStats sets the request counters to 0.
+func ResetStats() {
+	stats.Lock()
+	defer stats.Unlock()
+	stats.RequestStats = RequestStats{ByEndpoint: map[string]int64{}}
+}
+
+// countStats changes the counters with fn
+func countStats(fn func(s *RequestStats)) {
+	stats.Lock()
+	defer stats.Unlock()
+	fn(&stats.RequestStats)
+}
+
+// countCache counts a cache lookup
+func countCache(hit bool) {
+	countStats(func(s *RequestStats) {
+		if hit {
+			s.CacheHits++
+		} else {
+			s.CacheMisses++
+		}
+	})
+}
+
+// countResponse counts a request to the endpoint with the response status
+// code, 0 if it failed without a response
+func countResponse(endpoint string, statusCode int) {
+	name := endpointName(endpoint)
+	countStats(func(s *RequestStats) {
+		s.Requests++
+		s.ByEndpoint[name]++
+		switch {
+		case statusCode == 0:
+			s.TransportErrors++
+		case statusCode >= 500:
+			s.ServerErrors++
+		case statusCode >= 400:
+			s.ClientErrors++
+		}
+	})
+}
+
+// endpointWords are the path segments kept by endpointName, the others are
+// IDs
+var endpointWords = map[string]bool{
+	"forecast": true, "hourly": true, "stations": true, "observations": true,
+	"latest": true, "active": true, "zone": true, "area": true, "region": true,
+	"types": true, "locations": true, "count": true, "products": true,
+}
+
+// endpointName returns the path of the endpoint with IDs replaced by {id}, ex.
+// /gridpoints/{id}/{id}/forecast for /gridpoints/LOT/76,73/forecast
+func endpointName(endpoint string) string {
+	path := endpoint
+	if u, err := url.Parse(endpoint); err == nil {
+		path = u.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if i > 0 && !endpointWords[segment] {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// countingBody counts the bytes read from a response body
+type countingBody struct {
+	io.ReadCloser
+}
+
+func (b countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		countStats(func(s *RequestStats) { s.BytesRead += int64(n) })
+	}
+	return n, err
+}
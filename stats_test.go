@@ -0,0 +1,46 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestStats(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.9,-87.6":             `{"forecast": "{api}/gridpoints/LOT/76,73/forecast"}`,
+		"/gridpoints/LOT/76,73/forecast": `{"periods": [{"number": 1, "temperature": 59}]}`,
+	})
+	noaa.ResetStats()
+	for i := 0; i < 2; i++ {
+		if _, err := noaa.Forecast("41.9", "-87.6"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	noaa.Office("NOPE")
+
+	s := noaa.Stats()
+	if s.Requests != 4 {
+		t.Errorf("noaa.Stats() should count 4 requests, got %d", s.Requests)
+	}
+	if s.ByEndpoint["/gridpoints/{id}/{id}/forecast"] != 2 || s.ByEndpoint["/points/{id}"] != 1 || s.ByEndpoint["/offices/{id}"] != 1 {
+		t.Errorf("noaa.Stats() should count requests by endpoint, got %v", s.ByEndpoint)
+	}
+	if s.ClientErrors != 1 || s.ServerErrors != 0 {
+		t.Errorf("noaa.Stats() should count 1 client error, got %d and %d server errors", s.ClientErrors, s.ServerErrors)
+	}
+	if s.BytesRead == 0 {
+		t.Error("noaa.Stats() should count the bytes read")
+	}
+	if s.CacheHits != 1 || s.CacheMisses != 1 || s.CacheHitRate() != 0.5 {
+		t.Errorf("noaa.Stats() should count 1 points cache hit and miss, got %d and %d", s.CacheHits, s.CacheMisses)
+	}
+
+	noaa.ResetStats()
+	if s := noaa.Stats(); s.Requests != 0 || len(s.ByEndpoint) != 0 {
+		t.Errorf("noaa.ResetStats() should reset the counters, got %+v", s)
+	}
+}
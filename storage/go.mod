@@ -0,0 +1,12 @@
+module github.com/chrisdobbins/noaa/storage
+
+go 1.19
+
+require (
+	github.com/chrisdobbins/noaa v0.0.0-20261016200604-450e99af3d61
+	github.com/mattn/go-sqlite3 v1.14.32
+)
+
+// The replacement builds the module against the local checkout. It is ignored
+// when the module is required by other modules, which use the version above.
+replace github.com/chrisdobbins/noaa => ../
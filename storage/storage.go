@@ -0,0 +1,455 @@
+// Package storage archives observations, forecast snapshots and alerts from
+// the noaa package in a SQLite database. For example:
+//
+//	store, err := storage.Open("weather.db")
+//	poller := &noaa.Poller{Locations: locations, ObservationInterval: time.Hour}
+//	store.Attach(poller)
+//	poller.Run(ctx)
+//
+// Rows are upserted so saving the same observation, forecast period or alert
+// twice updates the existing row instead of adding a duplicate. The tables are
+// described by Schema and can be queried with any SQLite client.
+//
+// It is a separate module so that the noaa package does not depend on cgo and
+// the SQLite driver.
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 driver
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// Schema creates the archive tables. Times are stored as RFC 3339 text as
+// returned by weather.gov, observation and forecast values are in the units
+// given by the unit columns and raw holds the complete JSON object.
+//
+// observations has one row per station and observation time.
+// forecasts has one row per location, forecast update time and period, so
+// each forecast snapshot is kept and forecasts can be compared over time.
+// alerts has one row per alert message identifier.
+// alert_events and alert_seen hold the state of the alert trackers, see
+// AlertStore, and watermarks the watermarks of pollers, see SetWatermark.
+const Schema = `
+CREATE TABLE IF NOT EXISTS observations (
+	station           TEXT NOT NULL, -- station URL
+	timestamp         TEXT NOT NULL,
+	temperature       REAL,
+	temperature_unit  TEXT,
+	dewpoint          REAL,
+	relative_humidity REAL,
+	wind_direction    REAL,
+	wind_speed        REAL,
+	wind_gust         REAL,
+	wind_unit         TEXT,
+	pressure          REAL, -- barometric pressure in Pa
+	visibility        REAL, -- in m
+	raw               TEXT NOT NULL,
+	PRIMARY KEY (station, timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS forecasts (
+	location          TEXT NOT NULL, -- name of the location
+	updated           TEXT NOT NULL, -- time the forecast was updated
+	period            INTEGER NOT NULL,
+	name              TEXT,
+	start_time        TEXT,
+	end_time          TEXT,
+	is_daytime        INTEGER,
+	temperature       REAL,
+	temperature_unit  TEXT,
+	wind_speed        TEXT,
+	wind_direction    TEXT,
+	precipitation     REAL, -- probability of precipitation in percent
+	short_forecast    TEXT,
+	detailed_forecast TEXT,
+	raw               TEXT NOT NULL,
+	PRIMARY KEY (location, updated, period)
+);
+
+CREATE TABLE IF NOT EXISTS alerts (
+	id           TEXT PRIMARY KEY,
+	message_type TEXT,
+	event        TEXT,
+	status       TEXT,
+	severity     TEXT,
+	certainty    TEXT,
+	urgency      TEXT,
+	sent         TEXT,
+	effective    TEXT,
+	onset        TEXT,
+	expires      TEXT,
+	ends         TEXT,
+	sender_name  TEXT,
+	headline     TEXT,
+	description  TEXT,
+	instruction  TEXT,
+	raw          TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS alerts_sent ON alerts (sent);
+
+CREATE TABLE IF NOT EXISTS alert_events (
+	tracker TEXT NOT NULL, -- name of the tracker, ex. the location
+	key     TEXT NOT NULL, -- VTEC event ID or first alert identifier
+	raw     TEXT NOT NULL, -- the noaa.AlertEvent
+	PRIMARY KEY (tracker, key)
+);
+
+CREATE TABLE IF NOT EXISTS alert_seen (
+	tracker TEXT NOT NULL,
+	alert   TEXT NOT NULL, -- alert identifier
+	keys    TEXT NOT NULL, -- JSON array of the keys of the events of the alert
+	PRIMARY KEY (tracker, alert)
+);
+
+CREATE TABLE IF NOT EXISTS watermarks (
+	key  TEXT PRIMARY KEY, -- location name and /observations or /alerts
+	time TEXT NOT NULL
+);
+`
+
+// Store writes to an archive database. A Store is safe for concurrent use.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens or creates the SQLite database at path and creates the tables.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New creates the tables in an open SQLite database and returns a Store using it.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(Schema); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// DB returns the underlying database, ex. for queries.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Attach sets the forecast, observation and alert handlers of the poller to
+// save to the store. Existing handlers are still called. Errors are passed to
+// the OnError handler of the poller. The alert events of each location are
+// also kept in the store, see AlertStore, unless the poller has an
+// AlertStore, and so are the watermarks of the poller unless it has
+// Watermarks, so it can backfill the archive after downtime.
+func (s *Store) Attach(p *noaa.Poller) {
+	if p.AlertStore == nil {
+		p.AlertStore = func(loc noaa.Location) (noaa.AlertStore, error) {
+			return s.AlertStore(loc.Name), nil
+		}
+	}
+	if p.Watermarks == nil {
+		p.Watermarks = s
+	}
+	onForecast, onObservation, onAlert := p.OnForecast, p.OnObservation, p.OnAlert
+	report := func(loc noaa.Location, err error) {
+		if err != nil && p.OnError != nil {
+			p.OnError(loc, err)
+		}
+	}
+	p.OnForecast = func(loc noaa.Location, f *noaa.ForecastResponse) {
+		report(loc, s.SaveForecast(loc.Name, f))
+		if onForecast != nil {
+			onForecast(loc, f)
+		}
+	}
+	p.OnObservation = func(loc noaa.Location, o noaa.Observation) {
+		report(loc, s.SaveObservation(o))
+		if onObservation != nil {
+			onObservation(loc, o)
+		}
+	}
+	p.OnAlert = func(loc noaa.Location, e noaa.AlertEvent) {
+		report(loc, s.SaveAlert(e.Current))
+		if onAlert != nil {
+			onAlert(loc, e)
+		}
+	}
+}
+
+// SaveObservation inserts or replaces the observation of the station at its
+// timestamp.
+func (s *Store) SaveObservation(o noaa.Observation) error {
+	raw, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+INSERT INTO observations (station, timestamp, temperature, temperature_unit, dewpoint,
+	relative_humidity, wind_direction, wind_speed, wind_gust, wind_unit, pressure, visibility, raw)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (station, timestamp) DO UPDATE SET
+	temperature = excluded.temperature,
+	temperature_unit = excluded.temperature_unit,
+	dewpoint = excluded.dewpoint,
+	relative_humidity = excluded.relative_humidity,
+	wind_direction = excluded.wind_direction,
+	wind_speed = excluded.wind_speed,
+	wind_gust = excluded.wind_gust,
+	wind_unit = excluded.wind_unit,
+	pressure = excluded.pressure,
+	visibility = excluded.visibility,
+	raw = excluded.raw`,
+		o.Station, o.Timestamp.UTC().Format(time.RFC3339),
+		value(o.Temperature), unit(o.Temperature), value(o.Dewpoint),
+		value(o.RelativeHumidity), value(o.WindDirection), value(o.WindSpeed), value(o.WindGust), unit(o.WindSpeed),
+		value(o.BarometricPressure), value(o.Visibility), string(raw))
+	return err
+}
+
+// SaveForecast inserts or replaces each period of the forecast for the named
+// location. Forecasts with a different update time are kept as separate
+// snapshots.
+func (s *Store) SaveForecast(location string, f *noaa.ForecastResponse) error {
+	if f == nil {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(`
+INSERT INTO forecasts (location, updated, period, name, start_time, end_time, is_daytime,
+	temperature, temperature_unit, wind_speed, wind_direction, precipitation,
+	short_forecast, detailed_forecast, raw)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (location, updated, period) DO UPDATE SET
+	name = excluded.name,
+	start_time = excluded.start_time,
+	end_time = excluded.end_time,
+	is_daytime = excluded.is_daytime,
+	temperature = excluded.temperature,
+	temperature_unit = excluded.temperature_unit,
+	wind_speed = excluded.wind_speed,
+	wind_direction = excluded.wind_direction,
+	precipitation = excluded.precipitation,
+	short_forecast = excluded.short_forecast,
+	detailed_forecast = excluded.detailed_forecast,
+	raw = excluded.raw`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, p := range f.Periods {
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.Exec(location, f.Updated, p.ID, p.Name, p.StartTime, p.EndTime, p.IsDaytime,
+			p.Temperature, p.TemperatureUnit, p.WindSpeed, p.WindDirection,
+			p.ProbabilityOfPrecipitation.Value, p.Summary, p.Details, string(raw))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveAlert inserts or replaces the alert by its identifier.
+func (s *Store) SaveAlert(a noaa.Alert) error {
+	id := a.Identifier
+	if id == "" {
+		id = a.ID
+	}
+	raw, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+INSERT INTO alerts (id, message_type, event, status, severity, certainty, urgency, sent,
+	effective, onset, expires, ends, sender_name, headline, description, instruction, raw)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	message_type = excluded.message_type,
+	event = excluded.event,
+	status = excluded.status,
+	severity = excluded.severity,
+	certainty = excluded.certainty,
+	urgency = excluded.urgency,
+	sent = excluded.sent,
+	effective = excluded.effective,
+	onset = excluded.onset,
+	expires = excluded.expires,
+	ends = excluded.ends,
+	sender_name = excluded.sender_name,
+	headline = excluded.headline,
+	description = excluded.description,
+	instruction = excluded.instruction,
+	raw = excluded.raw`,
+		id, a.MessageType, a.Event, a.Status, a.Severity, a.Certainty, a.Urgency, a.Sent,
+		a.Effective, a.Onset, a.Expires, a.Ends, a.SenderName, a.Headline, a.Description, a.Instruction,
+		string(raw))
+	return err
+}
+
+// Watermark returns the watermark of the key, zero if there is none. It
+// implements noaa.WatermarkStore.
+func (s *Store) Watermark(key string) (time.Time, error) {
+	var t string
+	err := s.db.QueryRow(`SELECT time FROM watermarks WHERE key = ?`, key).Scan(&t)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, t)
+}
+
+// SetWatermark inserts or replaces the watermark of the key. It implements
+// noaa.WatermarkStore.
+func (s *Store) SetWatermark(key string, t time.Time) error {
+	_, err := s.db.Exec(`
+INSERT INTO watermarks (key, time) VALUES (?, ?)
+ON CONFLICT (key) DO UPDATE SET time = excluded.time`, key, t.UTC().Format(time.RFC3339Nano))
+	return err
+}
+
+// AlertStore returns a noaa.AlertStore of the events of the named tracker,
+// ex. the name of a location, so alerts are not reported again after a
+// restart, see noaa.NewPersistentAlertTracker.
+func (s *Store) AlertStore(tracker string) noaa.AlertStore {
+	return alertStore{db: s.db, tracker: tracker}
+}
+
+// alertStore is the noaa.AlertStore of a tracker
+type alertStore struct {
+	db      *sql.DB
+	tracker string
+}
+
+// Load implements noaa.AlertStore.
+func (a alertStore) Load() ([]noaa.AlertEvent, map[string][]string, error) {
+	rows, err := a.db.Query(`SELECT raw FROM alert_events WHERE tracker = ?`, a.tracker)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var events []noaa.AlertEvent
+	for rows.Next() {
+		var raw string
+		var e noaa.AlertEvent
+		if err := rows.Scan(&raw); err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal([]byte(raw), &e); err != nil {
+			return nil, nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err = a.db.Query(`SELECT alert, keys FROM alert_seen WHERE tracker = ?`, a.tracker)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	seen := map[string][]string{}
+	for rows.Next() {
+		var alert, keys string
+		if err := rows.Scan(&alert, &keys); err != nil {
+			return nil, nil, err
+		}
+		var k []string
+		if err := json.Unmarshal([]byte(keys), &k); err != nil {
+			return nil, nil, err
+		}
+		seen[alert] = k
+	}
+	return events, seen, rows.Err()
+}
+
+// Save implements noaa.AlertStore.
+func (a alertStore) Save(events []noaa.AlertEvent, seen map[string][]string) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, e := range events {
+		raw, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+INSERT INTO alert_events (tracker, key, raw) VALUES (?, ?, ?)
+ON CONFLICT (tracker, key) DO UPDATE SET raw = excluded.raw`, a.tracker, e.Key, string(raw))
+		if err != nil {
+			return err
+		}
+	}
+	for alert, keys := range seen {
+		k, err := json.Marshal(keys)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+INSERT INTO alert_seen (tracker, alert, keys) VALUES (?, ?, ?)
+ON CONFLICT (tracker, alert) DO UPDATE SET keys = excluded.keys`, a.tracker, alert, string(k))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Delete implements noaa.AlertStore.
+func (a alertStore) Delete(keys []string, alerts []string) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, key := range keys {
+		if _, err := tx.Exec(`DELETE FROM alert_events WHERE tracker = ? AND key = ?`, a.tracker, key); err != nil {
+			return err
+		}
+	}
+	for _, alert := range alerts {
+		if _, err := tx.Exec(`DELETE FROM alert_seen WHERE tracker = ? AND alert = ?`, a.tracker, alert); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// value returns the observation value, or NULL when it is missing
+func value(v noaa.ObservationValue) interface{} {
+	if v.IsMissing() {
+		return nil
+	}
+	return v.Value
+}
+
+// unit returns the unit code of the observation value without the wmoUnit: prefix
+func unit(v noaa.ObservationValue) string {
+	return strings.TrimPrefix(v.UnitCode, "wmoUnit:")
+}
@@ -0,0 +1,162 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/storage"
+)
+
+func open(t *testing.T) *storage.Store {
+	store, err := storage.Open(filepath.Join(t.TempDir(), "weather.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func count(t *testing.T, store *storage.Store, table string) int {
+	var n int
+	if err := store.DB().QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestSaveObservation(t *testing.T) {
+	store := open(t)
+	o := noaa.Observation{
+		Station:     "https://api.weather.gov/stations/KMDW",
+		Timestamp:   time.Date(2023, 7, 4, 18, 53, 0, 0, time.UTC),
+		Temperature: noaa.ObservationValue{Value: 25, UnitCode: "wmoUnit:degC"},
+	}
+	if err := store.SaveObservation(o); err != nil {
+		t.Fatal(err)
+	}
+	o.Temperature.Value = 26
+	if err := store.SaveObservation(o); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, store, "observations"); n != 1 {
+		t.Fatalf("expected 1 observation, got %d", n)
+	}
+	var temperature float64
+	var unit string
+	var dewpoint *float64
+	err := store.DB().QueryRow("SELECT temperature, temperature_unit, dewpoint FROM observations").Scan(&temperature, &unit, &dewpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temperature != 26 || unit != "degC" || dewpoint != nil {
+		t.Errorf("unexpected row %v %s %v", temperature, unit, dewpoint)
+	}
+}
+
+func TestSaveForecast(t *testing.T) {
+	store := open(t)
+	f := &noaa.ForecastResponse{Updated: "2023-07-04T18:00:00+00:00", Periods: []noaa.ForecastResponsePeriod{
+		{ID: 1, Name: "Tonight", Temperature: 70},
+		{ID: 2, Name: "Wednesday", Temperature: 85, IsDaytime: true},
+	}}
+	if err := store.SaveForecast("home", f); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveForecast("home", f); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, store, "forecasts"); n != 2 {
+		t.Fatalf("expected 2 periods, got %d", n)
+	}
+	f.Updated = "2023-07-04T21:00:00+00:00"
+	if err := store.SaveForecast("home", f); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, store, "forecasts"); n != 4 {
+		t.Errorf("expected a second snapshot, got %d periods", n)
+	}
+}
+
+func TestSaveAlert(t *testing.T) {
+	store := open(t)
+	a := noaa.Alert{Identifier: "urn:oid:2.49.0.1.840.0.1", Event: "Flood Watch", MessageType: "Alert"}
+	if err := store.SaveAlert(a); err != nil {
+		t.Fatal(err)
+	}
+	a.MessageType = "Update"
+	if err := store.SaveAlert(a); err != nil {
+		t.Fatal(err)
+	}
+	var messageType string
+	if err := store.DB().QueryRow("SELECT message_type FROM alerts").Scan(&messageType); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, store, "alerts"); n != 1 || messageType != "Update" {
+		t.Errorf("expected the alert to be updated, got %d rows with %s", n, messageType)
+	}
+}
+
+func TestAlertStore(t *testing.T) {
+	store := open(t)
+	alert := noaa.Alert{
+		Identifier: "a1",
+		Sent:       "2023-06-15T21:00:00Z",
+		Parameters: map[string][]string{"VTEC": {"/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/"}},
+	}
+	tracker, err := noaa.NewPersistentAlertTracker(store.AlertStore("home"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := tracker.Update(alert); len(changed) != 1 || tracker.Err() != nil {
+		t.Fatalf("expected the event to be saved, got %+v, %v", changed, tracker.Err())
+	}
+	if n := count(t, store, "alert_events"); n != 1 {
+		t.Errorf("expected 1 event, got %d", n)
+	}
+
+	tracker, err = noaa.NewPersistentAlertTracker(store.AlertStore("home"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := tracker.Update(alert); len(changed) != 0 {
+		t.Errorf("expected the alert to be restored as seen, got %+v", changed)
+	}
+	if event, ok := tracker.Event("KBOU.SV.W.0042"); !ok || event.Status != "NEW" {
+		t.Errorf("expected the event to be restored, got %+v", event)
+	}
+	other, err := noaa.NewPersistentAlertTracker(store.AlertStore("work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := other.Update(alert); len(changed) != 1 {
+		t.Errorf("expected the trackers to be separate, got %+v", changed)
+	}
+
+	tracker.Prune(time.Date(2023, 6, 16, 0, 0, 0, 0, time.UTC))
+	if n := count(t, store, "alert_seen"); n != 1 {
+		t.Errorf("expected the pruned alert to be deleted, got %d alerts", n)
+	}
+}
+
+func TestWatermarks(t *testing.T) {
+	store := open(t)
+	if w, err := store.Watermark("home/alerts"); err != nil || !w.IsZero() {
+		t.Fatalf("expected no watermark, got %v, %v", w, err)
+	}
+	want := time.Date(2023, 7, 4, 18, 53, 0, 0, time.UTC)
+	for _, w := range []time.Time{want.Add(-time.Hour), want} {
+		if err := store.SetWatermark("home/alerts", w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if w, err := store.Watermark("home/alerts"); err != nil || !w.Equal(want) {
+		t.Errorf("expected the watermark to be updated, got %v, %v", w, err)
+	}
+	p := &noaa.Poller{}
+	store.Attach(p)
+	if p.Watermarks == nil || p.AlertStore == nil {
+		t.Error("expected Attach to keep the watermarks and alert events in the store")
+	}
+}
@@ -0,0 +1,38 @@
+package noaa
+
+import (
+	"math"
+	"time"
+)
+
+// j2000 is the J2000.0 epoch, Julian date 2451545.0
+var j2000 = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// SunTimes returns the sunrise and sunset at a point on the date of day, in
+// the location of day, within about a minute. Both are zero on days the sun
+// does not rise or set, ex. polar days and nights.
+func SunTimes(c Coordinates, day time.Time) (sunrise time.Time, sunset time.Time) {
+	// sunrise equation, see https://en.wikipedia.org/wiki/Sunrise_equation
+	date := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.UTC)
+	n := math.Round(date.Sub(j2000).Hours() / 24)
+	noon := n - c.Lon/360 // mean solar noon, in days since J2000
+	m := math.Mod(357.5291+0.98560028*noon, 360)
+	center := 1.9148*sinDegrees(m) + 0.02*sinDegrees(2*m) + 0.0003*sinDegrees(3*m)
+	lambda := math.Mod(m+center+180+102.9372, 360)
+	transit := noon + 0.0053*sinDegrees(m) - 0.0069*sinDegrees(2*lambda)
+	declination := math.Asin(sinDegrees(lambda) * sinDegrees(23.4397))
+	cosHourAngle := (sinDegrees(-0.833) - sinDegrees(c.Lat)*math.Sin(declination)) / (math.Cos(radians(c.Lat)) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, time.Time{}
+	}
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+	at := func(days float64) time.Time {
+		return j2000.Add(time.Duration(days * 24 * float64(time.Hour))).Round(time.Second).In(day.Location())
+	}
+	return at(transit - hourAngle/360), at(transit + hourAngle/360)
+}
+
+// sinDegrees returns the sine of an angle in degrees
+func sinDegrees(degrees float64) float64 {
+	return math.Sin(radians(degrees))
+}
@@ -0,0 +1,35 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestSunTimes(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip(err)
+	}
+	sunrise, sunset := noaa.SunTimes(noaa.Coordinates{Lat: 41.837, Lon: -87.685}, time.Date(2023, 7, 4, 0, 0, 0, 0, chicago))
+	for _, c := range []struct {
+		name      string
+		got, want time.Time
+	}{
+		{"sunrise", sunrise, time.Date(2023, 7, 4, 5, 19, 0, 0, chicago)},
+		{"sunset", sunset, time.Date(2023, 7, 4, 20, 29, 0, 0, chicago)},
+	} {
+		if d := c.got.Sub(c.want); d < -2*time.Minute || d > 2*time.Minute {
+			t.Errorf("noaa.SunTimes() should return the %s within 2 minutes of %s, got %s", c.name, c.want, c.got)
+		}
+	}
+
+	sunrise, sunset = noaa.SunTimes(noaa.Coordinates{Lat: 71.29, Lon: -156.79}, time.Date(2023, 7, 4, 12, 0, 0, 0, time.UTC))
+	if !sunrise.IsZero() || !sunset.IsZero() {
+		t.Errorf("noaa.SunTimes() should return zero times during polar days, got %s and %s", sunrise, sunset)
+	}
+}
@@ -0,0 +1,129 @@
+package noaa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SupplementaryValue is a value of a SupplementarySeries.
+type SupplementaryValue struct {
+	Time     time.Time
+	Value    float64
+	Category string // category of the value if the provider has one, ex. High
+}
+
+// SupplementarySeries is a time series of data which weather.gov does not
+// provide, ex. tree pollen, smoke or the air quality index, see
+// SupplementaryDataProvider.
+type SupplementarySeries struct {
+	Name   string // ex. pollen.tree
+	Unit   string // ex. grains/m3, blank if the values have no unit
+	Values []SupplementaryValue
+}
+
+// SupplementaryDataProvider provides third-party time series for points, so
+// they can be merged into weather bundles without this package depending on
+// each data source. Providers are registered with
+// RegisterSupplementaryProvider.
+type SupplementaryDataProvider interface {
+	// Name identifies the provider, ex. pollen. Names must be unique.
+	Name() string
+
+	// Series returns the series of the provider for <lat,lon>.
+	Series(ctx context.Context, lat string, lon string) ([]SupplementarySeries, error)
+}
+
+// SupplementaryRegistry holds supplementary data providers by name. A
+// SupplementaryRegistry is safe for concurrent use.
+type SupplementaryRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]SupplementaryDataProvider
+}
+
+// DefaultSupplementaryProviders is the registry used by
+// RegisterSupplementaryProvider and GetWeatherBundle.
+var DefaultSupplementaryProviders = NewSupplementaryRegistry()
+
+// NewSupplementaryRegistry returns an empty registry.
+func NewSupplementaryRegistry() *SupplementaryRegistry {
+	return &SupplementaryRegistry{providers: map[string]SupplementaryDataProvider{}}
+}
+
+// Add registers the provider under its name. It fails if the name is blank or
+// already registered.
+func (r *SupplementaryRegistry) Add(p SupplementaryDataProvider) error {
+	name := p.Name()
+	if name == "" {
+		return errors.New("supplementary data provider name is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.providers[name]; ok {
+		return fmt.Errorf("supplementary data provider %q already registered", name)
+	}
+	r.providers[name] = p
+	return nil
+}
+
+// Remove removes the provider with the name, if any.
+func (r *SupplementaryRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.providers, name)
+}
+
+// Names returns the names of the registered providers, sorted.
+func (r *SupplementaryRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Fetch calls every registered provider at the same time and returns their
+// series and errors by provider name. A provider which fails only has an
+// error.
+func (r *SupplementaryRegistry) Fetch(ctx context.Context, lat string, lon string) (map[string][]SupplementarySeries, map[string]error) {
+	r.mu.RLock()
+	providers := make([]SupplementaryDataProvider, 0, len(r.providers))
+	for _, p := range r.providers {
+		providers = append(providers, p)
+	}
+	r.mu.RUnlock()
+
+	series := map[string][]SupplementarySeries{}
+	errs := map[string]error{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, p := range providers {
+		wg.Add(1)
+		go func(p SupplementaryDataProvider) {
+			defer wg.Done()
+			s, err := p.Series(ctx, lat, lon)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[p.Name()] = err
+				return
+			}
+			series[p.Name()] = s
+		}(p)
+	}
+	wg.Wait()
+	return series, errs
+}
+
+// RegisterSupplementaryProvider adds the provider to
+// DefaultSupplementaryProviders, whose series are then included in weather
+// bundles, see WeatherBundle.Supplementary.
+func RegisterSupplementaryProvider(p SupplementaryDataProvider) error {
+	return DefaultSupplementaryProviders.Add(p)
+}
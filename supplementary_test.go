@@ -0,0 +1,67 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// pollenProvider is a supplementary data provider returning a fixed series
+type pollenProvider struct {
+	name string
+	err  error
+}
+
+func (p pollenProvider) Name() string { return p.name }
+
+func (p pollenProvider) Series(ctx context.Context, lat string, lon string) ([]noaa.SupplementarySeries, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []noaa.SupplementarySeries{{
+		Name:   "pollen.tree",
+		Unit:   "grains/m3",
+		Values: []noaa.SupplementaryValue{{Time: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC), Value: 120, Category: "High"}},
+	}}, nil
+}
+
+func TestSupplementaryProviders(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/alerts/active": `{"@graph": []}`,
+	})
+	failing := errors.New("smoke feed unavailable")
+	for _, p := range []noaa.SupplementaryDataProvider{pollenProvider{name: "pollen"}, pollenProvider{name: "smoke", err: failing}} {
+		if err := noaa.RegisterSupplementaryProvider(p); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { noaa.DefaultSupplementaryProviders.Remove(p.Name()) })
+	}
+	if err := noaa.RegisterSupplementaryProvider(pollenProvider{name: "pollen"}); err == nil {
+		t.Error("noaa.RegisterSupplementaryProvider() should fail for a name already registered")
+	}
+	if err := noaa.RegisterSupplementaryProvider(pollenProvider{}); err == nil {
+		t.Error("noaa.RegisterSupplementaryProvider() should fail without a name")
+	}
+	if names := noaa.DefaultSupplementaryProviders.Names(); len(names) != 2 || names[0] != "pollen" || names[1] != "smoke" {
+		t.Errorf("noaa.SupplementaryRegistry.Names() should return the sorted names, got %v", names)
+	}
+
+	// the point lookup fails but the providers only need the coordinates
+	b := noaa.GetWeatherBundle("41.837", "-87.685")
+	pollen := b.Supplementary["pollen"]
+	if len(pollen) != 1 || pollen[0].Name != "pollen.tree" || pollen[0].Values[0].Category != "High" {
+		t.Errorf("noaa.GetWeatherBundle() should include the supplementary series, got %+v", b.Supplementary)
+	}
+	if _, ok := b.Supplementary["smoke"]; ok || !errors.Is(b.SupplementaryErrs["smoke"], failing) {
+		t.Errorf("noaa.GetWeatherBundle() should return the error of the failed provider, got %v", b.SupplementaryErrs)
+	}
+	if len(b.SupplementaryErrs) != 1 {
+		t.Errorf("noaa.GetWeatherBundle() should only return errors of failed providers, got %v", b.SupplementaryErrs)
+	}
+}
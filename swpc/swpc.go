@@ -0,0 +1,320 @@
+// Package swpc fetches the alerts, watches and warnings and the Kp index
+// forecast of the NOAA Space Weather Prediction Center, for aurora watchers
+// and users of GPS and HF radio. SWPC messages are converted to noaa.Alert so
+// they are tracked and published like weather alerts:
+//
+//	tracker := noaa.NewAlertTracker()
+//	for range time.Tick(15 * time.Minute) {
+//		if _, err := swpc.Poll(ctx, tracker, nil); err != nil {
+//			log.Print(err)
+//		}
+//	}
+//
+// Subscribers of noaa.DefaultBus then receive noaa.AlertIssued events for
+// geomagnetic storm watches, ex. "Geomagnetic Storm Category G2 Predicted".
+package swpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// BaseURL is the URL of the SWPC data service.
+var BaseURL = "https://services.swpc.noaa.gov"
+
+// SenderName is the sender name of the alerts.
+const SenderName = "NOAA Space Weather Prediction Center"
+
+// issueTimeLayout is the layout of the times of the messages, ex.
+// 2023 Jul 04 1234 UTC
+const issueTimeLayout = "2006 Jan 02 1504 MST"
+
+// Message is a message of the SWPC alerts product: an alert, watch, warning
+// or summary.
+type Message struct {
+	ProductID string // ex. A20F
+	Code      string // space weather message code, ex. WATA20
+	Serial    string // serial number of the message
+	Issued    time.Time
+	Text      string
+}
+
+// field returns the value of a "Name: value" line of the message, if any
+func (m Message) field(name string) string {
+	for _, line := range strings.Split(m.Text, "\n") {
+		if value, ok := cutPrefixFold(strings.TrimSpace(line), name+":"); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// Headline returns the line naming the message, ex. WATCH: Geomagnetic Storm
+// Category G1 Predicted.
+func (m Message) Headline() string {
+	for _, line := range strings.Split(m.Text, "\n") {
+		line = strings.TrimSpace(line)
+		for _, kind := range messageKinds {
+			if _, ok := cutPrefixFold(line, kind.prefix); ok {
+				return line
+			}
+		}
+	}
+	return ""
+}
+
+// messageKinds map the headline prefixes of messages to their alert codes.
+var messageKinds = []struct {
+	prefix      string
+	messageType string
+	urgency     string
+	certainty   string
+}{
+	{"CANCEL WATCH:", "Cancel", "Future", "Possible"},
+	{"CANCEL WARNING:", "Cancel", "Expected", "Likely"},
+	{"EXTENDED WARNING:", "Update", "Expected", "Likely"},
+	{"WATCH:", "Alert", "Future", "Possible"},
+	{"WARNING:", "Alert", "Expected", "Likely"},
+	{"ALERT:", "Alert", "Immediate", "Observed"},
+	{"CONTINUED ALERT:", "Update", "Immediate", "Observed"},
+	{"SUMMARY:", "Alert", "Past", "Observed"},
+}
+
+// scalePattern matches the NOAA space weather scale levels, ex. G3
+var scalePattern = regexp.MustCompile(`\b[GSR]([1-5])\b`)
+
+// scaleSeverity maps the levels of the scales to alert severities
+var scaleSeverity = []string{"Unknown", "Minor", "Moderate", "Severe", "Severe", "Extreme"}
+
+// Alert returns the message as an alert. The identifier is derived from the
+// serial number, and extended or cancelled watches and warnings reference the
+// message they extend or cancel, so a noaa.AlertTracker follows them as one
+// event.
+func (m Message) Alert() noaa.Alert {
+	headline := m.Headline()
+	a := noaa.Alert{
+		Identifier:  alertID(m.Serial),
+		MessageType: "Alert",
+		Status:      "Actual",
+		Severity:    "Unknown",
+		Certainty:   "Unknown",
+		Urgency:     "Unknown",
+		Event:       headline,
+		Headline:    headline,
+		Description: strings.TrimSpace(m.Text),
+		SenderName:  SenderName,
+		Parameters:  map[string][]string{"swpcCode": {m.Code}},
+	}
+	if _, event, ok := strings.Cut(headline, ":"); ok {
+		a.Event = strings.TrimSpace(event)
+	}
+	for _, kind := range messageKinds {
+		if _, ok := cutPrefixFold(headline, kind.prefix); ok {
+			a.MessageType, a.Urgency, a.Certainty = kind.messageType, kind.urgency, kind.certainty
+			break
+		}
+	}
+	if !m.Issued.IsZero() {
+		a.Sent = m.Issued.UTC().Format(time.RFC3339)
+		a.Effective = a.Sent
+	}
+	if t, ok := m.time("Valid From"); ok {
+		a.Onset = t
+	}
+	for _, name := range []string{"Valid To", "Now Valid Until"} {
+		if t, ok := m.time(name); ok {
+			a.Expires, a.Ends = t, t
+		}
+	}
+	for _, name := range []string{"Extension to Serial Number", "Cancel Serial Number", "Continuation of Serial Number"} {
+		if serial := m.field(name); serial != "" {
+			a.References = append(a.References, noaa.AlertReference{Identifier: alertID(serial), Sender: SenderName})
+		}
+	}
+	level := 0
+	for _, match := range scalePattern.FindAllStringSubmatch(headline+"\n"+m.Text, -1) {
+		if l, _ := strconv.Atoi(match[1]); l > level {
+			level = l
+		}
+	}
+	a.Severity = scaleSeverity[level]
+	return a
+}
+
+// time returns the time of a field of the message in RFC 3339
+func (m Message) time(name string) (string, bool) {
+	t, err := time.Parse(issueTimeLayout, m.field(name))
+	if err != nil {
+		return "", false
+	}
+	return t.UTC().Format(time.RFC3339), true
+}
+
+// alertID returns the identifier of the alert of a serial number
+func alertID(serial string) string {
+	return "swpc:" + strings.TrimSpace(serial)
+}
+
+// Messages returns the messages issued by SWPC in the last days, oldest
+// first.
+func Messages(ctx context.Context) ([]Message, error) {
+	var rows []struct {
+		ProductID string `json:"product_id"`
+		Issued    string `json:"issue_datetime"`
+		Message   string `json:"message"`
+	}
+	if err := get(ctx, "/products/alerts.json", &rows); err != nil {
+		return nil, err
+	}
+	messages := make([]Message, 0, len(rows))
+	for _, row := range rows {
+		m := Message{ProductID: row.ProductID, Text: strings.ReplaceAll(row.Message, "\r\n", "\n")}
+		m.Code = m.field("Space Weather Message Code")
+		m.Serial = m.field("Serial Number")
+		if t, err := time.Parse(issueTimeLayout, m.field("Issue Time")); err == nil {
+			m.Issued = t.UTC()
+		} else if t, err := time.Parse("2006-01-02 15:04:05.999", row.Issued); err == nil {
+			m.Issued = t
+		}
+		if m.Serial == "" {
+			continue
+		}
+		messages = append(messages, m)
+	}
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].Issued.Before(messages[j].Issued) })
+	return messages, nil
+}
+
+// Alerts returns the messages issued by SWPC in the last days as alerts,
+// oldest first, see Message.Alert.
+func Alerts(ctx context.Context) ([]noaa.Alert, error) {
+	messages, err := Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]noaa.Alert, len(messages))
+	for i, m := range messages {
+		alerts[i] = m.Alert()
+	}
+	return alerts, nil
+}
+
+// Poll fetches the alerts, applies them to the tracker and publishes the
+// events they issue, update or end on bus as noaa.AlertIssued events, like a
+// noaa.Poller. A nil bus is noaa.DefaultBus. The changed events are returned.
+func Poll(ctx context.Context, tracker *noaa.AlertTracker, bus *noaa.Bus) ([]noaa.AlertEvent, error) {
+	if tracker == nil {
+		return nil, errors.New("swpc: nil tracker")
+	}
+	alerts, err := Alerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if bus == nil {
+		bus = noaa.DefaultBus
+	}
+	events := tracker.Update(alerts...)
+	for _, event := range events {
+		bus.Publish(noaa.AlertIssued{Event: event})
+	}
+	return events, nil
+}
+
+// Kp is an observed, estimated or predicted planetary K index of a 3 hour
+// period.
+type Kp struct {
+	Time   time.Time // start of the period
+	Index  float64   // 0 to 9
+	Status string    // observed, estimated or predicted
+	Scale  string    // NOAA geomagnetic storm scale level, ex. G1, blank below storm levels
+}
+
+// Storm reports whether the index reaches geomagnetic storm levels, Kp 5 or
+// more, when aurora may be seen at mid-latitudes.
+func (k Kp) Storm() bool { return k.Index >= 5 }
+
+// KpForecast returns the planetary K index of the last week and the next 3
+// days, in order.
+func KpForecast(ctx context.Context) ([]Kp, error) {
+	var rows [][]interface{}
+	if err := get(ctx, "/products/noaa-planetary-k-index-forecast.json", &rows); err != nil {
+		return nil, err
+	}
+	var kps []Kp
+	for i, row := range rows {
+		if i == 0 || len(row) < 3 { // header
+			continue
+		}
+		s, _ := row[0].(string)
+		t, err := time.Parse("2006-01-02 15:04:05", s)
+		if err != nil {
+			return nil, fmt.Errorf("swpc: invalid time %q", s)
+		}
+		index, err := number(row[1])
+		if err != nil {
+			return nil, err
+		}
+		kp := Kp{Time: t, Index: index}
+		kp.Status, _ = row[2].(string)
+		if len(row) > 3 {
+			kp.Scale, _ = row[3].(string)
+		}
+		kps = append(kps, kp)
+	}
+	return kps, nil
+}
+
+// number returns the value of a JSON number or a number in a string
+func number(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, fmt.Errorf("swpc: invalid number %q", n)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("swpc: invalid number %v", v)
+}
+
+// get decodes the JSON response of the path into v
+func get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", noaa.GetConfig().UserAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", req.URL, res.Status)
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", req.URL, err)
+	}
+	return nil
+}
+
+// cutPrefixFold returns s without the prefix, ignoring case, and whether s
+// starts with it
+func cutPrefixFold(s string, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,74 @@
+package swpc_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/swpc"
+)
+
+// message returns the JSON of a message of the alerts product
+func message(code string, serial string, issued string, body string) string {
+	text := fmt.Sprintf(`Space Weather Message Code: %s\r\nSerial Number: %s\r\nIssue Time: %s\r\n\r\n%s`, code, serial, issued, body)
+	return fmt.Sprintf(`{"product_id": "%s", "issue_datetime": "2023-07-04 00:00:00.000", "message": "%s"}`, code[3:], text)
+}
+
+func TestPoll(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/products/alerts.json":
+			fmt.Fprintf(w, "[%s, %s]",
+				message("WARK05", "1001", "2023 Jul 04 1900 UTC",
+					`EXTENDED WARNING: Geomagnetic K-index of 5 expected\r\nExtension to Serial Number: 1000\r\nValid From: 2023 Jul 04 1200 UTC\r\nNow Valid Until: 2023 Jul 05 0300 UTC\r\nNOAA Scale: G1 - Minor`),
+				message("WARK05", "1000", "2023 Jul 04 1155 UTC",
+					`WARNING: Geomagnetic K-index of 5 expected\r\nValid From: 2023 Jul 04 1200 UTC\r\nValid To: 2023 Jul 04 2100 UTC\r\nNOAA Scale: G1 - Minor`))
+		case "/products/noaa-planetary-k-index-forecast.json":
+			fmt.Fprint(w, `[["time_tag", "kp", "observed", "noaa_scale"],
+				["2023-07-04 00:00:00", "2.33", "observed", null],
+				["2023-07-04 03:00:00", "5.67", "predicted", "G1"]]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client, baseURL := http.DefaultClient, swpc.BaseURL
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient, swpc.BaseURL = client, baseURL
+	})
+	swpc.BaseURL = api.URL
+
+	bus := noaa.NewBus()
+	var issued []noaa.AlertIssued
+	bus.Subscribe(func(e noaa.Event) { issued = append(issued, e.(noaa.AlertIssued)) })
+	tracker := noaa.NewAlertTracker()
+	events, err := swpc.Poll(context.Background(), tracker, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || len(issued) != 1 || len(events[0].History) != 2 {
+		t.Fatalf("expected the extension to update the warning, got %+v", events)
+	}
+	a := events[0].Current
+	if a.Identifier != "swpc:1001" || a.MessageType != "Update" || a.Event != "Geomagnetic K-index of 5 expected" ||
+		a.Severity != "Minor" || a.Urgency != "Expected" || a.Ends != "2023-07-05T03:00:00Z" || a.Sent != "2023-07-04T19:00:00Z" {
+		t.Errorf("expected the extended warning as an alert, got %+v", a)
+	}
+	if events, _ := swpc.Poll(context.Background(), tracker, bus); len(events) != 0 {
+		t.Errorf("expected no events for messages already tracked, got %+v", events)
+	}
+
+	kps, err := swpc.KpForecast(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kps) != 2 || !kps[1].Time.Equal(time.Date(2023, 7, 4, 3, 0, 0, 0, time.UTC)) || kps[1].Index != 5.67 ||
+		kps[1].Scale != "G1" || !kps[1].Storm() || kps[0].Storm() || kps[0].Status != "observed" {
+		t.Errorf("expected the Kp index forecast, got %+v", kps)
+	}
+}
@@ -0,0 +1,272 @@
+// Package tides wraps the NOAA CO-OPS Tides and Currents API, for coastal
+// applications to get tide predictions and water levels alongside the marine
+// forecasts of the noaa package:
+//
+//	station, predictions, err := tides.ForPoint(ctx, "37.80", "-122.47", time.Now(), time.Now().Add(48*time.Hour))
+//	...
+//	for _, p := range predictions {
+//		fmt.Println(station.Name, p.Time, p.Type(), p.Height)
+//	}
+//
+// Heights are in meters above the datum, see Datum.
+package tides
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// DataURL is the URL of the CO-OPS data API.
+var DataURL = "https://api.tidesandcurrents.noaa.gov/api/prod/datagetter"
+
+// MetadataURL is the URL of the CO-OPS metadata API.
+var MetadataURL = "https://api.tidesandcurrents.noaa.gov/mdapi/prod/webapi"
+
+// Datum is the datum of the heights, ex. MLLW (mean lower low water), the
+// datum of nautical charts, or MSL (mean sea level).
+var Datum = "MLLW"
+
+// ErrNoData is returned when CO-OPS responds with an error, usually because it
+// has no data for the station and time range.
+var ErrNoData = errors.New("no tide data")
+
+// timeLayout is the layout of the times of the data API, in GMT
+const timeLayout = "2006-01-02 15:04"
+
+// stationsTTL is how long the list of stations is cached
+const stationsTTL = 24 * time.Hour
+
+// Station is a CO-OPS station with tide predictions, see Stations.
+type Station struct {
+	ID       string // ex. 9414290
+	Name     string // ex. San Francisco
+	State    string
+	Location noaa.Coordinates
+	Distance float64 // meters from the point, set by Nearby
+}
+
+// Prediction is a predicted high or low tide.
+type Prediction struct {
+	Time   time.Time
+	Height float64 // m
+	High   bool
+}
+
+// Type returns High or Low.
+func (p Prediction) Type() string {
+	if p.High {
+		return "High"
+	}
+	return "Low"
+}
+
+// WaterLevel is a measured water level.
+type WaterLevel struct {
+	Time        time.Time
+	Height      float64 // m
+	Preliminary bool    // false once CO-OPS verified the level
+}
+
+var (
+	stationsMu      sync.Mutex
+	stationsCache   []Station
+	stationsURL     string
+	stationsFetched time.Time
+)
+
+// Stations returns the stations with tide predictions. The list is cached for
+// a day.
+func Stations(ctx context.Context) ([]Station, error) {
+	endpoint := MetadataURL + "/stations.json?type=tidepredictions"
+	stationsMu.Lock()
+	defer stationsMu.Unlock()
+	if stationsURL == endpoint && time.Since(stationsFetched) < stationsTTL {
+		return stationsCache, nil
+	}
+	var r struct {
+		Stations []struct {
+			ID    string  `json:"id"`
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Lat   float64 `json:"lat"`
+			Lon   float64 `json:"lng"`
+		} `json:"stations"`
+	}
+	if err := get(ctx, endpoint, &r); err != nil {
+		return nil, err
+	}
+	stations := make([]Station, len(r.Stations))
+	for i, s := range r.Stations {
+		stations[i] = Station{ID: s.ID, Name: s.Name, State: s.State, Location: noaa.Coordinates{Lat: s.Lat, Lon: s.Lon}}
+	}
+	stationsCache, stationsURL, stationsFetched = stations, endpoint, time.Now()
+	return stations, nil
+}
+
+// Nearby returns up to n stations within radius meters of <lat,lon>, nearest
+// first. A negative or zero n returns all of them.
+func Nearby(ctx context.Context, lat string, lon string, radius float64, n int) ([]Station, error) {
+	origin, err := noaa.ParseCoordinates(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	stations, err := Stations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var nearby []Station
+	for _, s := range stations {
+		if s.Distance = origin.DistanceTo(s.Location); s.Distance <= radius {
+			nearby = append(nearby, s)
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].Distance < nearby[j].Distance })
+	if n > 0 && len(nearby) > n {
+		nearby = nearby[:n]
+	}
+	return nearby, nil
+}
+
+// HighLow returns the predicted high and low tides of a station between
+// start and end, in order.
+func HighLow(ctx context.Context, station string, start time.Time, end time.Time) ([]Prediction, error) {
+	var r struct {
+		Predictions []struct {
+			Time  string `json:"t"`
+			Value string `json:"v"`
+			Type  string `json:"type"`
+		} `json:"predictions"`
+	}
+	if err := get(ctx, dataURL("predictions", station, start, end, url.Values{"interval": {"hilo"}}), &r); err != nil {
+		return nil, err
+	}
+	predictions := make([]Prediction, 0, len(r.Predictions))
+	for _, p := range r.Predictions {
+		t, height, err := parseValue(p.Time, p.Value)
+		if err != nil {
+			return nil, err
+		}
+		predictions = append(predictions, Prediction{Time: t, Height: height, High: p.Type == "H"})
+	}
+	return predictions, nil
+}
+
+// WaterLevels returns the water levels measured at a station between start
+// and end, every 6 minutes. Missing levels are left out. CO-OPS serves up to
+// 31 days of levels per request.
+func WaterLevels(ctx context.Context, station string, start time.Time, end time.Time) ([]WaterLevel, error) {
+	var r struct {
+		Data []struct {
+			Time    string `json:"t"`
+			Value   string `json:"v"`
+			Quality string `json:"q"`
+		} `json:"data"`
+	}
+	if err := get(ctx, dataURL("water_level", station, start, end, nil), &r); err != nil {
+		return nil, err
+	}
+	levels := make([]WaterLevel, 0, len(r.Data))
+	for _, d := range r.Data {
+		if d.Value == "" {
+			continue
+		}
+		t, height, err := parseValue(d.Time, d.Value)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, WaterLevel{Time: t, Height: height, Preliminary: d.Quality == "p"})
+	}
+	return levels, nil
+}
+
+// ForPoint returns the station nearest to <lat,lon>, within 50 km, and its
+// high and low tides between start and end.
+func ForPoint(ctx context.Context, lat string, lon string, start time.Time, end time.Time) (*Station, []Prediction, error) {
+	stations, err := Nearby(ctx, lat, lon, 50000, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stations) == 0 {
+		return nil, nil, fmt.Errorf("no tide station near %s,%s: %w", lat, lon, ErrNoData)
+	}
+	predictions, err := HighLow(ctx, stations[0].ID, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &stations[0], predictions, nil
+}
+
+// dataURL returns the URL of a product of the data API for a station, in
+// metric units and GMT
+func dataURL(product string, station string, start time.Time, end time.Time, params url.Values) string {
+	query := url.Values{
+		"product":     {product},
+		"station":     {station},
+		"begin_date":  {start.UTC().Format("20060102 15:04")},
+		"end_date":    {end.UTC().Format("20060102 15:04")},
+		"datum":       {Datum},
+		"units":       {"metric"},
+		"time_zone":   {"gmt"},
+		"format":      {"json"},
+		"application": {noaa.GetConfig().UserAgent},
+	}
+	for key, values := range params {
+		query[key] = values
+	}
+	return DataURL + "?" + query.Encode()
+}
+
+// parseValue parses the time and value of a data point
+func parseValue(t string, v string) (time.Time, float64, error) {
+	parsed, err := time.Parse(timeLayout, t)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid time %q", t)
+	}
+	value, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(value) {
+		return time.Time{}, 0, fmt.Errorf("invalid value %q", v)
+	}
+	return parsed, value, nil
+}
+
+// get decodes the JSON response of the endpoint into v. CO-OPS reports
+// errors, ex. no data for the time range, as an error object.
+func get(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", noaa.GetConfig().UserAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", req.URL.Path, res.Status)
+	}
+	var body struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	data := json.RawMessage{}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return fmt.Errorf("%s: %w", req.URL.Path, err)
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error != nil {
+		return fmt.Errorf("%w: %s", ErrNoData, body.Error.Message)
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,71 @@
+package tides_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/tides"
+)
+
+func TestForPoint(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/mdapi/stations.json":
+			fmt.Fprint(w, `{"count": 2, "stations": [
+				{"id": "9414750", "name": "Alameda", "state": "CA", "lat": 37.7717, "lng": -122.3}, 
+				{"id": "9414290", "name": "San Francisco", "state": "CA", "lat": 37.8063, "lng": -122.4659}
+			]}`)
+		case r.URL.Query().Get("station") != "9414290":
+			fmt.Fprint(w, `{"error": {"message": "No data was found."}}`)
+		case r.URL.Query().Get("product") == "predictions" && r.URL.Query().Get("interval") == "hilo":
+			fmt.Fprint(w, `{"predictions": [{"t": "2023-07-04 03:12", "v": "1.602", "type": "H"}, {"t": "2023-07-04 09:40", "v": "-0.210", "type": "L"}]}`)
+		case r.URL.Query().Get("product") == "water_level" && r.URL.Query().Get("begin_date") == "20230704 00:00":
+			fmt.Fprint(w, `{"data": [{"t": "2023-07-04 00:00", "v": "1.201", "q": "p"}, {"t": "2023-07-04 00:06", "v": "", "q": "p"}, {"t": "2023-07-04 00:12", "v": "1.190", "q": "v"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client, dataURL, metadataURL := http.DefaultClient, tides.DataURL, tides.MetadataURL
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient, tides.DataURL, tides.MetadataURL = client, dataURL, metadataURL
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	tides.DataURL = api.URL + "/api"
+	tides.MetadataURL = api.URL + "/mdapi"
+
+	start := time.Date(2023, 7, 4, 0, 0, 0, 0, time.UTC)
+	station, predictions, err := tides.ForPoint(context.Background(), "37.80", "-122.47", start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if station.ID != "9414290" || station.Distance > 2000 {
+		t.Errorf("expected the nearest station, got %+v", station)
+	}
+	if len(predictions) != 2 || !predictions[0].Time.Equal(start.Add(3*time.Hour+12*time.Minute)) ||
+		predictions[0].Type() != "High" || predictions[1].Type() != "Low" || predictions[1].Height != -0.21 {
+		t.Errorf("expected the high and low tides, got %+v", predictions)
+	}
+
+	levels, err := tides.WaterLevels(context.Background(), "9414290", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 2 || !levels[0].Preliminary || levels[1].Preliminary || levels[1].Height != 1.19 {
+		t.Errorf("expected the water levels without missing values, got %+v", levels)
+	}
+
+	if _, err := tides.HighLow(context.Background(), "9414750", start, start.Add(time.Hour)); !errors.Is(err, tides.ErrNoData) {
+		t.Errorf("expected ErrNoData for an error response, got %v", err)
+	}
+	if _, _, err := tides.ForPoint(context.Background(), "41.9", "-87.6", start, start.Add(time.Hour)); !errors.Is(err, tides.ErrNoData) {
+		t.Errorf("expected ErrNoData far from any station, got %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package noaa
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AlertUpdate is a single message applied to an AlertEvent.
+type AlertUpdate struct {
+	Action string // VTEC action, or NEW/CON/CAN derived from the message type
+	Alert  Alert
+}
+
+// AlertEvent is a single logical event (ex. one severe thunderstorm warning)
+// collapsed from the chain of alert messages that issued, updated and ended it.
+type AlertEvent struct {
+	Key     string        // VTEC event ID, or the first alert identifier if the alert has no VTEC
+	VTEC    *VTEC         // most recent VTEC for the event, nil if the event has none
+	Status  string        // most recent action (NEW, CON, EXT, UPG, CAN, EXP, ...)
+	Current Alert         // most recent message for the event
+	History []AlertUpdate // all messages applied to the event, oldest first
+}
+
+// IsActive reports whether the event has not been cancelled, expired or upgraded.
+func (e AlertEvent) IsActive() bool {
+	return !isEndingAction(e.Status)
+}
+
+// IsActiveAt reports whether the event is active and has not reached its end
+// time at t. Events with no known end time are active until they are ended.
+func (e AlertEvent) IsActiveAt(t time.Time) bool {
+	if !e.IsActive() {
+		return false
+	}
+	end := e.End()
+	return end.IsZero() || t.Before(end)
+}
+
+// End returns the time the event is expected to end, taken from the VTEC when
+// available and otherwise from the ends (or expires) value of the alert. The
+// zero time is returned if the end time is unknown.
+func (e AlertEvent) End() time.Time {
+	if e.VTEC != nil && !e.VTEC.End.IsZero() {
+		return e.VTEC.End
+	}
+	if t, err := time.Parse(time.RFC3339, e.Current.Ends); err == nil {
+		return t
+	}
+	if t, err := time.Parse(time.RFC3339, e.Current.Expires); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// AlertTracker collapses chains of alert messages (NEW/CON/EXT/UPG/CAN/...)
+// into logical events so that repeated polling of the alerts endpoints can be
+// deduplicated. Events are keyed by their VTEC event ID when available, and
+// otherwise by following the alert references back to the first message.
+// An AlertTracker is safe for concurrent use.
+type AlertTracker struct {
+	mu       sync.Mutex
+	events   map[string]*AlertEvent
+	alertKey map[string][]string // alert identifier -> event keys
+	store    AlertStore          // nil if the tracker is not persisted
+	err      error               // error of the last store operation
+}
+
+// NewAlertTracker returns an empty AlertTracker.
+func NewAlertTracker() *AlertTracker {
+	return &AlertTracker{
+		events:   map[string]*AlertEvent{},
+		alertKey: map[string][]string{},
+	}
+}
+
+// NewPersistentAlertTracker returns an AlertTracker restored from the store,
+// which then saves the changes of each Update and Prune to it, so a process
+// restarted with the same store does not report the events again.
+func NewPersistentAlertTracker(store AlertStore) (*AlertTracker, error) {
+	events, seen, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert events: %w", err)
+	}
+	t := NewAlertTracker()
+	t.store = store
+	for i := range events {
+		e := copyAlertEvent(&events[i])
+		t.events[e.Key] = &e
+	}
+	for id, keys := range seen {
+		// the alert stays seen even if the store lost its events
+		t.alertKey[id] = t.liveKeys(keys)
+	}
+	return t, nil
+}
+
+// Err returns the error of the last save to the store of the tracker, or nil
+// if it succeeded or the tracker has no store. Events are still tracked in
+// memory when saving fails.
+func (t *AlertTracker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Update applies the alerts to the tracked events and returns copies of the
+// events that changed. Alerts that were already applied are ignored, so the
+// same alerts can be passed in on every poll.
+func (t *AlertTracker) Update(alerts ...Alert) []AlertEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	changed := map[string]bool{}
+	seen := map[string][]string{} // alert identifiers applied by this update
+	var order []string
+	for _, alert := range alerts {
+		id := alertIdentifier(alert)
+		if _, seen := t.alertKey[id]; seen {
+			continue
+		}
+		var keys []string
+		if vtecs := alert.VTEC(); len(vtecs) > 0 {
+			for i := range vtecs {
+				keys = append(keys, t.applyVTEC(alert, vtecs[i]))
+			}
+		} else {
+			keys = append(keys, t.applyMessage(alert))
+		}
+		t.alertKey[id] = keys
+		seen[id] = keys
+		for _, key := range keys {
+			if !changed[key] {
+				changed[key] = true
+				order = append(order, key)
+			}
+		}
+	}
+
+	events := make([]AlertEvent, 0, len(order))
+	for _, key := range order {
+		events = append(events, copyAlertEvent(t.events[key]))
+	}
+	if t.store != nil && len(seen) > 0 {
+		t.err = t.store.Save(events, seen)
+	}
+	return events
+}
+
+// applyVTEC applies the alert to the event identified by the VTEC
+func (t *AlertTracker) applyVTEC(alert Alert, vtec VTEC) string {
+	key := vtec.EventID()
+	event := t.events[key]
+	if event == nil || (vtec.Action == VTECActionNew && !event.IsActive()) {
+		// event numbers are reused each year so a new event replaces an ended one
+		event = &AlertEvent{Key: key}
+		t.events[key] = event
+	}
+	v := vtec
+	t.apply(event, alert, vtec.Action, &v)
+	return key
+}
+
+// applyMessage applies an alert without VTEC by following its references
+func (t *AlertTracker) applyMessage(alert Alert) string {
+	action := VTECActionNew
+	switch alert.MessageType {
+	case "Update":
+		action = VTECActionContinue
+	case "Cancel":
+		action = VTECActionCancel
+	}
+	for _, ref := range alert.References {
+		for _, key := range t.alertKey[ref.Identifier] {
+			if event, ok := t.events[key]; ok {
+				t.apply(event, alert, action, nil)
+				return key
+			}
+		}
+	}
+	key := alertIdentifier(alert)
+	event := &AlertEvent{Key: key}
+	t.events[key] = event
+	t.apply(event, alert, action, nil)
+	return key
+}
+
+// apply records the update on the event. An update that was sent before the
+// current message (i.e. received out of order) is added to the history but
+// does not replace the current status of the event.
+func (t *AlertTracker) apply(event *AlertEvent, alert Alert, action string, vtec *VTEC) {
+	update := AlertUpdate{Action: action, Alert: alert}
+	event.History = append(event.History, update)
+	sort.SliceStable(event.History, func(i, j int) bool {
+		return alertSent(event.History[i].Alert).Before(alertSent(event.History[j].Alert))
+	})
+	latest := event.History[len(event.History)-1]
+	event.Status = latest.Action
+	event.Current = latest.Alert
+	if id := alertIdentifier(alert); id != "" && alertIdentifier(latest.Alert) == id && vtec != nil {
+		event.VTEC = vtec
+	}
+}
+
+// Event returns a copy of the event with the given key.
+func (t *AlertTracker) Event(key string) (event AlertEvent, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.events[key]
+	if !ok {
+		return AlertEvent{}, false
+	}
+	return copyAlertEvent(e), true
+}
+
+// Events returns copies of all tracked events sorted by key.
+func (t *AlertTracker) Events() []AlertEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	events := make([]AlertEvent, 0, len(t.events))
+	for _, e := range t.events {
+		events = append(events, copyAlertEvent(e))
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Key < events[j].Key })
+	return events
+}
+
+// Active returns copies of the events which are active at the given time.
+func (t *AlertTracker) Active(now time.Time) []AlertEvent {
+	var active []AlertEvent
+	for _, e := range t.Events() {
+		if e.IsActiveAt(now) {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+// Prune removes events which ended before the given time so that long running
+// trackers do not grow without bound. The alerts of the events are forgotten
+// unless they also apply to events which are kept, ex. alerts with several
+// VTEC codes.
+func (t *AlertTracker) Prune(before time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var keys []string
+	pruned := map[string]bool{} // alert identifiers of the pruned events
+	for key, e := range t.events {
+		end := e.End()
+		if !e.IsActive() || (!end.IsZero() && end.Before(before)) {
+			if alertSent(e.Current).Before(before) {
+				delete(t.events, key)
+				keys = append(keys, key)
+				for _, u := range e.History {
+					pruned[alertIdentifier(u.Alert)] = true
+				}
+			}
+		}
+	}
+	var alerts []string
+	kept := map[string][]string{} // alert identifiers of kept events, without the pruned keys
+	for id := range pruned {
+		if live := t.liveKeys(t.alertKey[id]); len(live) > 0 {
+			t.alertKey[id] = live
+			kept[id] = live
+		} else {
+			delete(t.alertKey, id)
+			alerts = append(alerts, id)
+		}
+	}
+	if t.store != nil && len(keys) > 0 {
+		t.err = t.store.Delete(keys, alerts)
+		if t.err == nil && len(kept) > 0 {
+			t.err = t.store.Save(nil, kept)
+		}
+	}
+}
+
+// liveKeys returns the event keys whose events are still tracked
+func (t *AlertTracker) liveKeys(keys []string) []string {
+	live := []string{}
+	for _, key := range keys {
+		if _, ok := t.events[key]; ok {
+			live = append(live, key)
+		}
+	}
+	return live
+}
+
+// alertIdentifier returns the identifier of the alert, falling back to its URL
+func alertIdentifier(a Alert) string {
+	if a.Identifier != "" {
+		return a.Identifier
+	}
+	return a.ID
+}
+
+// alertSent returns the time the alert was sent or the zero time if unknown
+func alertSent(a Alert) time.Time {
+	t, _ := time.Parse(time.RFC3339, a.Sent)
+	return t
+}
+
+// isEndingAction reports whether the VTEC action ends the event
+func isEndingAction(action string) bool {
+	return action == VTECActionCancel || action == VTECActionExpire || action == VTECActionUpgrade
+}
+
+// copyAlertEvent copies the event so callers cannot modify tracker state
+func copyAlertEvent(e *AlertEvent) AlertEvent {
+	c := *e
+	c.History = append([]AlertUpdate(nil), e.History...)
+	if e.VTEC != nil {
+		v := *e.VTEC
+		c.VTEC = &v
+	}
+	return c
+}
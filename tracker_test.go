@@ -0,0 +1,131 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func vtecAlert(id, sent, vtec string) noaa.Alert {
+	return noaa.Alert{
+		Identifier: id,
+		Sent:       sent,
+		Event:      "Severe Thunderstorm Warning",
+		Parameters: map[string][]string{"VTEC": {vtec}},
+	}
+}
+
+func TestAlertTrackerLifecycle(t *testing.T) {
+	tracker := noaa.NewAlertTracker()
+	issued := vtecAlert("a1", "2023-06-15T21:00:00Z", "/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/")
+	extended := vtecAlert("a2", "2023-06-15T21:30:00Z", "/O.EXT.KBOU.SV.W.0042.000000T0000Z-230615T2230Z/")
+	cancelled := vtecAlert("a3", "2023-06-15T22:00:00Z", "/O.CAN.KBOU.SV.W.0042.000000T0000Z-230615T2230Z/")
+
+	if changed := tracker.Update(issued); len(changed) != 1 || changed[0].Status != "NEW" {
+		t.Fatalf("a new alert should create a new event, got %+v", changed)
+	}
+	if changed := tracker.Update(issued); len(changed) != 0 {
+		t.Error("an alert which was already applied should be ignored")
+	}
+	changed := tracker.Update(issued, extended)
+	if len(changed) != 1 || changed[0].Status != "EXT" || changed[0].Key != "KBOU.SV.W.0042" {
+		t.Fatalf("an extension should update the existing event, got %+v", changed)
+	}
+	if end := changed[0].End(); !end.Equal(time.Date(2023, 6, 15, 22, 30, 0, 0, time.UTC)) {
+		t.Errorf("the end time should be extended, got %v", end)
+	}
+	if active := tracker.Active(time.Date(2023, 6, 15, 22, 15, 0, 0, time.UTC)); len(active) != 1 {
+		t.Error("the extended event should still be active")
+	}
+
+	tracker.Update(cancelled)
+	event, ok := tracker.Event("KBOU.SV.W.0042")
+	if !ok || event.IsActive() || len(event.History) != 3 {
+		t.Errorf("the cancelled event should be inactive with 3 updates, got %+v", event)
+	}
+}
+
+func TestAlertTrackerOutOfOrder(t *testing.T) {
+	tracker := noaa.NewAlertTracker()
+	tracker.Update(
+		vtecAlert("a2", "2023-06-15T21:30:00Z", "/O.CON.KBOU.SV.W.0042.000000T0000Z-230615T2200Z/"),
+		vtecAlert("a1", "2023-06-15T21:00:00Z", "/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/"),
+	)
+	event, _ := tracker.Event("KBOU.SV.W.0042")
+	if event.Status != "CON" || event.Current.Identifier != "a2" {
+		t.Errorf("an older message should not replace the current status, got %s", event.Status)
+	}
+}
+
+func TestAlertTrackerOutOfOrderWithoutIdentifier(t *testing.T) {
+	tracker := noaa.NewAlertTracker()
+	current := vtecAlert("", "2023-06-15T21:30:00Z", "/O.EXT.KBOU.SV.W.0042.000000T0000Z-230615T2230Z/")
+	current.ID = "https://api.weather.gov/alerts/a2"
+	older := vtecAlert("", "2023-06-15T21:00:00Z", "/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/")
+	older.ID = "https://api.weather.gov/alerts/a1"
+	tracker.Update(current, older)
+	event, _ := tracker.Event("KBOU.SV.W.0042")
+	if event.VTEC == nil || event.VTEC.Action != "EXT" {
+		t.Errorf("an older message without identifier should not replace the current VTEC, got %+v", event.VTEC)
+	}
+}
+
+func TestAlertTrackerPruneSharedAlert(t *testing.T) {
+	tracker := noaa.NewAlertTracker()
+	alert := vtecAlert("a1", "2023-06-15T21:00:00Z", "/O.CAN.KBOU.SV.W.0042.000000T0000Z-230615T2200Z/")
+	alert.Parameters["VTEC"] = append(alert.Parameters["VTEC"], "/O.NEW.KBOU.TO.W.0007.230615T2100Z-230616T0300Z/")
+	if changed := tracker.Update(alert); len(changed) != 2 {
+		t.Fatalf("an alert with two VTEC codes should update two events, got %+v", changed)
+	}
+	tracker.Prune(time.Date(2023, 6, 15, 23, 0, 0, 0, time.UTC))
+	if events := tracker.Events(); len(events) != 1 || events[0].Key != "KBOU.TO.W.0007" {
+		t.Fatalf("only the ended event should be pruned, got %+v", events)
+	}
+	if changed := tracker.Update(alert); len(changed) != 0 {
+		t.Errorf("an alert of an event which is kept should still be ignored after pruning, got %+v", changed)
+	}
+	update := noaa.Alert{
+		Identifier:  "m2",
+		MessageType: "Update",
+		Sent:        "2023-06-15T23:30:00Z",
+		References:  []noaa.AlertReference{{Identifier: "a1"}},
+	}
+	if changed := tracker.Update(update); len(changed) != 1 || changed[0].Key != "KBOU.TO.W.0007" {
+		t.Errorf("a message referencing the alert should update its remaining event, got %+v", changed)
+	}
+
+	// a store whose alerts refer to deleted events
+	store := noaa.NewMemoryAlertStore()
+	if err := store.Save(nil, map[string][]string{"a1": {"KBOU.SV.W.0042"}}); err != nil {
+		t.Fatal(err)
+	}
+	tracker, err := noaa.NewPersistentAlertTracker(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := tracker.Update(alert); len(changed) != 0 {
+		t.Errorf("a restored alert should still be ignored without its events, got %+v", changed)
+	}
+	if changed := tracker.Update(update); len(changed) != 1 || changed[0].Key != "m2" {
+		t.Errorf("a message referencing an alert without events should start a new event, got %+v", changed)
+	}
+}
+
+func TestAlertTrackerReferences(t *testing.T) {
+	tracker := noaa.NewAlertTracker()
+	tracker.Update(noaa.Alert{Identifier: "s1", MessageType: "Alert", Sent: "2023-06-15T21:00:00Z"})
+	tracker.Update(noaa.Alert{
+		Identifier:  "s2",
+		MessageType: "Cancel",
+		Sent:        "2023-06-15T21:30:00Z",
+		References:  []noaa.AlertReference{{Identifier: "s1"}},
+	})
+	events := tracker.Events()
+	if len(events) != 1 || events[0].Key != "s1" || events[0].IsActive() {
+		t.Errorf("the cancel message should end the referenced event, got %+v", events)
+	}
+}
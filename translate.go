@@ -0,0 +1,141 @@
+package noaa
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LocaleES formats values in SI units with Spanish compass points, period
+// names and common forecast summaries. Copy its Phrases before adding to
+// them, ex. for regional wording.
+var LocaleES = Locale{
+	Units: "si",
+	CompassPoints: [16]string{
+		"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+		"S", "SSO", "SO", "OSO", "O", "ONO", "NO", "NNO",
+	},
+	Calm: "calma",
+	Phrases: map[string]string{
+		"Today":          "Hoy",
+		"This Morning":   "Esta mañana",
+		"This Afternoon": "Esta tarde",
+		"Late Afternoon": "Al final de la tarde",
+		"This Evening":   "Esta noche",
+		"Tonight":        "Esta noche",
+		"Overnight":      "Durante la noche",
+		"Monday":         "Lunes",
+		"Tuesday":        "Martes",
+		"Wednesday":      "Miércoles",
+		"Thursday":       "Jueves",
+		"Friday":         "Viernes",
+		"Saturday":       "Sábado",
+		"Sunday":         "Domingo",
+		"%s Night":       "%s por la noche",
+
+		"Sunny":                     "Soleado",
+		"Mostly Sunny":              "Mayormente soleado",
+		"Partly Sunny":              "Parcialmente soleado",
+		"Clear":                     "Despejado",
+		"Mostly Clear":              "Mayormente despejado",
+		"Partly Cloudy":             "Parcialmente nublado",
+		"Mostly Cloudy":             "Mayormente nublado",
+		"Cloudy":                    "Nublado",
+		"Fog":                       "Niebla",
+		"Patchy Fog":                "Niebla dispersa",
+		"Drizzle":                   "Llovizna",
+		"Rain":                      "Lluvia",
+		"Rain Showers":              "Chubascos",
+		"Thunderstorms":             "Tormentas",
+		"Showers And Thunderstorms": "Chubascos y tormentas",
+		"Rain And Snow":             "Lluvia y nieve",
+		"Snow":                      "Nieve",
+		"Snow Showers":              "Chubascos de nieve",
+		"Sleet":                     "Aguanieve",
+		"Freezing Rain":             "Lluvia helada",
+		"Blowing Snow":              "Ventisca",
+		"Breezy":                    "Con brisa",
+		"Windy":                     "Ventoso",
+		"Hot":                       "Caluroso",
+		"Haze":                      "Bruma",
+		"Smoke":                     "Humo",
+		"Slight Chance %s":          "Ligera posibilidad de %s",
+		"Chance %s":                 "Posibilidad de %s",
+		"%s Likely":                 "%s probable",
+		"Isolated Showers":          "Chubascos aislados",
+		"Scattered Showers":         "Chubascos dispersos",
+		"Scattered Thunderstorms":   "Tormentas dispersas",
+		"Isolated Thunderstorms":    "Tormentas aisladas",
+		"Severe Thunderstorms":      "Tormentas severas",
+		"Areas Of Fog":              "Áreas de niebla",
+		"Patchy Drizzle":            "Llovizna dispersa",
+		"Periods Of Rain":           "Períodos de lluvia",
+		"Periods Of Snow":           "Períodos de nieve",
+		"Heavy Rain":                "Lluvia intensa",
+		"Heavy Snow":                "Nieve intensa",
+		"Light Rain":                "Lluvia ligera",
+		"Light Snow":                "Nieve ligera",
+	},
+}
+
+// Translate returns the translation of an English period name or summary,
+// such as Tonight or Mostly Sunny, from the Phrases of the locale. Phrases
+// are matched regardless of case. A phrase may contain one %s standing for
+// the rest of the text when the rest is a phrase too, ex. "%s Night" to "%s
+// por la noche" translates Monday Night. The longest matching phrase wins,
+// and the rest starts in lower case unless it begins the translation, ex.
+// Posibilidad de chubascos for Chance Rain Showers. Text without a
+// translation is returned unchanged, so a partial table degrades to English.
+func (l Locale) Translate(text string) string {
+	if t, ok := l.translate(text); ok {
+		return t
+	}
+	return text
+}
+
+// translate returns the translation of the text and whether it has one
+func (l Locale) translate(text string) (string, bool) {
+	if t, ok := l.Phrases[text]; ok {
+		return t, true
+	}
+	var best, translation string
+	for phrase, t := range l.Phrases {
+		if strings.EqualFold(phrase, text) {
+			return t, true
+		}
+		prefix, suffix, ok := strings.Cut(phrase, "%s")
+		if !ok || len(text) <= len(prefix)+len(suffix) || len(phrase) < len(best) || (len(phrase) == len(best) && phrase > best) {
+			continue
+		}
+		if !strings.EqualFold(text[:len(prefix)], prefix) || !strings.EqualFold(text[len(text)-len(suffix):], suffix) {
+			continue
+		}
+		rest, ok := l.translate(text[len(prefix) : len(text)-len(suffix)])
+		if !ok {
+			continue
+		}
+		if strings.Index(t, "%s") > 0 {
+			rest = lowerFirst(rest)
+		}
+		best, translation = phrase, strings.Replace(t, "%s", rest, 1)
+	}
+	return translation, best != ""
+}
+
+// lowerFirst returns the text with its first letter in lower case
+func lowerFirst(text string) string {
+	r, n := utf8.DecodeRuneInString(text)
+	return string(unicode.ToLower(r)) + text[n:]
+}
+
+// PeriodName returns the translated name of a forecast period, ex. Esta
+// noche for Tonight, see Translate.
+func (l Locale) PeriodName(p ForecastResponsePeriod) string {
+	return l.Translate(p.Name)
+}
+
+// PeriodSummary returns the translated summary of a forecast period, ex.
+// Mayormente soleado for Mostly Sunny, see Translate.
+func (l Locale) PeriodSummary(p ForecastResponsePeriod) string {
+	return l.Translate(p.Summary)
+}
@@ -0,0 +1,56 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestLocaleTranslate(t *testing.T) {
+	cases := map[string]string{
+		"Tonight":                               "Esta noche",
+		"monday night":                          "Lunes por la noche",
+		"Mostly Sunny":                          "Mayormente soleado",
+		"Chance Rain Showers":                   "Posibilidad de chubascos",
+		"Slight Chance Rain Showers":            "Ligera posibilidad de chubascos",
+		"Rain Likely":                           "Lluvia probable",
+		"Chance Rain Showers then Mostly Sunny": "Chance Rain Showers then Mostly Sunny",
+		"Independence Day":                      "Independence Day",
+		"":                                      "",
+	}
+	for text, want := range cases {
+		if got := noaa.LocaleES.Translate(text); got != want {
+			t.Errorf("noaa.Locale.Translate(%q) should be %q, got %q", text, want, got)
+		}
+	}
+	if got := noaa.LocaleUS.Translate("Tonight"); got != "Tonight" {
+		t.Errorf("noaa.Locale.Translate() without phrases should return the text, got %q", got)
+	}
+	custom := noaa.Locale{Phrases: map[string]string{"%s Night": "%s at night", "Monday": "Monday", "Monday Night": "Monday evening"}}
+	if got := custom.Translate("Monday Night"); got != "Monday evening" {
+		t.Errorf("noaa.Locale.Translate() should prefer the exact phrase, got %q", got)
+	}
+}
+
+func TestForecastFormatterLocale(t *testing.T) {
+	f := noaa.NewForecastFormatter()
+	f.Locale, f.Units = noaa.LocaleES, "si"
+	if err := f.SetPeriodTemplate(noaa.SpanishPeriodTemplate); err != nil {
+		t.Fatal(err)
+	}
+	period := noaa.ForecastResponsePeriod{Name: "Tuesday Night", Summary: "Partly Cloudy", Temperature: 50, TemperatureUnit: "F", WindSpeed: "5 mph", WindDirection: "WSW"}
+	got, err := f.FormatPeriod(period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Martes por la noche: Parcialmente nublado, con una mínima de 10°C. Viento OSO 8 km/h."; got != want {
+		t.Errorf("noaa.ForecastFormatter.FormatPeriod() should translate the period, got %q", got)
+	}
+	period.WindSpeed, period.WindDirection = "", ""
+	if text := f.Text(period); text.Wind != "calma" {
+		t.Errorf("noaa.ForecastFormatter.Text() should translate calm winds, got %q", text.Wind)
+	}
+}
@@ -0,0 +1,297 @@
+package noaa
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// englishCompassPoints are the 16 compass points starting at north, clockwise.
+var englishCompassPoints = [16]string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// Locale describes how values are formatted for display. The zero value
+// formats values in US units with English compass points.
+type Locale struct {
+	Units         string            // "us" (the default if blank) or "si"
+	CompassPoints [16]string        // N, NNE, NE, ... NNW; blank entries use the English abbreviation
+	Calm          string            // text used for calm winds, defaults to "calm"
+	Phrases       map[string]string // translations of English period names and summaries, see Translate
+}
+
+// Predefined locales for US and SI (metric) units in English.
+var (
+	LocaleUS = Locale{Units: "us"}
+	LocaleSI = Locale{Units: "si"}
+)
+
+// UnitName strips the namespace from a unit code, ex. wmoUnit:degC -> degC.
+func UnitName(unitCode string) string {
+	if i := strings.LastIndex(unitCode, ":"); i >= 0 {
+		return unitCode[i+1:]
+	}
+	return unitCode
+}
+
+// isSI reports whether the locale uses SI units
+func (l Locale) isSI() bool {
+	return strings.ToLower(l.Units) == "si"
+}
+
+// Temperature formats a temperature given in F, C or K (or the equivalent
+// wmoUnit codes) in the units of the locale, ex. 72°F. Blank or unknown units
+// are Celsius.
+func (l Locale) Temperature(value float64, unitCode string) string {
+	switch UnitName(unitCode) {
+	case "degC", "degF", "K", "C", "F":
+	default:
+		unitCode = "degC"
+	}
+	return l.Value(value, unitCode)
+}
+
+// WindSpeed formats a wind speed given in km/h, m/s, mph or knots (or the
+// equivalent wmoUnit codes) in the units of the locale, ex. 10 mph. Blank or
+// unknown units are km/h.
+func (l Locale) WindSpeed(value float64, unitCode string) string {
+	switch UnitName(unitCode) {
+	case "km_h-1", "m_s-1", "mph", "kt", "kn":
+	default:
+		unitCode = "km_h-1"
+	}
+	return l.Value(value, unitCode)
+}
+
+// Wind formats a wind speed and direction in degrees, ex. SW 10 mph.
+func (l Locale) Wind(speed float64, unitCode string, degrees float64) string {
+	if math.Round(toKilometersPerHour(speed, unitCode)) == 0 {
+		return l.calm()
+	}
+	return l.Compass(degrees) + " " + l.WindSpeed(speed, unitCode)
+}
+
+// Compass returns the localized compass point (one of 16) for a direction in degrees.
+func (l Locale) Compass(degrees float64) string {
+	i := int(math.Mod(math.Round(math.Mod(degrees, 360)/22.5)+16, 16))
+	return l.compassPoint(i)
+}
+
+// Direction localizes a compass point abbreviation such as the WindDirection
+// of a forecast period. Unknown values are returned unchanged.
+func (l Locale) Direction(abbr string) string {
+	for i, p := range englishCompassPoints {
+		if strings.EqualFold(p, abbr) {
+			return l.compassPoint(i)
+		}
+	}
+	return abbr
+}
+
+// compassPoint returns the localized name of compass point i
+func (l Locale) compassPoint(i int) string {
+	if l.CompassPoints[i] != "" {
+		return l.CompassPoints[i]
+	}
+	return englishCompassPoints[i]
+}
+
+// Precipitation formats a precipitation amount given in mm, cm, m or inches
+// (or the equivalent wmoUnit codes) in the units of the locale, ex. 0.25 in.
+// Blank or unknown units are mm.
+func (l Locale) Precipitation(value float64, unitCode string) string {
+	switch UnitName(unitCode) {
+	case "mm", "cm", "in":
+	case "m":
+		value, unitCode = value*1000, "mm"
+	default:
+		unitCode = "mm"
+	}
+	return l.Value(value, unitCode)
+}
+
+// Value formats a value by its unit code so that observations and gridpoint
+// series can be formatted uniformly. Temperatures, speeds, precipitation,
+// distances, pressures, percentages and angles are recognized; other units are
+// formatted as the value followed by the unit name.
+func (l Locale) Value(value float64, unitCode string) string {
+	if UnitName(unitCode) == "degree_(angle)" {
+		return l.Compass(value)
+	}
+	v, unit := l.Convert(value, unitCode)
+	switch unit {
+	case "°F", "°C":
+		return fmt.Sprintf("%.0f%s", v, unit)
+	case "%":
+		return fmt.Sprintf("%.0f%%", v)
+	case "in", "inHg":
+		return fmt.Sprintf("%.2f %s", v, unit)
+	case "mm", "km", "mi":
+		return fmt.Sprintf("%.1f %s", v, unit)
+	case "mph", "km/h", "m", "ft", "hPa":
+		return fmt.Sprintf("%.0f %s", v, unit)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%g %s", v, unit))
+}
+
+// Convert converts a value to the units of the locale and returns the
+// converted value with its display unit, ex. 20 wmoUnit:degC is 68 °F in US
+// units. Unrecognized units are returned unchanged without their namespace.
+func (l Locale) Convert(value float64, unitCode string) (float64, string) {
+	si := l.isSI()
+	unit := UnitName(unitCode)
+	switch unit {
+	case "degC", "degF", "K", "C", "F":
+		c := value
+		switch unit {
+		case "F", "degF":
+			c = (value - 32) * 5 / 9
+		case "K":
+			c = value - 273.15
+		}
+		if si {
+			return c, "°C"
+		}
+		return c*9/5 + 32, "°F"
+	case "km_h-1", "km/h", "m_s-1", "mph", "kt", "kn":
+		kmh := toKilometersPerHour(value, unitCode)
+		if si {
+			return kmh, "km/h"
+		}
+		return kmh / 1.609344, "mph"
+	case "mm", "cm", "in":
+		mm := value
+		switch unit {
+		case "cm":
+			mm = value * 10
+		case "in":
+			mm = value * 25.4
+		}
+		if si {
+			return mm, "mm"
+		}
+		return mm / 25.4, "in"
+	case "m":
+		if si {
+			return value, "m"
+		}
+		return value / 0.3048, "ft"
+	case "km":
+		if si {
+			return value, "km"
+		}
+		return value / 1.609344, "mi"
+	case "Pa":
+		if si {
+			return value / 100, "hPa"
+		}
+		return value / 3386.389, "inHg"
+	case "percent":
+		return value, "%"
+	}
+	return value, unit
+}
+
+// Observation formats an observation value, ex. observation.Temperature.
+func (l Locale) Observation(v ObservationValue) string {
+	return l.Value(v.Value, v.UnitCode)
+}
+
+// PeriodTemperature formats the temperature of a forecast period.
+func (l Locale) PeriodTemperature(p ForecastResponsePeriod) string {
+	return l.Temperature(p.Temperature, p.TemperatureUnit)
+}
+
+// PeriodWind formats the wind of a forecast period, ex. SW 5 to 10 mph.
+func (l Locale) PeriodWind(p ForecastResponsePeriod) string {
+	units := "us"
+	if l.isSI() {
+		units = "si"
+	}
+	wind := strings.TrimSpace(l.Direction(p.WindDirection) + " " + convertWindSpeed(p.WindSpeed, units))
+	if wind == "" {
+		return l.calm()
+	}
+	return wind
+}
+
+// calm returns the text used for calm winds
+func (l Locale) calm() string {
+	if l.Calm != "" {
+		return l.Calm
+	}
+	return "calm"
+}
+
+// toKilometersPerHour converts a speed in the given unit to km/h
+func toKilometersPerHour(value float64, unitCode string) float64 {
+	switch UnitName(unitCode) {
+	case "m_s-1":
+		return value * 3.6
+	case "mph":
+		return value * 1.609344
+	case "kt", "kn":
+		return value * 1.852
+	}
+	return value
+}
+
+// convertTemperature converts a temperature in F or C to the given units. If
+// units is blank the temperature is returned as is.
+func convertTemperature(value float64, unit string, units string) (float64, string) {
+	switch {
+	case units == "si" && unit == "F":
+		return math.Round((value-32)*5/9*10) / 10, "C"
+	case units == "us" && unit == "C":
+		return math.Round((value*9/5+32)*10) / 10, "F"
+	}
+	return value, unit
+}
+
+// windSpeedPattern matches the numbers in a wind speed such as "5 to 10 mph"
+var windSpeedPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// ParseWindSpeed returns the highest speed of the wind speed text of a
+// forecast period, ex. 10 for "5 to 10 mph", with its unit, mph, km/h or kt,
+// which Locale.Convert accepts. The unit is blank if the text has none and ok
+// is false if it has no speed, ex. for calm winds.
+func ParseWindSpeed(speed string) (value float64, unit string, ok bool) {
+	for _, n := range windSpeedPattern.FindAllString(speed, -1) {
+		if v, err := strconv.ParseFloat(n, 64); err == nil {
+			value, ok = math.Max(value, v), true
+		}
+	}
+	speed = strings.TrimSpace(speed)
+	for _, u := range []string{"mph", "km/h", "kt"} {
+		if strings.HasSuffix(speed, u) {
+			unit = u
+		}
+	}
+	return value, unit, ok
+}
+
+// convertWindSpeed converts wind speed text such as "5 to 10 mph" or
+// "10 km/h" to the given units. Text that cannot be converted is returned as is.
+func convertWindSpeed(speed string, units string) string {
+	var factor float64
+	var from, to string
+	switch {
+	case units == "si" && strings.HasSuffix(speed, "mph"):
+		factor, from, to = 1.609344, "mph", "km/h"
+	case units == "us" && strings.HasSuffix(speed, "km/h"):
+		factor, from, to = 1/1.609344, "km/h", "mph"
+	default:
+		return speed
+	}
+	converted := windSpeedPattern.ReplaceAllStringFunc(speed, func(n string) string {
+		v, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return n
+		}
+		return strconv.FormatFloat(math.Round(v*factor), 'f', -1, 64)
+	})
+	return strings.TrimSuffix(converted, from) + to
+}
@@ -0,0 +1,99 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestLocaleTemperature(t *testing.T) {
+	if s := noaa.LocaleUS.Temperature(20, "wmoUnit:degC"); s != "68°F" {
+		t.Errorf("20°C should format as 68°F, got %s", s)
+	}
+	if s := noaa.LocaleSI.Temperature(68, "F"); s != "20°C" {
+		t.Errorf("68°F should format as 20°C, got %s", s)
+	}
+}
+
+func TestLocaleUnknownUnits(t *testing.T) {
+	for _, unit := range []string{"", "wmoUnit:unknown"} {
+		if s := noaa.LocaleUS.Temperature(20, unit); s != "68°F" {
+			t.Errorf("a temperature in %q units should format as Celsius, got %s", unit, s)
+		}
+		if s := noaa.LocaleSI.WindSpeed(10, unit); s != "10 km/h" {
+			t.Errorf("a wind speed in %q units should format as km/h, got %s", unit, s)
+		}
+		if s := noaa.LocaleUS.Precipitation(6.35, unit); s != "0.25 in" {
+			t.Errorf("a precipitation in %q units should format as mm, got %s", unit, s)
+		}
+	}
+}
+
+func TestLocaleCompass(t *testing.T) {
+	spanish := noaa.Locale{Units: "si", CompassPoints: [16]string{
+		"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+		"S", "SSO", "SO", "OSO", "O", "ONO", "NO", "NNO",
+	}}
+	cases := map[float64]string{0: "N", 359: "N", 225: "SO", 270: "O", -90: "O"}
+	for degrees, want := range cases {
+		if got := spanish.Compass(degrees); got != want {
+			t.Errorf("Compass(%v) should be %s, got %s", degrees, want, got)
+		}
+	}
+	if got := spanish.Direction("WSW"); got != "OSO" {
+		t.Errorf("Direction(WSW) should be localized to OSO, got %s", got)
+	}
+}
+
+func TestLocaleValue(t *testing.T) {
+	observation := noaa.Observation{
+		WindSpeed:          noaa.ObservationValue{Value: 16.09344, UnitCode: "wmoUnit:km_h-1"},
+		BarometricPressure: noaa.ObservationValue{Value: 101320, UnitCode: "wmoUnit:Pa"},
+		RelativeHumidity:   noaa.ObservationValue{Value: 55.2, UnitCode: "wmoUnit:percent"},
+	}
+	if s := noaa.LocaleUS.Observation(observation.WindSpeed); s != "10 mph" {
+		t.Errorf("unexpected wind speed: %s", s)
+	}
+	if s := noaa.LocaleUS.Observation(observation.BarometricPressure); s != "29.92 inHg" {
+		t.Errorf("unexpected pressure: %s", s)
+	}
+	if s := noaa.LocaleSI.Observation(observation.BarometricPressure); s != "1013 hPa" {
+		t.Errorf("unexpected pressure: %s", s)
+	}
+	if s := noaa.LocaleSI.Observation(observation.RelativeHumidity); s != "55%" {
+		t.Errorf("unexpected humidity: %s", s)
+	}
+	if s := noaa.LocaleUS.Precipitation(6.35, "wmoUnit:mm"); s != "0.25 in" {
+		t.Errorf("unexpected precipitation: %s", s)
+	}
+	period := noaa.ForecastResponsePeriod{WindSpeed: "5 to 10 mph", WindDirection: "SW"}
+	if s := noaa.LocaleSI.PeriodWind(period); s != "SW 8 to 16 km/h" {
+		t.Errorf("unexpected period wind: %s", s)
+	}
+}
+
+func TestParseWindSpeed(t *testing.T) {
+	tests := []struct {
+		text  string
+		value float64
+		unit  string
+		ok    bool
+	}{
+		{"5 to 10 mph", 10, "mph", true},
+		{"15 km/h", 15, "km/h", true},
+		{"20 kt", 20, "kt", true},
+		{"Calm", 0, "", false},
+	}
+	for _, test := range tests {
+		value, unit, ok := noaa.ParseWindSpeed(test.text)
+		if value != test.value || unit != test.unit || ok != test.ok {
+			t.Errorf("noaa.ParseWindSpeed(%q) should return %v %q %v, got %v %q %v", test.text, test.value, test.unit, test.ok, value, unit, ok)
+		}
+	}
+	if v, unit := noaa.LocaleUS.Convert(16.09344, "km/h"); unit != "mph" || v < 9.99 || v > 10.01 {
+		t.Errorf("noaa.Locale.Convert() should convert km/h, got %v %s", v, unit)
+	}
+}
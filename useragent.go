@@ -0,0 +1,109 @@
+package noaa
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrDefaultUserAgent is returned for requests made with the default
+// User-Agent when RequireUserAgent is set.
+var ErrDefaultUserAgent = errors.New("the User-Agent has not been configured, see SetUserAgentInfo")
+
+// UserAgent identifies an application to weather.gov, which asks for contact
+// information in case of security events or abuse, ex.
+//
+//	noaa.SetUserAgentInfo(noaa.UserAgent{
+//		AppName:      "myweatherapp",
+//		Version:      "1.2.0",
+//		ContactURL:   "https://myweatherapp.com",
+//		ContactEmail: "contact@myweatherapp.com",
+//	})
+//
+// is sent as "myweatherapp/1.2.0 (https://myweatherapp.com, contact@myweatherapp.com)".
+type UserAgent struct {
+	AppName      string
+	Version      string // optional
+	ContactEmail string // at least one of ContactEmail and ContactURL is required
+	ContactURL   string
+}
+
+// String formats the User-Agent header value.
+func (u UserAgent) String() string {
+	product := u.AppName
+	if u.Version != "" {
+		product += "/" + u.Version
+	}
+	var contacts []string
+	for _, c := range []string{u.ContactURL, u.ContactEmail} {
+		if c != "" {
+			contacts = append(contacts, c)
+		}
+	}
+	return fmt.Sprintf("%s (%s)", product, strings.Join(contacts, ", "))
+}
+
+// Validate checks that the application name and a valid contact are set.
+func (u UserAgent) Validate() error {
+	if strings.TrimSpace(u.AppName) == "" || strings.ContainsAny(u.AppName, " /()") {
+		return fmt.Errorf("invalid user agent application name %q", u.AppName)
+	}
+	if u.ContactEmail == "" && u.ContactURL == "" {
+		return errors.New("the user agent requires a contact email or URL")
+	}
+	if u.ContactEmail != "" {
+		if _, err := mail.ParseAddress(u.ContactEmail); err != nil {
+			return fmt.Errorf("invalid user agent contact email %q", u.ContactEmail)
+		}
+	}
+	if u.ContactURL != "" {
+		if parsed, err := url.Parse(u.ContactURL); err != nil || parsed.Host == "" {
+			return fmt.Errorf("invalid user agent contact URL %q", u.ContactURL)
+		}
+	}
+	return nil
+}
+
+// SetUserAgentInfo validates the user agent and uses it for the User-Agent
+// header of requests. Use SetUserAgent to set the header value directly.
+func SetUserAgentInfo(u UserAgent) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+	agent := u.String()
+	updateConfig(func(c *Config) { c.UserAgent = agent })
+	return nil
+}
+
+// SetRequireUserAgent changes whether requests fail with ErrDefaultUserAgent
+// while the User-Agent is left at the library default. Otherwise a warning is
+// logged once, except in tests.
+func SetRequireUserAgent(required bool) {
+	updateConfig(func(c *Config) { c.RequireUserAgent = required })
+}
+
+// defaultUserAgentWarning logs the default User-Agent warning once
+var defaultUserAgentWarning sync.Once
+
+// checkUserAgent returns ErrDefaultUserAgent or logs a warning when the
+// default User-Agent is used
+func checkUserAgent() error {
+	c := currentConfig()
+	if c.UserAgent != APIKey {
+		return nil
+	}
+	if c.RequireUserAgent {
+		return ErrDefaultUserAgent
+	}
+	if !strings.HasSuffix(os.Args[0], ".test") {
+		defaultUserAgentWarning.Do(func() {
+			log.Printf("noaa: requests use the default User-Agent %q; weather.gov asks applications to identify themselves with contact information, see noaa.SetUserAgentInfo", APIKey)
+		})
+	}
+	return nil
+}
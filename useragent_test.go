@@ -0,0 +1,43 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestUserAgent(t *testing.T) {
+	t.Cleanup(func() { noaa.SetConfig(noaa.GetDefaultConfig()) })
+	ua := noaa.UserAgent{AppName: "myweatherapp", Version: "1.2.0", ContactURL: "https://myweatherapp.com", ContactEmail: "contact@myweatherapp.com"}
+	if err := noaa.SetUserAgentInfo(ua); err != nil {
+		t.Fatal(err)
+	}
+	expected := "myweatherapp/1.2.0 (https://myweatherapp.com, contact@myweatherapp.com)"
+	if noaa.GetConfig().UserAgent != expected {
+		t.Errorf("noaa.SetUserAgentInfo() should set %q, got %q", expected, noaa.GetConfig().UserAgent)
+	}
+
+	invalid := []noaa.UserAgent{
+		{ContactEmail: "contact@myweatherapp.com"},
+		{AppName: "myweatherapp"},
+		{AppName: "myweatherapp", ContactEmail: "not an email"},
+		{AppName: "myweatherapp", ContactURL: "myweatherapp"},
+	}
+	for _, u := range invalid {
+		if err := noaa.SetUserAgentInfo(u); err == nil {
+			t.Errorf("noaa.SetUserAgentInfo(%+v) should fail", u)
+		}
+	}
+}
+
+func TestRequireUserAgent(t *testing.T) {
+	t.Cleanup(func() { noaa.SetConfig(noaa.GetDefaultConfig()) })
+	noaa.SetRequireUserAgent(true)
+	if _, err := noaa.Office("LOT"); !errors.Is(err, noaa.ErrDefaultUserAgent) {
+		t.Errorf("noaa.Office() should return ErrDefaultUserAgent, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+// Package uv fetches the hourly UV index forecasts of the EPA Envirofacts
+// service, which the weather.gov API lacks, and aligns them with the periods
+// of NWS hourly forecasts:
+//
+//	forecast, err := noaa.HourlyForecast("41.837", "-87.685")
+//	...
+//	readings, err := uv.ForPoint(ctx, "41.837", "-87.685")
+//	...
+//	for _, p := range uv.Align(forecast, readings) {
+//		fmt.Println(p.Period.StartTime, p.Period.Summary, p.Index)
+//	}
+//
+// The EPA forecasts cover the next day by ZIP code or city. Points are
+// matched to the city nearest to them according to weather.gov. Provider adds
+// the UV index to weather bundles:
+//
+//	noaa.RegisterSupplementaryProvider(uv.Provider{})
+package uv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// BaseURL is the URL of the EPA Envirofacts service.
+var BaseURL = "https://data.epa.gov/efservice"
+
+// ErrNoForecast is returned when the EPA has no UV forecast for a location.
+var ErrNoForecast = errors.New("no UV index forecast for the location")
+
+// dateLayout is the layout of the DATE_TIME of the hourly forecasts, ex.
+// JUL/04/2023 01 PM
+const dateLayout = "Jan/02/2006 03 PM"
+
+// Reading is the forecast UV index of an hour.
+type Reading struct {
+	Time  time.Time // start of the hour
+	Index int
+}
+
+// Risk returns the WHO exposure category of the index, ex. High.
+func (r Reading) Risk() string {
+	switch {
+	case r.Index <= 2:
+		return "Low"
+	case r.Index <= 5:
+		return "Moderate"
+	case r.Index <= 7:
+		return "High"
+	case r.Index <= 10:
+		return "Very High"
+	}
+	return "Extreme"
+}
+
+// ForZIP returns the hourly UV index forecast of a ZIP code. Times are given
+// by the EPA in local time, loc.
+func ForZIP(ctx context.Context, zip string, loc *time.Location) ([]Reading, error) {
+	return fetch(ctx, "ZIP/"+url.PathEscape(strings.TrimSpace(zip)), loc)
+}
+
+// ForCity returns the hourly UV index forecast of a city, ex. Chicago, IL.
+// Times are given by the EPA in local time, loc.
+func ForCity(ctx context.Context, city string, state string, loc *time.Location) ([]Reading, error) {
+	return fetch(ctx, "CITY/"+url.PathEscape(strings.TrimSpace(city))+"/STATE/"+url.PathEscape(strings.ToUpper(strings.TrimSpace(state))), loc)
+}
+
+// ForPoint returns the hourly UV index forecast of the city nearest to
+// <lat,lon>, see noaa.Points.
+func ForPoint(ctx context.Context, lat string, lon string) ([]Reading, error) {
+	point, err := noaa.Points(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if point.RelativeLocation.City == "" || point.RelativeLocation.State == "" {
+		return nil, fmt.Errorf("no city near %s,%s: %w", lat, lon, ErrNoForecast)
+	}
+	loc, err := time.LoadLocation(point.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return ForCity(ctx, point.RelativeLocation.City, point.RelativeLocation.State, loc)
+}
+
+// fetch returns the readings of the getEnvirofactsUVHOURLY table filtered by
+// path, ex. ZIP/60601
+func fetch(ctx context.Context, path string, loc *time.Location) ([]Reading, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", BaseURL+"/getEnvirofactsUVHOURLY/"+path+"/JSON", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", noaa.GetConfig().UserAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", req.URL, res.Status)
+	}
+	var rows []struct {
+		DateTime string `json:"DATE_TIME"`
+		Value    int    `json:"UV_VALUE"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("%s: %w", req.URL, err)
+	}
+	readings := make([]Reading, 0, len(rows))
+	for _, row := range rows {
+		t, err := time.ParseInLocation(dateLayout, row.DateTime, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid time %q", req.URL, row.DateTime)
+		}
+		readings = append(readings, Reading{Time: t, Index: row.Value})
+	}
+	if len(readings) == 0 {
+		return nil, ErrNoForecast
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Time.Before(readings[j].Time) })
+	return readings, nil
+}
+
+// Period is an hourly forecast period with its UV index.
+type Period struct {
+	Period *noaa.ForecastResponsePeriodHourly
+	Index  int
+	OK     bool // false if the UV forecast does not cover the period
+}
+
+// Align returns the periods of the hourly forecast with the UV index of the
+// reading of their hour. Periods beyond the UV forecast, usually after the
+// next day, are not OK.
+func Align(forecast *noaa.HourlyForecastResponse, readings []Reading) []Period {
+	byHour := make(map[int64]int, len(readings))
+	for _, r := range readings {
+		byHour[r.Time.Truncate(time.Hour).Unix()] = r.Index
+	}
+	periods := make([]Period, len(forecast.Periods))
+	for i := range forecast.Periods {
+		p := &forecast.Periods[i]
+		periods[i].Period = p
+		start, err := time.Parse(time.RFC3339, p.StartTime)
+		if err != nil {
+			continue
+		}
+		periods[i].Index, periods[i].OK = byHour[start.Truncate(time.Hour).Unix()]
+	}
+	return periods
+}
+
+// Provider is a noaa.SupplementaryDataProvider of the UV index forecast of
+// points, named uv, with a single series named uv.index.
+type Provider struct{}
+
+// Name returns uv.
+func (Provider) Name() string { return "uv" }
+
+// Series returns the UV index forecast of <lat,lon>, see ForPoint.
+func (Provider) Series(ctx context.Context, lat string, lon string) ([]noaa.SupplementarySeries, error) {
+	readings, err := ForPoint(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	series := noaa.SupplementarySeries{Name: "uv.index", Values: make([]noaa.SupplementaryValue, len(readings))}
+	for i, r := range readings {
+		series.Values[i] = noaa.SupplementaryValue{Time: r.Time, Value: float64(r.Index), Category: r.Risk()}
+	}
+	return []noaa.SupplementarySeries{series}, nil
+}
@@ -0,0 +1,79 @@
+package uv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/uv"
+)
+
+func TestForPoint(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/41.837,-87.685":
+			fmt.Fprint(w, `{"timeZone": "America/Chicago", "relativeLocation": {"city": "Chicago", "state": "IL"}}`)
+		case "/getEnvirofactsUVHOURLY/CITY/Chicago/STATE/IL/JSON":
+			fmt.Fprint(w, `[
+				{"ORDER": 2, "CITY": "CHICAGO", "STATE": "IL", "DATE_TIME": "JUL/04/2023 01 PM", "UV_VALUE": 9},
+				{"ORDER": 1, "CITY": "CHICAGO", "STATE": "IL", "DATE_TIME": "JUL/04/2023 12 PM", "UV_VALUE": 8}
+			]`)
+		case "/getEnvirofactsUVHOURLY/ZIP/00000/JSON":
+			fmt.Fprint(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client, baseURL := http.DefaultClient, uv.BaseURL
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient, uv.BaseURL = client, baseURL
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	uv.BaseURL = api.URL
+
+	readings, err := uv.ForPoint(context.Background(), "41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	noon := time.Date(2023, 7, 4, 17, 0, 0, 0, time.UTC)
+	if len(readings) != 2 || !readings[0].Time.Equal(noon) || readings[0].Index != 8 || readings[1].Index != 9 {
+		t.Fatalf("expected the readings in order in Chicago time, got %+v", readings)
+	}
+	if readings[0].Risk() != "Very High" || (uv.Reading{Index: 11}).Risk() != "Extreme" {
+		t.Errorf("expected the WHO risk categories, got %s", readings[0].Risk())
+	}
+
+	if _, err := uv.ForZIP(context.Background(), "00000", time.UTC); !errors.Is(err, uv.ErrNoForecast) {
+		t.Errorf("expected ErrNoForecast for an unknown ZIP code, got %v", err)
+	}
+
+	forecast := &noaa.HourlyForecastResponse{}
+	for _, start := range []string{"2023-07-04T12:00:00-05:00", "2023-07-04T13:00:00-05:00", "2023-07-04T14:00:00-05:00"} {
+		var p noaa.ForecastResponsePeriodHourly
+		p.StartTime = start
+		forecast.Periods = append(forecast.Periods, p)
+	}
+	periods := uv.Align(forecast, readings)
+	if len(periods) != 3 || !periods[0].OK || periods[0].Index != 8 || periods[1].Index != 9 || periods[2].OK {
+		t.Errorf("expected the UV index of the first 2 periods, got %+v", periods)
+	}
+	if periods[1].Period != &forecast.Periods[1] {
+		t.Error("expected the periods to point to the forecast periods")
+	}
+
+	series, err := uv.Provider{}.Series(context.Background(), "41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || len(series[0].Values) != 2 || series[0].Values[1].Value != 9 || series[0].Values[1].Category != "Very High" {
+		t.Errorf("expected the provider to return the readings as a series, got %+v", series)
+	}
+}
@@ -0,0 +1,131 @@
+package noaa
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationPattern matches the ISO 8601 durations used by validTime values,
+// ex. PT1H, PT12H, P1D, P1DT6H or P7DT30M
+var durationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseValidTime parses an ISO 8601 time interval used by validTime values of
+// gridpoint forecasts, ex. 2019-07-04T18:00:00+00:00/PT3H, and returns the start
+// time and duration of the interval. Intervals given as start/end are supported.
+func ParseValidTime(validTime string) (start time.Time, duration time.Duration, err error) {
+	parts := strings.SplitN(validTime, "/", 2)
+	start, err = time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid valid time %q: %w", validTime, err)
+	}
+	if len(parts) == 1 {
+		return start, 0, nil
+	}
+	if strings.HasPrefix(parts[1], "P") {
+		duration, err = ParseDuration(parts[1])
+		return start, duration, err
+	}
+	end, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid valid time %q: %w", validTime, err)
+	}
+	return start, end.Sub(start), nil
+}
+
+// ParseDuration parses the subset of ISO 8601 durations used by the API, ex.
+// PT3H or P1DT6H. Years, months and weeks are not supported.
+func ParseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(s)
+	// a duration has at least one value and T is followed by a time value
+	if m == nil || m[1]+m[2]+m[3]+m[4] == "" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var d time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
+
+// ValidInterval returns the start time and duration of the forecast's
+// validTimes, the interval of the grid data it was generated from.
+func (f *ForecastResponse) ValidInterval() (start time.Time, duration time.Duration, err error) {
+	return ParseValidTime(f.ValidTimes)
+}
+
+// FreshUntil returns the end of the forecast's validTimes, after which the
+// forecast no longer covers the current time, or the zero time if validTimes
+// is missing or invalid.
+func (f *ForecastResponse) FreshUntil() time.Time {
+	return validUntil(f.ValidTimes)
+}
+
+// IsExpired reports whether the forecast's validTimes ended, see FreshUntil.
+// Forecasts without validTimes never expire.
+func (f *ForecastResponse) IsExpired() bool {
+	return expired(f.FreshUntil())
+}
+
+// ValidInterval returns the start time and duration of the hourly forecast's
+// validTimes, the interval of the grid data it was generated from.
+func (f *HourlyForecastResponse) ValidInterval() (start time.Time, duration time.Duration, err error) {
+	return ParseValidTime(f.ValidTimes)
+}
+
+// FreshUntil returns the end of the hourly forecast's validTimes, after which
+// the forecast no longer covers the current time, or the zero time if
+// validTimes is missing or invalid.
+func (f *HourlyForecastResponse) FreshUntil() time.Time {
+	return validUntil(f.ValidTimes)
+}
+
+// IsExpired reports whether the hourly forecast's validTimes ended, see
+// FreshUntil. Forecasts without validTimes never expire.
+func (f *HourlyForecastResponse) IsExpired() bool {
+	return expired(f.FreshUntil())
+}
+
+// validUntil returns the end of the validTimes interval, zero if invalid
+func validUntil(validTimes string) time.Time {
+	start, d, err := ParseValidTime(validTimes)
+	if err != nil || d == 0 {
+		return time.Time{}
+	}
+	return start.Add(d)
+}
+
+// expired reports whether the end of a validTimes interval has passed
+func expired(until time.Time) bool {
+	return !until.IsZero() && !time.Now().Before(until)
+}
+
+// Interval returns the start time and duration of the value's validTime.
+func (v GridpointForecastTimeSeriesValue) Interval() (start time.Time, duration time.Duration, err error) {
+	return ParseValidTime(v.ValidTime)
+}
+
+// At returns the value of the series which is valid at the given time. The
+// second return value is false if no value covers the time.
+func (s GridpointForecastTimeSeries) At(t time.Time) (float64, bool) {
+	for _, v := range s.Values {
+		start, d, err := v.Interval()
+		if err != nil {
+			continue
+		}
+		if !t.Before(start) && t.Before(start.Add(d)) {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
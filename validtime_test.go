@@ -0,0 +1,91 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestParseValidTime(t *testing.T) {
+	cases := map[string]time.Duration{
+		"2019-07-04T18:00:00+00:00/PT3H":                      3 * time.Hour,
+		"2019-07-04T18:00:00+00:00/P1DT6H":                    30 * time.Hour,
+		"2019-07-04T18:00:00+00:00/P7D":                       7 * 24 * time.Hour,
+		"2019-07-04T18:00:00+00:00/PT1H30M":                   90 * time.Minute,
+		"2019-07-04T18:00:00+00:00/2019-07-04T20:00:00+00:00": 2 * time.Hour,
+	}
+	for validTime, want := range cases {
+		start, d, err := noaa.ParseValidTime(validTime)
+		if err != nil {
+			t.Errorf("noaa.ParseValidTime(%q) returned an error: %v", validTime, err)
+			continue
+		}
+		if !start.Equal(time.Date(2019, 7, 4, 18, 0, 0, 0, time.UTC)) || d != want {
+			t.Errorf("noaa.ParseValidTime(%q) = %v, %v", validTime, start, d)
+		}
+	}
+	for _, invalid := range []string{"", "2019-07-04T18:00:00+00:00/P", "2019-07-04T18:00:00+00:00/P1Y"} {
+		if _, _, err := noaa.ParseValidTime(invalid); err == nil {
+			t.Errorf("noaa.ParseValidTime(%q) should return an error", invalid)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"PT3H":     3 * time.Hour,
+		"P1D":      24 * time.Hour,
+		"P1DT30M":  24*time.Hour + 30*time.Minute,
+		"PT45S":    45 * time.Second,
+		"PT0H":     0,
+		"P1DT6H1S": 30*time.Hour + time.Second,
+	}
+	for s, want := range cases {
+		if d, err := noaa.ParseDuration(s); err != nil || d != want {
+			t.Errorf("noaa.ParseDuration(%q) should return %v, got %v, %v", s, want, d, err)
+		}
+	}
+	for _, invalid := range []string{"", "P", "PT", "P1DT", "T1H", "P1H", "PT1D", "P1W"} {
+		if _, err := noaa.ParseDuration(invalid); err == nil {
+			t.Errorf("noaa.ParseDuration(%q) should return an error", invalid)
+		}
+	}
+}
+
+func TestTimeSeriesAt(t *testing.T) {
+	series := noaa.GridpointForecastTimeSeries{Values: []noaa.GridpointForecastTimeSeriesValue{
+		{ValidTime: "2019-07-04T18:00:00+00:00/PT3H", Value: 20},
+		{ValidTime: "2019-07-04T21:00:00+00:00/PT1H", Value: 18},
+	}}
+	if v, ok := series.At(time.Date(2019, 7, 4, 20, 59, 0, 0, time.UTC)); !ok || v != 20 {
+		t.Errorf("unexpected value %v", v)
+	}
+	if v, ok := series.At(time.Date(2019, 7, 4, 21, 0, 0, 0, time.UTC)); !ok || v != 18 {
+		t.Errorf("unexpected value %v", v)
+	}
+	if _, ok := series.At(time.Date(2019, 7, 4, 22, 0, 0, 0, time.UTC)); ok {
+		t.Error("no value should cover a time after the series")
+	}
+}
+
+func TestForecastExpiry(t *testing.T) {
+	now := time.Now().UTC()
+	fresh := &noaa.HourlyForecastResponse{ValidTimes: now.Add(-time.Hour).Format(time.RFC3339) + "/P7DT2H"}
+	if until := fresh.FreshUntil(); !until.Equal(now.Add(-time.Hour).Truncate(time.Second).Add(170*time.Hour)) || fresh.IsExpired() {
+		t.Errorf("noaa.HourlyForecastResponse.FreshUntil() should end with the validTimes, got %v", until)
+	}
+	expired := &noaa.ForecastResponse{ValidTimes: now.Add(-8*24*time.Hour).Format(time.RFC3339) + "/P7D"}
+	if !expired.IsExpired() {
+		t.Errorf("noaa.ForecastResponse.IsExpired() should report validTimes which ended, got %v", expired.FreshUntil())
+	}
+	for _, validTimes := range []string{"", "soon"} {
+		f := &noaa.HourlyForecastResponse{ValidTimes: validTimes}
+		if !f.FreshUntil().IsZero() || f.IsExpired() {
+			t.Errorf("noaa.HourlyForecastResponse.IsExpired() should not expire forecasts with validTimes %q", validTimes)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package noaa
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VTEC actions. See https://www.weather.gov/vtec/ for details.
+const (
+	VTECActionNew               = "NEW" // new event
+	VTECActionContinue          = "CON" // event continued
+	VTECActionExtendTime        = "EXT" // event extended (time)
+	VTECActionExtendArea        = "EXA" // event extended (area)
+	VTECActionExtendTimeAndArea = "EXB" // event extended (both time and area)
+	VTECActionUpgrade           = "UPG" // event upgraded
+	VTECActionCancel            = "CAN" // event cancelled
+	VTECActionExpire            = "EXP" // event expired
+	VTECActionCorrection        = "COR" // correction
+	VTECActionRoutine           = "ROU" // routine
+)
+
+// VTEC times are formatted as yymmddThhnnZ and all zeros means undefined
+const (
+	vtecTimeLayout    = "060102T1504Z"
+	vtecUndefinedTime = "000000T0000Z"
+)
+
+// ErrInvalidVTEC is returned when a string is not a valid P-VTEC string.
+var ErrInvalidVTEC = errors.New("invalid vtec string")
+
+// vtecPattern matches a P-VTEC string with or without the surrounding slashes
+var vtecPattern = regexp.MustCompile(`/?([OTEX])\.([A-Z]{3})\.([A-Z]{4})\.([A-Z]{2})\.([A-Z])\.(\d{4})\.(\d{6}T\d{4}Z)-(\d{6}T\d{4}Z)/?`)
+
+// VTEC holds the values of a Primary Valid Time Event Code (P-VTEC) string
+// such as /O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/ which is embedded
+// in alerts and products to identify an event across its updates.
+// See https://www.weather.gov/vtec/ for details.
+type VTEC struct {
+	Class        string    // O (operational), T (test), E or X (experimental)
+	Action       string    // NEW, CON, EXT, EXA, EXB, UPG, CAN, EXP, COR, ROU
+	Office       string    // ex. KBOU
+	Phenomenon   string    // ex. SV (severe thunderstorm), TO (tornado), WS (winter storm)
+	Significance string    // W (warning), A (watch), Y (advisory), S (statement), etc.
+	EventNumber  int       // ETN, unique per office, phenomenon and significance each year
+	Begin        time.Time // zero if the event was already in effect
+	End          time.Time // zero if the event ends until further notice
+}
+
+// ParseVTEC parses a single P-VTEC string. The surrounding slashes are optional.
+func ParseVTEC(s string) (vtec VTEC, err error) {
+	m := vtecPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil || len(m[0]) != len(strings.TrimSpace(s)) {
+		return VTEC{}, fmt.Errorf("%w: %q", ErrInvalidVTEC, s)
+	}
+	return vtecFromMatch(m)
+}
+
+// FindVTEC returns all the valid P-VTEC strings found in the given text, for
+// example the raw text of a product. Invalid matches are skipped.
+func FindVTEC(text string) []VTEC {
+	var found []VTEC
+	for _, m := range vtecPattern.FindAllStringSubmatch(text, -1) {
+		if vtec, err := vtecFromMatch(m); err == nil {
+			found = append(found, vtec)
+		}
+	}
+	return found
+}
+
+// vtecFromMatch converts the submatches of vtecPattern into a VTEC
+func vtecFromMatch(m []string) (vtec VTEC, err error) {
+	etn, err := strconv.Atoi(m[6])
+	if err != nil {
+		return VTEC{}, fmt.Errorf("%w: event number %q", ErrInvalidVTEC, m[6])
+	}
+	begin, err := parseVTECTime(m[7])
+	if err != nil {
+		return VTEC{}, err
+	}
+	end, err := parseVTECTime(m[8])
+	if err != nil {
+		return VTEC{}, err
+	}
+	return VTEC{
+		Class:        m[1],
+		Action:       m[2],
+		Office:       m[3],
+		Phenomenon:   m[4],
+		Significance: m[5],
+		EventNumber:  etn,
+		Begin:        begin,
+		End:          end,
+	}, nil
+}
+
+// parseVTECTime parses the yymmddThhnnZ format used by VTEC where all zeros
+// represents an undefined time.
+func parseVTECTime(s string) (time.Time, error) {
+	if s == vtecUndefinedTime {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(vtecTimeLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: time %q", ErrInvalidVTEC, s)
+	}
+	return t, nil
+}
+
+// formatVTECTime is the inverse of parseVTECTime
+func formatVTECTime(t time.Time) string {
+	if t.IsZero() {
+		return vtecUndefinedTime
+	}
+	return t.UTC().Format(vtecTimeLayout)
+}
+
+// String returns the P-VTEC string, including the surrounding slashes.
+func (v VTEC) String() string {
+	return fmt.Sprintf("/%s.%s.%s.%s.%s.%04d.%s-%s/", v.Class, v.Action, v.Office,
+		v.Phenomenon, v.Significance, v.EventNumber, formatVTECTime(v.Begin), formatVTECTime(v.End))
+}
+
+// EventID returns the key which identifies the event across all of its updates,
+// ex. KBOU.SV.W.0042. Event numbers are reused every year so callers tracking
+// events over long periods should also consider the year the event was issued.
+func (v VTEC) EventID() string {
+	return fmt.Sprintf("%s.%s.%s.%04d", v.Office, v.Phenomenon, v.Significance, v.EventNumber)
+}
+
+// IsOperational reports whether the VTEC is for an operational (not test or
+// experimental) product.
+func (v VTEC) IsOperational() bool {
+	return v.Class == "O"
+}
+
+// IsEnding reports whether the action ends the event (cancelled, expired or
+// upgraded to a different event).
+func (v VTEC) IsEnding() bool {
+	return isEndingAction(v.Action)
+}
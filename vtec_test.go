@@ -0,0 +1,64 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestParseVTEC(t *testing.T) {
+	vtec, err := noaa.ParseVTEC("/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/")
+	if err != nil {
+		t.Fatalf("noaa.ParseVTEC() should parse a valid vtec string: %v", err)
+	}
+	if vtec.Action != "NEW" || vtec.Office != "KBOU" || vtec.Phenomenon != "SV" ||
+		vtec.Significance != "W" || vtec.EventNumber != 42 {
+		t.Errorf("noaa.ParseVTEC() returned unexpected values: %+v", vtec)
+	}
+	if !vtec.Begin.Equal(time.Date(2023, 6, 15, 21, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected begin time: %v", vtec.Begin)
+	}
+	if !vtec.End.Equal(time.Date(2023, 6, 15, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected end time: %v", vtec.End)
+	}
+	if vtec.EventID() != "KBOU.SV.W.0042" {
+		t.Errorf("unexpected event id: %s", vtec.EventID())
+	}
+	if vtec.String() != "/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/" {
+		t.Errorf("String() should round trip, got %s", vtec.String())
+	}
+}
+
+func TestParseVTECUndefinedBegin(t *testing.T) {
+	vtec, err := noaa.ParseVTEC("O.CON.KLOT.WW.Y.0005.000000T0000Z-230102T0000Z")
+	if err != nil {
+		t.Fatalf("noaa.ParseVTEC() should parse a vtec string without slashes: %v", err)
+	}
+	if !vtec.Begin.IsZero() {
+		t.Error("an all zero begin time should be returned as the zero time")
+	}
+}
+
+func TestParseVTECInvalid(t *testing.T) {
+	for _, s := range []string{"", "/O.NEW.KBOU/", "/O.NEW.KBOU.SV.W.0042.230615T2100Z-230615T2200Z/ extra"} {
+		if _, err := noaa.ParseVTEC(s); err == nil {
+			t.Errorf("noaa.ParseVTEC(%q) should return an error", s)
+		}
+	}
+}
+
+func TestFindVTEC(t *testing.T) {
+	text := "WWUS53 KBOU 152100\n/O.UPG.KBOU.SV.A.0010.000000T0000Z-230615T2300Z/\n" +
+		"/O.NEW.KBOU.TO.W.0003.230615T2100Z-230615T2145Z/\n"
+	vtecs := noaa.FindVTEC(text)
+	if len(vtecs) != 2 {
+		t.Fatalf("noaa.FindVTEC() should find 2 vtec strings, found %d", len(vtecs))
+	}
+	if !vtecs[0].IsEnding() || vtecs[1].IsEnding() {
+		t.Error("only the upgraded event should be ending")
+	}
+}
@@ -0,0 +1,176 @@
+package noaa
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WindowCriteria are the conditions of the hours of an ActivityWindow, ex. to
+// find when to mow, run or paint. Nil limits are not checked. Temperatures and
+// wind speeds are in the units of Locale, °F and mph for the zero value.
+type WindowCriteria struct {
+	Duration time.Duration // minimum length of the windows, an hour if 0
+	Locale   Locale
+
+	MaxPrecipitationChance *float64 // percent, ex. 0 for no chance of rain
+	MinTemperature         *float64
+	MaxTemperature         *float64
+	MaxWindSpeed           *float64 // highest speed of the forecast, ex. 10 for "5 to 10 mph"
+	DaylightOnly           bool
+
+	Max int // maximum number of windows returned, all if 0
+}
+
+// ActivityWindow is a time when every hour meets the criteria, see
+// FindWindows.
+type ActivityWindow struct {
+	Start   time.Time
+	End     time.Time
+	Score   float64 // from 0 to 1, higher when the hours meet the criteria by a wider margin
+	Periods []ForecastResponsePeriodHourly
+}
+
+// Duration returns the length of the window.
+func (w ActivityWindow) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// FindWindows returns the windows of the hourly forecast of <lat,lon> which
+// meet the criteria, best first, see HourlyForecastResponse.FindWindows.
+func FindWindows(lat string, lon string, c WindowCriteria) ([]ActivityWindow, error) {
+	forecast, err := HourlyForecast(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	return forecast.FindWindows(c), nil
+}
+
+// FindWindows returns the runs of consecutive hours which meet the criteria
+// and last at least the criteria Duration, best first. Windows are ranked by
+// score, the mean margin of their hours within the limits, then by start
+// time. Hours whose wind speed or times cannot be parsed never qualify.
+func (f *HourlyForecastResponse) FindWindows(c WindowCriteria) []ActivityWindow {
+	minDuration := c.Duration
+	if minDuration <= 0 {
+		minDuration = time.Hour
+	}
+	var windows []ActivityWindow
+	var run ActivityWindow
+	var total float64
+	closeRun := func() {
+		if len(run.Periods) > 0 && run.Duration() >= minDuration {
+			run.Score = total / float64(len(run.Periods))
+			windows = append(windows, run)
+		}
+		run, total = ActivityWindow{}, 0
+	}
+	for _, p := range f.Periods {
+		start, end, err := p.Interval()
+		score, ok := c.score(p)
+		if err != nil || !ok {
+			closeRun()
+			continue
+		}
+		if len(run.Periods) > 0 && !start.Equal(run.End) {
+			closeRun()
+		}
+		if len(run.Periods) == 0 {
+			run.Start = start
+		}
+		run.End = end
+		run.Periods = append(run.Periods, p)
+		total += score
+	}
+	closeRun()
+	sort.SliceStable(windows, func(i, j int) bool {
+		if windows[i].Score != windows[j].Score {
+			return windows[i].Score > windows[j].Score
+		}
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	if c.Max > 0 && len(windows) > c.Max {
+		windows = windows[:c.Max]
+	}
+	return windows
+}
+
+// score reports whether the hour meets the criteria and how comfortably, from
+// 0 at a limit to 1 far from it
+func (c WindowCriteria) score(p ForecastResponsePeriodHourly) (float64, bool) {
+	if c.DaylightOnly && !p.IsDaytime {
+		return 0, false
+	}
+	var scores []float64
+	if c.MaxPrecipitationChance != nil {
+		chance := p.ProbabilityOfPrecipitation.Value
+		if chance > *c.MaxPrecipitationChance {
+			return 0, false
+		}
+		scores = append(scores, 1-chance/100)
+	}
+	if c.MinTemperature != nil || c.MaxTemperature != nil {
+		t, _ := c.Locale.Convert(p.Temperature, p.TemperatureUnit)
+		if (c.MinTemperature != nil && t < *c.MinTemperature) || (c.MaxTemperature != nil && t > *c.MaxTemperature) {
+			return 0, false
+		}
+		if c.MinTemperature != nil && c.MaxTemperature != nil && *c.MaxTemperature > *c.MinTemperature {
+			half := (*c.MaxTemperature - *c.MinTemperature) / 2
+			scores = append(scores, 1-math.Abs(t-*c.MinTemperature-half)/half)
+		}
+	}
+	if c.MaxWindSpeed != nil {
+		kmh, ok := maxWindSpeed(p.WindSpeed)
+		if !ok {
+			return 0, false
+		}
+		speed, _ := c.Locale.Convert(kmh, "km_h-1")
+		speed = math.Round(speed*10) / 10 // 10 mph is not 10.000000001 mph
+		if speed > *c.MaxWindSpeed {
+			return 0, false
+		}
+		if *c.MaxWindSpeed > 0 {
+			scores = append(scores, 1 - speed / *c.MaxWindSpeed)
+		}
+	}
+	if len(scores) == 0 {
+		return 1, true
+	}
+	var sum float64
+	for _, s := range scores {
+		sum += s
+	}
+	return sum / float64(len(scores)), true
+}
+
+// maxWindSpeed returns the highest speed of a wind speed text such as "5 to
+// 10 mph" or "15 km/h" in km/h. Blank and calm winds are 0.
+func maxWindSpeed(speed string) (float64, bool) {
+	speed = strings.TrimSpace(speed)
+	if speed == "" || strings.EqualFold(speed, "calm") {
+		return 0, true
+	}
+	var highest float64
+	numbers := windSpeedPattern.FindAllString(speed, -1)
+	if len(numbers) == 0 {
+		return 0, false
+	}
+	for _, n := range numbers {
+		v, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, false
+		}
+		highest = math.Max(highest, v)
+	}
+	switch {
+	case strings.HasSuffix(speed, "km/h"):
+		return highest, true
+	case strings.HasSuffix(speed, "mph"):
+		return toKilometersPerHour(highest, "mph"), true
+	case strings.HasSuffix(speed, "kt"):
+		return toKilometersPerHour(highest, "kt"), true
+	}
+	return 0, false
+}
@@ -0,0 +1,69 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// hourlyConditions returns an hourly forecast from 6:00 with the
+// temperature, wind and chance of precipitation of each hour
+func hourlyConditions(hours ...[3]interface{}) *noaa.HourlyForecastResponse {
+	start := time.Date(2023, 7, 4, 6, 0, 0, 0, time.FixedZone("CDT", -5*60*60))
+	f := &noaa.HourlyForecastResponse{}
+	for i, h := range hours {
+		var p noaa.ForecastResponsePeriodHourly
+		p.StartTime = start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		p.EndTime = start.Add(time.Duration(i+1) * time.Hour).Format(time.RFC3339)
+		p.IsDaytime = i < 14
+		p.Temperature, p.TemperatureUnit = h[0].(float64), "F"
+		p.WindSpeed = h[1].(string)
+		p.ProbabilityOfPrecipitation.Value = h[2].(float64)
+		f.Periods = append(f.Periods, p)
+	}
+	return f
+}
+
+func TestFindWindows(t *testing.T) {
+	f := hourlyConditions(
+		[3]interface{}{60.0, "5 mph", 0.0},
+		[3]interface{}{68.0, "5 mph", 0.0},
+		[3]interface{}{70.0, "5 to 15 mph", 0.0}, // too windy
+		[3]interface{}{72.0, "5 mph", 10.0},
+		[3]interface{}{74.0, "10 mph", 0.0},
+		[3]interface{}{75.0, "10 mph", 0.0},
+		[3]interface{}{80.0, "0 mph", 60.0}, // rain
+		[3]interface{}{70.0, "calm", 0.0},
+	)
+	chance, low, high, wind := 20.0, 55.0, 85.0, 10.0
+	windows := f.FindWindows(noaa.WindowCriteria{
+		Duration:               2 * time.Hour,
+		MaxPrecipitationChance: &chance,
+		MinTemperature:         &low,
+		MaxTemperature:         &high,
+		MaxWindSpeed:           &wind,
+		DaylightOnly:           true,
+	})
+	if len(windows) != 2 {
+		t.Fatalf("noaa.HourlyForecastResponse.FindWindows() should find 2 windows of 2 hours or more, got %+v", windows)
+	}
+	if windows[0].Start.Hour() != 6 || windows[0].Duration() != 2*time.Hour {
+		t.Errorf("noaa.HourlyForecastResponse.FindWindows() should rank the calmest window first, got %v to %v", windows[0].Start, windows[0].End)
+	}
+	if windows[1].Start.Hour() != 9 || len(windows[1].Periods) != 3 || windows[1].Score >= windows[0].Score {
+		t.Errorf("noaa.HourlyForecastResponse.FindWindows() should rank the windier window second, got %+v", windows[1])
+	}
+
+	c := 10.0
+	si := noaa.WindowCriteria{Locale: noaa.LocaleSI, MaxTemperature: &c, Max: 1}
+	if windows := f.FindWindows(si); len(windows) != 0 {
+		t.Errorf("noaa.HourlyForecastResponse.FindWindows() should use the units of the locale, got %+v", windows)
+	}
+	if windows := f.FindWindows(noaa.WindowCriteria{Max: 1}); len(windows) != 1 || len(windows[0].Periods) != 8 {
+		t.Errorf("noaa.HourlyForecastResponse.FindWindows() should return every hour without criteria, got %+v", windows)
+	}
+}
@@ -0,0 +1,139 @@
+package noaa
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// DefaultWindRoseSpeeds are the upper bounds in km/h of the speed bins used
+// by NewWindRose when none are given. The last bin holds the higher speeds.
+var DefaultWindRoseSpeeds = []float64{10, 20, 30, 40, 50}
+
+// WindRose holds the frequency of observed winds by direction sector and
+// speed bin, ready to plot as a wind rose, see NewWindRose. Sector 0 is
+// centered on north and sectors go clockwise.
+type WindRose struct {
+	Sectors int       // number of direction sectors, ex. 16
+	Speeds  []float64 // upper bounds of the speed bins in km/h, the last bin is open ended
+	Counts  [][]int   // observations by sector and speed bin
+	Calm    int       // observations without wind
+	Total   int       // observations counted, including calm ones
+
+	// Start and End are the times of the first and last observations
+	// counted.
+	Start time.Time
+	End   time.Time
+}
+
+// NewWindRose bins the wind of the observations by direction and speed.
+// sectors is the number of direction sectors, 16 if 0, and speeds are the
+// upper bounds of the speed bins in km/h, DefaultWindRoseSpeeds if empty.
+// Observations whose wind speed or direction failed quality control are
+// skipped, as are winds of variable direction, which weather.gov reports
+// with a direction of 0 (north is 360). A speed of 0 is calm.
+func NewWindRose(observations []Observation, sectors int, speeds []float64) *WindRose {
+	if sectors <= 0 {
+		sectors = 16
+	}
+	if len(speeds) == 0 {
+		speeds = DefaultWindRoseSpeeds
+	}
+	r := &WindRose{Sectors: sectors, Speeds: speeds, Counts: make([][]int, sectors)}
+	for i := range r.Counts {
+		r.Counts[i] = make([]int, len(speeds)+1)
+	}
+	for _, o := range observations {
+		if !o.WindSpeed.PassedQualityControl() || !o.WindDirection.PassedQualityControl() {
+			continue
+		}
+		speed := toKilometersPerHour(o.WindSpeed.Value, o.WindSpeed.UnitCode)
+		switch {
+		case speed <= 0:
+			r.Calm++
+		case o.WindDirection.Value <= 0:
+			continue // variable
+		default:
+			sector := int(math.Floor(math.Mod(o.WindDirection.Value+180/float64(sectors), 360) / (360 / float64(sectors))))
+			bin := len(speeds)
+			for i, upper := range speeds {
+				if speed < upper {
+					bin = i
+					break
+				}
+			}
+			r.Counts[sector%sectors][bin]++
+		}
+		r.Total++
+		if r.Start.IsZero() || o.Timestamp.Before(r.Start) {
+			r.Start = o.Timestamp
+		}
+		if o.Timestamp.After(r.End) {
+			r.End = o.Timestamp
+		}
+	}
+	return r
+}
+
+// WindRoseForStation returns the wind rose of the routine observations of a
+// station between start and end, see StationObservations and NewWindRose.
+// weather.gov keeps about a week of observations.
+func WindRoseForStation(station string, start time.Time, end time.Time, sectors int, speeds []float64) (*WindRose, error) {
+	observations, err := StationObservations(station, ObservationsOptions{Start: start, End: end})
+	if err != nil {
+		return nil, err
+	}
+	if len(observations) == 0 {
+		return nil, errors.New("no observations in the time range")
+	}
+	return NewWindRose(observations, sectors, speeds), nil
+}
+
+// Direction returns the direction of the center of a sector in degrees, ex.
+// 22.5 for sector 1 of 16.
+func (r *WindRose) Direction(sector int) float64 {
+	return float64(sector) * 360 / float64(r.Sectors)
+}
+
+// Frequency returns the percentage of the observations in a sector and speed
+// bin.
+func (r *WindRose) Frequency(sector int, bin int) float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Counts[sector][bin]) * 100 / float64(r.Total)
+}
+
+// SectorFrequency returns the percentage of the observations in a sector,
+// all speeds together.
+func (r *WindRose) SectorFrequency(sector int) float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	n := 0
+	for _, count := range r.Counts[sector] {
+		n += count
+	}
+	return float64(n) * 100 / float64(r.Total)
+}
+
+// CalmFrequency returns the percentage of calm observations.
+func (r *WindRose) CalmFrequency() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Calm) * 100 / float64(r.Total)
+}
+
+// Prevailing returns the sector the wind blows from most often, or -1 if no
+// wind was observed. See Direction for its direction and Locale.Compass for
+// its name.
+func (r *WindRose) Prevailing() int {
+	prevailing, most := -1, 0.0
+	for sector := 0; sector < r.Sectors; sector++ {
+		if f := r.SectorFrequency(sector); f > most {
+			prevailing, most = sector, f
+		}
+	}
+	return prevailing
+}
@@ -0,0 +1,84 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// windObservation returns an observation JSON document with the given wind
+func windObservation(t time.Time, direction float64, speed float64, qc string) string {
+	return fmt.Sprintf(`{"timestamp": "%s",
+		"windDirection": {"value": %v, "unitCode": "wmoUnit:degree_(angle)", "qualityControl": "V"},
+		"windSpeed": {"value": %v, "unitCode": "wmoUnit:km_h-1", "qualityControl": "%s"}}`,
+		t.Format(time.RFC3339), direction, speed, qc)
+}
+
+func TestWindRoseForStation(t *testing.T) {
+	start := time.Date(2023, 7, 4, 0, 0, 0, 0, time.UTC)
+	var observations []string
+	for i, wind := range [][2]float64{
+		{270, 15}, {290, 25}, {260, 55}, {360, 5}, {10, 12}, {90, 8}, {0, 0}, {0, 9}, {270, 35},
+	} {
+		observations = append(observations, windObservation(start.Add(time.Duration(i)*time.Hour), wind[0], wind[1], "V"))
+	}
+	observations = append(observations, windObservation(start.Add(10*time.Hour), 90, 40, "X"))
+	fakeAPI(t, map[string]string{
+		"/stations/KORD/observations": `{"@graph": [` + strings.Join(observations, ",") + `]}`,
+	})
+
+	rose, err := noaa.WindRoseForStation("KORD", start, start.Add(12*time.Hour), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rose.Sectors != 16 || len(rose.Counts) != 16 || len(rose.Counts[0]) != len(noaa.DefaultWindRoseSpeeds)+1 {
+		t.Fatalf("noaa.WindRoseForStation() should default to 16 sectors and the default speeds, got %d by %d", len(rose.Counts), len(rose.Counts[0]))
+	}
+	// the variable and the rejected winds are not counted
+	if rose.Total != 8 || rose.Calm != 1 {
+		t.Errorf("noaa.WindRoseForStation() should count 8 observations with 1 calm, got %d and %d", rose.Total, rose.Calm)
+	}
+	if !rose.Start.Equal(start) || !rose.End.Equal(start.Add(8*time.Hour)) {
+		t.Errorf("noaa.WindRoseForStation() should span the counted observations, got %v to %v", rose.Start, rose.End)
+	}
+
+	west := 12
+	if rose.Counts[west][1] != 1 || rose.Counts[west][3] != 1 || rose.Counts[west][5] != 1 || rose.Counts[west+1][2] != 1 {
+		t.Errorf("noaa.WindRoseForStation() should bin the west winds by speed, got %v and %v", rose.Counts[west], rose.Counts[west+1])
+	}
+	if rose.Counts[0][0] != 1 || rose.Counts[0][1] != 1 || rose.Counts[4][0] != 1 {
+		t.Errorf("noaa.WindRoseForStation() should bin north and east winds, got %v and %v", rose.Counts[0], rose.Counts[4])
+	}
+	if got := rose.Prevailing(); got != west || rose.Direction(got) != 270 {
+		t.Errorf("noaa.WindRose.Prevailing() should be west, got sector %d", got)
+	}
+	if got := rose.SectorFrequency(west); got != 37.5 {
+		t.Errorf("noaa.WindRose.SectorFrequency() should be 37.5%%, got %v", got)
+	}
+	if got := rose.CalmFrequency(); got != 12.5 {
+		t.Errorf("noaa.WindRose.CalmFrequency() should be 12.5%%, got %v", got)
+	}
+	total := rose.CalmFrequency()
+	for sector := range rose.Counts {
+		for bin := range rose.Counts[sector] {
+			total += rose.Frequency(sector, bin)
+		}
+	}
+	if math.Abs(total-100) > 1e-9 {
+		t.Errorf("noaa.WindRose frequencies should add up to 100%%, got %v", total)
+	}
+}
+
+func TestNewWindRoseEmpty(t *testing.T) {
+	rose := noaa.NewWindRose(nil, 8, []float64{20})
+	if rose.Total != 0 || rose.Prevailing() != -1 || rose.CalmFrequency() != 0 || rose.Direction(1) != 45 {
+		t.Errorf("noaa.NewWindRose() without observations should be empty, got %+v", rose)
+	}
+}
@@ -0,0 +1,233 @@
+// Package wssi retrieves the Winter Storm Severity Index (WSSI) of the Weather
+// Prediction Center for a point and pairs its impact categories with the
+// snowfall of the gridpoint forecast, for winter operations:
+//
+//	report, err := wssi.ForPoint(ctx, "41.837", "-87.685")
+//	...
+//	forecast, err := noaa.GridpointForecast("41.837", "-87.685")
+//	...
+//	for _, d := range wssi.Pair(forecast, report.Days) {
+//		fmt.Println(d.Start, d.Impact, d.Snowfall, d.Unit)
+//	}
+//
+// weather.gov does not serve the WSSI. It is read from the WPC layers of the
+// NWS map services, see BaseURL.
+package wssi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+// BaseURL is the URL of the map service of the WSSI.
+var BaseURL = "https://mapservices.weather.noaa.gov/vector/rest/services/outlooks/wpc_wssi/MapServer"
+
+// Layers are the IDs of the map service layers of the overall impact of each
+// day, Day 1 first.
+var Layers = []int{1, 2, 3}
+
+// ImpactField is the attribute of the features holding the impact category.
+var ImpactField = "impact"
+
+// Impact is a WSSI impact category.
+type Impact int
+
+// WSSI impact categories, from no impact to extreme impacts
+const (
+	ImpactNone Impact = iota
+	ImpactLimited
+	ImpactMinor
+	ImpactModerate
+	ImpactMajor
+	ImpactExtreme
+)
+
+// impactNames are the names of the categories, by Impact
+var impactNames = []string{"None", "Limited", "Minor", "Moderate", "Major", "Extreme"}
+
+// String returns the name of the category, ex. Moderate.
+func (i Impact) String() string {
+	if i < 0 || int(i) >= len(impactNames) {
+		return fmt.Sprintf("Impact(%d)", int(i))
+	}
+	return impactNames[i]
+}
+
+// ParseImpact parses the name of a category, ignoring case and an Impacts
+// suffix, ex. Minor Impacts. A blank name is ImpactNone.
+func ParseImpact(s string) (Impact, error) {
+	name := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(strings.ToLower(s)), "impacts"))
+	if name == "" {
+		return ImpactNone, nil
+	}
+	for i, n := range impactNames {
+		if strings.EqualFold(name, n) {
+			return Impact(i), nil
+		}
+	}
+	return ImpactNone, fmt.Errorf("unknown WSSI impact %q", s)
+}
+
+// Day is the WSSI of a day at a point. WSSI days last 24 hours from 12 UTC.
+type Day struct {
+	Day        int // 1 for the current day
+	Start      time.Time
+	End        time.Time
+	Impact     Impact
+	Attributes map[string]interface{} // attributes of the feature, nil if the point has no impact
+}
+
+// Report is the WSSI of a point.
+type Report struct {
+	Office string // forecast office (WFO) of the point, ex. LOT
+	Days   []Day
+}
+
+// ForPoint returns the WSSI of each day of Layers at <lat,lon>, with the
+// forecast office of the point. Points outside the WSSI areas have
+// ImpactNone.
+func ForPoint(ctx context.Context, lat string, lon string) (*Report, error) {
+	c, err := noaa.ParseCoordinates(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	point, err := noaa.Points(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Office: point.CWA}
+	start := dayStart(time.Now())
+	for i, layer := range Layers {
+		d := Day{Day: i + 1, Start: start.AddDate(0, 0, i), End: start.AddDate(0, 0, i+1)}
+		if d.Attributes, err = query(ctx, layer, c); err != nil {
+			return nil, err
+		}
+		if d.Attributes != nil {
+			if d.Impact, err = ParseImpact(fmt.Sprint(d.Attributes[ImpactField])); err != nil {
+				return nil, fmt.Errorf("wssi: day %d: %w", d.Day, err)
+			}
+		}
+		report.Days = append(report.Days, d)
+	}
+	return report, nil
+}
+
+// dayStart returns the start of the WSSI day of t, 12 UTC
+func dayStart(t time.Time) time.Time {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, time.UTC)
+	if t.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+// query returns the attributes of the feature of the layer at the point,
+// the highest impact if several intersect it, or nil if none does
+func query(ctx context.Context, layer int, c noaa.Coordinates) (map[string]interface{}, error) {
+	q := url.Values{}
+	q.Set("geometry", fmt.Sprintf("%g,%g", c.Lon, c.Lat))
+	q.Set("geometryType", "esriGeometryPoint")
+	q.Set("inSR", "4326")
+	q.Set("spatialRel", "esriSpatialRelIntersects")
+	q.Set("outFields", "*")
+	q.Set("returnGeometry", "false")
+	q.Set("f", "json")
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%d/query?%s", BaseURL, layer, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", noaa.GetConfig().UserAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wssi: layer %d: %s", layer, res.Status)
+	}
+	var r struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Features []struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("wssi: layer %d: %w", layer, err)
+	}
+	if r.Error != nil {
+		return nil, fmt.Errorf("wssi: layer %d: %s", layer, r.Error.Message)
+	}
+	var highest map[string]interface{}
+	best := ImpactNone - 1
+	for _, f := range r.Features {
+		impact, err := ParseImpact(fmt.Sprint(f.Attributes[ImpactField]))
+		if err != nil {
+			return nil, fmt.Errorf("wssi: layer %d: %w", layer, err)
+		}
+		if impact > best {
+			highest, best = f.Attributes, impact
+		}
+	}
+	return highest, nil
+}
+
+// DaySnow is a WSSI day with the snowfall forecast for it.
+type DaySnow struct {
+	Day
+	Snowfall float64 // total snowfall of the day
+	Unit     string  // unit of the snowfall, ex. wmoUnit:mm
+}
+
+// Pair returns the days with the total of the SnowfallAmount series of the
+// gridpoint forecast during each day. Snowfall values which partly overlap a
+// day are counted in proportion to the overlap.
+func Pair(forecast *noaa.GridpointForecastResponse, days []Day) []DaySnow {
+	snowfall := forecast.SnowfallAmount.Series()
+	paired := make([]DaySnow, len(days))
+	for i, d := range days {
+		paired[i] = DaySnow{Day: d, Unit: snowfall.Unit}
+		for _, v := range snowfall.Values {
+			start, end := v.Start, v.End()
+			if start.Before(d.Start) {
+				start = d.Start
+			}
+			if end.After(d.End) {
+				end = d.End
+			}
+			if v.Duration > 0 && end.After(start) {
+				paired[i].Snowfall += v.Value * float64(end.Sub(start)) / float64(v.Duration)
+			}
+		}
+	}
+	return paired
+}
+
+// Provider is a noaa.SupplementaryDataProvider of the WSSI of points, named
+// wssi, with a single series named wssi.impact of the Impact of each day.
+type Provider struct{}
+
+// Name returns wssi.
+func (Provider) Name() string { return "wssi" }
+
+// Series returns the WSSI of <lat,lon>, see ForPoint.
+func (Provider) Series(ctx context.Context, lat string, lon string) ([]noaa.SupplementarySeries, error) {
+	report, err := ForPoint(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	series := noaa.SupplementarySeries{Name: "wssi.impact", Values: make([]noaa.SupplementaryValue, len(report.Days))}
+	for i, d := range report.Days {
+		series.Values[i] = noaa.SupplementaryValue{Time: d.Start, Value: float64(d.Impact), Category: d.Impact.String()}
+	}
+	return []noaa.SupplementarySeries{series}, nil
+}
@@ -0,0 +1,98 @@
+package wssi_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chrisdobbins/noaa"
+	"github.com/chrisdobbins/noaa/wssi"
+)
+
+func TestForPoint(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/points/41.837,-87.685":
+			fmt.Fprint(w, `{"cwa": "LOT", "timeZone": "America/Chicago"}`)
+		case "/wssi/1/query":
+			if r.URL.Query().Get("geometry") != "-87.685,41.837" {
+				t.Errorf("expected the point as x,y, got %s", r.URL.Query().Get("geometry"))
+			}
+			fmt.Fprint(w, `{"features": [{"attributes": {"impact": "Minor", "idp_source": "wssi_day1"}}, {"attributes": {"impact": "Moderate"}}]}`)
+		case "/wssi/2/query":
+			fmt.Fprint(w, `{"features": []}`)
+		case "/wssi/3/query":
+			fmt.Fprint(w, `{"features": [{"attributes": {"impact": "Limited Impacts"}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client, baseURL := http.DefaultClient, wssi.BaseURL
+	http.DefaultClient = api.Client()
+	t.Cleanup(func() {
+		api.Close()
+		http.DefaultClient, wssi.BaseURL = client, baseURL
+		noaa.SetConfig(noaa.GetDefaultConfig())
+	})
+	noaa.SetBaseURL(api.URL)
+	wssi.BaseURL = api.URL + "/wssi"
+
+	report, err := wssi.ForPoint(context.Background(), "41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Office != "LOT" || len(report.Days) != 3 {
+		t.Fatalf("expected 3 days for LOT, got %+v", report)
+	}
+	impacts := []wssi.Impact{wssi.ImpactModerate, wssi.ImpactNone, wssi.ImpactLimited}
+	for i, d := range report.Days {
+		if d.Impact != impacts[i] {
+			t.Errorf("expected a %s impact on day %d, got %s", impacts[i], d.Day, d.Impact)
+		}
+		if d.Start.Hour() != 12 || d.End.Sub(d.Start) != 24*time.Hour || time.Since(report.Days[0].Start) >= 24*time.Hour {
+			t.Errorf("expected day %d to last from 12 UTC to 12 UTC, got %v to %v", d.Day, d.Start, d.End)
+		}
+	}
+
+	series, err := wssi.Provider{}.Series(context.Background(), "41.837", "-87.685")
+	if err != nil || len(series) != 1 || series[0].Values[0].Category != "Moderate" {
+		t.Errorf("expected the provider to return the impacts, got %+v, %v", series, err)
+	}
+}
+
+func TestParseImpact(t *testing.T) {
+	for s, want := range map[string]wssi.Impact{"": wssi.ImpactNone, "major": wssi.ImpactMajor, "Extreme Impacts": wssi.ImpactExtreme} {
+		if got, err := wssi.ParseImpact(s); err != nil || got != want {
+			t.Errorf("expected %q to be %s, got %s, %v", s, want, got, err)
+		}
+	}
+	if _, err := wssi.ParseImpact("Severe"); err == nil {
+		t.Error("expected an error for an unknown impact")
+	}
+}
+
+func TestPair(t *testing.T) {
+	start := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC)
+	forecast := &noaa.GridpointForecastResponse{SnowfallAmount: noaa.GridpointForecastTimeSeries{
+		Uom: "wmoUnit:mm",
+		Values: []noaa.GridpointForecastTimeSeriesValue{
+			{ValidTime: "2023-01-16T00:00:00+00:00/PT6H", Value: 30},
+			{ValidTime: "2023-01-16T06:00:00+00:00/PT12H", Value: 60}, // half on each day
+		},
+	}}
+	days := []wssi.Day{
+		{Day: 1, Start: start, End: start.Add(24 * time.Hour), Impact: wssi.ImpactMajor},
+		{Day: 2, Start: start.Add(24 * time.Hour), End: start.Add(48 * time.Hour)},
+	}
+	paired := wssi.Pair(forecast, days)
+	if len(paired) != 2 || math.Abs(paired[0].Snowfall-60) > 1e-9 || math.Abs(paired[1].Snowfall-30) > 1e-9 || paired[0].Unit != "wmoUnit:mm" {
+		t.Errorf("expected 60 and 30 mm of snow, got %+v", paired)
+	}
+	if paired[0].Impact != wssi.ImpactMajor {
+		t.Errorf("expected the impact of the day, got %s", paired[0].Impact)
+	}
+}
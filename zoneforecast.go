@@ -0,0 +1,169 @@
+package noaa
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ZoneForecastResponse holds the JSON values from
+// /zones/forecast/{zoneId}/forecast, the text forecast of a forecast zone.
+type ZoneForecastResponse struct {
+	Zone    string               `json:"zone"`
+	Updated string               `json:"updated"`
+	Periods []ZoneForecastPeriod `json:"periods"`
+}
+
+// ZoneForecastPeriod is a period of a zone forecast, ex. Tonight, with its
+// forecast text.
+type ZoneForecastPeriod struct {
+	Number           int32  `json:"number"`
+	Name             string `json:"name"`
+	DetailedForecast string `json:"detailedForecast"`
+}
+
+// TemperaturePhrase is a temperature extracted from forecast text, ex. "Highs
+// in the upper 70s" is a high of 77 to 79. Min and Max are equal for a single
+// temperature, ex. "Lows around 60".
+type TemperaturePhrase struct {
+	Kind   string // high, low or temperature
+	Min    int
+	Max    int
+	Phrase string
+}
+
+// PrecipitationChance is a chance of precipitation extracted from forecast
+// text, ex. "Chance of showers and thunderstorms 40 percent".
+type PrecipitationChance struct {
+	Type    string // ex. showers and thunderstorms
+	Percent int
+}
+
+// ZoneForecastDetails are the values extracted from the text of a zone
+// forecast period, see ParseZoneForecastText.
+type ZoneForecastDetails struct {
+	Temperatures  []TemperaturePhrase
+	Precipitation []PrecipitationChance
+}
+
+// High returns the first high temperature, ok is false if there is none.
+func (d ZoneForecastDetails) High() (t TemperaturePhrase, ok bool) {
+	return d.temperature("high")
+}
+
+// Low returns the first low temperature, ok is false if there is none.
+func (d ZoneForecastDetails) Low() (t TemperaturePhrase, ok bool) {
+	return d.temperature("low")
+}
+
+// temperature returns the first temperature of the kind
+func (d ZoneForecastDetails) temperature(kind string) (TemperaturePhrase, bool) {
+	for _, t := range d.Temperatures {
+		if t.Kind == kind {
+			return t, true
+		}
+	}
+	return TemperaturePhrase{}, false
+}
+
+// MaxPrecipitationChance returns the highest chance of precipitation in
+// percent, or 0 if the text does not mention one.
+func (d ZoneForecastDetails) MaxPrecipitationChance() int {
+	percent := 0
+	for _, p := range d.Precipitation {
+		if p.Percent > percent {
+			percent = p.Percent
+		}
+	}
+	return percent
+}
+
+// GetZoneForecast returns the text forecast of a forecast zone, ex. ILZ014.
+func GetZoneForecast(zoneID string) (*ZoneForecastResponse, error) {
+	return zoneForecast(ZoneTypeForecast, zoneID)
+}
+
+// zoneForecast returns the text forecast of a zone of the type
+func zoneForecast(zoneType string, zoneID string) (*ZoneForecastResponse, error) {
+	endpoint := apiURL("zones", zoneType, zoneID, "forecast").String()
+	var forecast ZoneForecastResponse
+	if err := getDecoded(endpoint, &forecast); err != nil {
+		return nil, err
+	}
+	return &forecast, nil
+}
+
+// Details extracts the temperatures and chances of precipitation from the
+// forecast text of the period.
+func (p ZoneForecastPeriod) Details() ZoneForecastDetails {
+	return ParseZoneForecastText(p.DetailedForecast)
+}
+
+var (
+	// temperaturePhrase matches the temperature phrases used in zone
+	// forecasts, ex. "Highs in the lower 80s", "Lows around 5 below",
+	// "Highs 85 to 90", "Highs in the upper 70s to lower 80s" and
+	// "Temperatures falling into the 40s"
+	temperaturePhrase = regexp.MustCompile(`(?i)\b(highs|lows|high|low|temperatures? (?:rising|falling|steady)?(?: to| into| in| around| near)*)\s*(?:(?:around|near|in the|the|of|generally|mainly)\s+)*(?:(lower|mid|upper)\s+)?(-?\d+)(s)?(?:\s+(below|above)(?: zero)?)?(?:\s+to\s+(?:(?:around|near|the)\s+)?(?:(lower|mid|upper)\s+)?(-?\d+)(s)?(?:\s+(below|above)(?: zero)?)?)?`)
+	// precipitationPhrase matches chances of precipitation, ex. "Chance of
+	// rain 40 percent" and "Chance of snow near 100 percent"
+	precipitationPhrase = regexp.MustCompile(`(?i)\bchance of ([a-z ]+?)\s+(?:(?:near|around|is)\s+)?(\d{1,3})\s+percent`)
+)
+
+// ParseZoneForecastText extracts the temperatures and chances of
+// precipitation from the text of a zone forecast period, ex. "Mostly sunny.
+// Highs in the upper 70s. Chance of rain 20 percent." has a high of 77 to 79
+// and a 20 percent chance of rain. Decades are split into lower (0-3), mid
+// (3-6) and upper (7-9), as in NWS forecast wording.
+func ParseZoneForecastText(text string) ZoneForecastDetails {
+	text = strings.Join(strings.Fields(text), " ")
+	var d ZoneForecastDetails
+	for _, m := range temperaturePhrase.FindAllStringSubmatch(text, -1) {
+		kind := strings.ToLower(m[1])
+		switch {
+		case strings.HasPrefix(kind, "high"):
+			kind = "high"
+		case strings.HasPrefix(kind, "low"):
+			kind = "low"
+		default:
+			kind = "temperature"
+		}
+		min, max := temperatureRange(m[2], m[3], m[4] != "", m[5])
+		if m[7] != "" {
+			_, max = temperatureRange(m[6], m[7], m[8] != "", m[9])
+		}
+		if min > max {
+			min, max = max, min
+		}
+		d.Temperatures = append(d.Temperatures, TemperaturePhrase{Kind: kind, Min: min, Max: max, Phrase: m[0]})
+	}
+	for _, m := range precipitationPhrase.FindAllStringSubmatch(text, -1) {
+		percent, _ := strconv.Atoi(m[2])
+		if percent > 100 {
+			continue
+		}
+		d.Precipitation = append(d.Precipitation, PrecipitationChance{Type: strings.ToLower(m[1]), Percent: percent})
+	}
+	return d
+}
+
+// temperatureRange returns the range of a temperature phrase, ex. "upper",
+// "70", decade true is 77 to 79
+func temperatureRange(part string, value string, decade bool, sign string) (min, max int) {
+	n, _ := strconv.Atoi(value)
+	if strings.EqualFold(sign, "below") {
+		n = -n
+	}
+	if !decade {
+		return n, n
+	}
+	switch strings.ToLower(part) {
+	case "lower":
+		return n, n + 3
+	case "mid":
+		return n + 3, n + 6
+	case "upper":
+		return n + 7, n + 9
+	}
+	return n, n + 9
+}
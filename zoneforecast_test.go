@@ -0,0 +1,82 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestGetZoneForecast(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/zones/forecast/ILZ014/forecast": `{
+			"zone": "{api}/zones/forecast/ILZ014",
+			"updated": "2023-07-04T15:30:00+00:00",
+			"periods": [
+				{"number": 1, "name": "This Afternoon", "detailedForecast": "Mostly sunny. Highs in the upper 80s. Southwest winds 10 to 15 mph."},
+				{"number": 2, "name": "Tonight", "detailedForecast": "Showers and thunderstorms likely. Lows around 70. Chance of precipitation 60 percent."}
+			]
+		}`,
+	})
+	forecast, err := noaa.GetZoneForecast("ILZ014")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forecast.Periods) != 2 || forecast.Periods[1].Name != "Tonight" {
+		t.Fatalf("noaa.GetZoneForecast() should return the periods, got %+v", forecast)
+	}
+	if high, ok := forecast.Periods[0].Details().High(); !ok || high.Min != 87 || high.Max != 89 {
+		t.Errorf("noaa.ZoneForecastPeriod.Details() should return a high of 87 to 89, got %+v", high)
+	}
+	tonight := forecast.Periods[1].Details()
+	if low, ok := tonight.Low(); !ok || low.Min != 70 || low.Max != 70 {
+		t.Errorf("noaa.ZoneForecastPeriod.Details() should return a low of 70, got %+v", low)
+	}
+	if tonight.MaxPrecipitationChance() != 60 {
+		t.Errorf("noaa.ZoneForecastPeriod.Details() should return a 60 percent chance, got %+v", tonight.Precipitation)
+	}
+}
+
+func TestParseZoneForecastText(t *testing.T) {
+	tests := []struct {
+		text    string
+		kind    string
+		min     int
+		max     int
+		percent int
+	}{
+		{"Sunny. Highs in the lower 80s.", "high", 80, 83, 0},
+		{"Partly cloudy. Highs in the mid 70s.", "high", 73, 76, 0},
+		{"Mostly cloudy. Lows in the 60s.", "low", 60, 69, 0},
+		{"Highs 85 to 90. Chance of thunderstorms 30 percent.", "high", 85, 90, 30},
+		{"Highs in the upper 70s to lower 80s.", "high", 77, 83, 0},
+		{"Snow. Lows around 5 below. Chance of snow near 100 percent.", "low", -5, -5, 100},
+		{"Lows 5 below to 5 above zero.", "low", -5, 5, 0},
+		{"Temperatures falling into the 40s. Chance of rain 40 percent.", "temperature", 40, 49, 40},
+		{"Chance of showers and thunderstorms 20 percent. Highs near 90.", "high", 90, 90, 20},
+	}
+	for _, test := range tests {
+		d := noaa.ParseZoneForecastText(test.text)
+		if len(d.Temperatures) != 1 {
+			t.Errorf("%q: expected one temperature, got %+v", test.text, d.Temperatures)
+			continue
+		}
+		temp := d.Temperatures[0]
+		if temp.Kind != test.kind || temp.Min != test.min || temp.Max != test.max {
+			t.Errorf("%q: expected %s %d to %d, got %+v", test.text, test.kind, test.min, test.max, temp)
+		}
+		if p := d.MaxPrecipitationChance(); p != test.percent {
+			t.Errorf("%q: expected a %d percent chance, got %d", test.text, test.percent, p)
+		}
+	}
+
+	d := noaa.ParseZoneForecastText("Southwest winds 10 to 15 mph. Chance of showers and thunderstorms 40 percent.")
+	if len(d.Temperatures) != 0 {
+		t.Errorf("expected no temperatures, got %+v", d.Temperatures)
+	}
+	if len(d.Precipitation) != 1 || d.Precipitation[0].Type != "showers and thunderstorms" {
+		t.Errorf("expected a chance of showers and thunderstorms, got %+v", d.Precipitation)
+	}
+}
@@ -0,0 +1,358 @@
+package noaa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Types of zones, as used in /zones/{type}/{zoneId} endpoints.
+const (
+	ZoneTypeForecast = "forecast"
+	ZoneTypeCounty   = "county"
+	ZoneTypeFire     = "fire"
+
+	// Marine zones, see IsMarineZone. Coastal zones cover waters up to about
+	// 60 nautical miles from shore, offshore zones the waters beyond them and
+	// the marine type lists both.
+	ZoneTypeCoastal  = "coastal"
+	ZoneTypeOffshore = "offshore"
+	ZoneTypeMarine   = "marine"
+)
+
+// zoneSampleGrid is the number of rows and columns of the grid sampled by
+// PointsInZone
+const zoneSampleGrid = 4
+
+// Zone holds the JSON values from /zones/{type}/{zoneId}. Geometry is a WKT
+// string for JSON-LD responses and a GeoJSON geometry for GeoJSON responses.
+type Zone struct {
+	URI             string          `json:"@id"`
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Name            string          `json:"name"`
+	State           string          `json:"state"`
+	CWA             []string        `json:"cwa"`
+	ForecastOffices []string        `json:"forecastOffices"`
+	TimeZone        []string        `json:"timeZone"`
+	RadarStation    string          `json:"radarStation"`
+	Geometry        json.RawMessage `json:"geometry"`
+}
+
+// PointZones are the zones containing a point. A zone is nil if the point is
+// not in a zone of that type.
+type PointZones struct {
+	Forecast *Zone
+	County   *Zone
+	Fire     *Zone
+}
+
+// ZoneID returns the zone ID from a zone URL, ex. ILZ014 for
+// https://api.weather.gov/zones/forecast/ILZ014.
+func ZoneID(zoneURL string) string {
+	return zoneURL[strings.LastIndex(zoneURL, "/")+1:]
+}
+
+// GetZone returns a zone of the type, ex. GetZone(ZoneTypeForecast, "ILZ014").
+func GetZone(zoneType string, id string) (*Zone, error) {
+	return getZone(apiURL("zones", zoneType, id).String())
+}
+
+// getZone returns the zone at the endpoint
+func getZone(endpoint string) (*Zone, error) {
+	return shared(context.Background(), endpoint, func(context.Context) (*Zone, error) {
+		var zone Zone
+		if err := getDecoded(endpoint, &zone); err != nil {
+			return nil, err
+		}
+		return &zone, nil
+	})
+}
+
+// ZonesForPoint returns the forecast, county and fire weather zones containing
+// a point.
+func ZonesForPoint(lat string, lon string) (*PointZones, error) {
+	point, err := Points(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	zones := &PointZones{}
+	for _, z := range []struct {
+		url  string
+		zone **Zone
+	}{
+		{point.ForecastZone, &zones.Forecast},
+		{point.County, &zones.County},
+		{point.FireWeatherZone, &zones.Fire},
+	} {
+		if z.url == "" {
+			continue
+		}
+		if *z.zone, err = getZone(z.url); err != nil {
+			return nil, fmt.Errorf("failed to get zone %s: %w", ZoneID(z.url), err)
+		}
+	}
+	return zones, nil
+}
+
+// PointsInZone returns representative points inside a forecast zone, or a
+// county for county IDs such as ILC031, to be used with the point endpoints.
+// The first point is the centroid of the largest area of the zone when it is
+// inside the zone, followed by points sampled on a grid over that area.
+func PointsInZone(zoneID string) ([]Coordinates, error) {
+	zoneType := ZoneTypeForecast
+	if len(zoneID) == 6 && zoneID[2] == 'C' {
+		zoneType = ZoneTypeCounty
+	}
+	zone, err := GetZone(zoneType, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	polygons, err := zone.Polygons()
+	if err != nil {
+		return nil, err
+	}
+	if len(polygons) == 0 {
+		return nil, fmt.Errorf("zone %s has no geometry", zoneID)
+	}
+	return samplePolygon(largestPolygon(polygons)), nil
+}
+
+// Polygon is a polygon of an area, the outer ring followed by any holes.
+// Rings are closed, the last point repeats the first point.
+type Polygon [][]Coordinates
+
+// Contains reports whether the point is inside the outer ring and outside
+// any holes of the polygon.
+func (p Polygon) Contains(c Coordinates) bool {
+	if len(p) == 0 || !ringContains(p[0], c) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Polygons returns the polygons of the zone geometry.
+func (z *Zone) Polygons() ([]Polygon, error) {
+	return parseGeometry("zone", z.Geometry)
+}
+
+// parseGeometry returns the polygons of a WKT string or GeoJSON geometry, or
+// nil for a missing geometry. Empty polygons and rings are dropped. kind
+// describes the geometry in errors.
+func parseGeometry(kind string, raw json.RawMessage) ([]Polygon, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+	if !isObject(raw) {
+		var wkt string
+		if err := json.Unmarshal(raw, &wkt); err != nil {
+			return nil, fmt.Errorf("invalid %s geometry: %w", kind, err)
+		}
+		return parseWKTPolygons(wkt)
+	}
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(raw, &geometry); err != nil {
+		return nil, fmt.Errorf("invalid %s geometry: %w", kind, err)
+	}
+	var rings [][][][2]float64
+	switch geometry.Type {
+	case "Polygon":
+		var polygon [][][2]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("invalid %s geometry: %w", kind, err)
+		}
+		rings = append(rings, polygon)
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid %s geometry: %w", kind, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported %s geometry type %q", kind, geometry.Type)
+	}
+	polygons := make([]Polygon, 0, len(rings))
+	for _, polygon := range rings {
+		var p Polygon
+		for _, ring := range polygon {
+			if len(ring) == 0 {
+				continue
+			}
+			r := make([]Coordinates, len(ring))
+			for i, position := range ring {
+				r[i] = Coordinates{Lon: position[0], Lat: position[1]} // GeoJSON positions are lon, lat
+			}
+			p = append(p, r)
+		}
+		if len(p) > 0 {
+			polygons = append(polygons, p)
+		}
+	}
+	return polygons, nil
+}
+
+// parseWKTPolygons parses a WKT POLYGON or MULTIPOLYGON, ex.
+// POLYGON ((-88.1 41.9, -87.5 41.9, -87.5 42.1, -88.1 41.9)), dropping
+// empty polygons and rings
+func parseWKTPolygons(wkt string) ([]Polygon, error) {
+	wkt = strings.TrimSpace(wkt)
+	i := strings.Index(wkt, "(")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid WKT geometry %q", wkt)
+	}
+	kind := strings.ToUpper(strings.TrimSpace(wkt[:i]))
+	body := wkt[i:]
+	var polygons []string
+	switch kind {
+	case "POLYGON":
+		polygons = []string{body}
+	case "MULTIPOLYGON":
+		body = strings.TrimSpace(body)
+		if len(body) < 2 || !strings.HasSuffix(body, ")") {
+			return nil, fmt.Errorf("invalid WKT geometry %q", wkt)
+		}
+		polygons = splitWKT(body[1 : len(body)-1])
+	default:
+		return nil, fmt.Errorf("unsupported WKT geometry %q", kind)
+	}
+	var result []Polygon
+	for _, polygon := range polygons {
+		polygon = strings.TrimSpace(polygon)
+		if len(polygon) < 2 || polygon[0] != '(' || polygon[len(polygon)-1] != ')' {
+			return nil, fmt.Errorf("invalid WKT polygon %q", polygon)
+		}
+		var p Polygon
+		for _, ring := range splitWKT(polygon[1 : len(polygon)-1]) {
+			ring = strings.TrimSpace(strings.Trim(strings.TrimSpace(ring), "()"))
+			if ring == "" {
+				continue
+			}
+			var r []Coordinates
+			for _, position := range strings.Split(ring, ",") {
+				fields := strings.Fields(position)
+				if len(fields) < 2 {
+					return nil, fmt.Errorf("invalid WKT position %q", position)
+				}
+				lon, err1 := strconv.ParseFloat(fields[0], 64)
+				lat, err2 := strconv.ParseFloat(fields[1], 64)
+				if err1 != nil || err2 != nil {
+					return nil, fmt.Errorf("invalid WKT position %q", position)
+				}
+				r = append(r, Coordinates{Lat: lat, Lon: lon})
+			}
+			p = append(p, r)
+		}
+		if len(p) > 0 {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// splitWKT splits a list of parenthesized WKT values, ex. "(a), (b)"
+func splitWKT(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				parts = append(parts, s[start:i+1])
+			}
+		}
+	}
+	return parts
+}
+
+// ringContains reports whether the point is inside the ring, using the
+// even-odd rule
+func ringContains(ring []Coordinates, c Coordinates) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > c.Lat) != (b.Lat > c.Lat) &&
+			c.Lon < (b.Lon-a.Lon)*(c.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ringArea returns the signed area of the ring in square degrees and its
+// centroid
+func ringArea(ring []Coordinates) (area float64, centroid Coordinates) {
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[j], ring[i]
+		cross := a.Lon*b.Lat - b.Lon*a.Lat
+		area += cross
+		centroid.Lon += (a.Lon + b.Lon) * cross
+		centroid.Lat += (a.Lat + b.Lat) * cross
+	}
+	area /= 2
+	if area != 0 {
+		centroid.Lon /= 6 * area
+		centroid.Lat /= 6 * area
+	}
+	return area, centroid
+}
+
+// largestPolygon returns the polygon with the largest outer ring
+func largestPolygon(polygons []Polygon) Polygon {
+	var largest Polygon
+	largestArea := -1.0
+	for _, p := range polygons {
+		if len(p) == 0 {
+			continue
+		}
+		if area, _ := ringArea(p[0]); math.Abs(area) > largestArea {
+			largest, largestArea = p, math.Abs(area)
+		}
+	}
+	return largest
+}
+
+// samplePolygon returns the centroid of the polygon, if it is inside, and the
+// centers of the cells of a grid over the polygon which are inside it. A
+// vertex is returned for polygons too small to contain any of those points.
+func samplePolygon(p Polygon) []Coordinates {
+	var points []Coordinates
+	if _, centroid := ringArea(p[0]); p.Contains(centroid) {
+		points = append(points, centroid)
+	}
+	south, west, north, east := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, c := range p[0] {
+		south, north = math.Min(south, c.Lat), math.Max(north, c.Lat)
+		west, east = math.Min(west, c.Lon), math.Max(east, c.Lon)
+	}
+	for row := 0; row < zoneSampleGrid; row++ {
+		for col := 0; col < zoneSampleGrid; col++ {
+			c := Coordinates{
+				Lat: south + (north-south)*(float64(row)+0.5)/zoneSampleGrid,
+				Lon: west + (east-west)*(float64(col)+0.5)/zoneSampleGrid,
+			}
+			if p.Contains(c) {
+				points = append(points, c)
+			}
+		}
+	}
+	if len(points) == 0 && len(p[0]) > 0 {
+		points = append(points, p[0][0])
+	}
+	return points
+}
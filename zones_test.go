@@ -0,0 +1,82 @@
+//go:build !examples
+// +build !examples
+
+package noaa_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrisdobbins/noaa"
+)
+
+func TestZonesForPoint(t *testing.T) {
+	fakeAPI(t, map[string]string{
+		"/points/41.837,-87.685": `{"forecastZone": "{api}/zones/forecast/ILZ014", "county": "{api}/zones/county/ILC031", "fireWeatherZone": "{api}/zones/fire/ILZ014"}`,
+		"/zones/forecast/ILZ014": `{"id": "ILZ014", "type": "public", "name": "Cook", "state": "IL", "cwa": ["LOT"]}`,
+		"/zones/county/ILC031":   `{"id": "ILC031", "type": "county", "name": "Cook", "state": "IL"}`,
+		"/zones/fire/ILZ014":     `{"id": "ILZ014", "type": "fire", "name": "Cook", "state": "IL"}`,
+	})
+
+	zones, err := noaa.ZonesForPoint("41.837", "-87.685")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zones.Forecast.ID != "ILZ014" || zones.Forecast.CWA[0] != "LOT" || zones.County.ID != "ILC031" || zones.Fire.Type != "fire" {
+		t.Errorf("noaa.ZonesForPoint() should return the forecast, county and fire zones, got %+v %+v %+v", zones.Forecast, zones.County, zones.Fire)
+	}
+	if noaa.ZoneID("https://api.weather.gov/zones/county/ILC031") != "ILC031" {
+		t.Error("noaa.ZoneID() should return the ID from the zone URL")
+	}
+}
+
+func TestPointsInZone(t *testing.T) {
+	// an L shaped zone whose centroid is outside it, and a small island
+	fakeAPI(t, map[string]string{
+		"/zones/forecast/ILZ014": `{"id": "ILZ014", "geometry": "MULTIPOLYGON (((-88 41, -86 41, -86 42, -87 42, -87 43, -88 43, -88 41)), ((-85 41, -84.9 41, -84.9 41.1, -85 41)))"}`,
+		"/zones/county/ILC031":   `{"id": "ILC031", "geometry": {"type": "Polygon", "coordinates": [[[-88, 41], [-87, 41], [-87, 42], [-88, 42], [-88, 41]]]}}`,
+	})
+
+	for _, id := range []string{"ILZ014", "ILC031"} {
+		points, err := noaa.PointsInZone(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(points) == 0 {
+			t.Fatalf("noaa.PointsInZone(%q) should return points", id)
+		}
+		for _, p := range points {
+			if p.Lat < 41 || p.Lat > 43 || p.Lon < -88 || p.Lon > -86 {
+				t.Errorf("noaa.PointsInZone(%q) should return points in the largest area, got %+v", id, p)
+			}
+			if p.Lat > 42 && p.Lon > -87 {
+				t.Errorf("noaa.PointsInZone(%q) should not return points outside the zone, got %+v", id, p)
+			}
+		}
+	}
+
+	points, _ := noaa.PointsInZone("ILC031")
+	if lat, lon := points[0].Strings(); lat != "41.5000" || lon != "-87.5000" {
+		t.Errorf("noaa.PointsInZone() should return the centroid first, got %s,%s", lat, lon)
+	}
+}
+
+func TestPointsInZoneEmptyGeometry(t *testing.T) {
+	geometries := map[string]string{
+		"ILZ001": `{"type": "Polygon", "coordinates": []}`,
+		"ILZ002": `{"type": "MultiPolygon", "coordinates": [[[]]]}`,
+		"ILZ003": `"POLYGON ()"`,
+		"ILZ004": `"MULTIPOLYGON ((()))"`,
+	}
+	routes := map[string]string{}
+	for id, geometry := range geometries {
+		routes["/zones/forecast/"+id] = `{"id": "` + id + `", "geometry": ` + geometry + `}`
+	}
+	fakeAPI(t, routes)
+
+	for id, geometry := range geometries {
+		if _, err := noaa.PointsInZone(id); err == nil || !strings.Contains(err.Error(), "has no geometry") {
+			t.Errorf("noaa.PointsInZone() should fail for the empty geometry %s, got %v", geometry, err)
+		}
+	}
+}